@@ -45,19 +45,70 @@ func main() {
 	statusConfigService := service.NewStatusConfigService(db)
 	exportService := service.NewExportService(db, jobService)
 	resumeService := service.NewResumeService(db)
+	statusConsistencyService := service.NewStatusConsistencyService(db)
+	analyticsService := service.NewAnalyticsService(db)
+	successScoreService := service.NewSuccessProbabilityService(db)
+	recommendationService := service.NewRecommendationService(db)
+	activityService := service.NewActivityService(db)
+	auditService := service.NewAuditService(db)
+	trashService := service.NewTrashService(db, cfg.Jobs.TrashRetentionDays)
+	operationService := service.NewOperationService(db)
+	tagService := service.NewTagService(db)
+	customFieldService := service.NewCustomFieldService(db)
+	savedViewService := service.NewSavedViewService(db, jobService)
+	companyService := service.NewCompanyService(db)
+	offerService := service.NewOfferService(db, statusTrackingService)
+	contactService := service.NewContactService(db)
 
     // 在创建处理器之前，确保默认模板包含直通规则（幂等补齐）
     if err := statusConfigService.EnsureDirectTransitionsInDefaultTemplate(); err != nil {
         log.Printf("Warning: ensure default flow transitions failed: %v", err)
     }
 
+	// 状态历史一致性检查任务（可选）
+	if cfg.Jobs.StatusReconcileInterval != "" {
+		interval, err := time.ParseDuration(cfg.Jobs.StatusReconcileInterval)
+		if err != nil {
+			log.Printf("Warning: invalid STATUS_RECONCILE_INTERVAL %q: %v", cfg.Jobs.StatusReconcileInterval, err)
+		} else {
+			statusConsistencyService.StartScheduler(interval, cfg.Jobs.StatusReconcileAutoFix)
+		}
+	}
+
+	// 审计日志保留期清理
+	auditService.StartRetentionJob(cfg.Jobs.AuditRetentionDays)
+
+	// 回收站保留期清理
+	trashService.StartPurgeJob()
+
+	// offer 答复截止提醒（截止前 7/3/1 天）
+	offerService.StartReminderJob()
+
+	// 申请提醒到期后记入时间线
+	activityService.StartReminderJob()
+
+	// 成功率后台重新计算（查询与排序只读取已保存的结果）
+	successScoreService.StartScoreJob()
+
     // 初始化处理器
 	jobHandler := handler.NewJobApplicationHandler(jobService)
 	authHandler := handler.NewAuthHandler(authService)
-	statusTrackingHandler := handler.NewStatusTrackingHandler(statusTrackingService)
+	statusTrackingHandler := handler.NewStatusTrackingHandler(statusTrackingService, recommendationService)
 	statusConfigHandler := handler.NewStatusConfigHandler(statusConfigService)
 	exportHandler := handler.NewExportHandler(exportService)
 	resumeHandler := handler.NewResumeHandler(resumeService)
+	statusConsistencyHandler := handler.NewStatusConsistencyHandler(statusConsistencyService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	activityHandler := handler.NewActivityHandler(activityService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	operationHandler := handler.NewOperationHandler(operationService)
+	tagHandler := handler.NewTagHandler(tagService)
+	customFieldHandler := handler.NewCustomFieldHandler(customFieldService)
+	savedViewHandler := handler.NewSavedViewHandler(savedViewService)
+	companyHandler := handler.NewCompanyHandler(companyService)
+	offerHandler := handler.NewOfferHandler(offerService)
+	contactHandler := handler.NewContactHandler(contactService)
 
 	// 设置路由
 	router := mux.NewRouter()
@@ -113,7 +164,15 @@ func main() {
 	api.HandleFunc("/applications", jobHandler.GetJobApplicationsWithFilters).Methods("GET") // 更新为筛选版本
 	api.HandleFunc("/applications/statistics", jobHandler.GetStatistics).Methods("GET")
 	api.HandleFunc("/applications/search", jobHandler.SearchJobApplications).Methods("GET")
+	api.HandleFunc("/applications/query-fields", jobHandler.GetQueryFields).Methods("GET")
+	api.HandleFunc("/applications/position-taxonomy", jobHandler.GetPositionTaxonomy).Methods("GET")
+	api.HandleFunc("/applications/autocomplete/{field}", jobHandler.Autocomplete).Methods("GET")
 	api.HandleFunc("/applications/dashboard", jobHandler.GetDashboardData).Methods("GET")
+	api.HandleFunc("/applications/bulk", jobHandler.BulkCreate).Methods("POST")
+	api.HandleFunc("/applications/bulk", jobHandler.BulkUpdate).Methods("PUT")
+	api.HandleFunc("/applications/bulk", jobHandler.BulkDelete).Methods("DELETE")
+	api.HandleFunc("/applications/bulk-edit/preview", jobHandler.PreviewBulkEdit).Methods("POST")
+	api.HandleFunc("/applications/bulk-edit/apply", jobHandler.ApplyBulkEdit).Methods("POST")
 	api.HandleFunc("/applications/{id}", jobHandler.GetByID).Methods("GET")
 	api.HandleFunc("/applications/{id}", jobHandler.Update).Methods("PUT")
 	api.HandleFunc("/applications/{id}", jobHandler.Delete).Methods("DELETE")
@@ -122,10 +181,89 @@ func main() {
 	api.HandleFunc("/job-applications/{id}/status-history", statusTrackingHandler.GetStatusHistory).Methods("GET")
 	api.HandleFunc("/job-applications/{id}/status", statusTrackingHandler.UpdateJobStatus).Methods("POST")
 	api.HandleFunc("/job-applications/{id}/status-timeline", statusTrackingHandler.GetStatusTimeline).Methods("GET")
+	api.HandleFunc("/job-applications/{id}/activity", activityHandler.GetTimeline).Methods("GET")
+	api.HandleFunc("/job-applications/{id}/notes", activityHandler.AddNote).Methods("POST")
+	api.HandleFunc("/job-applications/{id}/audit-log", auditHandler.GetApplicationAuditLog).Methods("GET")
+	api.HandleFunc("/job-applications/{id}/tags", tagHandler.GetApplicationTags).Methods("GET")
+	api.HandleFunc("/job-applications/{id}/tags", tagHandler.SetApplicationTags).Methods("PUT")
+	api.HandleFunc("/audit-log", auditHandler.GetAuditLog).Methods("GET")
+
+	// 回收站（软删除的申请）
+	api.HandleFunc("/trash", trashHandler.List).Methods("GET")
+	api.HandleFunc("/trash", trashHandler.Empty).Methods("DELETE")
+	api.HandleFunc("/trash/restore", trashHandler.BatchRestore).Methods("POST")
+	api.HandleFunc("/trash/{id}/restore", trashHandler.Restore).Methods("POST")
+	api.HandleFunc("/trash/{id}", trashHandler.Purge).Methods("DELETE")
+
+	// 标签
+	api.HandleFunc("/tags", tagHandler.List).Methods("GET")
+	api.HandleFunc("/tags", tagHandler.Create).Methods("POST")
+	api.HandleFunc("/tags/{id}", tagHandler.Update).Methods("PUT")
+	api.HandleFunc("/tags/{id}", tagHandler.Delete).Methods("DELETE")
+
+	// 自定义字段
+	api.HandleFunc("/custom-fields", customFieldHandler.List).Methods("GET")
+	api.HandleFunc("/custom-fields", customFieldHandler.Create).Methods("POST")
+	api.HandleFunc("/custom-fields/{id}", customFieldHandler.Update).Methods("PUT")
+	api.HandleFunc("/custom-fields/{id}", customFieldHandler.Delete).Methods("DELETE")
+
+	// 保存的视图
+	api.HandleFunc("/saved-views", savedViewHandler.List).Methods("GET")
+	api.HandleFunc("/saved-views", savedViewHandler.Create).Methods("POST")
+	api.HandleFunc("/saved-views/{id}", savedViewHandler.Get).Methods("GET")
+	api.HandleFunc("/saved-views/{id}", savedViewHandler.Update).Methods("PUT")
+	api.HandleFunc("/saved-views/{id}", savedViewHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/saved-views/{id}/applications", savedViewHandler.GetApplications).Methods("GET")
+
+	// 公司与别名
+	api.HandleFunc("/companies", companyHandler.List).Methods("GET")
+	api.HandleFunc("/companies", companyHandler.Create).Methods("POST")
+	api.HandleFunc("/companies/{id}", companyHandler.Get).Methods("GET")
+	api.HandleFunc("/companies/{id}", companyHandler.Update).Methods("PUT")
+	api.HandleFunc("/companies/{id}", companyHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/companies/{id}/aliases", companyHandler.AddAlias).Methods("POST")
+	api.HandleFunc("/companies/{id}/aliases/{aliasId}", companyHandler.RemoveAlias).Methods("DELETE")
+	api.HandleFunc("/companies/{id}/merge", companyHandler.Merge).Methods("POST")
+
+	// offer 条款、谈判记录与加权对比
+	api.HandleFunc("/offers", offerHandler.List).Methods("GET")
+	api.HandleFunc("/offers", offerHandler.Create).Methods("POST")
+	api.HandleFunc("/offers/compare", offerHandler.Compare).Methods("POST")
+	api.HandleFunc("/offers/compare/export", offerHandler.ExportComparison).Methods("POST")
+	api.HandleFunc("/offers/{id}", offerHandler.Get).Methods("GET")
+	api.HandleFunc("/offers/{id}", offerHandler.Update).Methods("PUT")
+	api.HandleFunc("/offers/{id}", offerHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/offers/{id}/negotiations", offerHandler.AddNegotiation).Methods("POST")
+	api.HandleFunc("/job-applications/{id}/decision-deadline", offerHandler.SetDecisionDeadline).Methods("PUT")
+	api.HandleFunc("/job-applications/{id}/offer-decision", offerHandler.RecordDecision).Methods("POST")
+
+	// 联系人、沟通记录与跟进提醒
+	api.HandleFunc("/contacts", contactHandler.List).Methods("GET")
+	api.HandleFunc("/contacts", contactHandler.Create).Methods("POST")
+	api.HandleFunc("/contacts/follow-ups", contactHandler.FollowUps).Methods("GET")
+	api.HandleFunc("/contacts/{id}", contactHandler.Get).Methods("GET")
+	api.HandleFunc("/contacts/{id}", contactHandler.Update).Methods("PUT")
+	api.HandleFunc("/contacts/{id}", contactHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/contacts/{id}/applications", contactHandler.SetApplications).Methods("PUT")
+	api.HandleFunc("/contacts/{id}/interactions", contactHandler.AddInteraction).Methods("POST")
+	api.HandleFunc("/contacts/{id}/interactions/{interactionId}", contactHandler.DeleteInteraction).Methods("DELETE")
+	api.HandleFunc("/job-applications/{id}/contacts", contactHandler.GetApplicationContacts).Methods("GET")
+
+	// 批量操作撤销
+	api.HandleFunc("/operations/{id}/undo", operationHandler.Undo).Methods("POST")
 	api.HandleFunc("/job-applications/status/batch", statusTrackingHandler.BatchUpdateStatus).Methods("PUT")
 	api.HandleFunc("/job-applications/status-analytics", statusTrackingHandler.GetStatusAnalytics).Methods("GET")
 	api.HandleFunc("/job-applications/status-trends", statusTrackingHandler.GetStatusTrends).Methods("GET")
 	api.HandleFunc("/job-applications/process-insights", statusTrackingHandler.GetProcessInsights).Methods("GET")
+	api.HandleFunc("/job-applications/status-consistency", statusConsistencyHandler.CheckConsistency).Methods("GET")
+	api.HandleFunc("/job-applications/status-consistency/repair", statusConsistencyHandler.RepairConsistency).Methods("POST")
+
+	// 历史分析路由
+	api.HandleFunc("/analytics/funnel", analyticsHandler.GetFunnelAnalytics).Methods("GET")
+	api.HandleFunc("/analytics/survival", analyticsHandler.GetSurvivalAnalytics).Methods("GET")
+	api.HandleFunc("/analytics/sankey", analyticsHandler.GetSankeyFlow).Methods("GET")
+	api.HandleFunc("/analytics/compensation", analyticsHandler.GetCompensationAnalytics).Methods("GET")
+	api.HandleFunc("/analytics/success-probability/refresh", analyticsHandler.RefreshSuccessProbabilities).Methods("POST")
 
 	// 状态配置管理路由
 	api.HandleFunc("/status-flow-templates", statusConfigHandler.GetStatusFlowTemplates).Methods("GET")
@@ -0,0 +1,52 @@
+// 状态历史一致性检查/修复命令行工具
+//
+// 用法:
+//
+//	go run ./cmd/reconcile                 # 全量 dry-run，仅输出报告
+//	go run ./cmd/reconcile -user 3         # 只检查指定用户
+//	go run ./cmd/reconcile -apply          # 从 job_status_history 重建 JSONB 投影
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"jobView-backend/internal/config"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"log"
+	"os"
+)
+
+func main() {
+	userID := flag.Uint("user", 0, "只检查指定用户ID（0 表示全部用户）")
+	apply := flag.Bool("apply", false, "执行修复（默认仅 dry-run）")
+	limit := flag.Int("limit", 0, "最多检查的记录数（0 表示不限制）")
+	flag.Parse()
+
+	cfg := config.Load()
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	req := &model.StatusReconcileRequest{DryRun: !*apply, Limit: *limit}
+	if *userID > 0 {
+		uid := *userID
+		req.UserID = &uid
+	}
+
+	result, err := service.NewStatusConsistencyService(db).Reconcile(req)
+	if err != nil {
+		log.Fatalf("Reconcile failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+	log.Printf("checked=%d drifted=%d repaired=%d dry_run=%v",
+		result.Checked, result.Drifted, result.Repaired, result.DryRun)
+}
@@ -13,6 +13,7 @@ type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
 	JWT      JWTConfig
+	Jobs     JobsConfig
 }
 
 type DatabaseConfig struct {
@@ -38,6 +39,14 @@ type JWTConfig struct {
 	RefreshTokenDuration string
 }
 
+// JobsConfig 后台维护任务配置
+type JobsConfig struct {
+	StatusReconcileInterval string // 状态历史一致性检查间隔，空表示不启用
+	StatusReconcileAutoFix  bool   // 检查发现问题时是否自动重建投影
+	AuditRetentionDays      int    // 审计日志保留天数，0 表示永久保留
+	TrashRetentionDays      int    // 回收站保留天数，到期后彻底删除，0 表示不自动清理
+}
+
 func Load() *Config {
 	// 尝试加载 .env 文件
 	if err := godotenv.Load(); err != nil {
@@ -69,6 +78,12 @@ func Load() *Config {
 			AccessTokenDuration:  getEnv("JWT_ACCESS_DURATION", "24h"),
 			RefreshTokenDuration: getEnv("JWT_REFRESH_DURATION", "720h"), // 30天
 		},
+		Jobs: JobsConfig{
+			StatusReconcileInterval: getEnv("STATUS_RECONCILE_INTERVAL", ""),
+			StatusReconcileAutoFix:  getEnvAsBool("STATUS_RECONCILE_AUTOFIX", false),
+			AuditRetentionDays:      getEnvAsInt("AUDIT_RETENTION_DAYS", 365),
+			TrashRetentionDays:      getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+		},
 	}
 }
 
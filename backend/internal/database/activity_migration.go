@@ -0,0 +1,82 @@
+package database
+
+import "fmt"
+
+// ensureInterviewActivities 面试安排/改期/取消在 interview_time 变化时由触发器写入 application_activities，
+// 时间线不再从申请当前的 interview_time 推导（改期后之前的安排会丢失）。
+// 已有面试时间但还没有任何面试记录的申请补录一条，发生时间取申请最后更新时间
+func (db *DB) ensureInterviewActivities() error {
+	triggerFn := `
+CREATE OR REPLACE FUNCTION trigger_application_interview_activity()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_previous TIMESTAMP WITH TIME ZONE;
+    v_summary TEXT;
+BEGIN
+    IF NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.interview_time IS NOT DISTINCT FROM OLD.interview_time THEN
+            RETURN NEW;
+        END IF;
+        v_previous := OLD.interview_time;
+    END IF;
+
+    IF NEW.interview_time IS NULL THEN
+        IF v_previous IS NULL THEN
+            RETURN NEW;
+        END IF;
+        v_summary := '面试取消';
+    ELSIF v_previous IS NOT NULL THEN
+        v_summary := '面试改期';
+    ELSE
+        v_summary := '面试安排';
+    END IF;
+    IF COALESCE(NEW.interview_type, '') <> '' THEN
+        v_summary := v_summary || '：' || NEW.interview_type;
+    END IF;
+
+    INSERT INTO application_activities (job_application_id, user_id, activity_type, summary, payload)
+    VALUES (NEW.id, NEW.user_id, 'interview', v_summary, jsonb_strip_nulls(jsonb_build_object(
+        'interview_time', NEW.interview_time,
+        'previous_interview_time', v_previous,
+        'interview_type', NULLIF(NEW.interview_type, ''),
+        'interview_location', NULLIF(NEW.interview_location, ''))));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;`
+	if _, err := db.Exec(triggerFn); err != nil {
+		return fmt.Errorf("failed to create interview activity function: %w", err)
+	}
+
+	createTrigger := `
+		DROP TRIGGER IF EXISTS trg_application_interview_activity ON job_applications;
+		CREATE TRIGGER trg_application_interview_activity
+			AFTER INSERT OR UPDATE OF interview_time ON job_applications
+			FOR EACH ROW EXECUTE FUNCTION trigger_application_interview_activity();
+	`
+	if _, err := db.Exec(createTrigger); err != nil {
+		return fmt.Errorf("failed to create interview activity trigger: %w", err)
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO application_activities (job_application_id, user_id, activity_type, summary, payload, occurred_at)
+		SELECT ja.id, ja.user_id, 'interview',
+		       '面试安排' || CASE WHEN COALESCE(ja.interview_type, '') <> '' THEN '：' || ja.interview_type ELSE '' END,
+		       jsonb_strip_nulls(jsonb_build_object(
+		           'interview_time', ja.interview_time,
+		           'interview_type', NULLIF(ja.interview_type, ''),
+		           'interview_location', NULLIF(ja.interview_location, ''))),
+		       COALESCE(ja.updated_at, ja.created_at)
+		FROM job_applications ja
+		WHERE ja.interview_time IS NOT NULL AND ja.deleted_at IS NULL
+		  AND NOT EXISTS (
+		      SELECT 1 FROM application_activities aa
+		      WHERE aa.job_application_id = ja.id AND aa.activity_type = 'interview'
+		  )
+	`); err != nil {
+		return fmt.Errorf("failed to backfill interview activities: %w", err)
+	}
+	return nil
+}
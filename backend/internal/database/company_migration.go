@@ -0,0 +1,270 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"jobView-backend/internal/model"
+
+	"github.com/lib/pq"
+)
+
+// companyNameKeyFunction 数据库侧的别名键函数，须与 model.CompanyNameKey 保持一致
+const companyNameKeyFunction = `
+CREATE OR REPLACE FUNCTION company_name_key(p_name TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    v_lowered TEXT := lower(btrim(COALESCE(p_name, ''), ' '));
+    v_key TEXT;
+    v_stripped TEXT;
+BEGIN
+    v_key := regexp_replace(v_lowered, '[\s\-_.,·•()（）【】\[\]&/''"“”‘’]+', '', 'g');
+    v_stripped := regexp_replace(v_key, '(股份有限公司|有限责任公司|有限公司|集团|公司|corporation|limited|ltd|inc)$', '');
+    IF v_stripped <> '' THEN
+        v_key := v_stripped;
+    END IF;
+    IF v_key = '' THEN
+        v_key := v_lowered;
+    END IF;
+    RETURN v_key;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;`
+
+// resolveCompanyFunction 写入申请时按公司名称解析 company_id：
+// 先按别名键查找，再按常见公司分组查找同组别名，都未命中时自动创建公司
+const resolveCompanyFunction = `
+CREATE OR REPLACE FUNCTION resolve_application_company()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_name TEXT;
+    v_key TEXT;
+    v_company_id INTEGER;
+BEGIN
+    -- 显式改变 company_id（如合并公司、拆分别名）时保留指定值
+    IF TG_OP = 'UPDATE' AND NEW.company_id IS NOT NULL AND NEW.company_id IS DISTINCT FROM OLD.company_id THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.company_id IS NOT NULL AND NEW.company_name IS NOT DISTINCT FROM OLD.company_name THEN
+        RETURN NEW;
+    END IF;
+
+    v_name := btrim(COALESCE(NEW.company_name, ''));
+    IF v_name = '' THEN
+        NEW.company_id := NULL;
+        RETURN NEW;
+    END IF;
+    v_key := company_name_key(v_name);
+
+    SELECT company_id INTO v_company_id
+    FROM company_aliases
+    WHERE user_id = NEW.user_id AND alias_key = v_key;
+
+    IF v_company_id IS NULL THEN
+        SELECT a.company_id INTO v_company_id
+        FROM company_alias_seeds s
+        JOIN company_alias_seeds g ON g.group_name = s.group_name
+        JOIN company_aliases a ON a.alias_key = g.alias_key AND a.user_id = NEW.user_id
+        WHERE s.alias_key = v_key
+        ORDER BY a.company_id
+        LIMIT 1;
+    END IF;
+
+    IF v_company_id IS NULL THEN
+        INSERT INTO companies (user_id, name) VALUES (NEW.user_id, v_name)
+        RETURNING id INTO v_company_id;
+    END IF;
+
+    INSERT INTO company_aliases (company_id, user_id, alias, alias_key)
+    VALUES (v_company_id, NEW.user_id, v_name, v_key)
+    ON CONFLICT (user_id, alias_key) DO NOTHING;
+
+    NEW.company_id := v_company_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;`
+
+// ensureCompanies 创建公司、别名与常见公司种子表，为 job_applications 添加 company_id，
+// 安装自动关联触发器，并将尚未关联的历史申请按名称聚类后回填
+func (db *DB) ensureCompanies() error {
+	createTablesSQL := `
+		CREATE TABLE IF NOT EXISTS companies (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			industry VARCHAR(100),
+			size VARCHAR(20),
+			website VARCHAR(255),
+			notes TEXT,
+			rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS company_aliases (
+			id SERIAL PRIMARY KEY,
+			company_id INTEGER NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			alias VARCHAR(255) NOT NULL,
+			alias_key VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, alias_key)
+		);
+
+		CREATE TABLE IF NOT EXISTS company_alias_seeds (
+			alias_key VARCHAR(255) PRIMARY KEY,
+			group_name VARCHAR(255) NOT NULL
+		);
+	`
+	if _, err := db.Exec(createTablesSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec("ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL"); err != nil {
+		return err
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_companies_user ON companies(user_id, LOWER(name));",
+		"CREATE INDEX IF NOT EXISTS idx_company_aliases_company ON company_aliases(company_id);",
+		"CREATE INDEX IF NOT EXISTS idx_company_alias_seeds_group ON company_alias_seeds(group_name);",
+		"CREATE INDEX IF NOT EXISTS idx_job_applications_company_id ON job_applications(company_id);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create company index: %v", err)
+		}
+	}
+
+	if err := db.seedCompanyAliases(); err != nil {
+		return fmt.Errorf("failed to seed company aliases: %w", err)
+	}
+	if _, err := db.Exec(companyNameKeyFunction); err != nil {
+		return err
+	}
+	if _, err := db.Exec(resolveCompanyFunction); err != nil {
+		return err
+	}
+	createTrigger := `
+		DROP TRIGGER IF EXISTS trg_job_application_company ON job_applications;
+		CREATE TRIGGER trg_job_application_company
+			BEFORE INSERT OR UPDATE OF company_name, company_id ON job_applications
+			FOR EACH ROW EXECUTE FUNCTION resolve_application_company();
+	`
+	if _, err := db.Exec(createTrigger); err != nil {
+		return err
+	}
+
+	if err := db.clusterUnlinkedCompanies(); err != nil {
+		return fmt.Errorf("failed to cluster company names: %w", err)
+	}
+	linked, err := db.backfillCompanyIDs()
+	if err != nil {
+		return fmt.Errorf("failed to backfill company_id: %w", err)
+	}
+	if linked > 0 {
+		log.Printf("Linked %d job applications to companies", linked)
+	}
+	return nil
+}
+
+// backfillCompanyIDs 由触发器为尚未关联且公司名称非空的行完成关联（名称为空的行无法关联，不再反复更新），
+// 回填保留 updated_at
+func (db *DB) backfillCompanyIDs() (int64, error) {
+	tx, err := db.beginPreservingUpdatedAt()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec(`
+		UPDATE job_applications SET company_id = NULL
+		WHERE company_id IS NULL AND btrim(COALESCE(company_name, '')) <> ''
+	`)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// seedCompanyAliases 写入常见公司别名分组（每次启动覆盖更新）
+func (db *DB) seedCompanyAliases() error {
+	var values []string
+	var args []interface{}
+	for _, group := range model.KnownCompanyAliases {
+		for _, alias := range group {
+			values = append(values, fmt.Sprintf("($%d, $%d)", len(args)+1, len(args)+2))
+			args = append(args, model.CompanyNameKey(alias), group[0])
+		}
+	}
+	query := `INSERT INTO company_alias_seeds (alias_key, group_name) VALUES ` + strings.Join(values, ", ") + `
+		ON CONFLICT (alias_key) DO UPDATE SET group_name = EXCLUDED.group_name`
+	_, err := db.Exec(query, args...)
+	return err
+}
+
+// clusterUnlinkedCompanies 将未关联公司的历史申请按用户聚类（见 model.ClusterCompanyNames），
+// 为每组创建公司（已有同键别名时复用）并登记全部写法为别名
+func (db *DB) clusterUnlinkedCompanies() error {
+	rows, err := db.Query(`
+		SELECT user_id, BTRIM(company_name), COUNT(*)
+		FROM job_applications
+		WHERE company_id IS NULL AND BTRIM(COALESCE(company_name, '')) <> ''
+		GROUP BY user_id, BTRIM(company_name)
+	`)
+	if err != nil {
+		return err
+	}
+	namesByUser := make(map[int]map[string]int)
+	for rows.Next() {
+		var userID, count int
+		var name string
+		if err := rows.Scan(&userID, &name, &count); err != nil {
+			rows.Close()
+			return err
+		}
+		if namesByUser[userID] == nil {
+			namesByUser[userID] = make(map[string]int)
+		}
+		namesByUser[userID][name] = count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for userID, counts := range namesByUser {
+		for _, names := range model.ClusterCompanyNames(counts) {
+			keys := make([]string, len(names))
+			for i, name := range names {
+				keys[i] = model.CompanyNameKey(name)
+			}
+
+			var companyID int
+			err := db.QueryRow(`
+				SELECT company_id FROM company_aliases
+				WHERE user_id = $1 AND alias_key = ANY($2)
+				ORDER BY company_id LIMIT 1
+			`, userID, pq.Array(keys)).Scan(&companyID)
+			if err == sql.ErrNoRows {
+				err = db.QueryRow("INSERT INTO companies (user_id, name) VALUES ($1, $2) RETURNING id",
+					userID, names[0]).Scan(&companyID)
+			}
+			if err != nil {
+				return err
+			}
+			for i, name := range names {
+				if _, err := db.Exec(`
+					INSERT INTO company_aliases (company_id, user_id, alias, alias_key)
+					VALUES ($1, $2, $3, $4)
+					ON CONFLICT (user_id, alias_key) DO NOTHING
+				`, companyID, userID, name, keys[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,391 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"jobView-backend/internal/model"
+)
+
+// hrContactsMigration data_migrations 中 HR 字段迁移的名称
+const hrContactsMigration = "hr_contacts"
+
+// linkHRContactFunction 写入申请的 HR 字段时在同一事务内关联 hr 联系人，归并规则与 migrateHRContacts 一致：
+// 按邮箱、电话、姓名查找已有联系人（补充缺少的联系方式），未找到时创建。
+// 只在 HR 字段有变化时执行；已有的关联不会解除，联系人的修改也不会写回申请的 HR 字段。
+// 联系方式的规范化须与 model.NormalizeContactChannel 保持一致
+const linkHRContactFunction = `
+CREATE OR REPLACE FUNCTION link_application_hr_contact()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_name TEXT := btrim(COALESCE(NEW.hr_name, ''));
+    v_email TEXT := btrim(COALESCE(NEW.hr_email, ''));
+    v_phone TEXT := btrim(COALESCE(NEW.hr_phone, ''));
+    v_normalized TEXT;
+    v_channels JSONB := '[]'::jsonb;
+    v_existing JSONB;
+    v_merged JSONB;
+    v_contact_id INTEGER;
+BEGIN
+    IF NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.hr_name IS NOT DISTINCT FROM OLD.hr_name AND NEW.hr_phone IS NOT DISTINCT FROM OLD.hr_phone
+           AND NEW.hr_email IS NOT DISTINCT FROM OLD.hr_email THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    IF v_name = '' AND v_email = '' AND v_phone = '' THEN
+        RETURN NEW;
+    END IF;
+
+    -- 不合法的邮箱/电话按 other 类型原样保存
+    IF v_email <> '' THEN
+        v_normalized := lower(v_email);
+        IF v_normalized ~ '^[^\s@]+@[^\s@]+\.[^\s@]+$' AND char_length(v_normalized) <= 255 THEN
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'email', 'value', v_normalized));
+        ELSE
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'other', 'value', v_email));
+        END IF;
+    END IF;
+    IF v_phone <> '' THEN
+        v_normalized := regexp_replace(v_phone, '[\s\-().（）]+', '', 'g');
+        IF v_normalized ~ '^\+?[0-9]{3,20}(#[0-9]{1,6})?$' THEN
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'phone', 'value', v_normalized));
+        ELSE
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'other', 'value', v_phone));
+        END IF;
+    END IF;
+    IF v_name = '' THEN
+        v_name := v_channels->0->>'value';
+        IF v_channels->0->>'type' = 'email' THEN
+            v_name := split_part(v_name, '@', 1);
+        END IF;
+    END IF;
+    v_name := left(v_name, 100);
+
+    IF jsonb_array_length(v_channels) > 0 THEN
+        SELECT id, channels INTO v_contact_id, v_existing
+        FROM contacts
+        WHERE user_id = NEW.user_id AND channels @> jsonb_build_array(v_channels->0)
+        ORDER BY id LIMIT 1;
+    END IF;
+    IF v_contact_id IS NULL THEN
+        SELECT id, channels INTO v_contact_id, v_existing
+        FROM contacts
+        WHERE user_id = NEW.user_id AND LOWER(name) = LOWER(v_name)
+          AND (jsonb_array_length(v_channels) = 0 OR channels = '[]'::jsonb)
+        ORDER BY id LIMIT 1;
+    END IF;
+
+    IF v_contact_id IS NULL THEN
+        INSERT INTO contacts (user_id, name, role, company_id, channels, source)
+        VALUES (NEW.user_id, v_name, 'hr', NEW.company_id, v_channels, 'hr_fields')
+        RETURNING id INTO v_contact_id;
+    ELSE
+        SELECT v_existing || COALESCE(jsonb_agg(c), '[]'::jsonb) INTO v_merged
+        FROM jsonb_array_elements(v_channels) c
+        WHERE NOT EXISTS (
+            SELECT 1 FROM jsonb_array_elements(v_existing) e
+            WHERE e->>'type' = c->>'type' AND lower(e->>'value') = lower(c->>'value')
+        );
+        IF v_merged <> v_existing THEN
+            UPDATE contacts SET channels = v_merged, updated_at = NOW() WHERE id = v_contact_id;
+        END IF;
+    END IF;
+
+    INSERT INTO contact_applications (contact_id, job_application_id)
+    VALUES (v_contact_id, NEW.id)
+    ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;`
+
+// ensureContacts 创建联系人、联系人与申请的关联以及沟通记录表，将申请上的 HR 字段一次性迁移为联系人，
+// 并安装之后写入 HR 字段时自动关联联系人的触发器
+func (db *DB) ensureContacts() error {
+	createTablesSQL := `
+		CREATE TABLE IF NOT EXISTS contacts (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			role VARCHAR(20) NOT NULL DEFAULT 'other'
+				CHECK (role IN ('recruiter', 'hr', 'hiring_manager', 'interviewer', 'referrer', 'other')),
+			company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL,
+			channels JSONB NOT NULL DEFAULT '[]',
+			notes TEXT,
+			last_contacted_at TIMESTAMP WITH TIME ZONE,
+			follow_up_at TIMESTAMP WITH TIME ZONE,
+			follow_up_note VARCHAR(255),
+			source VARCHAR(20) NOT NULL DEFAULT 'manual',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS contact_applications (
+			contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+			job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (contact_id, job_application_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS data_migrations (
+			name VARCHAR(100) PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS contact_interactions (
+			id SERIAL PRIMARY KEY,
+			contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			job_application_id INTEGER REFERENCES job_applications(id) ON DELETE SET NULL,
+			interaction_type VARCHAR(20) NOT NULL
+				CHECK (interaction_type IN ('call', 'email', 'message', 'meeting', 'other')),
+			direction VARCHAR(10) NOT NULL DEFAULT 'outbound' CHECK (direction IN ('inbound', 'outbound')),
+			summary TEXT NOT NULL,
+			occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+	if _, err := db.Exec(createTablesSQL); err != nil {
+		return err
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_contacts_user_name ON contacts(user_id, LOWER(name));",
+		"CREATE INDEX IF NOT EXISTS idx_contacts_user_follow_up ON contacts(user_id, follow_up_at) WHERE follow_up_at IS NOT NULL;",
+		"CREATE INDEX IF NOT EXISTS idx_contact_applications_application ON contact_applications(job_application_id);",
+		"CREATE INDEX IF NOT EXISTS idx_contact_interactions_contact ON contact_interactions(contact_id, occurred_at DESC);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create contact index: %v", err)
+		}
+	}
+
+	migrated, err := db.migrateHRContacts()
+	if err != nil {
+		return fmt.Errorf("failed to migrate HR fields to contacts: %w", err)
+	}
+	if migrated > 0 {
+		log.Printf("Linked %d job applications to contacts from HR fields", migrated)
+	}
+
+	if _, err := db.Exec(linkHRContactFunction); err != nil {
+		return err
+	}
+	createTrigger := `
+		DROP TRIGGER IF EXISTS trg_job_application_hr_contact ON job_applications;
+		CREATE TRIGGER trg_job_application_hr_contact
+			AFTER INSERT OR UPDATE OF hr_name, hr_phone, hr_email ON job_applications
+			FOR EACH ROW EXECUTE FUNCTION link_application_hr_contact();
+	`
+	if _, err := db.Exec(createTrigger); err != nil {
+		return err
+	}
+	return nil
+}
+
+// hrContactGroup 按同一 HR 归并的申请
+type hrContactGroup struct {
+	userID     uint
+	name       string
+	channels   model.ContactChannels
+	companyIDs map[int]bool
+	noCompany  bool
+	jobIDs     []int
+}
+
+// migrateHRContacts 将尚未关联联系人且不在回收站中的申请上的 HR 姓名/电话/邮箱迁移为 hr 角色联系人：
+// 按邮箱、电话、姓名（依次取第一个非空值）归并同一个人，已有联系人包含相同联系方式或同名时复用。
+// 只执行一次（记录在 data_migrations），之后由触发器在写入 HR 字段时关联，
+// 用户删除联系人或解除关联后不会在重启时被重新创建。返回关联的申请数
+func (db *DB) migrateHRContacts() (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// 标记与迁移在同一事务内提交；并发启动时后到的实例等待先到者提交后跳过
+	result, err := tx.Exec("INSERT INTO data_migrations (name) VALUES ($1) ON CONFLICT DO NOTHING", hrContactsMigration)
+	if err != nil {
+		return 0, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return 0, err
+	}
+	// 引入标记前的版本每次启动都会迁移，已有迁移出的联系人说明迁移已完成，只补记标记
+	var migrated bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM contacts WHERE source = 'hr_fields')").Scan(&migrated); err != nil {
+		return 0, err
+	}
+	if migrated {
+		return 0, tx.Commit()
+	}
+
+	rows, err := tx.Query(`
+		SELECT ja.id, ja.user_id, ja.company_id,
+		       COALESCE(btrim(ja.hr_name), ''), COALESCE(btrim(ja.hr_phone), ''), COALESCE(btrim(ja.hr_email), '')
+		FROM job_applications ja
+		WHERE (COALESCE(btrim(ja.hr_name), '') <> '' OR COALESCE(btrim(ja.hr_phone), '') <> ''
+		       OR COALESCE(btrim(ja.hr_email), '') <> '')
+		  AND ja.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM contact_applications ca WHERE ca.job_application_id = ja.id)
+		ORDER BY ja.user_id, ja.id
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	groups := make(map[string]*hrContactGroup)
+	var order []string
+	for rows.Next() {
+		var jobID int
+		var userID uint
+		var companyID sql.NullInt64
+		var name, phone, email string
+		if err := rows.Scan(&jobID, &userID, &companyID, &name, &phone, &email); err != nil {
+			return 0, err
+		}
+
+		var channels model.ContactChannels
+		for _, channel := range []model.ContactChannel{{Type: model.ChannelEmail, Value: email}, {Type: model.ChannelPhone, Value: phone}} {
+			if channel.Value == "" {
+				continue
+			}
+			if normalized, err := model.NormalizeContactChannel(channel); err == nil {
+				channel = normalized
+			} else {
+				channel.Type = model.ChannelOther
+			}
+			channels = append(channels, channel)
+		}
+		key := fmt.Sprintf("%d|name:%s", userID, strings.ToLower(name))
+		if len(channels) > 0 {
+			key = fmt.Sprintf("%d|%s", userID, channels[0].Key())
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &hrContactGroup{userID: userID, companyIDs: make(map[int]bool)}
+			groups[key] = group
+			order = append(order, key)
+		}
+		if group.name == "" {
+			group.name = name
+		}
+		group.channels = mergeContactChannels(group.channels, channels)
+		if companyID.Valid {
+			group.companyIDs[int(companyID.Int64)] = true
+		} else {
+			group.noCompany = true
+		}
+		group.jobIDs = append(group.jobIDs, jobID)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	linked := 0
+	for _, key := range order {
+		group := groups[key]
+		if group.name == "" {
+			group.name = group.channels[0].Value
+			if group.channels[0].Type == model.ChannelEmail {
+				group.name = strings.SplitN(group.name, "@", 2)[0]
+			}
+		}
+		if len([]rune(group.name)) > 100 {
+			group.name = string([]rune(group.name)[:100])
+		}
+
+		contactID, channels, err := findHRContact(tx, group)
+		if err != nil {
+			return 0, err
+		}
+		if contactID == 0 {
+			var companyID interface{}
+			if len(group.companyIDs) == 1 && !group.noCompany {
+				for id := range group.companyIDs {
+					companyID = id
+				}
+			}
+			if err := tx.QueryRow(`
+				INSERT INTO contacts (user_id, name, role, company_id, channels, source)
+				VALUES ($1, $2, $3, $4, $5::jsonb, 'hr_fields')
+				RETURNING id
+			`, group.userID, group.name, string(model.ContactHR), companyID, group.channels).Scan(&contactID); err != nil {
+				return 0, err
+			}
+		} else if merged := mergeContactChannels(channels, group.channels); len(merged) > len(channels) {
+			if _, err := tx.Exec("UPDATE contacts SET channels = $1::jsonb, updated_at = NOW() WHERE id = $2",
+				merged, contactID); err != nil {
+				return 0, err
+			}
+		}
+
+		for _, jobID := range group.jobIDs {
+			result, err := tx.Exec(`
+				INSERT INTO contact_applications (contact_id, job_application_id) VALUES ($1, $2)
+				ON CONFLICT DO NOTHING
+			`, contactID, jobID)
+			if err != nil {
+				return 0, err
+			}
+			if affected, _ := result.RowsAffected(); affected > 0 {
+				linked++
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return linked, nil
+}
+
+// findHRContact 查找可复用的已有联系人：包含相同联系方式，或姓名相同且双方至少一方没有联系方式
+func findHRContact(tx *sql.Tx, group *hrContactGroup) (int, model.ContactChannels, error) {
+	var id int
+	var channels model.ContactChannels
+	if len(group.channels) > 0 {
+		err := tx.QueryRow(`
+			SELECT id, channels FROM contacts
+			WHERE user_id = $1 AND channels @> $2::jsonb
+			ORDER BY id LIMIT 1
+		`, group.userID, model.ContactChannels{group.channels[0]}).Scan(&id, &channels)
+		if err != sql.ErrNoRows {
+			return id, channels, err
+		}
+	}
+	err := tx.QueryRow(`
+		SELECT id, channels FROM contacts
+		WHERE user_id = $1 AND LOWER(name) = LOWER($2) AND ($3 OR channels = '[]'::jsonb)
+		ORDER BY id LIMIT 1
+	`, group.userID, group.name, len(group.channels) == 0).Scan(&id, &channels)
+	if err == sql.ErrNoRows {
+		return 0, nil, nil
+	}
+	return id, channels, err
+}
+
+// mergeContactChannels 追加 base 中尚未包含的联系方式
+func mergeContactChannels(base, extra model.ContactChannels) model.ContactChannels {
+	seen := make(map[string]bool, len(base))
+	for _, channel := range base {
+		seen[channel.Key()] = true
+	}
+	for _, channel := range extra {
+		if !seen[channel.Key()] {
+			seen[channel.Key()] = true
+			base = append(base, channel)
+		}
+	}
+	return base
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+    "database/sql"
     "fmt"
     "log"
 )
@@ -154,11 +155,91 @@ func (db *DB) RunMigrations() error {
 		return fmt.Errorf("failed to create resume tables: %w", err)
 	}
 
+	// 创建申请活动表（统一时间线）
+	if err := db.createApplicationActivitiesTable(); err != nil {
+		return fmt.Errorf("failed to create application_activities table: %w", err)
+	}
+
     // 确保状态流转校验函数存在且支持应用层放行回退（基于GUC）
     if err := db.ensureStatusTransitionFunctions(); err != nil {
         log.Printf("Warning: failed to ensure status transition functions: %v", err)
     }
 
+	// 软删除（回收站）
+	if err := db.ensureSoftDelete(); err != nil {
+		return fmt.Errorf("failed to ensure soft delete column: %w", err)
+	}
+
+	// 批量操作记录（撤销）
+	if err := db.createBatchOperationsTable(); err != nil {
+		return fmt.Errorf("failed to create batch_operations table: %w", err)
+	}
+
+	// 标签及申请-标签关联
+	if err := db.createTagTables(); err != nil {
+		return fmt.Errorf("failed to create tag tables: %w", err)
+	}
+
+	// 用户自定义字段
+	if err := db.createCustomFieldTables(); err != nil {
+		return fmt.Errorf("failed to create custom field tables: %w", err)
+	}
+
+	// 保存的视图
+	if err := db.createSavedViewsTable(); err != nil {
+		return fmt.Errorf("failed to create saved_views table: %w", err)
+	}
+
+	// 全文搜索三元组索引（pg_trgm 不可用时搜索退化为子串匹配）
+	if err := db.ensureSearchIndexes(); err != nil {
+		log.Printf("Warning: failed to ensure search indexes: %v", err)
+	}
+
+	// 字段级审计日志（触发器记录 create/update/delete 的前后值）
+	if err := db.ensureAuditLog(); err != nil {
+		log.Printf("Warning: failed to ensure job application audit log: %v", err)
+	}
+
+	// updated_at 触发器支持投影字段写入时保留原值
+	if err := db.ensureUpdatedAtGuard(); err != nil {
+		return fmt.Errorf("failed to ensure updated_at guard: %w", err)
+	}
+
+	// 公司实体与别名自动关联（回填在审计触发器更新之后执行，company_id 不记审计）
+	if err := db.ensureCompanies(); err != nil {
+		return fmt.Errorf("failed to ensure companies: %w", err)
+	}
+
+	// 职位名称归一化（岗位类别、职级、技术栈），规则变更后自动重新回填
+	if err := db.ensurePositionTaxonomy(); err != nil {
+		return fmt.Errorf("failed to ensure position taxonomy: %w", err)
+	}
+
+	// 薪资结构化（月薪区间、薪数、年薪、币种），解析规则升级后自动重新回填
+	if err := db.ensureStructuredSalary(); err != nil {
+		return fmt.Errorf("failed to ensure structured salary: %w", err)
+	}
+
+	// offer 条款与谈判记录
+	if err := db.createOfferTables(); err != nil {
+		return fmt.Errorf("failed to create offer tables: %w", err)
+	}
+
+	// offer 答复截止日期（由 offers.deadline 迁移到申请上）
+	if err := db.ensureDecisionDeadline(); err != nil {
+		return fmt.Errorf("failed to ensure decision deadline: %w", err)
+	}
+
+	// 联系人、沟通记录与跟进提醒（申请上的 HR 字段迁移为联系人）
+	if err := db.ensureContacts(); err != nil {
+		return fmt.Errorf("failed to ensure contacts: %w", err)
+	}
+
+	// 面试安排/改期/取消记入申请时间线
+	if err := db.ensureInterviewActivities(); err != nil {
+		return fmt.Errorf("failed to ensure interview activities: %w", err)
+	}
+
     log.Println("Database migrations completed successfully")
     return nil
 }
@@ -577,6 +658,308 @@ func (db *DB) createExportTasksTable() error {
 	return nil
 }
 
+// createApplicationActivitiesTable 创建申请活动表
+func (db *DB) createApplicationActivitiesTable() error {
+	createTableSQL := `
+		CREATE TABLE IF NOT EXISTS application_activities (
+			id BIGSERIAL PRIMARY KEY,
+			job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			activity_type VARCHAR(30) NOT NULL,
+			summary TEXT NOT NULL DEFAULT '',
+			payload JSONB NOT NULL DEFAULT '{}',
+			occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			CONSTRAINT chk_activity_payload_is_object CHECK (jsonb_typeof(payload) = 'object')
+		);
+	`
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_application_activities_job_time ON application_activities(job_application_id, occurred_at DESC, id DESC);",
+		"CREATE INDEX IF NOT EXISTS idx_application_activities_user_type ON application_activities(user_id, activity_type);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create application_activities index: %v", err)
+		}
+	}
+	return nil
+}
+
+// createBatchOperationsTable 创建批量操作记录表（用于撤销）
+func (db *DB) createBatchOperationsTable() error {
+	createTableSQL := `
+		CREATE TABLE IF NOT EXISTS batch_operations (
+			id BIGSERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			operation_type VARCHAR(30) NOT NULL,
+			affected_count INTEGER NOT NULL DEFAULT 0,
+			snapshot JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			undone_at TIMESTAMP WITH TIME ZONE
+		);
+	`
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_batch_operations_user_time ON batch_operations(user_id, created_at DESC);"); err != nil {
+		log.Printf("Warning: Failed to create batch_operations index: %v", err)
+	}
+	return nil
+}
+
+// createTagTables 创建用户标签表和申请-标签多对多关联表
+func (db *DB) createTagTables() error {
+	createTablesSQL := `
+		CREATE TABLE IF NOT EXISTS tags (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(50) NOT NULL,
+			color VARCHAR(7) NOT NULL DEFAULT '#6366f1',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS job_application_tags (
+			job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+			tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (job_application_id, tag_id)
+		);
+	`
+	if _, err := db.Exec(createTablesSQL); err != nil {
+		return err
+	}
+
+	indexes := []string{
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, LOWER(name));",
+		"CREATE INDEX IF NOT EXISTS idx_job_application_tags_tag ON job_application_tags(tag_id);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create tag index: %v", err)
+		}
+	}
+	return nil
+}
+
+// createCustomFieldTables 创建自定义字段定义表，并为 job_applications 添加 custom_fields 列
+func (db *DB) createCustomFieldTables() error {
+	createTableSQL := `
+		CREATE TABLE IF NOT EXISTS custom_field_definitions (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			field_key VARCHAR(50) NOT NULL,
+			label VARCHAR(100) NOT NULL,
+			field_type VARCHAR(10) NOT NULL CHECK (field_type IN ('text', 'number', 'date', 'enum', 'url')),
+			options JSONB NOT NULL DEFAULT '[]',
+			required BOOLEAN NOT NULL DEFAULT FALSE,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, field_key)
+		);
+	`
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec("ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}'"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_job_applications_custom_fields ON job_applications USING GIN (custom_fields);"); err != nil {
+		log.Printf("Warning: Failed to create custom_fields index: %v", err)
+	}
+	return nil
+}
+
+// createSavedViewsTable 创建保存的视图表（列表/搜索的筛选条件与排序）
+func (db *DB) createSavedViewsTable() error {
+	createTableSQL := `
+		CREATE TABLE IF NOT EXISTS saved_views (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			filters JSONB NOT NULL DEFAULT '{}',
+			pinned BOOLEAN NOT NULL DEFAULT FALSE,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_views_user_name ON saved_views(user_id, LOWER(name));"); err != nil {
+		log.Printf("Warning: Failed to create saved_views index: %v", err)
+	}
+	return nil
+}
+
+// ApplicationSearchDocument 搜索文档表达式：公司、职位、地点、HR、备注与 JD 拼接。
+// 搜索查询须使用完全相同的表达式才能命中三元组索引
+const ApplicationSearchDocument = `(COALESCE(company_name, '') || ' ' || COALESCE(position_title, '') || ' ' || COALESCE(work_location, '') || ' ' || COALESCE(hr_name, '') || ' ' || COALESCE(notes, '') || ' ' || COALESCE(job_description, ''))`
+
+// ensureSearchIndexes 启用 pg_trgm 并为搜索文档创建三元组 GIN 索引，支持中文子串与模糊匹配
+func (db *DB) ensureSearchIndexes() error {
+	if _, err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm"); err != nil {
+		return fmt.Errorf("pg_trgm extension unavailable: %w", err)
+	}
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_job_applications_search_trgm ON job_applications USING GIN (" + ApplicationSearchDocument + " gin_trgm_ops);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create search index: %v", err)
+		}
+	}
+	return nil
+}
+
+// ensureSoftDelete 为 job_applications 添加 deleted_at 列，非空表示已移入回收站
+func (db *DB) ensureSoftDelete() error {
+	if _, err := db.Exec("ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_job_applications_deleted ON job_applications(user_id, deleted_at) WHERE deleted_at IS NOT NULL"); err != nil {
+		log.Printf("Warning: Failed to create soft delete index: %v", err)
+	}
+	return nil
+}
+
+// ensureAuditLog 创建审计日志表与触发器。
+// 会话变量 jobview.audit_batch = 'on' 时动作记为 batch_*，同一事务内的变更共享 transaction_id。
+// 依赖 deleted_at 列，需在 ensureSoftDelete 之后执行。
+func (db *DB) ensureAuditLog() error {
+	createTableSQL := `
+		CREATE TABLE IF NOT EXISTS job_application_audit_logs (
+			id BIGSERIAL PRIMARY KEY,
+			job_application_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			action VARCHAR(20) NOT NULL,
+			changes JSONB NOT NULL DEFAULT '{}',
+			transaction_id BIGINT NOT NULL DEFAULT txid_current(),
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_audit_logs_job_time ON job_application_audit_logs(job_application_id, created_at DESC, id DESC);",
+		"CREATE INDEX IF NOT EXISTS idx_audit_logs_user_time ON job_application_audit_logs(user_id, created_at DESC);",
+		"CREATE INDEX IF NOT EXISTS idx_audit_logs_changes_gin ON job_application_audit_logs USING GIN (changes);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create audit log index: %v", err)
+		}
+	}
+
+	triggerFn := `
+CREATE OR REPLACE FUNCTION trigger_job_application_audit()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_action TEXT;
+    v_changes JSONB;
+    v_ignored TEXT[] := ARRAY['id', 'user_id', 'created_at', 'updated_at', 'status_history',
+                              'status_duration_stats', 'last_status_change', 'status_version', 'company_id',
+                              'role_family', 'role_level', 'tech_stack', 'role_taxonomy_version',
+                              'salary_min_monthly', 'salary_max_monthly', 'salary_months', 'salary_annual_min',
+                              'salary_annual_max', 'salary_currency', 'salary_parser_version'];
+BEGIN
+    v_action := lower(TG_OP);
+    -- 软删除/恢复通过 deleted_at 表达；物理删除记为 purge
+    IF TG_OP = 'UPDATE' AND OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        v_action := 'delete';
+    ELSIF TG_OP = 'UPDATE' AND OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        v_action := 'restore';
+    ELSIF TG_OP = 'DELETE' THEN
+        v_action := 'purge';
+    END IF;
+    IF COALESCE(current_setting('jobview.audit_batch', true), '') = 'on' THEN
+        v_action := 'batch_' || v_action;
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        SELECT jsonb_object_agg(key, jsonb_build_object('before', NULL, 'after', value)) INTO v_changes
+        FROM jsonb_each(to_jsonb(NEW))
+        WHERE key <> ALL(v_ignored) AND value <> 'null'::jsonb;
+    ELSIF TG_OP = 'UPDATE' THEN
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('before', o.value, 'after', n.value)) INTO v_changes
+        FROM jsonb_each(to_jsonb(NEW)) n
+        JOIN jsonb_each(to_jsonb(OLD)) o ON o.key = n.key
+        WHERE n.key <> ALL(v_ignored) AND n.value IS DISTINCT FROM o.value;
+        -- 仅投影字段变化（如成功率、状态摘要）不记录
+        IF v_changes IS NULL THEN
+            RETURN NEW;
+        END IF;
+    ELSE
+        SELECT jsonb_object_agg(key, jsonb_build_object('before', value, 'after', NULL)) INTO v_changes
+        FROM jsonb_each(to_jsonb(OLD))
+        WHERE key <> ALL(v_ignored) AND value <> 'null'::jsonb;
+        INSERT INTO job_application_audit_logs (job_application_id, user_id, action, changes)
+        VALUES (OLD.id, OLD.user_id, v_action, COALESCE(v_changes, '{}'::jsonb));
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO job_application_audit_logs (job_application_id, user_id, action, changes)
+    VALUES (NEW.id, NEW.user_id, v_action, COALESCE(v_changes, '{}'::jsonb));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;`
+	if _, err := db.Exec(triggerFn); err != nil {
+		return err
+	}
+
+	createTrigger := `
+		DROP TRIGGER IF EXISTS trg_job_application_audit ON job_applications;
+		CREATE TRIGGER trg_job_application_audit
+			AFTER INSERT OR UPDATE OR DELETE ON job_applications
+			FOR EACH ROW EXECUTE FUNCTION trigger_job_application_audit();
+	`
+	if _, err := db.Exec(createTrigger); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ensureUpdatedAtGuard 覆盖 updated_at 触发器函数：设置 jobview.preserve_updated_at='on' 时保留原值，
+// 供成功率等投影字段的后台写入使用，避免影响撤销判断与按更新时间排序
+func (db *DB) ensureUpdatedAtGuard() error {
+	triggerFn := `
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(current_setting('jobview.preserve_updated_at', true), '') = 'on' THEN
+        NEW.updated_at = OLD.updated_at;
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;`
+	_, err := db.Exec(triggerFn)
+	return err
+}
+
+// beginPreservingUpdatedAt 开启保留 updated_at 的事务，用于回填派生列：
+// 回填不是用户修改，不应影响撤销判断与按更新时间排序
+func (db *DB) beginPreservingUpdatedAt() (*sql.Tx, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("SET LOCAL jobview.preserve_updated_at = 'on'"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
+
 // createResumeTables 创建简历相关表
 func (db *DB) createResumeTables() error {
     // resumes
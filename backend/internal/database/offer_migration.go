@@ -0,0 +1,97 @@
+package database
+
+import "log"
+
+// createOfferTables 创建 offer 表与谈判记录表（每个申请至多一个 offer）
+func (db *DB) createOfferTables() error {
+	createTablesSQL := `
+		CREATE TABLE IF NOT EXISTS offers (
+			id SERIAL PRIMARY KEY,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+			currency VARCHAR(3) NOT NULL DEFAULT 'CNY',
+			base_monthly NUMERIC(12, 2) NOT NULL,
+			salary_months NUMERIC(4, 1) NOT NULL DEFAULT 12,
+			annual_bonus NUMERIC(14, 2),
+			sign_on_bonus NUMERIC(14, 2),
+			stock_value NUMERIC(14, 2),
+			vesting_years SMALLINT,
+			vesting_schedule VARCHAR(255),
+			benefits JSONB NOT NULL DEFAULT '[]',
+			location VARCHAR(255),
+			start_date DATE,
+			decision_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+				CHECK (decision_status IN ('pending', 'negotiating', 'accepted', 'declined', 'expired')),
+			ratings JSONB NOT NULL DEFAULT '{}',
+			notes TEXT,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (job_application_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS offer_negotiations (
+			id SERIAL PRIMARY KEY,
+			offer_id INTEGER NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			note TEXT,
+			changes JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+	if _, err := db.Exec(createTablesSQL); err != nil {
+		return err
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_offers_user_status ON offers(user_id, decision_status);",
+		"CREATE INDEX IF NOT EXISTS idx_offer_negotiations_offer ON offer_negotiations(offer_id, created_at);",
+	}
+	for _, indexSQL := range indexes {
+		if _, err := db.Exec(indexSQL); err != nil {
+			log.Printf("Warning: Failed to create offer index: %v", err)
+		}
+	}
+	return nil
+}
+
+// ensureDecisionDeadline 为 job_applications 添加 offer 答复截止日期。截止日期原先存于 offers.deadline，
+// 迁移时并入申请（申请上已有值时保留）后删除该列，答复截止日期只保存在申请上
+func (db *DB) ensureDecisionDeadline() error {
+	if _, err := db.Exec("ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS decision_deadline DATE"); err != nil {
+		return err
+	}
+
+	var legacy bool
+	if err := db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'offers' AND column_name = 'deadline'
+		)
+	`).Scan(&legacy); err != nil {
+		return err
+	}
+	if legacy {
+		result, err := db.Exec(`
+			UPDATE job_applications ja
+			SET decision_deadline = o.deadline
+			FROM offers o
+			WHERE o.job_application_id = ja.id AND o.deadline IS NOT NULL AND ja.decision_deadline IS NULL
+		`)
+		if err != nil {
+			return err
+		}
+		if moved, _ := result.RowsAffected(); moved > 0 {
+			log.Printf("Moved %d offer deadlines to job_applications.decision_deadline", moved)
+		}
+		if _, err := db.Exec("ALTER TABLE offers DROP COLUMN deadline"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_job_applications_decision_deadline
+		ON job_applications(decision_deadline)
+		WHERE decision_deadline IS NOT NULL AND deleted_at IS NULL`); err != nil {
+		log.Printf("Warning: Failed to create decision deadline index: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+)
+
+// positionTaxonomyBatchSize 回填时每批处理的记录数
+const positionTaxonomyBatchSize = 500
+
+// ensurePositionTaxonomy 为 job_applications 添加岗位类别、职级与技术栈列，
+// 并将规则版本与当前规则不一致的记录（含历史数据）重新归一化
+func (db *DB) ensurePositionTaxonomy() error {
+	columns := []string{
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS role_family VARCHAR(30)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS role_level VARCHAR(20)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS tech_stack JSONB NOT NULL DEFAULT '[]'::jsonb",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS role_taxonomy_version VARCHAR(16)",
+	}
+	for _, columnSQL := range columns {
+		if _, err := db.Exec(columnSQL); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_job_applications_role ON job_applications(user_id, role_family, role_level)"); err != nil {
+		log.Printf("Warning: Failed to create position taxonomy index: %v", err)
+	}
+
+	updated, err := db.backfillPositionTaxonomy(taxonomy.Active())
+	if err != nil {
+		return fmt.Errorf("failed to backfill position taxonomy: %w", err)
+	}
+	if updated > 0 {
+		log.Printf("Normalized position titles of %d job applications", updated)
+	}
+	return nil
+}
+
+// backfillPositionTaxonomy 分批重新归一化 role_taxonomy_version 与当前规则不一致的记录，保留 updated_at
+func (db *DB) backfillPositionTaxonomy(rules *taxonomy.Taxonomy) (int, error) {
+	version := rules.Version()
+	total := 0
+	for {
+		rows, err := db.Query(`
+			SELECT id, COALESCE(position_title, '')
+			FROM job_applications
+			WHERE role_taxonomy_version IS DISTINCT FROM $1
+			ORDER BY id
+			LIMIT $2
+		`, version, positionTaxonomyBatchSize)
+		if err != nil {
+			return total, err
+		}
+		titles := make(map[int]string)
+		var ids []int
+		for rows.Next() {
+			var id int
+			var title string
+			if err := rows.Scan(&id, &title); err != nil {
+				rows.Close()
+				return total, err
+			}
+			ids = append(ids, id)
+			titles[id] = title
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		tx, err := db.beginPreservingUpdatedAt()
+		if err != nil {
+			return total, err
+		}
+		for _, id := range ids {
+			result := rules.Normalize(titles[id])
+			if _, err := tx.Exec(`
+				UPDATE job_applications
+				SET role_family = $1, role_level = $2, tech_stack = $3::jsonb, role_taxonomy_version = $4
+				WHERE id = $5
+			`, nullableString(result.RoleFamily), nullableString(result.RoleLevel),
+				model.StringList(result.TechStack), version, id); err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+		total += len(ids)
+	}
+}
+
+// nullableString 空字符串写入为 NULL
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"jobView-backend/internal/salary"
+)
+
+// salaryBackfillBatchSize 回填时每批处理的记录数
+const salaryBackfillBatchSize = 500
+
+// ensureStructuredSalary 为 job_applications 添加结构化薪酬列，
+// 并用当前解析规则重新解析解析版本落后的记录（含历史数据）
+func (db *DB) ensureStructuredSalary() error {
+	columns := []string{
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_min_monthly NUMERIC(12, 2)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_max_monthly NUMERIC(12, 2)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_months NUMERIC(4, 1)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_annual_min NUMERIC(14, 2)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_annual_max NUMERIC(14, 2)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_currency VARCHAR(3)",
+		"ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_parser_version SMALLINT",
+	}
+	for _, columnSQL := range columns {
+		if _, err := db.Exec(columnSQL); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_job_applications_salary
+		ON job_applications(user_id, salary_currency, salary_max_monthly)
+		WHERE salary_max_monthly IS NOT NULL`); err != nil {
+		log.Printf("Warning: Failed to create salary index: %v", err)
+	}
+
+	updated, err := db.backfillStructuredSalary()
+	if err != nil {
+		return fmt.Errorf("failed to backfill structured salary: %w", err)
+	}
+	if updated > 0 {
+		log.Printf("Parsed salary ranges of %d job applications", updated)
+	}
+	return nil
+}
+
+// backfillStructuredSalary 分批重新解析 salary_parser_version 落后的记录，保留 updated_at
+func (db *DB) backfillStructuredSalary() (int, error) {
+	total := 0
+	for {
+		rows, err := db.Query(`
+			SELECT id, COALESCE(salary_range, '')
+			FROM job_applications
+			WHERE salary_parser_version IS DISTINCT FROM $1
+			ORDER BY id
+			LIMIT $2
+		`, salary.ParserVersion, salaryBackfillBatchSize)
+		if err != nil {
+			return total, err
+		}
+		ranges := make(map[int]string)
+		var ids []int
+		for rows.Next() {
+			var id int
+			var raw string
+			if err := rows.Scan(&id, &raw); err != nil {
+				rows.Close()
+				return total, err
+			}
+			ids = append(ids, id)
+			ranges[id] = raw
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		tx, err := db.beginPreservingUpdatedAt()
+		if err != nil {
+			return total, err
+		}
+		for _, id := range ids {
+			var values []interface{}
+			if parsed, ok := salary.Parse(ranges[id]); ok {
+				values = []interface{}{parsed.MinMonthly, parsed.MaxMonthly, parsed.Months, parsed.AnnualMin, parsed.AnnualMax, parsed.Currency}
+			} else {
+				values = []interface{}{nil, nil, nil, nil, nil, nil}
+			}
+			values = append(values, salary.ParserVersion, id)
+			if _, err := tx.Exec(`
+				UPDATE job_applications
+				SET salary_min_monthly = $1, salary_max_monthly = $2, salary_months = $3,
+				    salary_annual_min = $4, salary_annual_max = $5, salary_currency = $6,
+				    salary_parser_version = $7
+				WHERE id = $8
+			`, values...); err != nil {
+				tx.Rollback()
+				return total, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+		total += len(ids)
+	}
+}
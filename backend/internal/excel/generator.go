@@ -3,6 +3,9 @@ package excel
 import (
 	"fmt"
 	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/xuri/excelize/v2"
@@ -13,10 +16,11 @@ import (
 // 功能：负责将求职投递数据生成为 Excel 文件，支持流式处理和样式设置
 // 依赖：依赖 Excelize v2 库和内部数据模型
 type Generator struct {
-	file        *excelize.File
-	sheetName   string
-	currentRow  int
-	styleConfig *StyleConfig
+	file          *excelize.File
+	sheetName     string
+	currentRow    int
+	styleConfig   *StyleConfig
+	customColumns []model.CustomFieldDefinition // 追加在固定列之后的自定义字段列
 }
 
 // StyleConfig 样式配置结构
@@ -36,6 +40,11 @@ func NewGenerator() *Generator {
 	}
 }
 
+// SetCustomColumns 设置需要导出的自定义字段列，需在 InitializeWorkbook 之前调用
+func (g *Generator) SetCustomColumns(columns []model.CustomFieldDefinition) {
+	g.customColumns = columns
+}
+
 // InitializeWorkbook 初始化工作簿，设置样式和表头
 func (g *Generator) InitializeWorkbook() error {
 	// 重命名默认工作表
@@ -172,11 +181,16 @@ func (g *Generator) setHeaders() error {
 	headers := []string{
 		"序号", "公司名称", "职位标题", "投递日期", "当前状态", "薪资范围",
 		"工作地点", "面试时间", "面试地点", "面试类型", "HR姓名", "HR电话",
-		"HR邮箱", "提醒时间", "跟进日期", "备注", "创建时间", "更新时间",
+		"HR邮箱", "提醒时间", "跟进日期", "备注", "标签", "创建时间", "更新时间",
+		"岗位类别", "职级", "技术栈", "月薪下限", "月薪上限", "薪数", "年薪下限", "年薪上限", "币种",
 	}
 
 	// 设置列宽
-	columnWidths := []float64{6, 20, 25, 12, 15, 15, 15, 18, 20, 10, 12, 15, 20, 18, 12, 30, 20, 20}
+	columnWidths := []float64{6, 20, 25, 12, 15, 15, 15, 18, 20, 10, 12, 15, 20, 18, 12, 30, 20, 20, 20, 12, 10, 20, 12, 12, 8, 12, 12, 8}
+	for _, column := range g.customColumns {
+		headers = append(headers, column.Label)
+		columnWidths = append(columnWidths, 18)
+	}
 
 	for i, header := range headers {
 		colName, err := excelize.ColumnNumberToName(i + 1)
@@ -251,8 +265,22 @@ func (g *Generator) writeJobApplication(sequenceNumber int, app *model.JobApplic
 		g.getTimeString(app.ReminderTime),       // 提醒时间
 		g.getString(app.FollowUpDate),           // 跟进日期
 		g.getString(app.Notes),                  // 备注
+		g.getTagNames(app.Tags),                 // 标签
 		app.CreatedAt.Format("2006-01-02 15:04:05"), // 创建时间
 		app.UpdatedAt.Format("2006-01-02 15:04:05"), // 更新时间
+		g.getRoleFamilyName(app.RoleFamily),         // 岗位类别
+		g.getRoleLevelName(app.RoleLevel),           // 职级
+		g.getTechStackNames(app.TechStack),          // 技术栈
+		g.getNumber(app.SalaryMinMonthly),           // 月薪下限
+		g.getNumber(app.SalaryMaxMonthly),           // 月薪上限
+		g.getNumber(app.SalaryMonths),               // 薪数
+		g.getNumber(app.SalaryAnnualMin),            // 年薪下限
+		g.getNumber(app.SalaryAnnualMax),            // 年薪上限
+		g.getString(app.SalaryCurrency),             // 币种
+	}
+	fixedColumns := len(values)
+	for _, column := range g.customColumns {
+		values = append(values, g.getCustomFieldValue(app.CustomFields, &column))
 	}
 
 	// 写入数据并应用样式
@@ -276,11 +304,14 @@ func (g *Generator) writeJobApplication(sequenceNumber int, app *model.JobApplic
 			} else {
 				styleID = g.styleConfig.DataStyle
 			}
-		case 3, 7, 13, 16, 17: // 日期列
+		case 3, 7, 13, 17, 18: // 日期列
 			styleID = g.styleConfig.DateStyle
 		default:
 			styleID = g.styleConfig.DataStyle
 		}
+		if colIndex >= fixedColumns && g.customColumns[colIndex-fixedColumns].FieldType == model.CustomFieldDate {
+			styleID = g.styleConfig.DateStyle
+		}
 
 		if err := g.file.SetCellStyle(g.sheetName, cell, cell, styleID); err != nil {
 			return err
@@ -397,9 +428,77 @@ func (g *Generator) writeStatisticsData(sheetName string, stats map[string]inter
 		}
 	}
 
+	// 岗位类别、职级与月薪分布（按数量降序）
+	sections := []struct {
+		title string
+		key   string
+	}{
+		{"岗位类别统计", "roleDistribution"},
+		{"职级统计", "levelDistribution"},
+		{"月薪分布统计", "salaryDistribution"},
+	}
+	for _, section := range sections {
+		distribution, ok := stats[section.key].(map[string]int)
+		if !ok || len(distribution) == 0 {
+			continue
+		}
+		currentRow++
+		next, err := g.writeDistribution(sheetName, currentRow, section.title, distribution)
+		if err != nil {
+			return err
+		}
+		currentRow = next
+	}
+
 	return nil
 }
 
+// writeDistribution 从 startRow 开始写入一个带标题的分布表（名称、数量、百分比），返回下一可用行
+func (g *Generator) writeDistribution(sheetName string, startRow int, title string, distribution map[string]int) (int, error) {
+	total := 0
+	names := make([]string, 0, len(distribution))
+	for name, count := range distribution {
+		total += count
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if distribution[names[i]] != distribution[names[j]] {
+			return distribution[names[i]] > distribution[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	row := startRow
+	titleCell := fmt.Sprintf("A%d", row)
+	if err := g.file.SetCellValue(sheetName, titleCell, title); err != nil {
+		return row, err
+	}
+	if err := g.file.MergeCell(sheetName, titleCell, fmt.Sprintf("C%d", row)); err != nil {
+		return row, err
+	}
+	if err := g.file.SetCellStyle(sheetName, titleCell, fmt.Sprintf("C%d", row), g.styleConfig.HeaderStyle); err != nil {
+		return row, err
+	}
+	row++
+
+	for _, name := range names {
+		count := distribution[name]
+		values := []interface{}{name, count, fmt.Sprintf("%.1f%%", float64(count)/float64(total)*100)}
+		for col, value := range values {
+			colName, _ := excelize.ColumnNumberToName(col + 1)
+			cell := fmt.Sprintf("%s%d", colName, row)
+			if err := g.file.SetCellValue(sheetName, cell, value); err != nil {
+				return row, err
+			}
+			if err := g.file.SetCellStyle(sheetName, cell, cell, g.styleConfig.DataStyle); err != nil {
+				return row, err
+			}
+		}
+		row++
+	}
+	return row, nil
+}
+
 // SaveToFile 保存Excel文件到指定路径
 func (g *Generator) SaveToFile(filePath string) error {
 	// 保护工作表
@@ -444,6 +543,63 @@ func (g *Generator) getString(s *string) string {
 	return *s
 }
 
+// getNumber 数值指针的单元格值，为空时留空
+func (g *Generator) getNumber(v *float64) interface{} {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// getRoleFamilyName 岗位类别显示名称，未识别为“未分类”
+func (g *Generator) getRoleFamilyName(key *string) string {
+	if key == nil {
+		return model.UnclassifiedRoleName
+	}
+	return taxonomy.Active().FamilyName(*key)
+}
+
+// getRoleLevelName 职级显示名称，未识别为空
+func (g *Generator) getRoleLevelName(key *string) string {
+	if key == nil {
+		return ""
+	}
+	return taxonomy.Active().LevelName(*key)
+}
+
+// getTechStackNames 技术栈显示名称以顿号连接
+func (g *Generator) getTechStackNames(keys model.StringList) string {
+	rules := taxonomy.Active()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = rules.StackName(key)
+	}
+	return strings.Join(names, "、")
+}
+
+// getTagNames 标签名以顿号连接
+func (g *Generator) getTagNames(tags []model.ApplicationTag) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return strings.Join(names, "、")
+}
+
+// getCustomFieldValue 自定义字段的单元格值，数字保持数值类型，未填写为空
+func (g *Generator) getCustomFieldValue(values model.CustomFieldValues, column *model.CustomFieldDefinition) interface{} {
+	value, ok := values[column.Key]
+	if !ok || value == nil {
+		return ""
+	}
+	if column.FieldType == model.CustomFieldNumber {
+		if number, ok := value.(float64); ok {
+			return number
+		}
+	}
+	return fmt.Sprint(value)
+}
+
 // getTimeString 安全格式化时间指针
 func (g *Generator) getTimeString(t *time.Time) string {
 	if t == nil {
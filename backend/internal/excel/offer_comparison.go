@@ -0,0 +1,155 @@
+package excel
+
+import (
+	"fmt"
+	"jobView-backend/internal/model"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// offerComparisonSheet offer 对比工作表名称
+const offerComparisonSheet = "Offer对比"
+
+// offerMatrixRow 决策矩阵中的一行：名称、权重（可空）与每个 offer 的单元格值
+type offerMatrixRow struct {
+	label  string
+	weight interface{}
+	value  func(entry *model.OfferComparisonEntry) interface{}
+}
+
+// WriteOfferComparison 将 offer 对比写为单工作表的决策矩阵：
+// 每列一个 offer（按排名），上半部分为条款与年化薪酬，下半部分为各维度得分、加权总分与排名
+func (g *Generator) WriteOfferComparison(comparison *model.OfferComparison) error {
+	if err := g.file.SetSheetName("Sheet1", offerComparisonSheet); err != nil {
+		return fmt.Errorf("设置工作表名称失败: %v", err)
+	}
+	g.sheetName = offerComparisonSheet
+	if err := g.initializeStyles(); err != nil {
+		return fmt.Errorf("初始化样式失败: %v", err)
+	}
+
+	header := []interface{}{"项目", "权重"}
+	for _, entry := range comparison.Entries {
+		header = append(header, fmt.Sprintf("%s - %s", entry.Offer.CompanyName, entry.Offer.PositionTitle))
+	}
+	if err := g.writeOfferRow(header, g.styleConfig.HeaderStyle); err != nil {
+		return err
+	}
+
+	terms := []offerMatrixRow{
+		{label: "币种", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.Currency }},
+		{label: "月薪", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.BaseMonthly }},
+		{label: "薪数", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.SalaryMonths }},
+		{label: "年base", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.Compensation.BaseAnnual }},
+		{label: "年终奖", value: func(e *model.OfferComparisonEntry) interface{} { return g.getNumber(e.Offer.AnnualBonus) }},
+		{label: "签字费", value: func(e *model.OfferComparisonEntry) interface{} { return g.getNumber(e.Offer.SignOnBonus) }},
+		{label: "股票总价值", value: func(e *model.OfferComparisonEntry) interface{} { return g.getNumber(e.Offer.StockValue) }},
+		{label: "归属", value: func(e *model.OfferComparisonEntry) interface{} { return g.getVesting(&e.Offer) }},
+		{label: "股票年化", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.Compensation.StockAnnual }},
+		{label: "年度总包", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.Compensation.AnnualTotal }},
+		{label: "首年总包", value: func(e *model.OfferComparisonEntry) interface{} { return e.Offer.Compensation.FirstYearTotal }},
+		{label: "福利", value: func(e *model.OfferComparisonEntry) interface{} { return strings.Join(e.Offer.Benefits, "、") }},
+		{label: "工作地点", value: func(e *model.OfferComparisonEntry) interface{} { return g.getString(e.Offer.Location) }},
+		{label: "入职日期", value: func(e *model.OfferComparisonEntry) interface{} { return g.getString(e.Offer.StartDate) }},
+		{label: "答复截止", value: func(e *model.OfferComparisonEntry) interface{} { return g.getString(e.Offer.Deadline) }},
+		{label: "决策状态", value: func(e *model.OfferComparisonEntry) interface{} {
+			return model.OfferDecisionStatusNames[e.Offer.DecisionStatus]
+		}},
+	}
+
+	var scores []offerMatrixRow
+	for _, criterion := range comparison.Criteria {
+		key := criterion.Key
+		label := criterion.Label
+		if criterion.LowerIsBetter {
+			label += "（越低越好）"
+		}
+		if !model.IsOfferMetric(key) {
+			terms = append(terms, offerMatrixRow{label: criterion.Label + "评分", value: func(e *model.OfferComparisonEntry) interface{} {
+				return g.getNumber(e.Values[key])
+			}})
+		}
+		scores = append(scores, offerMatrixRow{label: label, weight: fmt.Sprintf("%.1f%%", criterion.Weight*100),
+			value: func(e *model.OfferComparisonEntry) interface{} { return e.Scores[key] }})
+	}
+	scores = append(scores,
+		offerMatrixRow{label: "加权总分", value: func(e *model.OfferComparisonEntry) interface{} { return e.TotalScore }},
+		offerMatrixRow{label: "排名", value: func(e *model.OfferComparisonEntry) interface{} { return e.Rank }},
+	)
+
+	for _, row := range terms {
+		if err := g.writeOfferMatrixRow(comparison, row); err != nil {
+			return err
+		}
+	}
+	g.currentRow++
+	if err := g.writeOfferRow([]interface{}{"维度得分（0-100）", "权重"}, g.styleConfig.HeaderStyle); err != nil {
+		return err
+	}
+	for _, row := range scores {
+		if err := g.writeOfferMatrixRow(comparison, row); err != nil {
+			return err
+		}
+	}
+
+	widths := map[string]float64{"A": 20, "B": 10}
+	for col, width := range widths {
+		if err := g.file.SetColWidth(g.sheetName, col, col, width); err != nil {
+			return err
+		}
+	}
+	if len(comparison.Entries) > 0 {
+		last, err := excelize.ColumnNumberToName(len(comparison.Entries) + 2)
+		if err != nil {
+			return err
+		}
+		if err := g.file.SetColWidth(g.sheetName, "C", last, 24); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeOfferMatrixRow 写入决策矩阵的一行
+func (g *Generator) writeOfferMatrixRow(comparison *model.OfferComparison, row offerMatrixRow) error {
+	values := []interface{}{row.label, ""}
+	if row.weight != nil {
+		values[1] = row.weight
+	}
+	for i := range comparison.Entries {
+		values = append(values, row.value(&comparison.Entries[i]))
+	}
+	return g.writeOfferRow(values, g.styleConfig.DataStyle)
+}
+
+// writeOfferRow 在当前行写入一组单元格并应用样式
+func (g *Generator) writeOfferRow(values []interface{}, style int) error {
+	for i, value := range values {
+		colName, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+		cell := fmt.Sprintf("%s%d", colName, g.currentRow)
+		if err := g.file.SetCellValue(g.sheetName, cell, value); err != nil {
+			return err
+		}
+		if err := g.file.SetCellStyle(g.sheetName, cell, cell, style); err != nil {
+			return err
+		}
+	}
+	g.currentRow++
+	return nil
+}
+
+// getVesting 归属年限与节奏说明
+func (g *Generator) getVesting(offer *model.Offer) string {
+	parts := []string{}
+	if offer.VestingYears != nil {
+		parts = append(parts, fmt.Sprintf("%d年", *offer.VestingYears))
+	}
+	if offer.VestingSchedule != nil {
+		parts = append(parts, *offer.VestingSchedule)
+	}
+	return strings.Join(parts, "，")
+}
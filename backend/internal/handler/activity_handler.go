@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type ActivityHandler struct {
+	activityService *service.ActivityService
+}
+
+func NewActivityHandler(activityService *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// GetTimeline 获取申请的统一活动时间线
+// GET /api/v1/job-applications/{id}/activity?types=status_change,note&cursor=&limit=20
+func (h *ActivityHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	jobID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &model.ActivityTimelineRequest{Cursor: query.Get("cursor")}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			req.Limit = limit
+		}
+	}
+	for _, raw := range query["types"] {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				req.Types = append(req.Types, model.ActivityType(t))
+			}
+		}
+	}
+
+	timeline, err := h.activityService.GetTimeline(userID, jobID, req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get activity timeline")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "activity timeline retrieved successfully", timeline)
+}
+
+// AddNote 为申请添加备注
+// POST /api/v1/job-applications/{id}/notes
+func (h *ActivityHandler) AddNote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	jobID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	var req model.CreateNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	item, err := h.activityService.AddNote(userID, jobID, req.Content)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to add note")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "note added successfully", item)
+}
+
+// writeServiceError 按错误类型映射响应状态码
+func (h *ActivityHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case err.Error() == "job application not found or access denied":
+		h.writeErrorResponse(w, http.StatusNotFound, "job application not found", nil)
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *ActivityHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *ActivityHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetFunnelAnalytics 按投递队列获取漏斗分析
+// GET /api/v1/analytics/funnel?group_by=month&start_date=2025-09-01&end_date=2025-09-30（group_by: week / month / tag）
+func (h *AnalyticsHandler) GetFunnelAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &model.FunnelAnalyticsRequest{
+		GroupBy:   query.Get("group_by"),
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+	}
+
+	result, err := h.analyticsService.GetFunnelAnalytics(userID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get funnel analytics", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "funnel analytics retrieved successfully", result)
+}
+
+// GetSurvivalAnalytics 获取首次反馈/offer/被拒的生存曲线
+// GET /api/v1/analytics/survival?start_date=&end_date=&company_name=&min_samples=3
+func (h *AnalyticsHandler) GetSurvivalAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &model.SurvivalAnalyticsRequest{
+		StartDate:   query.Get("start_date"),
+		EndDate:     query.Get("end_date"),
+		CompanyName: query.Get("company_name"),
+	}
+	if v := query.Get("min_samples"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			req.MinSamples = n
+		}
+	}
+
+	result, err := h.analyticsService.GetSurvivalAnalytics(userID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get survival analytics", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "survival analytics retrieved successfully", result)
+}
+
+// GetSankeyFlow 获取状态流转桑基图数据
+// GET /api/v1/analytics/sankey?start_date=&end_date=&company_name=A&company_name=B&min_count=2
+func (h *AnalyticsHandler) GetSankeyFlow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &model.SankeyRequest{
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+	}
+	for _, company := range query["company_name"] {
+		if company = strings.TrimSpace(company); company != "" {
+			req.CompanyNames = append(req.CompanyNames, company)
+		}
+	}
+	if v := query.Get("min_count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid min_count parameter", nil)
+			return
+		}
+		req.MinCount = n
+	}
+
+	result, err := h.analyticsService.GetSankeyFlow(userID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get sankey flow", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "sankey flow retrieved successfully", result)
+}
+
+// GetCompensationAnalytics 获取结构化薪酬分析
+// GET /api/v1/analytics/compensation?currency=CNY&start_date=&end_date=&min_samples=2
+func (h *AnalyticsHandler) GetCompensationAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &model.CompensationAnalyticsRequest{
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+		Currency:  query.Get("currency"),
+	}
+	if v := query.Get("min_samples"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid min_samples parameter", nil)
+			return
+		}
+		req.MinSamples = n
+	}
+
+	result, err := h.analyticsService.GetCompensationAnalytics(userID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get compensation analytics", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "compensation analytics retrieved successfully", result)
+}
+
+// RefreshSuccessProbabilities 重新计算并返回申请成功率
+// POST /api/v1/analytics/success-probability/refresh
+func (h *AnalyticsHandler) RefreshSuccessProbabilities(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	result, err := h.analyticsService.RefreshSuccessProbabilities(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to refresh success probabilities", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "success probabilities refreshed successfully", result)
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *AnalyticsHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *AnalyticsHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLog 查询当前用户的审计日志
+// GET /api/v1/audit-log?job_application_id=&transaction_id=&action=update&field=salary_range&start_date=&end_date=&page=1&page_size=20
+func (h *AuditHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	q := parseAuditLogQuery(r)
+	if idStr := r.URL.Query().Get("job_application_id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid job_application_id", nil)
+			return
+		}
+		q.JobApplicationID = id
+	}
+	h.queryLogs(w, userID, q)
+}
+
+// GetApplicationAuditLog 查询单条申请的审计日志（包括已删除的申请）
+// GET /api/v1/job-applications/{id}/audit-log?page=1&page_size=20
+func (h *AuditHandler) GetApplicationAuditLog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	jobID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	q := parseAuditLogQuery(r)
+	q.JobApplicationID = jobID
+	h.queryLogs(w, userID, q)
+}
+
+func (h *AuditHandler) queryLogs(w http.ResponseWriter, userID uint, q *model.AuditLogQuery) {
+	result, err := h.auditService.QueryLogs(userID, q)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get audit log", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "audit log retrieved successfully", result)
+}
+
+// parseAuditLogQuery 解析通用查询参数
+func parseAuditLogQuery(r *http.Request) *model.AuditLogQuery {
+	query := r.URL.Query()
+	q := &model.AuditLogQuery{
+		Action:    query.Get("action"),
+		Field:     query.Get("field"),
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+	}
+	q.TransactionID, _ = strconv.ParseInt(query.Get("transaction_id"), 10, 64)
+	q.Page, _ = strconv.Atoi(query.Get("page"))
+	q.PageSize, _ = strconv.Atoi(query.Get("page_size"))
+	return q
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *AuditHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *AuditHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
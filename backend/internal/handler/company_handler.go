@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type CompanyHandler struct {
+	companyService *service.CompanyService
+}
+
+func NewCompanyHandler(companyService *service.CompanyService) *CompanyHandler {
+	return &CompanyHandler{
+		companyService: companyService,
+	}
+}
+
+// List 获取用户的公司
+// GET /api/v1/companies?q=字节
+func (h *CompanyHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	companies, err := h.companyService.List(userID, r.URL.Query().Get("q"))
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get companies", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "companies retrieved successfully", companies)
+}
+
+// Create 创建公司
+// POST /api/v1/companies {"name": "字节跳动", "aliases": ["ByteDance"], "industry": "互联网", "rating": 4}
+func (h *CompanyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.CreateCompanyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	company, err := h.companyService.Create(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to create company")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "company created successfully", company)
+}
+
+// Get 获取公司聚合页（申请、到达阶段、响应时间）
+// GET /api/v1/companies/{id}
+func (h *CompanyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid company id", err)
+		return
+	}
+
+	detail, err := h.companyService.Get(userID, id)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get company")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "company retrieved successfully", detail)
+}
+
+// Update 修改公司信息
+// PUT /api/v1/companies/{id}
+func (h *CompanyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid company id", err)
+		return
+	}
+
+	var req model.UpdateCompanyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	company, err := h.companyService.Update(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to update company")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "company updated successfully", company)
+}
+
+// Delete 删除没有关联申请的公司
+// DELETE /api/v1/companies/{id}
+func (h *CompanyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid company id", err)
+		return
+	}
+
+	if err := h.companyService.Delete(userID, id); err != nil {
+		h.writeServiceError(w, err, "failed to delete company")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "company deleted successfully", nil)
+}
+
+// AddAlias 为公司添加别名
+// POST /api/v1/companies/{id}/aliases {"alias": "ByteDance"}
+func (h *CompanyHandler) AddAlias(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid company id", err)
+		return
+	}
+
+	var req model.AddCompanyAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	company, err := h.companyService.AddAlias(userID, id, req.Alias)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to add company alias")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "company alias added successfully", company)
+}
+
+// RemoveAlias 移除别名；仍有申请使用该写法时拆分为新公司
+// DELETE /api/v1/companies/{id}/aliases/{aliasId}
+func (h *CompanyHandler) RemoveAlias(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid company id", err)
+		return
+	}
+	aliasID, err := strconv.Atoi(vars["aliasId"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid alias id", err)
+		return
+	}
+
+	company, err := h.companyService.RemoveAlias(userID, id, aliasID)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to remove company alias")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "company alias removed successfully", company)
+}
+
+// Merge 将其他公司合并到当前公司
+// POST /api/v1/companies/{id}/merge {"source_ids": [3, 5]}
+func (h *CompanyHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid company id", err)
+		return
+	}
+
+	var req model.MergeCompaniesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	company, err := h.companyService.Merge(userID, id, req.SourceIDs)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to merge companies")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "companies merged successfully", company)
+}
+
+// writeServiceError 按错误类型映射状态码
+func (h *CompanyHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	case strings.Contains(err.Error(), "not found or access denied"):
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+	case strings.Contains(err.Error(), "already exists"):
+		h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *CompanyHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *CompanyHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type ContactHandler struct {
+	contactService *service.ContactService
+}
+
+func NewContactHandler(contactService *service.ContactService) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+	}
+}
+
+// List 获取用户的联系人
+// GET /api/v1/contacts?q=王&role=recruiter&company_id=3&job_application_id=12
+func (h *ContactHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	query := r.URL.Query()
+	req := model.ContactListRequest{
+		Q:    query.Get("q"),
+		Role: model.ContactRole(query.Get("role")),
+	}
+	for param, target := range map[string]*int{"company_id": &req.CompanyID, "job_application_id": &req.JobApplicationID} {
+		if raw := query.Get(param); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				h.writeErrorResponse(w, http.StatusBadRequest, "invalid "+param, err)
+				return
+			}
+			*target = value
+		}
+	}
+
+	contacts, err := h.contactService.List(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get contacts")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contacts retrieved successfully", contacts)
+}
+
+// FollowUps 获取即将到期（含已逾期）的跟进提醒
+// GET /api/v1/contacts/follow-ups?days=7
+func (h *ContactHandler) FollowUps(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	days := 0
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid days", err)
+			return
+		}
+		days = value
+	}
+
+	contacts, err := h.contactService.FollowUps(userID, days)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get contact follow-ups")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contact follow-ups retrieved successfully", contacts)
+}
+
+// Create 创建联系人
+// POST /api/v1/contacts {"name": "王女士", "role": "recruiter", "company": "某猎头", "channels": [{"type": "wechat", "value": "wang_hr"}], "job_application_ids": [12]}
+func (h *ContactHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.CreateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	contact, err := h.contactService.Create(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to create contact")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "contact created successfully", contact)
+}
+
+// Get 获取联系人详情（含关联申请与沟通记录）
+// GET /api/v1/contacts/{id}
+func (h *ContactHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid contact id", err)
+		return
+	}
+
+	contact, err := h.contactService.Get(userID, id)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get contact")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contact retrieved successfully", contact)
+}
+
+// Update 修改联系人
+// PUT /api/v1/contacts/{id} {"follow_up_at": "2025-10-20T10:00:00+08:00", "follow_up_note": "询问二面结果"}
+func (h *ContactHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid contact id", err)
+		return
+	}
+
+	var req model.UpdateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	contact, err := h.contactService.Update(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to update contact")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contact updated successfully", contact)
+}
+
+// Delete 删除联系人
+// DELETE /api/v1/contacts/{id}
+func (h *ContactHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid contact id", err)
+		return
+	}
+
+	if err := h.contactService.Delete(userID, id); err != nil {
+		h.writeServiceError(w, err, "failed to delete contact")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contact deleted successfully", nil)
+}
+
+// SetApplications 替换联系人关联的申请
+// PUT /api/v1/contacts/{id}/applications {"job_application_ids": [12, 15]}
+func (h *ContactHandler) SetApplications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid contact id", err)
+		return
+	}
+
+	var req model.SetContactApplicationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	contact, err := h.contactService.SetApplications(userID, id, req.JobApplicationIDs)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to set contact applications")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contact applications updated successfully", contact)
+}
+
+// AddInteraction 记录一次沟通
+// POST /api/v1/contacts/{id}/interactions {"type": "call", "summary": "约了下周二面", "job_application_id": 12, "next_follow_up_at": "2025-10-24T10:00:00+08:00"}
+func (h *ContactHandler) AddInteraction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid contact id", err)
+		return
+	}
+
+	var req model.CreateContactInteractionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	contact, err := h.contactService.AddInteraction(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to add contact interaction")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "contact interaction added successfully", contact)
+}
+
+// DeleteInteraction 删除沟通记录
+// DELETE /api/v1/contacts/{id}/interactions/{interactionId}
+func (h *ContactHandler) DeleteInteraction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid contact id", err)
+		return
+	}
+	interactionID, err := strconv.Atoi(vars["interactionId"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid interaction id", err)
+		return
+	}
+
+	if err := h.contactService.DeleteInteraction(userID, id, interactionID); err != nil {
+		h.writeServiceError(w, err, "failed to delete contact interaction")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "contact interaction deleted successfully", nil)
+}
+
+// GetApplicationContacts 获取申请关联的联系人
+// GET /api/v1/job-applications/{id}/contacts
+func (h *ContactHandler) GetApplicationContacts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	contacts, err := h.contactService.ApplicationContacts(userID, id)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get application contacts")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "application contacts retrieved successfully", contacts)
+}
+
+// writeServiceError 按错误类型映射状态码
+func (h *ContactHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	case strings.Contains(err.Error(), "not found or access denied"):
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *ContactHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *ContactHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type CustomFieldHandler struct {
+	customFieldService *service.CustomFieldService
+}
+
+func NewCustomFieldHandler(customFieldService *service.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldService: customFieldService,
+	}
+}
+
+// List 获取用户的自定义字段定义
+// GET /api/v1/custom-fields
+func (h *CustomFieldHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	defs, err := h.customFieldService.List(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get custom fields", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "custom fields retrieved successfully", defs)
+}
+
+// Create 创建自定义字段
+// POST /api/v1/custom-fields {"key": "team_name", "label": "团队", "field_type": "text"}
+func (h *CustomFieldHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.CreateCustomFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	def, err := h.customFieldService.Create(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to create custom field")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "custom field created successfully", def)
+}
+
+// Update 修改自定义字段（键和类型不可修改）
+// PUT /api/v1/custom-fields/{id}
+func (h *CustomFieldHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid custom field id", err)
+		return
+	}
+
+	var req model.UpdateCustomFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	def, err := h.customFieldService.Update(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to update custom field")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "custom field updated successfully", def)
+}
+
+// Delete 删除自定义字段及所有申请上的对应值
+// DELETE /api/v1/custom-fields/{id}
+func (h *CustomFieldHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid custom field id", err)
+		return
+	}
+
+	if err := h.customFieldService.Delete(userID, id); err != nil {
+		h.writeServiceError(w, err, "failed to delete custom field")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "custom field deleted successfully", nil)
+}
+
+// writeServiceError 按错误类型映射状态码
+func (h *CustomFieldHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	case strings.Contains(err.Error(), "not found or access denied"):
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+	case strings.Contains(err.Error(), "already exists"):
+		h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *CustomFieldHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *CustomFieldHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
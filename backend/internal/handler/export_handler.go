@@ -35,7 +35,7 @@ func NewExportHandler(exportService *service.ExportService) *ExportHandler {
 }
 
 // StartExport 启动导出任务
-// POST /api/v1/export/applications
+// POST /api/v1/export/applications，指定 view_id 时使用保存的视图作为筛选条件
 func (h *ExportHandler) StartExport(w http.ResponseWriter, r *http.Request) {
 	// 获取用户ID
 	userID, ok := auth.GetUserIDFromContext(r.Context())
@@ -63,8 +63,10 @@ func (h *ExportHandler) StartExport(w http.ResponseWriter, r *http.Request) {
 		// 根据错误类型返回不同的HTTP状态码
 		if strings.Contains(err.Error(), "上限") || strings.Contains(err.Error(), "超限") {
 			h.writeErrorResponse(w, http.StatusTooManyRequests, err.Error(), nil)
-		} else if strings.Contains(err.Error(), "没有符合条件的数据") {
+		} else if strings.Contains(err.Error(), "没有符合条件的数据") || strings.HasPrefix(err.Error(), "invalid") {
 			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else if strings.Contains(err.Error(), "not found or access denied") {
+			h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
 		} else {
 			h.writeErrorResponse(w, http.StatusInternalServerError, "启动导出任务失败", err)
 		}
@@ -255,111 +257,135 @@ func (h *ExportHandler) GetSupportedFormats(w http.ResponseWriter, r *http.Reque
 // GetExportFields 获取可导出的字段
 // GET /api/v1/export/fields
 func (h *ExportHandler) GetExportFields(w http.ResponseWriter, r *http.Request) {
-	fields := map[string]interface{}{
-		"fields": []map[string]interface{}{
-			{
-				"value":       "company_name",
-				"label":       "公司名称",
-				"required":    true,
-				"description": "投递的公司名称",
-			},
-			{
-				"value":       "position_title",
-				"label":       "职位标题",
-				"required":    true,
-				"description": "申请的职位名称",
-			},
-			{
-				"value":       "application_date",
-				"label":       "投递日期",
-				"required":    false,
-				"description": "投递简历的日期",
-			},
-			{
-				"value":       "status",
-				"label":       "当前状态",
-				"required":    false,
-				"description": "当前投递状态",
-			},
-			{
-				"value":       "salary_range",
-				"label":       "薪资范围",
-				"required":    false,
-				"description": "期望或提供的薪资范围",
-			},
-			{
-				"value":       "work_location",
-				"label":       "工作地点",
-				"required":    false,
-				"description": "工作城市或地点",
-			},
-			{
-				"value":       "interview_time",
-				"label":       "面试时间",
-				"required":    false,
-				"description": "面试安排时间",
-			},
-			{
-				"value":       "interview_location",
-				"label":       "面试地点",
-				"required":    false,
-				"description": "面试地点或方式",
-			},
-			{
-				"value":       "interview_type",
-				"label":       "面试类型",
-				"required":    false,
-				"description": "面试形式（线上/线下/电话等）",
-			},
-			{
-				"value":       "hr_name",
-				"label":       "HR姓名",
-				"required":    false,
-				"description": "负责HR的姓名",
-			},
-			{
-				"value":       "hr_phone",
-				"label":       "HR电话",
-				"required":    false,
-				"description": "HR联系电话",
-			},
-			{
-				"value":       "hr_email",
-				"label":       "HR邮箱",
-				"required":    false,
-				"description": "HR联系邮箱",
-			},
-			{
-				"value":       "reminder_time",
-				"label":       "提醒时间",
-				"required":    false,
-				"description": "设置的提醒时间",
-			},
-			{
-				"value":       "follow_up_date",
-				"label":       "跟进日期",
-				"required":    false,
-				"description": "计划跟进的日期",
-			},
-			{
-				"value":       "notes",
-				"label":       "备注",
-				"required":    false,
-				"description": "相关备注信息",
-			},
-			{
-				"value":       "created_at",
-				"label":       "创建时间",
-				"required":    false,
-				"description": "记录创建时间",
-			},
-			{
-				"value":       "updated_at",
-				"label":       "更新时间",
-				"required":    false,
-				"description": "记录最后更新时间",
-			},
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	customFields, err := h.exportService.GetCustomExportFields(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "查询自定义字段失败", err)
+		return
+	}
+
+	exportFields := []map[string]interface{}{
+		{
+			"value":       "company_name",
+			"label":       "公司名称",
+			"required":    true,
+			"description": "投递的公司名称",
+		},
+		{
+			"value":       "position_title",
+			"label":       "职位标题",
+			"required":    true,
+			"description": "申请的职位名称",
+		},
+		{
+			"value":       "application_date",
+			"label":       "投递日期",
+			"required":    false,
+			"description": "投递简历的日期",
+		},
+		{
+			"value":       "status",
+			"label":       "当前状态",
+			"required":    false,
+			"description": "当前投递状态",
+		},
+		{
+			"value":       "salary_range",
+			"label":       "薪资范围",
+			"required":    false,
+			"description": "期望或提供的薪资范围",
+		},
+		{
+			"value":       "work_location",
+			"label":       "工作地点",
+			"required":    false,
+			"description": "工作城市或地点",
 		},
+		{
+			"value":       "interview_time",
+			"label":       "面试时间",
+			"required":    false,
+			"description": "面试安排时间",
+		},
+		{
+			"value":       "interview_location",
+			"label":       "面试地点",
+			"required":    false,
+			"description": "面试地点或方式",
+		},
+		{
+			"value":       "interview_type",
+			"label":       "面试类型",
+			"required":    false,
+			"description": "面试形式（线上/线下/电话等）",
+		},
+		{
+			"value":       "hr_name",
+			"label":       "HR姓名",
+			"required":    false,
+			"description": "负责HR的姓名",
+		},
+		{
+			"value":       "hr_phone",
+			"label":       "HR电话",
+			"required":    false,
+			"description": "HR联系电话",
+		},
+		{
+			"value":       "hr_email",
+			"label":       "HR邮箱",
+			"required":    false,
+			"description": "HR联系邮箱",
+		},
+		{
+			"value":       "reminder_time",
+			"label":       "提醒时间",
+			"required":    false,
+			"description": "设置的提醒时间",
+		},
+		{
+			"value":       "follow_up_date",
+			"label":       "跟进日期",
+			"required":    false,
+			"description": "计划跟进的日期",
+		},
+		{
+			"value":       "notes",
+			"label":       "备注",
+			"required":    false,
+			"description": "相关备注信息",
+		},
+		{
+			"value":       "created_at",
+			"label":       "创建时间",
+			"required":    false,
+			"description": "记录创建时间",
+		},
+		{
+			"value":       "updated_at",
+			"label":       "更新时间",
+			"required":    false,
+			"description": "记录最后更新时间",
+		},
+	}
+
+	// 自定义字段以 cf.<key> 作为字段值，选中后追加在表格末尾
+	for _, def := range customFields {
+		exportFields = append(exportFields, map[string]interface{}{
+			"value":       model.CustomFieldPrefix + def.Key,
+			"label":       def.Label,
+			"required":    false,
+			"description": fmt.Sprintf("自定义字段（%s）", def.FieldType),
+		})
+	}
+
+	fields := map[string]interface{}{
+		"fields": exportFields,
 		"defaultFields": []string{
 			"company_name",
 			"position_title",
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"net/http"
+	"strings"
+)
+
+// BulkCreate 批量创建投递记录
+// POST /api/v1/applications/bulk {"mode": "atomic|best_effort", "applications": [...]}
+func (h *JobApplicationHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.BatchCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// 复用单条创建的校验，错误记录到对应条目
+	itemErrors := make([]error, len(req.Applications))
+	for i := range req.Applications {
+		itemErrors[i] = h.validateCreateRequest(&req.Applications[i])
+	}
+
+	result, err := h.service.BulkCreate(userID, req.Mode, req.Applications, itemErrors)
+	h.writeBulkResponse(w, result, err, "create", http.StatusCreated)
+}
+
+// BulkUpdate 批量更新投递记录
+// PUT /api/v1/applications/bulk {"mode": "atomic|best_effort", "items": [{"id": 1, "notes": "..."}]}
+func (h *JobApplicationHandler) BulkUpdate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.BulkUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	itemErrors := make([]error, len(req.Items))
+	for i := range req.Items {
+		itemErrors[i] = h.validateUpdateRequest(&req.Items[i].UpdateJobApplicationRequest)
+	}
+
+	result, err := h.service.BulkUpdate(userID, req.Mode, req.Items, itemErrors)
+	h.writeBulkResponse(w, result, err, "update", http.StatusOK)
+}
+
+// BulkDelete 批量删除投递记录（移入回收站），返回的 operation_id 可用于撤销
+// DELETE /api/v1/applications/bulk {"mode": "atomic|best_effort", "ids": [1, 2]}
+func (h *JobApplicationHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.BulkDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	result, err := h.service.BulkDelete(userID, req.Mode, req.IDs)
+	h.writeBulkResponse(w, result, err, "delete", http.StatusOK)
+}
+
+// writeBulkResponse 原子模式有失败时返回 400（没有任何修改生效），否则返回逐条结果
+func (h *JobApplicationHandler) writeBulkResponse(w http.ResponseWriter, result *model.BulkOperationResponse, err error, action string, successStatus int) {
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		} else {
+			h.writeErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("failed to bulk %s job applications", action), err)
+		}
+		return
+	}
+
+	switch {
+	case result.Failed == 0:
+		h.writeSuccessResponse(w, successStatus, fmt.Sprintf("bulk %s completed successfully", action), result)
+	case result.Mode == model.BulkModeAtomic:
+		h.writeSuccessResponse(w, http.StatusBadRequest, fmt.Sprintf("bulk %s rejected, no changes were applied", action), result)
+	default:
+		h.writeSuccessResponse(w, http.StatusOK, fmt.Sprintf("bulk %s completed with %d failed items", action, result.Failed), result)
+	}
+}
+
+// PreviewBulkEdit 预览按条件批量编辑
+// POST /api/v1/applications/bulk-edit/preview {"filter": {"status": ["一面未通过"]}, "patch": {"reminder_enabled": false}}
+// 批量打标签：{"filter": {"company_names": ["字节跳动"]}, "add_tag_ids": [3]}
+func (h *JobApplicationHandler) PreviewBulkEdit(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.BulkEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	if err := h.validateUpdateRequest(&req.Patch); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	preview, err := h.service.PreviewBulkEdit(userID, &req)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid"):
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		case strings.Contains(err.Error(), "not found or access denied"):
+			h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to preview bulk edit", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "bulk edit preview generated successfully", preview)
+}
+
+// ApplyBulkEdit 执行按条件批量编辑，expected_count 需与预览的 matched_count 一致
+// POST /api/v1/applications/bulk-edit/apply {"filter": {...}, "patch": {...}, "expected_count": 12}
+func (h *JobApplicationHandler) ApplyBulkEdit(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.BulkEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	if err := h.validateUpdateRequest(&req.Patch); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := h.service.ApplyBulkEdit(userID, &req)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid"):
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		case strings.Contains(err.Error(), "changed since preview"):
+			h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+		case strings.Contains(err.Error(), "not found or access denied"):
+			h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to apply bulk edit", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "bulk edit applied successfully", result)
+}
@@ -2,13 +2,19 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"jobView-backend/internal/auth"
 	"jobView-backend/internal/model"
+	"jobView-backend/internal/querylang"
 	"jobView-backend/internal/service"
+	"jobView-backend/internal/taxonomy"
 	"jobView-backend/internal/utils"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -44,6 +50,10 @@ func (h *JobApplicationHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	job, err := h.service.Create(userID, &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to create job application", err)
 		return
 	}
@@ -142,6 +152,8 @@ func (h *JobApplicationHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err.Error() == "job application not found" {
 			h.writeErrorResponse(w, http.StatusNotFound, "job application not found", nil)
+		} else if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
 		} else {
 			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to update job application", err)
 		}
@@ -326,7 +338,7 @@ func (h *JobApplicationHandler) validateUpdateRequest(req *model.UpdateJobApplic
 }
 
 // GetJobApplicationsWithFilters 根据状态和阶段筛选获取岗位申请
-// GET /api/v1/applications?status={status}&stage={stage}
+// GET /api/v1/applications?status={status}&stage={stage}&tag_ids=1,2&start_date=&end_date=&salary_min=20&salary_max=40&cf.{key}={value}&sort_by=cf.{key}&q={query}
 func (h *JobApplicationHandler) GetJobApplicationsWithFilters(w http.ResponseWriter, r *http.Request) {
 	// 获取用户ID
 	userID, ok := auth.GetUserIDFromContext(r.Context())
@@ -369,9 +381,43 @@ func (h *JobApplicationHandler) GetJobApplicationsWithFilters(w http.ResponseWri
 		}
 	}
 
+	// 解析标签筛选
+	req.TagIDs = parseTagIDs(r.URL.Query().Get("tag_ids"))
+
+	// 解析投递日期范围
+	req.StartDate = r.URL.Query().Get("start_date")
+	req.EndDate = r.URL.Query().Get("end_date")
+
+	// 解析月薪区间（K）
+	var err error
+	if req.SalaryMin, req.SalaryMax, err = parseSalaryBounds(r.URL.Query()); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// 解析自定义字段筛选：cf.<key>=value
+	for key, values := range r.URL.Query() {
+		if fieldKey, ok := strings.CutPrefix(key, model.CustomFieldPrefix); ok && len(values) > 0 && values[0] != "" {
+			if req.CustomFilters == nil {
+				req.CustomFilters = make(map[string]string)
+			}
+			req.CustomFilters[fieldKey] = values[0]
+		}
+	}
+
+	// 解析查询语句，如 status:一面中,二面中 company:"字节" applied>=2025-09-01
+	req.Query = r.URL.Query().Get("q")
+
 	// 调用服务获取筛选结果
 	result, err := h.service.GetJobApplicationsWithStatusFilters(userID, status, stage, req)
 	if err != nil {
+		if h.writeQueryError(w, err) {
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get filtered job applications", err)
 		return
 	}
@@ -380,7 +426,7 @@ func (h *JobApplicationHandler) GetJobApplicationsWithFilters(w http.ResponseWri
 }
 
 // SearchJobApplications 搜索岗位申请
-// GET /api/v1/applications/search?q={query}&filters={filters}
+// GET /api/v1/applications/search?q={query}&status={status}&tag_ids=1,2&start_date=&end_date=&salary_min=&salary_max=
 func (h *JobApplicationHandler) SearchJobApplications(w http.ResponseWriter, r *http.Request) {
 	// 获取用户ID
 	userID, ok := auth.GetUserIDFromContext(r.Context())
@@ -411,9 +457,30 @@ func (h *JobApplicationHandler) SearchJobApplications(w http.ResponseWriter, r *
 		}
 	}
 
+	// 解析标签过滤器
+	req.TagIDs = parseTagIDs(r.URL.Query().Get("tag_ids"))
+
+	// 解析投递日期范围
+	req.StartDate = r.URL.Query().Get("start_date")
+	req.EndDate = r.URL.Query().Get("end_date")
+
+	// 解析月薪区间（K）
+	var err error
+	if req.SalaryMin, req.SalaryMax, err = parseSalaryBounds(r.URL.Query()); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
 	// 调用服务进行搜索
 	result, err := h.service.SearchApplications(userID, query, req)
 	if err != nil {
+		if h.writeQueryError(w, err) {
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to search job applications", err)
 		return
 	}
@@ -421,6 +488,96 @@ func (h *JobApplicationHandler) SearchJobApplications(w http.ResponseWriter, r *
 	h.writeSuccessResponse(w, http.StatusOK, "job applications search completed successfully", result)
 }
 
+// GetQueryFields 获取查询语言的字段目录（字段、别名、操作符、候选值），供自动补全使用
+// GET /api/v1/applications/query-fields
+func (h *JobApplicationHandler) GetQueryFields(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	fields, err := h.service.QueryFields(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get query fields", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "query fields retrieved successfully", map[string]interface{}{
+		"fields":     fields,
+		"max_length": querylang.MaxQueryLength,
+		"max_terms":  querylang.MaxTerms,
+	})
+}
+
+// GetPositionTaxonomy 获取当前生效的岗位分类规则（类别、职级、技术栈）；带 title 参数时同时返回该职位名称的归一化结果
+// GET /api/v1/applications/position-taxonomy?title=高级Golang后端
+func (h *JobApplicationHandler) GetPositionTaxonomy(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.GetUserIDFromContext(r.Context()); !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	rules := taxonomy.Active()
+	data := map[string]interface{}{
+		"version":     rules.Version(),
+		"families":    rules.Families,
+		"levels":      rules.Levels,
+		"tech_stacks": rules.TechStacks,
+	}
+	if title := r.URL.Query().Get("title"); title != "" {
+		data["normalized"] = rules.Normalize(title)
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "position taxonomy retrieved successfully", data)
+}
+
+// Autocomplete 公司/职位/地点输入联想，返回用户已有值中的规范写法
+// GET /api/v1/applications/autocomplete/{field}?q={prefix}&limit=10，field 为 company、position 或 location
+func (h *JobApplicationHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	field := model.AutocompleteField(mux.Vars(r)["field"])
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil {
+			limit = parsed
+		}
+	}
+
+	suggestions, err := h.service.Autocomplete(userID, field, r.URL.Query().Get("q"), limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get autocomplete suggestions", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "autocomplete suggestions retrieved successfully", suggestions)
+}
+
+// writeQueryError 查询语句语法错误时返回 400，并在 data 中带上出错位置与建议
+func (h *JobApplicationHandler) writeQueryError(w http.ResponseWriter, err error) bool {
+	var queryErr *querylang.Error
+	if !errors.As(err, &queryErr) {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(model.APIResponse{
+		Code:    http.StatusBadRequest,
+		Message: err.Error(),
+		Data:    queryErr,
+	})
+	return true
+}
+
 // GetDashboardData 获取仪表板数据
 // GET /api/v1/applications/dashboard
 func (h *JobApplicationHandler) GetDashboardData(w http.ResponseWriter, r *http.Request) {
@@ -440,3 +597,31 @@ func (h *JobApplicationHandler) GetDashboardData(w http.ResponseWriter, r *http.
 
 	h.writeSuccessResponse(w, http.StatusOK, "dashboard data retrieved successfully", dashboard)
 }
+
+// parseSalaryBounds 解析月薪筛选 salary_min/salary_max（单位 K），未提供时为空
+func parseSalaryBounds(query url.Values) (*float64, *float64, error) {
+	var bounds [2]*float64
+	for i, key := range []string{"salary_min", "salary_max"} {
+		raw := strings.TrimSpace(query.Get(key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s parameter: %s", key, raw)
+		}
+		bounds[i] = &value
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// parseTagIDs 解析逗号分隔的标签ID，忽略无效值
+func parseTagIDs(raw string) []int {
+	var tagIDs []int
+	for _, part := range strings.Split(raw, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && id > 0 {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+	return tagIDs
+}
@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type OfferHandler struct {
+	offerService *service.OfferService
+}
+
+func NewOfferHandler(offerService *service.OfferService) *OfferHandler {
+	return &OfferHandler{
+		offerService: offerService,
+	}
+}
+
+// List 获取用户的 offer
+// GET /api/v1/offers?decision_status=pending
+func (h *OfferHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	offers, err := h.offerService.List(userID, r.URL.Query().Get("decision_status"))
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get offers")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "offers retrieved successfully", offers)
+}
+
+// Create 为申请登记 offer
+// POST /api/v1/offers {"job_application_id": 12, "base_monthly": 35000, "salary_months": 15, "stock_value": 400000, "vesting_years": 4}
+func (h *OfferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.CreateOfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	offer, err := h.offerService.Create(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to create offer")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "offer created successfully", offer)
+}
+
+// Get 获取 offer 详情（含谈判记录）
+// GET /api/v1/offers/{id}
+func (h *OfferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid offer id", err)
+		return
+	}
+
+	offer, err := h.offerService.Get(userID, id)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get offer")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "offer retrieved successfully", offer)
+}
+
+// Update 修改 offer 条款或决策状态，条款变化自动记入谈判记录
+// PUT /api/v1/offers/{id} {"base_monthly": 38000, "negotiation_note": "HR 同意上调 base"}
+func (h *OfferHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid offer id", err)
+		return
+	}
+
+	var req model.UpdateOfferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	offer, err := h.offerService.Update(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to update offer")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "offer updated successfully", offer)
+}
+
+// Delete 删除 offer
+// DELETE /api/v1/offers/{id}
+func (h *OfferHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid offer id", err)
+		return
+	}
+
+	if err := h.offerService.Delete(userID, id); err != nil {
+		h.writeServiceError(w, err, "failed to delete offer")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "offer deleted successfully", nil)
+}
+
+// AddNegotiation 添加谈判沟通备注
+// POST /api/v1/offers/{id}/negotiations {"note": "已告知对方竞争 offer 的总包"}
+func (h *OfferHandler) AddNegotiation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid offer id", err)
+		return
+	}
+
+	var req model.AddOfferNegotiationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	offer, err := h.offerService.AddNegotiation(userID, id, req.Note)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to add offer negotiation")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "offer negotiation added successfully", offer)
+}
+
+// Compare 按加权维度对比 offer，返回排序后的决策矩阵
+// POST /api/v1/offers/compare {"offer_ids": [1, 2], "criteria": [{"key": "first_year_total", "weight": 5}, {"key": "growth", "label": "成长空间", "weight": 3}]}
+func (h *OfferHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.OfferComparisonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	comparison, err := h.offerService.Compare(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to compare offers")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "offers compared successfully", comparison)
+}
+
+// ExportComparison 下载 offer 对比的 Excel 决策矩阵，请求体同 Compare
+// POST /api/v1/offers/compare/export
+func (h *OfferHandler) ExportComparison(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.OfferComparisonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	data, err := h.offerService.ExportComparison(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to export offer comparison")
+		return
+	}
+
+	filename := fmt.Sprintf("offer_comparison_%s.xlsx", time.Now().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// SetDecisionDeadline 设置或清除申请的 offer 答复截止日期，截止前 7/3/1 天发送提醒
+// PUT /api/v1/job-applications/{id}/decision-deadline {"deadline": "2025-10-20"}
+func (h *OfferHandler) SetDecisionDeadline(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	var req model.DecisionDeadlineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	deadline, err := h.offerService.SetDecisionDeadline(userID, id, req.Deadline)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to set decision deadline")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "decision deadline updated successfully", map[string]interface{}{
+		"job_application_id": id,
+		"decision_deadline":  deadline,
+	})
+}
+
+// RecordDecision 记录接受/拒绝 offer，申请自动推进到已接受offer或流程结束并添加备注
+// POST /api/v1/job-applications/{id}/offer-decision {"decision": "accepted", "note": "薪资与团队最匹配"}
+func (h *OfferHandler) RecordDecision(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	var req model.OfferDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	job, err := h.offerService.RecordDecision(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to record offer decision")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "offer decision recorded successfully", job)
+}
+
+// writeServiceError 按错误类型映射状态码
+func (h *OfferHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	case strings.Contains(err.Error(), "not found or access denied"):
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+	case strings.Contains(err.Error(), "already exists"):
+		h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *OfferHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *OfferHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
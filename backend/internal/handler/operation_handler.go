@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type OperationHandler struct {
+	operationService *service.OperationService
+}
+
+func NewOperationHandler(operationService *service.OperationService) *OperationHandler {
+	return &OperationHandler{
+		operationService: operationService,
+	}
+}
+
+// Undo 撤销批量操作
+// POST /api/v1/operations/{id}/undo
+func (h *OperationHandler) Undo(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	operationID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid operation id", err)
+		return
+	}
+
+	result, err := h.operationService.Undo(userID, operationID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found or access denied"):
+			h.writeErrorResponse(w, http.StatusNotFound, "operation not found", nil)
+		case strings.Contains(err.Error(), "already undone"), strings.Contains(err.Error(), "cannot be undone"):
+			h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "failed to undo operation", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "operation undone successfully", result)
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *OperationHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *OperationHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type SavedViewHandler struct {
+	savedViewService *service.SavedViewService
+}
+
+func NewSavedViewHandler(savedViewService *service.SavedViewService) *SavedViewHandler {
+	return &SavedViewHandler{
+		savedViewService: savedViewService,
+	}
+}
+
+// List 获取用户保存的视图及当前匹配数
+// GET /api/v1/saved-views
+func (h *SavedViewHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	views, err := h.savedViewService.List(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get saved views", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "saved views retrieved successfully", views)
+}
+
+// Create 保存视图
+// POST /api/v1/saved-views {"name": "本周面试", "filters": {"stage": "interviews", "sort_by": "updated_at"}, "pinned": true}
+func (h *SavedViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.CreateSavedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	view, err := h.savedViewService.Create(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to create saved view")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "saved view created successfully", view)
+}
+
+// Get 获取单个视图
+// GET /api/v1/saved-views/{id}
+func (h *SavedViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid saved view id", err)
+		return
+	}
+
+	view, err := h.savedViewService.Get(userID, id)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get saved view")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "saved view retrieved successfully", view)
+}
+
+// Update 修改视图
+// PUT /api/v1/saved-views/{id}
+func (h *SavedViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid saved view id", err)
+		return
+	}
+
+	var req model.UpdateSavedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	view, err := h.savedViewService.Update(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to update saved view")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "saved view updated successfully", view)
+}
+
+// Delete 删除视图
+// DELETE /api/v1/saved-views/{id}
+func (h *SavedViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid saved view id", err)
+		return
+	}
+
+	if err := h.savedViewService.Delete(userID, id); err != nil {
+		h.writeServiceError(w, err, "failed to delete saved view")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "saved view deleted successfully", nil)
+}
+
+// GetApplications 按视图条件查询申请
+// GET /api/v1/saved-views/{id}/applications?page=1&page_size=20
+func (h *SavedViewHandler) GetApplications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid saved view id", err)
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+
+	result, err := h.savedViewService.Run(userID, id, page, pageSize)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get saved view applications")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "saved view applications retrieved successfully", result)
+}
+
+// writeServiceError 按错误类型映射状态码
+func (h *SavedViewHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	case strings.Contains(err.Error(), "not found or access denied"):
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+	case strings.Contains(err.Error(), "already exists"):
+		h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *SavedViewHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *SavedViewHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type StatusConsistencyHandler struct {
+	consistencyService *service.StatusConsistencyService
+}
+
+func NewStatusConsistencyHandler(consistencyService *service.StatusConsistencyService) *StatusConsistencyHandler {
+	return &StatusConsistencyHandler{
+		consistencyService: consistencyService,
+	}
+}
+
+// CheckConsistency 检查当前用户状态历史一致性（dry-run 报告）
+// GET /api/v1/job-applications/status-consistency
+func (h *StatusConsistencyHandler) CheckConsistency(w http.ResponseWriter, r *http.Request) {
+	h.reconcile(w, r, true)
+}
+
+// RepairConsistency 从状态历史表重建当前用户的 JSONB 投影
+// POST /api/v1/job-applications/status-consistency/repair?dry_run=true
+func (h *StatusConsistencyHandler) RepairConsistency(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid dry_run parameter", err)
+			return
+		}
+		dryRun = parsed
+	}
+	h.reconcile(w, r, dryRun)
+}
+
+func (h *StatusConsistencyHandler) reconcile(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	req := &model.StatusReconcileRequest{UserID: &userID, DryRun: dryRun}
+	result, err := h.consistencyService.Reconcile(req)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to reconcile status history", err)
+		return
+	}
+
+	message := "status history consistency checked"
+	if !dryRun {
+		message = "status history projections rebuilt"
+	}
+	h.writeSuccessResponse(w, http.StatusOK, message, result)
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *StatusConsistencyHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *StatusConsistencyHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
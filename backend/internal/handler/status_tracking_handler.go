@@ -13,17 +13,20 @@ import (
 	"jobView-backend/internal/utils"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
 
 type StatusTrackingHandler struct {
-	statusService *service.StatusTrackingService
+	statusService         *service.StatusTrackingService
+	recommendationService *service.RecommendationService
 }
 
-func NewStatusTrackingHandler(statusService *service.StatusTrackingService) *StatusTrackingHandler {
+func NewStatusTrackingHandler(statusService *service.StatusTrackingService, recommendationService *service.RecommendationService) *StatusTrackingHandler {
 	return &StatusTrackingHandler{
-		statusService: statusService,
+		statusService:         statusService,
+		recommendationService: recommendationService,
 	}
 }
 
@@ -203,14 +206,16 @@ func (h *StatusTrackingHandler) BatchUpdateStatus(w http.ResponseWriter, r *http
 	}
 
 	// 调用服务进行批量更新
-	err := h.statusService.BatchUpdateStatus(uint(userID), updates)
+	result, err := h.statusService.BatchUpdateStatus(uint(userID), updates)
 	if err != nil {
 		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to batch update status", err)
 		return
 	}
 
+	// operation_id 可用于 POST /api/v1/operations/{id}/undo 撤销本次操作
 	h.writeSuccessResponse(w, http.StatusOK, "batch status update completed successfully", map[string]interface{}{
-		"updated_count": len(updates),
+		"updated_count": result.AffectedCount,
+		"operation_id":  result.OperationID,
 	})
 }
 
@@ -244,25 +249,34 @@ func (h *StatusTrackingHandler) GetStatusTrends(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	query := r.URL.Query()
+	req := &model.StatusTrendRequest{
+		StartDate:   query.Get("start_date"),
+		EndDate:     query.Get("end_date"),
+		Granularity: query.Get("granularity"),
+		Timezone:    query.Get("timezone"),
+		GroupBy:     query.Get("group_by"),
+	}
 	// 获取天数参数
-	days := 30 // 默认30天
-	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+	req.Days = 30 // 默认30天
+	if daysStr := query.Get("days"); daysStr != "" {
 		if d, err := strconv.Atoi(daysStr); err == nil && d > 0 && d <= 365 {
-			days = d
+			req.Days = d
 		}
 	}
 
 	// 调用服务获取趋势数据
-	trends, err := h.statusService.GetStatusTrends(uint(userID), days)
+	trends, err := h.statusService.GetStatusTrendSeries(uint(userID), req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get status trends", err)
 		return
 	}
 
-	h.writeSuccessResponse(w, http.StatusOK, "status trends retrieved successfully", map[string]interface{}{
-		"days":   days,
-		"trends": trends,
-	})
+	h.writeSuccessResponse(w, http.StatusOK, "status trends retrieved successfully", trends)
 }
 
 // GetProcessInsights 获取流程洞察数据
@@ -289,6 +303,13 @@ func (h *StatusTrackingHandler) GetProcessInsights(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// 基于规则生成建议
+	recommendations, err := h.recommendationService.GenerateRecommendations(uint(userID), analytics, trends, recommendationLang(r))
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to generate recommendations", err)
+		return
+	}
+
 	// 构建洞察数据
 	insights := map[string]interface{}{
 		"summary": map[string]interface{}{
@@ -303,7 +324,7 @@ func (h *StatusTrackingHandler) GetProcessInsights(w http.ResponseWriter, r *htt
 		"trends": map[string]interface{}{
 			"recent_activity": trends,
 		},
-		"recommendations": h.generateRecommendations(analytics),
+		"recommendations": recommendations,
 	}
 
 	h.writeSuccessResponse(w, http.StatusOK, "process insights retrieved successfully", insights)
@@ -357,32 +378,16 @@ func (h *StatusTrackingHandler) calculateActiveApplications(statusDistribution m
 	return activeCount
 }
 
-// generateRecommendations 生成建议
-func (h *StatusTrackingHandler) generateRecommendations(analytics *model.StatusAnalyticsResponse) []string {
-	var recommendations []string
-
-	// 基于成功率的建议
-	if analytics.SuccessRate < 10 {
-		recommendations = append(recommendations, "建议优化简历和投递策略，当前成功率较低")
-	} else if analytics.SuccessRate > 50 {
-		recommendations = append(recommendations, "投递策略很好，继续保持")
-	}
-
-	// 基于申请数量的建议
-	if analytics.TotalApplications < 10 {
-		recommendations = append(recommendations, "建议增加投递数量，扩大求职机会")
+// recommendationLang 从 ?lang= 或 Accept-Language 中解析建议语言
+func recommendationLang(r *http.Request) string {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = r.Header.Get("Accept-Language")
 	}
-
-	// 基于持续时间的建议
-	if avgDuration, ok := analytics.AverageDurations["简历筛选中"]; ok && avgDuration > 7*24*60 { // 超过7天
-		recommendations = append(recommendations, "简历筛选时间较长，可考虑主动跟进或优化简历")
+	if strings.HasPrefix(strings.ToLower(lang), "en") {
+		return "en"
 	}
-
-	if len(recommendations) == 0 {
-		recommendations = append(recommendations, "继续保持良好的求职进展")
-	}
-
-	return recommendations
+	return "zh"
 }
 
 // writeSuccessResponse 写入成功响应
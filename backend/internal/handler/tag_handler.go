@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type TagHandler struct {
+	tagService *service.TagService
+}
+
+func NewTagHandler(tagService *service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// List 获取用户的标签
+// GET /api/v1/tags
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	tags, err := h.tagService.List(userID)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get tags", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "tags retrieved successfully", tags)
+}
+
+// Create 创建标签
+// POST /api/v1/tags {"name": "远程", "color": "#10b981"}
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	tag, err := h.tagService.Create(userID, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to create tag")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusCreated, "tag created successfully", tag)
+}
+
+// Update 修改标签名称或颜色
+// PUT /api/v1/tags/{id}
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid tag id", err)
+		return
+	}
+
+	var req model.UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	tag, err := h.tagService.Update(userID, id, &req)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to update tag")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "tag updated successfully", tag)
+}
+
+// Delete 删除标签（同时移除所有申请上的该标签）
+// DELETE /api/v1/tags/{id}
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid tag id", err)
+		return
+	}
+
+	if err := h.tagService.Delete(userID, id); err != nil {
+		h.writeServiceError(w, err, "failed to delete tag")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "tag deleted successfully", nil)
+}
+
+// GetApplicationTags 获取申请的标签
+// GET /api/v1/job-applications/{id}/tags
+func (h *TagHandler) GetApplicationTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	tags, err := h.tagService.GetApplicationTags(userID, id)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to get application tags")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "application tags retrieved successfully", tags)
+}
+
+// SetApplicationTags 整体替换申请的标签
+// PUT /api/v1/job-applications/{id}/tags {"tag_ids": [1, 2]}
+func (h *TagHandler) SetApplicationTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	var req model.SetApplicationTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	tags, err := h.tagService.SetApplicationTags(userID, id, req.TagIDs)
+	if err != nil {
+		h.writeServiceError(w, err, "failed to set application tags")
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "application tags updated successfully", tags)
+}
+
+// writeServiceError 按错误类型映射状态码
+func (h *TagHandler) writeServiceError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid"):
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+	case strings.Contains(err.Error(), "not found or access denied"):
+		h.writeErrorResponse(w, http.StatusNotFound, err.Error(), nil)
+	case strings.Contains(err.Error(), "already exists"):
+		h.writeErrorResponse(w, http.StatusConflict, err.Error(), nil)
+	default:
+		h.writeErrorResponse(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *TagHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *TagHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
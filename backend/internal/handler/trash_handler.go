@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/auth"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// List 列出回收站中的申请
+// GET /api/v1/trash?page=1&page_size=20
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	result, err := h.trashService.List(userID, page, pageSize)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "failed to get trash", err)
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, "trash retrieved successfully", result)
+}
+
+// Restore 恢复单条申请
+// POST /api/v1/trash/{id}/restore
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	affected, err := h.trashService.Restore(userID, []int{id})
+	h.writeOperationResult(w, affected, err, "job application restored successfully")
+}
+
+// BatchRestore 批量恢复申请
+// POST /api/v1/trash/restore {"ids": [1, 2]}
+func (h *TrashHandler) BatchRestore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.TrashIDsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	affected, err := h.trashService.Restore(userID, req.IDs)
+	h.writeOperationResult(w, affected, err, "job applications restored successfully")
+}
+
+// Purge 彻底删除单条申请
+// DELETE /api/v1/trash/{id}
+func (h *TrashHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, "invalid job application id", err)
+		return
+	}
+
+	affected, err := h.trashService.Purge(userID, []int{id})
+	h.writeOperationResult(w, affected, err, "job application purged successfully")
+}
+
+// Empty 清空回收站，带 ids 时只彻底删除指定申请
+// DELETE /api/v1/trash  可选 body {"ids": [1, 2]}
+func (h *TrashHandler) Empty(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorResponse(w, http.StatusUnauthorized, "用户未认证", nil)
+		return
+	}
+
+	var req model.TrashIDsRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body", err)
+			return
+		}
+	}
+
+	if len(req.IDs) > 0 {
+		affected, err := h.trashService.Purge(userID, req.IDs)
+		h.writeOperationResult(w, affected, err, "job applications purged successfully")
+		return
+	}
+	affected, err := h.trashService.Empty(userID)
+	h.writeOperationResult(w, affected, err, "trash emptied successfully")
+}
+
+// writeOperationResult 按错误类型映射状态码
+func (h *TrashHandler) writeOperationResult(w http.ResponseWriter, affected int64, err error, message string) {
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid"):
+			h.writeErrorResponse(w, http.StatusBadRequest, err.Error(), nil)
+		case strings.Contains(err.Error(), "not found or access denied"):
+			h.writeErrorResponse(w, http.StatusNotFound, "job application not found in trash", nil)
+		default:
+			h.writeErrorResponse(w, http.StatusInternalServerError, "trash operation failed", err)
+		}
+		return
+	}
+
+	h.writeSuccessResponse(w, http.StatusOK, message, model.TrashOperationResponse{Affected: affected})
+}
+
+// writeSuccessResponse 写入成功响应
+func (h *TrashHandler) writeSuccessResponse(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+		Data:    data,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeErrorResponse 写入错误响应
+func (h *TrashHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := model.APIResponse{
+		Code:    statusCode,
+		Message: message,
+	}
+
+	if err != nil && statusCode >= 500 {
+		response.Data = map[string]string{"error": err.Error()}
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
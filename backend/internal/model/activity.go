@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// ActivityType 时间线条目类型
+type ActivityType string
+
+const (
+	ActivityStatusChange ActivityType = "status_change" // 状态变更（job_status_history）
+	ActivityFieldEdit    ActivityType = "field_edit"    // 字段编辑
+	ActivityNote         ActivityType = "note"          // 备注
+	ActivityInterview    ActivityType = "interview"     // 面试安排/改期/取消（interview_time 变化时记录）
+	ActivityReminder     ActivityType = "reminder"      // 已触发的提醒（reminder_time 到期时记录）
+	ActivityExport       ActivityType = "export"        // 包含该申请的导出
+	// ActivityDeadlineReminder 已发送的 offer 答复截止提醒（截止前 7/3/1 天）
+	ActivityDeadlineReminder ActivityType = "deadline_reminder"
+)
+
+// IsValid 检查时间线条目类型是否有效
+func (t ActivityType) IsValid() bool {
+	switch t {
+	case ActivityStatusChange, ActivityFieldEdit, ActivityNote, ActivityInterview,
+		ActivityReminder, ActivityExport, ActivityDeadlineReminder:
+		return true
+	}
+	return false
+}
+
+// TimelineItem 统一时间线中的一条记录
+type TimelineItem struct {
+	ID         string                 `json:"id"` // 形如 "status_change:12"，在时间线内唯一
+	Type       ActivityType           `json:"type"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Summary    string                 `json:"summary"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
+
+// ActivityTimelineRequest 时间线查询参数
+type ActivityTimelineRequest struct {
+	Types  []ActivityType `json:"types"`
+	Cursor string         `json:"cursor"`
+	Limit  int            `json:"limit"`
+}
+
+// ActivityTimelineResponse 时间线结果，按时间倒序；NextCursor 为空表示没有更多
+type ActivityTimelineResponse struct {
+	JobApplicationID int            `json:"job_application_id"`
+	Items            []TimelineItem `json:"items"`
+	NextCursor       string         `json:"next_cursor,omitempty"`
+	HasMore          bool           `json:"has_more"`
+}
+
+// CreateNoteRequest 添加备注请求
+type CreateNoteRequest struct {
+	Content string `json:"content" binding:"required"`
+}
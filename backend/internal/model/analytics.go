@@ -0,0 +1,202 @@
+package model
+
+// FunnelStageDefinition 漏斗阶段定义
+type FunnelStageDefinition struct {
+	Key      string              `json:"key"`
+	Name     string              `json:"name"`
+	Statuses []ApplicationStatus `json:"statuses"`
+	Optional bool                `json:"optional"` // 可跳过的阶段（如笔试、三面），只统计实际经过的申请
+}
+
+// FunnelStages 漏斗阶段，按流程先后排序
+var FunnelStages = []FunnelStageDefinition{
+	{Key: "applied", Name: "已投递", Statuses: []ApplicationStatus{StatusApplied}},
+	{Key: "screening", Name: "简历筛选", Statuses: []ApplicationStatus{StatusResumeScreening, StatusResumeScreeningFail}},
+	{Key: "written", Name: "笔试", Statuses: []ApplicationStatus{StatusWrittenTest, StatusWrittenTestPass, StatusWrittenTestFail}, Optional: true},
+	{Key: "first", Name: "一面", Statuses: []ApplicationStatus{StatusFirstInterview, StatusFirstPass, StatusFirstFail}},
+	{Key: "second", Name: "二面", Statuses: []ApplicationStatus{StatusSecondInterview, StatusSecondPass, StatusSecondFail}},
+	{Key: "third", Name: "三面", Statuses: []ApplicationStatus{StatusThirdInterview, StatusThirdPass, StatusThirdFail}, Optional: true},
+	{Key: "hr", Name: "HR面", Statuses: []ApplicationStatus{StatusHRInterview, StatusHRPass, StatusHRFail}},
+	{Key: "offer", Name: "Offer", Statuses: []ApplicationStatus{StatusOfferWaiting, StatusOfferReceived, StatusOfferAccepted}},
+	{Key: "accepted", Name: "接受Offer", Statuses: []ApplicationStatus{StatusOfferAccepted}},
+}
+
+// FunnelStageIndex 返回状态所属漏斗阶段的最大下标，不属于任何阶段时返回 -1
+func FunnelStageIndex(status ApplicationStatus) int {
+	idx := -1
+	for i, stage := range FunnelStages {
+		for _, st := range stage.Statuses {
+			if st == status {
+				idx = i
+			}
+		}
+	}
+	return idx
+}
+
+// FunnelAnalyticsRequest 漏斗分析请求
+type FunnelAnalyticsRequest struct {
+	GroupBy   string `json:"group_by"`   // week / month / tag（按标签时一条申请计入其每个标签）；申请未记录渠道，不支持按渠道分组
+	StartDate string `json:"start_date"` // 投递日期范围 YYYY-MM-DD
+	EndDate   string `json:"end_date"`
+}
+
+// FunnelStageStat 漏斗单阶段统计
+type FunnelStageStat struct {
+	Key            string  `json:"key"`
+	Name           string  `json:"name"`
+	Count          int     `json:"count"`
+	ConversionRate float64 `json:"conversion_rate"` // 相对上一必经阶段的转化率（%）
+	OverallRate    float64 `json:"overall_rate"`    // 相对队列总数的比例（%）
+}
+
+// FunnelCohort 单个队列的漏斗数据
+type FunnelCohort struct {
+	Cohort        string            `json:"cohort"`
+	Total         int               `json:"total"`
+	Stages        []FunnelStageStat `json:"stages"`
+	FurthestStage map[string]int    `json:"furthest_stage"` // 最远到达阶段分布
+}
+
+// FunnelAnalyticsResponse 漏斗分析响应
+type FunnelAnalyticsResponse struct {
+	GroupBy string         `json:"group_by"`
+	Cohorts []FunnelCohort `json:"cohorts"`
+	Overall FunnelCohort   `json:"overall"`
+}
+
+// SurvivalAnalyticsRequest 生存分析请求
+type SurvivalAnalyticsRequest struct {
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	CompanyName string `json:"company_name"`
+	MinSamples  int    `json:"min_samples"` // 公司维度最少样本数
+}
+
+// SurvivalPoint 生存曲线上的一个点（仅在事件时间点变化）
+type SurvivalPoint struct {
+	TimeDays float64 `json:"time_days"`
+	Survival float64 `json:"survival"` // 尚未发生事件的概率
+	AtRisk   int     `json:"at_risk"`
+	Events   int     `json:"events"`
+	Censored int     `json:"censored"`
+}
+
+// SurvivalEstimate Kaplan-Meier 估计结果
+type SurvivalEstimate struct {
+	Metric      string              `json:"metric"` // first_response / offer / rejection / stage:<key>
+	Label       string              `json:"label"`
+	Samples     int                 `json:"samples"`
+	Events      int                 `json:"events"`
+	Censored    int                 `json:"censored"`
+	MedianDays  *float64            `json:"median_days"` // 未达到 50% 时为空
+	Percentiles map[string]*float64 `json:"percentiles"` // p25 / p75 / p90（天）
+	Curve       []SurvivalPoint     `json:"curve,omitempty"`
+}
+
+// SurvivalAnalyticsResponse 生存分析响应
+type SurvivalAnalyticsResponse struct {
+	Outcomes  []SurvivalEstimate            `json:"outcomes"`
+	ByStage   []SurvivalEstimate            `json:"by_stage"`
+	ByCompany map[string][]SurvivalEstimate `json:"by_company"`
+}
+
+// SuccessStageRate 成功率模型中某阶段的基础数据
+type SuccessStageRate struct {
+	StageKey   string  `json:"stage_key"`
+	StageName  string  `json:"stage_name"`
+	Samples    int     `json:"samples"`     // 已有结果的样本数（本人）
+	Offers     int     `json:"offers"`      // 其中最终拿到offer的数量
+	PriorRate  float64 `json:"prior_rate"`  // 先验（全局匿名数据或默认值）
+	SmoothRate float64 `json:"smooth_rate"` // 平滑后的阶段基础成功率
+}
+
+// SuccessScoreResult 成功率评分结果
+type SuccessScoreResult struct {
+	Updated    int                `json:"updated"`
+	UsesGlobal bool               `json:"uses_global"`
+	Stages     []SuccessStageRate `json:"stages"`
+	Scores     map[int]float64    `json:"scores"` // job_application_id -> probability
+}
+
+// SankeyRequest 状态流转桑基图请求
+type SankeyRequest struct {
+	StartDate    string   `json:"start_date"` // 状态变更时间范围 YYYY-MM-DD
+	EndDate      string   `json:"end_date"`
+	CompanyNames []string `json:"company_names"`
+	MinCount     int      `json:"min_count"` // 低于该次数的流转合并到“其他”
+}
+
+// SankeyNode 桑基图节点
+type SankeyNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SankeyLink 桑基图连线
+type SankeyLink struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Value    int    `json:"value"`
+	Backward bool   `json:"backward,omitempty"` // 回退流转，前端可按需过滤以避免成环
+}
+
+// SankeyResponse 桑基图数据
+type SankeyResponse struct {
+	Nodes            []SankeyNode `json:"nodes"`
+	Links            []SankeyLink `json:"links"`
+	TotalTransitions int          `json:"total_transitions"`
+	Collapsed        int          `json:"collapsed"` // 被合并到“其他”的流转次数
+	MinCount         int          `json:"min_count"`
+}
+
+// CompensationAnalyticsRequest 薪酬分析请求
+type CompensationAnalyticsRequest struct {
+	StartDate  string `json:"start_date"` // 投递日期范围 YYYY-MM-DD
+	EndDate    string `json:"end_date"`
+	Currency   string `json:"currency"`    // 仅统计该币种，默认 CNY
+	MinSamples int    `json:"min_samples"` // 公司/岗位分组最少样本数
+}
+
+// CompensationStats 一组薪酬的统计（币种基本单位），月薪/年薪取区间中位值
+type CompensationStats struct {
+	Samples        int     `json:"samples"`
+	MinMonthly     float64 `json:"min_monthly"` // 月薪下限最小值
+	MaxMonthly     float64 `json:"max_monthly"` // 月薪上限最大值
+	P25Monthly     float64 `json:"p25_monthly"`
+	MedianMonthly  float64 `json:"median_monthly"`
+	P75Monthly     float64 `json:"p75_monthly"`
+	AverageMonthly float64 `json:"average_monthly"`
+	MedianAnnual   float64 `json:"median_annual"`
+	AverageAnnual  float64 `json:"average_annual"`
+}
+
+// CompensationGroup 按阶段/公司/岗位类别分组的薪酬统计
+type CompensationGroup struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	CompensationStats
+}
+
+// CompensationBucket 月薪分布区间
+type CompensationBucket struct {
+	Label      string   `json:"label"`
+	MinMonthly float64  `json:"min_monthly"`
+	MaxMonthly *float64 `json:"max_monthly"` // 最后一档为空表示无上限
+	Count      int      `json:"count"`
+}
+
+// CompensationAnalyticsResponse 薪酬分析响应
+type CompensationAnalyticsResponse struct {
+	Currency        string               `json:"currency"`
+	Total           int                  `json:"total"`            // 范围内投递数
+	Parsed          int                  `json:"parsed"`           // 已解析且为所选币种
+	Unparsed        int                  `json:"unparsed"`         // 填写了薪资但无法解析
+	Missing         int                  `json:"missing"`          // 未填写薪资
+	OtherCurrencies map[string]int       `json:"other_currencies"` // 其他币种的记录数
+	Summary         CompensationStats    `json:"summary"`
+	Distribution    []CompensationBucket `json:"distribution"`
+	ByStage         []CompensationGroup  `json:"by_stage"` // 到达各漏斗阶段的投递的薪酬
+	ByCompany       []CompensationGroup  `json:"by_company"`
+	ByRole          []CompensationGroup  `json:"by_role"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// 审计动作
+const (
+	AuditActionCreate       = "create"
+	AuditActionUpdate       = "update"
+	AuditActionDelete       = "delete"  // 软删除（移入回收站）
+	AuditActionRestore      = "restore" // 从回收站恢复
+	AuditActionPurge        = "purge"   // 彻底删除
+	AuditActionBatchCreate  = "batch_create"
+	AuditActionBatchUpdate  = "batch_update"
+	AuditActionBatchDelete  = "batch_delete"
+	AuditActionBatchRestore = "batch_restore"
+	AuditActionBatchPurge   = "batch_purge"
+)
+
+// FieldChange 单个字段的前后值
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditLogEntry 审计日志记录
+type AuditLogEntry struct {
+	ID               int64                  `json:"id"`
+	JobApplicationID int                    `json:"job_application_id"`
+	UserID           uint                   `json:"user_id"`
+	Action           string                 `json:"action"`
+	Changes          map[string]FieldChange `json:"changes"`
+	TransactionID    int64                  `json:"transaction_id"` // 同一次（批量）操作共享
+	CreatedAt        time.Time              `json:"created_at"`
+}
+
+// AuditLogQuery 审计日志查询条件
+type AuditLogQuery struct {
+	JobApplicationID int    `json:"job_application_id"`
+	TransactionID    int64  `json:"transaction_id"` // 同一次（批量）操作的全部记录
+	Action           string `json:"action"`
+	Field            string `json:"field"` // 只返回修改了该字段的记录
+	StartDate        string `json:"start_date"`
+	EndDate          string `json:"end_date"`
+	Page             int    `json:"page"`
+	PageSize         int    `json:"page_size"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// AutocompleteField 支持输入联想的字段
+type AutocompleteField string
+
+const (
+	AutocompleteCompany  AutocompleteField = "company"
+	AutocompletePosition AutocompleteField = "position"
+	AutocompleteLocation AutocompleteField = "location"
+)
+
+// Column 对应的 job_applications 列名，未知字段返回空字符串
+func (f AutocompleteField) Column() string {
+	switch f {
+	case AutocompleteCompany:
+		return "company_name"
+	case AutocompletePosition:
+		return "position_title"
+	case AutocompleteLocation:
+		return "work_location"
+	}
+	return ""
+}
+
+// AutocompleteSuggestion 输入联想候选项。忽略大小写与多余空白后相同的写法归为一组，
+// Value 为组内使用次数最多的写法（规范写法），其余写法列在 Variants 中
+type AutocompleteSuggestion struct {
+	Value      string    `json:"value"`
+	Variants   []string  `json:"variants,omitempty"`
+	Count      int       `json:"count"`      // 组内所有写法的使用次数
+	MatchType  string    `json:"match_type"` // prefix / word_prefix / contains / fuzzy / frequent
+	Score      float64   `json:"score"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
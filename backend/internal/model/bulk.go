@@ -0,0 +1,90 @@
+package model
+
+// BulkMode 批量接口的执行模式
+type BulkMode string
+
+const (
+	BulkModeAtomic     BulkMode = "atomic"      // 任一条失败则全部不生效
+	BulkModeBestEffort BulkMode = "best_effort" // 逐条执行，失败的条目不影响其他条目
+)
+
+// IsValid 检查执行模式是否有效
+func (m BulkMode) IsValid() bool {
+	return m == BulkModeAtomic || m == BulkModeBestEffort
+}
+
+// BulkUpdateItem 批量更新中的单条记录
+type BulkUpdateItem struct {
+	ID int `json:"id"`
+	UpdateJobApplicationRequest
+}
+
+// BulkUpdateRequest 批量更新请求
+type BulkUpdateRequest struct {
+	Mode  BulkMode         `json:"mode"`
+	Items []BulkUpdateItem `json:"items" binding:"required,min=1,max=100"`
+}
+
+// BulkDeleteRequest 批量删除请求
+type BulkDeleteRequest struct {
+	Mode BulkMode `json:"mode"`
+	IDs  []int    `json:"ids" binding:"required,min=1,max=100"`
+}
+
+// BulkItemResult 单条记录的执行结果，Index 对应请求数组中的下标
+type BulkItemResult struct {
+	Index   int             `json:"index"`
+	ID      int             `json:"id,omitempty"`
+	Success bool            `json:"success"`
+	Error   string          `json:"error,omitempty"`
+	Data    *JobApplication `json:"data,omitempty"`
+}
+
+// BulkOperationResponse 批量接口响应
+type BulkOperationResponse struct {
+	Mode        BulkMode         `json:"mode"`
+	Total       int              `json:"total"`
+	Succeeded   int              `json:"succeeded"`
+	Failed      int              `json:"failed"`
+	OperationID int64            `json:"operation_id,omitempty"` // 批量删除可通过 /operations/{id}/undo 撤销
+	Results     []BulkItemResult `json:"results"`
+}
+
+// BulkEditFilter 按条件批量编辑的筛选条件，与导出筛选一致，另支持按阶段筛选
+type BulkEditFilter struct {
+	ExportFilters
+	Stage string `json:"stage,omitempty"` // application/screening/written_test/interviews/final/in_progress/passed/failed
+}
+
+// BulkEditRequest 按条件批量编辑请求。Patch 中非空字段会写入所有匹配的申请，
+// AddTagIDs/RemoveTagIDs 在同一事务内为所有匹配的申请添加/移除标签（可与 Patch 同时使用或单独使用）
+type BulkEditRequest struct {
+	Filter        BulkEditFilter              `json:"filter"`
+	Patch         UpdateJobApplicationRequest `json:"patch"`
+	AddTagIDs     []int                       `json:"add_tag_ids,omitempty"`
+	RemoveTagIDs  []int                       `json:"remove_tag_ids,omitempty"`
+	ExpectedCount *int64                      `json:"expected_count,omitempty"` // 执行时必填，需与预览的匹配数一致
+}
+
+// BulkEditSample 预览中的单条申请及将要发生的字段变化
+type BulkEditSample struct {
+	ID            int                    `json:"id"`
+	CompanyName   string                 `json:"company_name"`
+	PositionTitle string                 `json:"position_title"`
+	Status        ApplicationStatus      `json:"status"`
+	Changes       map[string]FieldChange `json:"changes"`
+}
+
+// BulkEditPreview 批量编辑预览，Fields 为请求中修改的字段（增删标签时包含 tags，其变化为标签名称列表）
+type BulkEditPreview struct {
+	MatchedCount int64            `json:"matched_count"`
+	Fields       []string         `json:"fields"`
+	Sample       []BulkEditSample `json:"sample"`
+}
+
+// BulkEditResult 批量编辑结果，TransactionID 可用于查询本次操作的审计日志
+type BulkEditResult struct {
+	UpdatedCount  int64    `json:"updated_count"`
+	Fields        []string `json:"fields"`
+	TransactionID int64    `json:"transaction_id"`
+}
@@ -0,0 +1,266 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CompanySizes 公司规模可选值
+var CompanySizes = []string{"1-99", "100-499", "500-999", "1000-9999", "10000+"}
+
+// Company 公司实体。同一公司的不同写法（中英文、简称）作为别名归到同一条记录
+type Company struct {
+	ID               int            `json:"id"`
+	UserID           uint           `json:"user_id"`
+	Name             string         `json:"name"` // 规范名称
+	Industry         *string        `json:"industry,omitempty"`
+	Size             *string        `json:"size,omitempty"`
+	Website          *string        `json:"website,omitempty"`
+	Notes            *string        `json:"notes,omitempty"`
+	Rating           *int           `json:"rating,omitempty"` // 1-5
+	Aliases          []CompanyAlias `json:"aliases"`
+	ApplicationCount int            `json:"application_count"` // 关联的申请数（不含回收站）
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// CompanyAlias 公司别名，申请的 company_name 按别名键自动关联到公司
+type CompanyAlias struct {
+	ID    int    `json:"id"`
+	Alias string `json:"alias"`
+}
+
+// CreateCompanyRequest 创建公司请求
+type CreateCompanyRequest struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+	Industry *string  `json:"industry"`
+	Size     *string  `json:"size"`
+	Website  *string  `json:"website"`
+	Notes    *string  `json:"notes"`
+	Rating   *int     `json:"rating"`
+}
+
+// UpdateCompanyRequest 更新公司请求。字符串传空串、rating 传 0 表示清空
+type UpdateCompanyRequest struct {
+	Name     *string `json:"name"`
+	Industry *string `json:"industry"`
+	Size     *string `json:"size"`
+	Website  *string `json:"website"`
+	Notes    *string `json:"notes"`
+	Rating   *int    `json:"rating"`
+}
+
+// AddCompanyAliasRequest 添加别名请求
+type AddCompanyAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+// MergeCompaniesRequest 将 source_ids 对应的公司合并到目标公司
+type MergeCompaniesRequest struct {
+	SourceIDs []int `json:"source_ids"`
+}
+
+// CompanyApplicationSummary 公司详情页中的申请摘要
+type CompanyApplicationSummary struct {
+	ID              int               `json:"id"`
+	CompanyName     string            `json:"company_name"` // 申请上填写的原始名称
+	PositionTitle   string            `json:"position_title"`
+	Status          ApplicationStatus `json:"status"`
+	ApplicationDate string            `json:"application_date"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// CompanyStageReached 到达某漏斗阶段的申请数
+type CompanyStageReached struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// CompanyResponseTime 公司响应时间：从投递到首次状态变化（离开“已投递”）的天数
+type CompanyResponseTime struct {
+	Responded   int      `json:"responded"`   // 已有响应的申请数
+	NoResponse  int      `json:"no_response"` // 仍停留在已投递的申请数
+	AverageDays *float64 `json:"average_days"`
+	MedianDays  *float64 `json:"median_days"`
+	FastestDays *float64 `json:"fastest_days"`
+	SlowestDays *float64 `json:"slowest_days"`
+}
+
+// CompanyDetail 公司聚合页
+type CompanyDetail struct {
+	Company
+	Applications  []CompanyApplicationSummary `json:"applications"`
+	InProgress    int                         `json:"in_progress"`
+	Offers        int                         `json:"offers"`
+	Failed        int                         `json:"failed"`
+	StagesReached []CompanyStageReached       `json:"stages_reached"`
+	ResponseTime  CompanyResponseTime         `json:"response_time"`
+}
+
+var (
+	// companyKeySeparators 生成别名键时去掉的空白与标点
+	companyKeySeparators = regexp.MustCompile(`[\s\-_.,·•()（）【】\[\]&/'"“”‘’]+`)
+	// companyKeySuffix 生成别名键时去掉的公司类型后缀
+	companyKeySuffix = regexp.MustCompile(`(股份有限公司|有限责任公司|有限公司|集团|公司|corporation|limited|ltd|inc)$`)
+)
+
+// CompanyNameKey 公司名称的别名键：小写、去空白标点、去“有限公司/Inc.”等后缀。
+// 必须与数据库函数 company_name_key 保持一致
+func CompanyNameKey(name string) string {
+	lowered := strings.ToLower(strings.Trim(name, " "))
+	key := companyKeySeparators.ReplaceAllString(lowered, "")
+	if stripped := companyKeySuffix.ReplaceAllString(key, ""); stripped != "" {
+		key = stripped
+	}
+	if key == "" {
+		key = lowered
+	}
+	return key
+}
+
+// KnownCompanyAliases 常见公司的中英文名与简称，用于自动归并。首项为推荐的规范名称
+var KnownCompanyAliases = [][]string{
+	{"字节跳动", "ByteDance", "字节", "抖音", "TikTok", "飞书"},
+	{"阿里巴巴", "Alibaba", "阿里", "淘宝", "天猫", "阿里云"},
+	{"蚂蚁集团", "Ant Group", "蚂蚁金服"},
+	{"腾讯", "Tencent", "微信", "WeChat"},
+	{"百度", "Baidu"},
+	{"美团", "Meituan", "美团点评"},
+	{"京东", "JD", "JD.com"},
+	{"拼多多", "PDD", "Temu"},
+	{"网易", "NetEase"},
+	{"快手", "Kuaishou"},
+	{"小红书", "Xiaohongshu", "RED"},
+	{"华为", "Huawei"},
+	{"小米", "Xiaomi"},
+	{"滴滴", "DiDi", "滴滴出行"},
+	{"米哈游", "miHoYo", "HoYoverse"},
+	{"哔哩哔哩", "Bilibili", "B站"},
+	{"携程", "Ctrip", "Trip.com"},
+	{"大疆", "DJI"},
+	{"蔚来", "NIO"},
+	{"理想汽车", "Li Auto", "理想"},
+	{"小鹏汽车", "XPeng", "小鹏"},
+	{"比亚迪", "BYD"},
+	{"商汤", "SenseTime", "商汤科技"},
+	{"微软", "Microsoft"},
+	{"谷歌", "Google", "Alphabet"},
+	{"亚马逊", "Amazon", "AWS"},
+	{"苹果", "Apple"},
+	{"英伟达", "NVIDIA"},
+	{"英特尔", "Intel"},
+	{"Meta", "Facebook"},
+	{"Shopee", "虾皮", "Sea"},
+}
+
+// knownCompanyGroups 别名键到常见公司分组下标的索引
+var knownCompanyGroups = func() map[string]int {
+	index := make(map[string]int)
+	for i, group := range KnownCompanyAliases {
+		for _, alias := range group {
+			index[CompanyNameKey(alias)] = i
+		}
+	}
+	return index
+}()
+
+// KnownCompanyGroup 返回别名键所属的常见公司分组下标，未收录时返回 -1
+func KnownCompanyGroup(key string) int {
+	if group, ok := knownCompanyGroups[key]; ok {
+		return group
+	}
+	return -1
+}
+
+// ClusterCompanyNames 将同一用户的公司名称（名称 -> 使用次数）归并为若干组：
+// 别名键相同、属于同一常见公司，或较短的键（至少 2 个字符）恰好是唯一一组的前缀。
+// 每组按使用次数降序排列，首项即规范名称
+func ClusterCompanyNames(counts map[string]int) [][]string {
+	type cluster struct {
+		keys  []string
+		names []string
+	}
+
+	// 1. 按别名键与常见公司分组
+	clusters := make(map[string]*cluster)
+	for name := range counts {
+		key := CompanyNameKey(name)
+		if key == "" {
+			continue
+		}
+		id := "key:" + key
+		if group := KnownCompanyGroup(key); group >= 0 {
+			id = "known:" + KnownCompanyAliases[group][0]
+		}
+		c, ok := clusters[id]
+		if !ok {
+			c = &cluster{}
+			clusters[id] = c
+		}
+		c.keys = append(c.keys, key)
+		c.names = append(c.names, name)
+	}
+
+	ids := make([]string, 0, len(clusters))
+	for id := range clusters {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// 2. 前缀归并：如“影石”并入唯一以其开头的“影石创新”；有多个候选时不归并
+	for _, id := range ids {
+		c := clusters[id]
+		if c == nil || !strings.HasPrefix(id, "key:") {
+			continue
+		}
+		key := strings.TrimPrefix(id, "key:")
+		if len([]rune(key)) < 2 {
+			continue
+		}
+		target := ""
+		for _, otherID := range ids {
+			other := clusters[otherID]
+			if otherID == id || other == nil {
+				continue
+			}
+			for _, otherKey := range other.keys {
+				if otherKey != key && strings.HasPrefix(otherKey, key) {
+					if target != "" && target != otherID {
+						target = "-"
+					} else {
+						target = otherID
+					}
+					break
+				}
+			}
+		}
+		if target == "" || target == "-" {
+			continue
+		}
+		clusters[target].keys = append(clusters[target].keys, c.keys...)
+		clusters[target].names = append(clusters[target].names, c.names...)
+		clusters[id] = nil
+	}
+
+	var groups [][]string
+	for _, id := range ids {
+		c := clusters[id]
+		if c == nil {
+			continue
+		}
+		names := c.names
+		sort.Slice(names, func(i, j int) bool {
+			if counts[names[i]] != counts[names[j]] {
+				return counts[names[i]] > counts[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		groups = append(groups, names)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
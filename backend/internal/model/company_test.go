@@ -0,0 +1,64 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompanyNameKey(t *testing.T) {
+	cases := map[string]string{
+		"字节跳动":                  "字节跳动",
+		" ByteDance ":           "bytedance",
+		"Byte Dance Ltd.":       "bytedance",
+		"北京字节跳动科技有限公司":          "北京字节跳动科技",
+		"阿里巴巴（中国）有限公司":          "阿里巴巴中国",
+		"阿里巴巴集团":                "阿里巴巴",
+		"Microsoft Corporation": "microsoft",
+		"JD.com":                "jdcom",
+		"公司":                    "公司",
+		"...":                   "...",
+	}
+	for input, want := range cases {
+		if got := CompanyNameKey(input); got != want {
+			t.Fatalf("CompanyNameKey(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestClusterCompanyNames(t *testing.T) {
+	groups := ClusterCompanyNames(map[string]int{
+		"字节跳动":      3,
+		"ByteDance": 1,
+		"字节":        2,
+		"bytedance": 1,
+		"蚂蚁":        1,
+		"蚂蚁金服":      2,
+		"影石":        1,
+		"影石创新":      1,
+		"腾讯音乐":      1,
+		"腾讯云":       1,
+		"未来科技":      1,
+		"未来科技有限公司":  4,
+	})
+
+	want := [][]string{
+		{"腾讯云"},
+		{"腾讯音乐"},
+		{"字节跳动", "字节", "ByteDance", "bytedance"},
+		{"未来科技有限公司", "未来科技"},
+		{"蚂蚁金服", "蚂蚁"},
+		{"影石", "影石创新"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d groups, got %d: %v", len(want), len(groups), groups)
+	}
+	byFirst := make(map[string][]string)
+	for _, group := range groups {
+		byFirst[group[0]] = group
+	}
+	for _, group := range want {
+		if !reflect.DeepEqual(byFirst[group[0]], group) {
+			t.Fatalf("expected group %v, got %v (all: %v)", group, byFirst[group[0]], groups)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContactRole 联系人角色
+type ContactRole string
+
+const (
+	ContactRecruiter     ContactRole = "recruiter"      // 猎头/招聘专员
+	ContactHR            ContactRole = "hr"             // HR
+	ContactHiringManager ContactRole = "hiring_manager" // 用人经理
+	ContactInterviewer   ContactRole = "interviewer"    // 面试官
+	ContactReferrer      ContactRole = "referrer"       // 内推人
+	ContactOther         ContactRole = "other"
+)
+
+// IsValid 检查联系人角色是否有效
+func (r ContactRole) IsValid() bool {
+	_, ok := ContactRoleNames[r]
+	return ok
+}
+
+// ContactRoleNames 联系人角色显示名称
+var ContactRoleNames = map[ContactRole]string{
+	ContactRecruiter:     "猎头/招聘",
+	ContactHR:            "HR",
+	ContactHiringManager: "用人经理",
+	ContactInterviewer:   "面试官",
+	ContactReferrer:      "内推人",
+	ContactOther:         "其他",
+}
+
+// ContactChannelType 联系方式类型
+type ContactChannelType string
+
+const (
+	ChannelPhone    ContactChannelType = "phone"
+	ChannelEmail    ContactChannelType = "email"
+	ChannelWeChat   ContactChannelType = "wechat"
+	ChannelLinkedIn ContactChannelType = "linkedin"
+	ChannelOther    ContactChannelType = "other"
+)
+
+// IsValid 检查联系方式类型是否有效
+func (t ContactChannelType) IsValid() bool {
+	switch t {
+	case ChannelPhone, ChannelEmail, ChannelWeChat, ChannelLinkedIn, ChannelOther:
+		return true
+	}
+	return false
+}
+
+var (
+	// contactEmailPattern 宽松的邮箱格式校验
+	contactEmailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+	// contactPhoneSeparators 电话号码中去掉的空白与分隔符
+	contactPhoneSeparators = regexp.MustCompile(`[\s\-().（）]+`)
+	// contactPhonePattern 去掉分隔符后的电话号码，可带国际区号与分机号
+	contactPhonePattern = regexp.MustCompile(`^\+?[0-9]{3,20}(#[0-9]{1,6})?$`)
+)
+
+// ContactChannel 一种联系方式
+type ContactChannel struct {
+	Type  ContactChannelType `json:"type"`
+	Value string             `json:"value"`
+}
+
+// NormalizeContactChannel 校验并规范化联系方式：邮箱转小写，电话去掉空格与分隔符
+func NormalizeContactChannel(channel ContactChannel) (ContactChannel, error) {
+	if !channel.Type.IsValid() {
+		return channel, fmt.Errorf("invalid channel type: %s", channel.Type)
+	}
+	value := strings.TrimSpace(channel.Value)
+	switch channel.Type {
+	case ChannelEmail:
+		value = strings.ToLower(value)
+		if !contactEmailPattern.MatchString(value) {
+			return channel, fmt.Errorf("invalid email: %s", channel.Value)
+		}
+	case ChannelPhone:
+		value = contactPhoneSeparators.ReplaceAllString(value, "")
+		if !contactPhonePattern.MatchString(value) {
+			return channel, fmt.Errorf("invalid phone: %s", channel.Value)
+		}
+	}
+	if value == "" {
+		return channel, fmt.Errorf("invalid %s: value is required", channel.Type)
+	}
+	if utf8.RuneCountInString(value) > 255 {
+		return channel, fmt.Errorf("invalid %s: at most 255 characters", channel.Type)
+	}
+	return ContactChannel{Type: channel.Type, Value: value}, nil
+}
+
+// Key 去重键，同类型同值（不区分大小写）视为同一联系方式
+func (c ContactChannel) Key() string {
+	return string(c.Type) + ":" + strings.ToLower(c.Value)
+}
+
+// ContactChannels 联系方式列表
+type ContactChannels []ContactChannel
+
+// Value 实现 JSONB 字段的数据库写入（以文本传递，供 ::jsonb 使用）
+func (c ContactChannels) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]ContactChannel(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 JSONB 字段的数据库读取
+func (c *ContactChannels) Scan(value interface{}) error {
+	if value == nil {
+		*c = ContactChannels{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into ContactChannels", value)
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// InteractionType 沟通记录类型
+type InteractionType string
+
+const (
+	InteractionCall    InteractionType = "call"
+	InteractionEmail   InteractionType = "email"
+	InteractionMessage InteractionType = "message" // 微信、短信、站内信等
+	InteractionMeeting InteractionType = "meeting"
+	InteractionOther   InteractionType = "other"
+)
+
+// IsValid 检查沟通记录类型是否有效
+func (t InteractionType) IsValid() bool {
+	switch t {
+	case InteractionCall, InteractionEmail, InteractionMessage, InteractionMeeting, InteractionOther:
+		return true
+	}
+	return false
+}
+
+// InteractionDirection 沟通方向
+type InteractionDirection string
+
+const (
+	InteractionInbound  InteractionDirection = "inbound"  // 对方联系我
+	InteractionOutbound InteractionDirection = "outbound" // 我联系对方
+)
+
+// Contact 联系人：猎头、HR、内推人等，可关联多个申请
+type Contact struct {
+	ID               int                  `json:"id"`
+	UserID           uint                 `json:"user_id"`
+	Name             string               `json:"name"`
+	Role             ContactRole          `json:"role"`
+	CompanyID        *int                 `json:"company_id,omitempty"`
+	CompanyName      *string              `json:"company_name,omitempty"` // 关联公司的规范名称
+	Channels         ContactChannels      `json:"channels"`
+	Notes            *string              `json:"notes,omitempty"`
+	LastContactedAt  *time.Time           `json:"last_contacted_at,omitempty"` // 最近一次沟通时间，由沟通记录维护
+	FollowUpAt       *time.Time           `json:"follow_up_at,omitempty"`      // 下次跟进时间
+	FollowUpNote     *string              `json:"follow_up_note,omitempty"`
+	FollowUpDue      bool                 `json:"follow_up_due"` // 跟进时间已到
+	Source           string               `json:"source"`        // manual 或 hr_fields（由申请的 HR 字段迁移或写入时自动创建）
+	ApplicationCount int                  `json:"application_count"`
+	Applications     []ContactApplication `json:"applications,omitempty"` // 仅详情返回
+	Interactions     []ContactInteraction `json:"interactions,omitempty"` // 仅详情返回
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// ContactApplication 联系人关联的申请摘要
+type ContactApplication struct {
+	ID              int               `json:"id"`
+	CompanyName     string            `json:"company_name"`
+	PositionTitle   string            `json:"position_title"`
+	Status          ApplicationStatus `json:"status"`
+	ApplicationDate string            `json:"application_date"`
+}
+
+// ContactInteraction 一次沟通记录
+type ContactInteraction struct {
+	ID               int                  `json:"id"`
+	ContactID        int                  `json:"contact_id"`
+	JobApplicationID *int                 `json:"job_application_id,omitempty"` // 沟通涉及的申请
+	Type             InteractionType      `json:"type"`
+	Direction        InteractionDirection `json:"direction"`
+	Summary          string               `json:"summary"`
+	OccurredAt       time.Time            `json:"occurred_at"`
+	CreatedAt        time.Time            `json:"created_at"`
+}
+
+// ContactListRequest 联系人列表筛选
+type ContactListRequest struct {
+	Q                string      `json:"q"` // 按姓名或联系方式模糊匹配
+	Role             ContactRole `json:"role"`
+	CompanyID        int         `json:"company_id"`
+	JobApplicationID int         `json:"job_application_id"`
+}
+
+// CreateContactRequest 创建联系人请求。Company 为公司名称，按别名关联到公司
+type CreateContactRequest struct {
+	Name              string           `json:"name"`
+	Role              ContactRole      `json:"role"` // 默认 other
+	Company           *string          `json:"company"`
+	Channels          []ContactChannel `json:"channels"`
+	Notes             *string          `json:"notes"`
+	FollowUpAt        *time.Time       `json:"follow_up_at"`
+	FollowUpNote      *string          `json:"follow_up_note"`
+	JobApplicationIDs []int            `json:"job_application_ids"`
+}
+
+// UpdateContactRequest 更新联系人请求。字符串传空串表示清空，Channels 非 nil 时整体替换，
+// ClearFollowUp 为 true 时清除跟进提醒
+type UpdateContactRequest struct {
+	Name          *string          `json:"name"`
+	Role          *ContactRole     `json:"role"`
+	Company       *string          `json:"company"`
+	Channels      []ContactChannel `json:"channels"`
+	Notes         *string          `json:"notes"`
+	FollowUpAt    *time.Time       `json:"follow_up_at"`
+	FollowUpNote  *string          `json:"follow_up_note"`
+	ClearFollowUp bool             `json:"clear_follow_up"`
+}
+
+// SetContactApplicationsRequest 替换联系人关联的申请
+type SetContactApplicationsRequest struct {
+	JobApplicationIDs []int `json:"job_application_ids"`
+}
+
+// CreateContactInteractionRequest 添加沟通记录请求。未指定 NextFollowUpAt 时，
+// 已到期的跟进提醒视为已完成并清除
+type CreateContactInteractionRequest struct {
+	Type             InteractionType      `json:"type"`
+	Direction        InteractionDirection `json:"direction"` // 默认 outbound
+	Summary          string               `json:"summary"`
+	OccurredAt       *time.Time           `json:"occurred_at"` // 默认当前时间
+	JobApplicationID *int                 `json:"job_application_id"`
+	NextFollowUpAt   *time.Time           `json:"next_follow_up_at"`
+	NextFollowUpNote *string              `json:"next_follow_up_note"`
+}
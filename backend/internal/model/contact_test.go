@@ -0,0 +1,50 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeContactChannel(t *testing.T) {
+	cases := []struct {
+		in   ContactChannel
+		want string
+		ok   bool
+	}{
+		{ContactChannel{Type: ChannelEmail, Value: "  Wang.HR@Example.com "}, "wang.hr@example.com", true},
+		{ContactChannel{Type: ChannelEmail, Value: "wang.hr"}, "", false},
+		{ContactChannel{Type: ChannelPhone, Value: "+86 138-0013-8000"}, "+8613800138000", true},
+		{ContactChannel{Type: ChannelPhone, Value: "(010) 6275 1234#802"}, "01062751234#802", true},
+		{ContactChannel{Type: ChannelPhone, Value: "call me"}, "", false},
+		{ContactChannel{Type: ChannelWeChat, Value: " wang_hr "}, "wang_hr", true},
+		{ContactChannel{Type: ChannelWeChat, Value: "   "}, "", false},
+		{ContactChannel{Type: "fax", Value: "123"}, "", false},
+	}
+	for _, c := range cases {
+		got, err := NormalizeContactChannel(c.in)
+		if (err == nil) != c.ok {
+			t.Errorf("NormalizeContactChannel(%+v) error = %v, want ok=%v", c.in, err, c.ok)
+			continue
+		}
+		if err != nil {
+			if !strings.HasPrefix(err.Error(), "invalid") {
+				t.Errorf("NormalizeContactChannel(%+v) error %q should start with invalid", c.in, err)
+			}
+			continue
+		}
+		if got.Value != c.want || got.Type != c.in.Type {
+			t.Errorf("NormalizeContactChannel(%+v) = %+v, want value %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestContactChannelKey(t *testing.T) {
+	a := ContactChannel{Type: ChannelWeChat, Value: "Wang_HR"}
+	b := ContactChannel{Type: ChannelWeChat, Value: "wang_hr"}
+	if a.Key() != b.Key() {
+		t.Fatalf("expected case-insensitive key, got %q and %q", a.Key(), b.Key())
+	}
+	if a.Key() == (ContactChannel{Type: ChannelOther, Value: "wang_hr"}).Key() {
+		t.Fatalf("expected channel type to be part of the key")
+	}
+}
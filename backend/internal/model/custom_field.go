@@ -0,0 +1,91 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CustomFieldType 自定义字段类型
+type CustomFieldType string
+
+const (
+	CustomFieldText   CustomFieldType = "text"
+	CustomFieldNumber CustomFieldType = "number"
+	CustomFieldDate   CustomFieldType = "date" // YYYY-MM-DD
+	CustomFieldEnum   CustomFieldType = "enum" // 取值限定在 options 中
+	CustomFieldURL    CustomFieldType = "url"  // http/https 链接
+)
+
+// IsValid 检查字段类型是否有效
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldEnum, CustomFieldURL:
+		return true
+	}
+	return false
+}
+
+// CustomFieldPrefix 列表排序/筛选及导出字段中自定义字段的前缀，如 cf.team_name
+const CustomFieldPrefix = "cf."
+
+// CustomFieldDefinition 用户自定义字段定义
+type CustomFieldDefinition struct {
+	ID        int             `json:"id"`
+	UserID    uint            `json:"user_id"`
+	Key       string          `json:"key"` // 存储在 custom_fields JSONB 中的键，创建后不可修改
+	Label     string          `json:"label"`
+	FieldType CustomFieldType `json:"field_type"`
+	Options   []string        `json:"options,omitempty"` // enum 可选值
+	Required  bool            `json:"required"`
+	SortOrder int             `json:"sort_order"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CreateCustomFieldRequest 创建自定义字段请求
+type CreateCustomFieldRequest struct {
+	Key       string          `json:"key" binding:"required"`
+	Label     string          `json:"label" binding:"required"`
+	FieldType CustomFieldType `json:"field_type" binding:"required"`
+	Options   []string        `json:"options"`
+	Required  bool            `json:"required"`
+	SortOrder int             `json:"sort_order"`
+}
+
+// UpdateCustomFieldRequest 更新自定义字段请求（键和类型不可修改）
+type UpdateCustomFieldRequest struct {
+	Label     *string  `json:"label"`
+	Options   []string `json:"options"`
+	Required  *bool    `json:"required"`
+	SortOrder *int     `json:"sort_order"`
+}
+
+// CustomFieldValues 投递记录上的自定义字段值，键为字段定义的 key
+type CustomFieldValues map[string]interface{}
+
+// Value 实现 JSONB 字段的数据库写入（以文本传递，供 ::jsonb 使用）
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 JSONB 字段的数据库读取
+func (v *CustomFieldValues) Scan(value interface{}) error {
+	if value == nil {
+		*v = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into CustomFieldValues", value)
+	}
+	return json.Unmarshal(bytes, v)
+}
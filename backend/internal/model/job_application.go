@@ -73,48 +73,54 @@ func (s *ApplicationStatus) Scan(value interface{}) error {
 	return fmt.Errorf("cannot scan %T into ApplicationStatus", value)
 }
 
+// allStatuses 全部有效状态，按流程顺序排列
+var allStatuses = []ApplicationStatus{
+	// 基础状态
+	StatusApplied,
+	StatusResumeScreening,
+	StatusResumeScreeningFail,
+	
+	// 笔试状态
+	StatusWrittenTest,
+	StatusWrittenTestPass,
+	StatusWrittenTestFail,
+	
+	// 一面状态
+	StatusFirstInterview,
+	StatusFirstPass,
+	StatusFirstFail,
+	
+	// 二面状态
+	StatusSecondInterview,
+	StatusSecondPass,
+	StatusSecondFail,
+	
+	// 三面状态
+	StatusThirdInterview,
+	StatusThirdPass,
+	StatusThirdFail,
+	
+	// HR面状态
+	StatusHRInterview,
+	StatusHRPass,
+	StatusHRFail,
+	
+	// 最终状态
+	StatusOfferWaiting,
+	StatusRejected,
+	StatusOfferReceived,
+	StatusOfferAccepted,
+	StatusProcessFinished,
+}
+
+// AllStatuses 返回全部有效状态（按流程顺序）
+func AllStatuses() []ApplicationStatus {
+	return append([]ApplicationStatus(nil), allStatuses...)
+}
+
 // IsValid 检查状态是否有效
 func (s ApplicationStatus) IsValid() bool {
-	validStatuses := []ApplicationStatus{
-		// 基础状态
-		StatusApplied,
-		StatusResumeScreening,
-		StatusResumeScreeningFail,
-		
-		// 笔试状态
-		StatusWrittenTest,
-		StatusWrittenTestPass,
-		StatusWrittenTestFail,
-		
-		// 一面状态
-		StatusFirstInterview,
-		StatusFirstPass,
-		StatusFirstFail,
-		
-		// 二面状态
-		StatusSecondInterview,
-		StatusSecondPass,
-		StatusSecondFail,
-		
-		// 三面状态
-		StatusThirdInterview,
-		StatusThirdPass,
-		StatusThirdFail,
-		
-		// HR面状态
-		StatusHRInterview,
-		StatusHRPass,
-		StatusHRFail,
-		
-		// 最终状态
-		StatusOfferWaiting,
-		StatusRejected,
-		StatusOfferReceived,
-		StatusOfferAccepted,
-		StatusProcessFinished,
-	}
-	
-	for _, validStatus := range validStatuses {
+	for _, validStatus := range allStatuses {
 		if s == validStatus {
 			return true
 		}
@@ -122,6 +128,39 @@ func (s ApplicationStatus) IsValid() bool {
 	return false
 }
 
+// ApplicationStages 列表筛选支持的阶段，顺序即展示顺序
+var ApplicationStages = []string{
+	"application", "screening", "written_test", "interviews", "final",
+	"in_progress", "passed", "failed",
+}
+
+// StatusesForStage 阶段与状态的对应关系，未知阶段返回空列表
+func StatusesForStage(stage string) []string {
+	stageMap := map[string][]string{
+		"application": {"已投递"},
+		"screening": {"简历筛选中", "简历筛选未通过"},
+		"written_test": {"笔试中", "笔试通过", "笔试未通过"},
+		"interviews": {
+			"一面中", "一面通过", "一面未通过",
+			"二面中", "二面通过", "二面未通过",
+			"三面中", "三面通过", "三面未通过",
+			"HR面中", "HR面通过", "HR面未通过",
+		},
+		"final": {
+			"待发offer", "已收到offer", "已接受offer",
+			"已拒绝", "流程结束",
+		},
+		"in_progress": {"已投递", "简历筛选中", "笔试中", "一面中", "二面中", "三面中", "HR面中"},
+		"passed": {"笔试通过", "一面通过", "二面通过", "三面通过", "HR面通过", "待发offer", "已收到offer", "已接受offer", "流程结束"},
+		"failed": {"简历筛选未通过", "笔试未通过", "一面未通过", "二面未通过", "三面未通过", "HR面未通过", "已拒绝"},
+	}
+
+	if statuses, exists := stageMap[stage]; exists {
+		return statuses
+	}
+	return []string{}
+}
+
 // IsFailedStatus 检查是否为失败状态
 func (s ApplicationStatus) IsFailedStatus() bool {
 	failedStatuses := []ApplicationStatus{
@@ -189,11 +228,21 @@ type JobApplication struct {
 	ID                   int               `json:"id" db:"id"`
 	UserID               uint              `json:"user_id" db:"user_id"`
 	CompanyName          string            `json:"company_name" db:"company_name"`
+	CompanyID            *int              `json:"company_id,omitempty" db:"company_id"` // 由触发器按公司别名自动关联
 	PositionTitle        string            `json:"position_title" db:"position_title"`
+	RoleFamily           *string           `json:"role_family,omitempty" db:"role_family"` // 由职位名称归一化得到的岗位类别
+	RoleLevel            *string           `json:"role_level,omitempty" db:"role_level"`   // 由职位名称归一化得到的职级
+	TechStack            StringList        `json:"tech_stack,omitempty" db:"tech_stack"`
 	ApplicationDate      string            `json:"application_date" db:"application_date"`
 	Status               ApplicationStatus `json:"status" db:"status"`
 	JobDescription       *string           `json:"job_description" db:"job_description"`
 	SalaryRange          *string           `json:"salary_range" db:"salary_range"`
+	SalaryMinMonthly     *float64          `json:"salary_min_monthly,omitempty" db:"salary_min_monthly"` // 由 salary_range 解析，币种基本单位
+	SalaryMaxMonthly     *float64          `json:"salary_max_monthly,omitempty" db:"salary_max_monthly"`
+	SalaryMonths         *float64          `json:"salary_months,omitempty" db:"salary_months"` // 每年薪数
+	SalaryAnnualMin      *float64          `json:"salary_annual_min,omitempty" db:"salary_annual_min"`
+	SalaryAnnualMax      *float64          `json:"salary_annual_max,omitempty" db:"salary_annual_max"`
+	SalaryCurrency       *string           `json:"salary_currency,omitempty" db:"salary_currency"`
 	WorkLocation         *string           `json:"work_location" db:"work_location"`
 	ContactInfo          *string           `json:"contact_info" db:"contact_info"`
 	Notes                *string           `json:"notes" db:"notes"`
@@ -201,6 +250,7 @@ type JobApplication struct {
 	ReminderTime         *time.Time        `json:"reminder_time" db:"reminder_time"`
 	ReminderEnabled      bool              `json:"reminder_enabled" db:"reminder_enabled"`
 	FollowUpDate         *string           `json:"follow_up_date" db:"follow_up_date"`
+	DecisionDeadline     *string           `json:"decision_deadline,omitempty" db:"decision_deadline"` // offer 答复截止日期 YYYY-MM-DD
 	HRName               *string           `json:"hr_name" db:"hr_name"`
 	HRPhone              *string           `json:"hr_phone" db:"hr_phone"`
 	HREmail              *string           `json:"hr_email" db:"hr_email"`
@@ -214,6 +264,18 @@ type JobApplication struct {
 	LastStatusChange     *time.Time        `json:"last_status_change,omitempty" db:"last_status_change"`
 	StatusDurationStats  *DurationStats    `json:"status_duration_stats,omitempty" db:"status_duration_stats"`
 	StatusVersion        *int              `json:"status_version,omitempty" db:"status_version"`
+	SuccessProbability   *float64          `json:"success_probability,omitempty" db:"-"` // 来自 status_duration_stats.analytics
+	Tags                 []ApplicationTag  `json:"tags,omitempty" db:"-"`
+	CustomFields         CustomFieldValues `json:"custom_fields,omitempty" db:"custom_fields"`
+	SearchScore          *float64          `json:"search_score,omitempty" db:"-"` // 仅搜索结果：相关度
+	Highlights           []SearchHighlight `json:"highlights,omitempty" db:"-"`   // 仅搜索结果：命中字段片段
+}
+
+// SearchHighlight 搜索命中片段。Snippet 已做 HTML 转义，命中部分以 <mark></mark> 包裹
+type SearchHighlight struct {
+	Field   string `json:"field"`           // 命中字段，如 company_name、job_description
+	Snippet string `json:"snippet"`
+	Fuzzy   bool   `json:"fuzzy,omitempty"` // 模糊（容错）命中
 }
 
 // CreateJobApplicationRequest 创建投递记录请求
@@ -236,6 +298,7 @@ type CreateJobApplicationRequest struct {
 	HREmail           *string           `json:"hr_email"`
 	InterviewLocation *string           `json:"interview_location"`
 	InterviewType     *string           `json:"interview_type"`
+	CustomFields      CustomFieldValues `json:"custom_fields"`
 }
 
 // UpdateJobApplicationRequest 更新投递记录请求
@@ -258,6 +321,7 @@ type UpdateJobApplicationRequest struct {
 	HREmail           *string            `json:"hr_email"`
 	InterviewLocation *string            `json:"interview_location"`
 	InterviewType     *string            `json:"interview_type"`
+	CustomFields      CustomFieldValues  `json:"custom_fields"` // 与现有值合并，值为 null 时删除该字段
 }
 
 // APIResponse 通用API响应格式
@@ -274,6 +338,13 @@ type PaginationRequest struct {
 	SortBy   string `json:"sort_by" form:"sort_by"`   // 排序字段，默认application_date
 	SortDir  string `json:"sort_dir" form:"sort_dir"` // 排序方向，ASC或DESC，默认DESC
 	Status   *ApplicationStatus `json:"status" form:"status"` // 状态筛选，可选
+	TagIDs   []int  `json:"tag_ids" form:"tag_ids"`   // 标签筛选，命中任一标签即可
+	CustomFilters map[string]string `json:"custom_filters,omitempty"` // 自定义字段筛选，键为字段 key
+	StartDate string `json:"start_date,omitempty"` // 投递日期下限 YYYY-MM-DD，可选
+	EndDate   string `json:"end_date,omitempty"`   // 投递日期上限 YYYY-MM-DD，可选
+	Query     string `json:"query,omitempty"`      // 查询语句，如 status:一面中 company:"字节" salary>=25k
+	SalaryMin *float64 `json:"salary_min,omitempty"` // 月薪筛选下限（K），与月薪区间有交集即命中
+	SalaryMax *float64 `json:"salary_max,omitempty"` // 月薪筛选上限（K）
 }
 
 // PaginationResponse 分页响应结构
@@ -306,6 +377,17 @@ func (p *PaginationRequest) ValidateAndSetDefaults() {
 	}
 }
 
+// SuccessProbabilitySortExpr 按成功率排序时使用的表达式（存储在 status_duration_stats 中）
+const SuccessProbabilitySortExpr = "(status_duration_stats->'analytics'->>'success_probability')::float8"
+
+// SortExpression 将排序字段转换为 ORDER BY 表达式，调用方需先完成白名单校验
+func (p *PaginationRequest) SortExpression() string {
+	if p.SortBy == "success_probability" {
+		return SuccessProbabilitySortExpr + " " + p.SortDir + " NULLS LAST"
+	}
+	return p.SortBy + " " + p.SortDir
+}
+
 // GetOffset 计算偏移量
 func (p *PaginationRequest) GetOffset() int {
 	return (p.Page - 1) * p.PageSize
@@ -353,6 +435,7 @@ type BatchStatusUpdate struct {
 
 // BatchCreateRequest 批量创建请求结构
 type BatchCreateRequest struct {
+	Mode         BulkMode                      `json:"mode"` // atomic（默认）或 best_effort
 	Applications []CreateJobApplicationRequest `json:"applications" binding:"required,min=1,max=50"`
 }
 
@@ -461,10 +544,50 @@ type StatusAnalyticsResponse struct {
 type StatusTrend struct {
 	Date        string `json:"date"`
 	Status      string `json:"status"`
+	Group       string `json:"group,omitempty"` // 分组维度的取值（状态/阶段/公司/岗位类别/职级）
 	Count       int    `json:"count"`
 	SuccessRate float64 `json:"success_rate,omitempty"`
 }
 
+// 趋势聚合粒度
+const (
+	TrendGranularityDay   = "day"
+	TrendGranularityWeek  = "week"
+	TrendGranularityMonth = "month"
+)
+
+// 趋势分组维度
+const (
+	TrendGroupByStatus  = "status"
+	TrendGroupByStage   = "stage"
+	TrendGroupByCompany = "company"
+	TrendGroupByRole    = "role"  // 归一化的岗位类别
+	TrendGroupByLevel   = "level" // 归一化的职级
+)
+
+// StatusTrendRequest 状态趋势查询参数；StartDate/EndDate 为用户时区下的日期，未指定时取最近 Days 天
+type StatusTrendRequest struct {
+	Days        int    `json:"days"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Granularity string `json:"granularity"`
+	Timezone    string `json:"timezone"`
+	GroupBy     string `json:"group_by"`
+}
+
+// StatusTrendResponse 状态趋势结果，每个时间桶 × 分组都有一条记录（无数据时 count 为 0）
+type StatusTrendResponse struct {
+	Days        int           `json:"days"`
+	StartDate   string        `json:"start_date"`
+	EndDate     string        `json:"end_date"`
+	Granularity string        `json:"granularity"`
+	Timezone    string        `json:"timezone"`
+	GroupBy     string        `json:"group_by"`
+	Buckets     []string      `json:"buckets"`
+	Groups      []string      `json:"groups"`
+	Trends      []StatusTrend `json:"trends"`
+}
+
 // StageStatistics 阶段统计数据
 type StageStatistics struct {
 	StageName          string  `json:"stage_name"`
@@ -503,6 +626,7 @@ type ExportRequest struct {
 	Format  string        `json:"format" binding:"required"`        // 导出格式：xlsx, csv
 	Fields  []string      `json:"fields"`                           // 导出字段列表
 	Filters ExportFilters `json:"filters"`                          // 筛选条件
+	ViewID  *int          `json:"view_id,omitempty"`                // 保存的视图ID，指定时以视图的筛选条件替代 filters
 	Options ExportOptions `json:"options"`                          // 导出选项
 }
 
@@ -512,6 +636,12 @@ type ExportFilters struct {
 	DateRange   *DateRange          `json:"date_range,omitempty"`   // 日期范围
 	CompanyNames []string           `json:"company_names,omitempty"` // 公司名称筛选
 	Keywords    string             `json:"keywords,omitempty"`      // 关键词搜索
+	Query       string             `json:"query,omitempty"`         // 查询语句（见 querylang 包）
+	Search      string             `json:"search,omitempty"`        // 搜索视图的关键词，匹配规则与全文搜索一致
+	TagIDs      []int              `json:"tag_ids,omitempty"`       // 标签筛选，命中任一标签即可
+	CustomFilters map[string]string `json:"custom_filters,omitempty"` // 自定义字段筛选，键为字段 key
+	SalaryMin   *float64           `json:"salary_min,omitempty"`    // 月薪筛选下限（K）
+	SalaryMax   *float64           `json:"salary_max,omitempty"`    // 月薪筛选上限（K）
 }
 
 // DateRange 日期范围结构
@@ -717,6 +847,13 @@ func (req *ExportRequest) ValidateExportRequest() error {
 		}
 	}
 
+	// 验证标签筛选
+	for _, tagID := range req.Filters.TagIDs {
+		if tagID <= 0 {
+			return fmt.Errorf("无效的标签ID: %d", tagID)
+		}
+	}
+
 	return nil
 }
 
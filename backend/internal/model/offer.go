@@ -0,0 +1,311 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OfferDecisionStatus offer 决策状态
+type OfferDecisionStatus string
+
+const (
+	OfferPending     OfferDecisionStatus = "pending"     // 待决定
+	OfferNegotiating OfferDecisionStatus = "negotiating" // 谈判中
+	OfferAccepted    OfferDecisionStatus = "accepted"    // 已接受
+	OfferDeclined    OfferDecisionStatus = "declined"    // 已拒绝
+	OfferExpired     OfferDecisionStatus = "expired"     // 已过期/被撤回
+)
+
+// IsValid 检查决策状态是否有效
+func (s OfferDecisionStatus) IsValid() bool {
+	switch s {
+	case OfferPending, OfferNegotiating, OfferAccepted, OfferDeclined, OfferExpired:
+		return true
+	}
+	return false
+}
+
+// OfferDecisionStatusNames 决策状态显示名称
+var OfferDecisionStatusNames = map[OfferDecisionStatus]string{
+	OfferPending:     "待决定",
+	OfferNegotiating: "谈判中",
+	OfferAccepted:    "已接受",
+	OfferDeclined:    "已拒绝",
+	OfferExpired:     "已过期",
+}
+
+// DefaultVestingYears 未注明归属年限时按 4 年计算股票年化价值
+const DefaultVestingYears = 4
+
+// Offer 与申请关联的 offer 条款。金额均为币种基本单位（元、美元）
+type Offer struct {
+	ID               int                 `json:"id"`
+	UserID           uint                `json:"user_id"`
+	JobApplicationID int                 `json:"job_application_id"`
+	CompanyName      string              `json:"company_name"` // 来自关联申请
+	PositionTitle    string              `json:"position_title"`
+	Currency         string              `json:"currency"`
+	BaseMonthly      float64             `json:"base_monthly"`
+	SalaryMonths     float64             `json:"salary_months"`
+	AnnualBonus      *float64            `json:"annual_bonus,omitempty"`     // 年终奖/绩效奖金（目标值）
+	SignOnBonus      *float64            `json:"sign_on_bonus,omitempty"`    // 签字费，仅计入首年
+	StockValue       *float64            `json:"stock_value,omitempty"`      // 股票/期权授予总价值
+	VestingYears     *int                `json:"vesting_years,omitempty"`    // 归属年限
+	VestingSchedule  *string             `json:"vesting_schedule,omitempty"` // 归属节奏说明，如“25/25/25/25，1年cliff”
+	Benefits         StringList          `json:"benefits"`
+	Location         *string             `json:"location,omitempty"`
+	StartDate        *string             `json:"start_date,omitempty"` // 入职日期 YYYY-MM-DD
+	Deadline         *string             `json:"deadline,omitempty"`   // 答复截止日期 YYYY-MM-DD，即申请的 decision_deadline
+	DecisionStatus   OfferDecisionStatus `json:"decision_status"`
+	Ratings          OfferRatings        `json:"ratings"` // 用户自定义维度的主观评分（0-10），用于加权对比
+	Notes            *string             `json:"notes,omitempty"`
+	Compensation     OfferCompensation   `json:"compensation"`
+	Negotiations     []OfferNegotiation  `json:"negotiations,omitempty"` // 仅详情返回
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+// OfferCompensation 由条款计算的年化薪酬
+type OfferCompensation struct {
+	BaseAnnual     float64 `json:"base_annual"`      // 月薪 × 薪数
+	StockAnnual    float64 `json:"stock_annual"`     // 授予总价值 ÷ 归属年限
+	AnnualTotal    float64 `json:"annual_total"`     // 年 base + 年终奖 + 股票年化
+	FirstYearTotal float64 `json:"first_year_total"` // 年度总包 + 签字费
+}
+
+// Compute 按条款计算年化薪酬
+func (o *Offer) Compute() {
+	c := OfferCompensation{BaseAnnual: o.BaseMonthly * o.SalaryMonths}
+	if o.StockValue != nil {
+		years := DefaultVestingYears
+		if o.VestingYears != nil && *o.VestingYears > 0 {
+			years = *o.VestingYears
+		}
+		c.StockAnnual = *o.StockValue / float64(years)
+	}
+	c.AnnualTotal = c.BaseAnnual + c.StockAnnual
+	if o.AnnualBonus != nil {
+		c.AnnualTotal += *o.AnnualBonus
+	}
+	c.FirstYearTotal = c.AnnualTotal
+	if o.SignOnBonus != nil {
+		c.FirstYearTotal += *o.SignOnBonus
+	}
+	o.Compensation = c
+}
+
+// OfferRatings 自定义维度评分，键为维度 key
+type OfferRatings map[string]float64
+
+// Value 实现 JSONB 字段的数据库写入（以文本传递，供 ::jsonb 使用）
+func (r OfferRatings) Value() (driver.Value, error) {
+	if r == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]float64(r))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 JSONB 字段的数据库读取
+func (r *OfferRatings) Scan(value interface{}) error {
+	if value == nil {
+		*r = OfferRatings{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into OfferRatings", value)
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// OfferTermChange 一项条款在谈判中的变化
+type OfferTermChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// OfferTermChanges 条款变化，键为条款字段名（如 base_monthly）
+type OfferTermChanges map[string]OfferTermChange
+
+// Value 实现 JSONB 字段的数据库写入（以文本传递，供 ::jsonb 使用）
+func (c OfferTermChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]OfferTermChange(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 JSONB 字段的数据库读取
+func (c *OfferTermChanges) Scan(value interface{}) error {
+	if value == nil {
+		*c = OfferTermChanges{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into OfferTermChanges", value)
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// OfferNegotiation 谈判记录：条款修改时自动记录变化，也可单独添加沟通备注
+type OfferNegotiation struct {
+	ID        int              `json:"id"`
+	OfferID   int              `json:"offer_id"`
+	Note      *string          `json:"note,omitempty"`
+	Changes   OfferTermChanges `json:"changes"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// CreateOfferRequest 创建 offer 请求
+type CreateOfferRequest struct {
+	JobApplicationID int                  `json:"job_application_id"`
+	Currency         string               `json:"currency"`
+	BaseMonthly      float64              `json:"base_monthly"`
+	SalaryMonths     *float64             `json:"salary_months"` // 默认 12
+	AnnualBonus      *float64             `json:"annual_bonus"`
+	SignOnBonus      *float64             `json:"sign_on_bonus"`
+	StockValue       *float64             `json:"stock_value"`
+	VestingYears     *int                 `json:"vesting_years"`
+	VestingSchedule  *string              `json:"vesting_schedule"`
+	Benefits         []string             `json:"benefits"`
+	Location         *string              `json:"location"`
+	StartDate        *string              `json:"start_date"`
+	Deadline         *string              `json:"deadline"`
+	DecisionStatus   *OfferDecisionStatus `json:"decision_status"` // 默认 pending
+	Ratings          map[string]float64   `json:"ratings"`
+	Notes            *string              `json:"notes"`
+}
+
+// UpdateOfferRequest 更新 offer 请求。可选数值传 0、字符串传空串表示清空；
+// 薪酬条款变化会连同 NegotiationNote 记录为一条谈判记录
+type UpdateOfferRequest struct {
+	Currency        *string              `json:"currency"`
+	BaseMonthly     *float64             `json:"base_monthly"`
+	SalaryMonths    *float64             `json:"salary_months"`
+	AnnualBonus     *float64             `json:"annual_bonus"`
+	SignOnBonus     *float64             `json:"sign_on_bonus"`
+	StockValue      *float64             `json:"stock_value"`
+	VestingYears    *int                 `json:"vesting_years"`
+	VestingSchedule *string              `json:"vesting_schedule"`
+	Benefits        []string             `json:"benefits"` // 非 nil 时整体替换
+	Location        *string              `json:"location"`
+	StartDate       *string              `json:"start_date"`
+	Deadline        *string              `json:"deadline"`
+	DecisionStatus  *OfferDecisionStatus `json:"decision_status"`
+	Ratings         map[string]float64   `json:"ratings"` // 非 nil 时整体替换
+	Notes           *string              `json:"notes"`
+	NegotiationNote *string              `json:"negotiation_note"`
+}
+
+// AddOfferNegotiationRequest 添加谈判备注请求
+type AddOfferNegotiationRequest struct {
+	Note string `json:"note"`
+}
+
+// OfferCriterion 对比维度。Key 为内置薪酬指标（见 OfferMetrics）或 ratings 中的自定义维度
+type OfferCriterion struct {
+	Key           string  `json:"key"`
+	Label         string  `json:"label,omitempty"` // 为空时使用内置名称或 key
+	Weight        float64 `json:"weight"`
+	LowerIsBetter bool    `json:"lower_is_better,omitempty"`
+}
+
+// OfferMetrics 内置薪酬指标 key 与显示名称
+var OfferMetrics = []struct {
+	Key   string
+	Label string
+}{
+	{"first_year_total", "首年总包"},
+	{"annual_total", "年度总包"},
+	{"base_annual", "年base"},
+	{"annual_bonus", "年终奖"},
+	{"stock_annual", "股票年化"},
+	{"sign_on_bonus", "签字费"},
+}
+
+// IsOfferMetric 是否为内置薪酬指标
+func IsOfferMetric(key string) bool {
+	for _, metric := range OfferMetrics {
+		if metric.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultOfferCriteria 未指定维度时的默认权重
+var DefaultOfferCriteria = []OfferCriterion{
+	{Key: "first_year_total", Weight: 3},
+	{Key: "annual_total", Weight: 5},
+	{Key: "base_annual", Weight: 2},
+}
+
+// OfferComparisonRequest offer 对比请求
+type OfferComparisonRequest struct {
+	OfferIDs []int            `json:"offer_ids"`
+	Criteria []OfferCriterion `json:"criteria"`
+}
+
+// OfferComparisonEntry 对比矩阵中的一列（一个 offer）
+type OfferComparisonEntry struct {
+	Offer      Offer               `json:"offer"`
+	Values     map[string]*float64 `json:"values"` // 各维度原始值，未评分的自定义维度为空
+	Scores     map[string]float64  `json:"scores"` // 各维度归一化得分 0-100（最优为 100）
+	TotalScore float64             `json:"total_score"`
+	Rank       int                 `json:"rank"`
+}
+
+// OfferComparison 加权决策矩阵，Entries 按排名排序
+type OfferComparison struct {
+	Currency string                 `json:"currency"`
+	Criteria []OfferCriterion       `json:"criteria"` // 权重已归一化为占比（合计 1）
+	Entries  []OfferComparisonEntry `json:"entries"`
+}
+
+// OfferReminderDays 答复截止提醒的提前天数，由远及近逐级发送
+var OfferReminderDays = []int{7, 3, 1}
+
+// DecisionUrgency 待答复 offer 的紧急程度
+type DecisionUrgency string
+
+const (
+	UrgencyOverdue  DecisionUrgency = "overdue"  // 已过截止日期
+	UrgencyCritical DecisionUrgency = "critical" // 1 天内截止
+	UrgencyHigh     DecisionUrgency = "high"     // 3 天内截止
+	UrgencyMedium   DecisionUrgency = "medium"   // 7 天内截止
+	UrgencyLow      DecisionUrgency = "low"      // 7 天以后截止或未设置截止日期
+)
+
+// PendingDecision 待答复的 offer：处于已收到offer状态的申请及其答复截止情况
+type PendingDecision struct {
+	JobApplicationID int                  `json:"job_application_id"`
+	CompanyName      string               `json:"company_name"`
+	PositionTitle    string               `json:"position_title"`
+	DecisionDeadline *string              `json:"decision_deadline,omitempty"`
+	DaysLeft         *int                 `json:"days_left,omitempty"` // 距截止日期的天数，负数表示已逾期
+	Urgency          DecisionUrgency      `json:"urgency"`
+	OfferID          *int                 `json:"offer_id,omitempty"` // 已登记 offer 条款时返回
+	OfferStatus      *OfferDecisionStatus `json:"offer_status,omitempty"`
+}
+
+// DecisionDeadlineRequest 设置答复截止日期请求，空串或 null 表示清除
+type DecisionDeadlineRequest struct {
+	Deadline *string `json:"deadline"`
+}
+
+// OfferDecisionRequest 记录接受/拒绝 offer 请求
+type OfferDecisionRequest struct {
+	Decision OfferDecisionStatus `json:"decision"` // accepted 或 declined
+	Note     string              `json:"note"`     // 为空时使用默认备注
+}
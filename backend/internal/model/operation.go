@@ -0,0 +1,42 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OperationType 可撤销的批量操作类型
+type OperationType string
+
+const (
+	OperationBatchStatusUpdate OperationType = "batch_status_update"
+	OperationBatchDelete       OperationType = "batch_delete"
+)
+
+// OperationRowSnapshot 批量操作修改前单条申请的快照
+type OperationRowSnapshot struct {
+	ID                  int               `json:"id"`
+	Status              ApplicationStatus `json:"status"`
+	LastStatusChange    *time.Time        `json:"last_status_change"`
+	StatusVersion       *int              `json:"status_version"`
+	StatusHistory       json.RawMessage   `json:"status_history"`
+	StatusDurationStats json.RawMessage   `json:"status_duration_stats"`
+	DeletedAt           *time.Time        `json:"deleted_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+	AfterUpdatedAt      time.Time         `json:"after_updated_at"`      // 操作完成后的 updated_at，撤销时据此判断是否被再次修改
+	HistoryIDs          []int64           `json:"history_ids,omitempty"` // 本次操作写入的状态历史
+}
+
+// BatchOperationResult 批量操作结果，OperationID 为 0 表示没有记录被修改
+type BatchOperationResult struct {
+	OperationID   int64 `json:"operation_id,omitempty"`
+	AffectedCount int   `json:"affected_count"`
+}
+
+// UndoOperationResponse 撤销结果
+type UndoOperationResponse struct {
+	OperationID   int64         `json:"operation_id"`
+	OperationType OperationType `json:"operation_type"`
+	RestoredCount int           `json:"restored_count"`
+	UndoneAt      time.Time     `json:"undone_at"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// UnclassifiedRoleName 未能归一化出岗位类别/职级时在统计与导出中显示的名称
+const UnclassifiedRoleName = "未分类"
+
+// StringList 以 JSONB 数组存储的字符串列表（如技术栈）
+type StringList []string
+
+// Value 实现 JSONB 字段的数据库写入（以文本传递，供 ::jsonb 使用）
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 JSONB 字段的数据库读取
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
@@ -0,0 +1,45 @@
+package model
+
+// RecommendationScope 建议规则的作用范围
+type RecommendationScope string
+
+const (
+	RecommendationScopeUser        RecommendationScope = "user"        // 基于用户整体统计
+	RecommendationScopeApplication RecommendationScope = "application" // 基于单条申请
+)
+
+// 建议严重程度
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// RuleCondition 规则条件：Fact 为事实名，Op 支持 lt/lte/gt/gte/eq/neq/in/exists
+type RuleCondition struct {
+	Fact  string      `json:"fact"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// RecommendationRule 数据定义的建议规则，所有条件同时满足时触发
+type RecommendationRule struct {
+	ID         string              `json:"id"`
+	Category   string              `json:"category"`
+	Scope      RecommendationScope `json:"scope"`
+	Severity   string              `json:"severity"`
+	Conditions []RuleCondition     `json:"conditions"`
+	Fallback   bool                `json:"fallback,omitempty"` // 没有其他规则命中时使用
+	Title      map[string]string   `json:"title"`              // 语言 -> 标题
+	Message    map[string]string   `json:"message"`            // 语言 -> text/template 模板
+}
+
+// Recommendation 建议结果
+type Recommendation struct {
+	RuleID         string `json:"rule_id"`
+	Category       string `json:"category"`
+	Severity       string `json:"severity"`
+	Title          string `json:"title"`
+	Message        string `json:"message"`
+	ApplicationIDs []int  `json:"application_ids,omitempty"`
+}
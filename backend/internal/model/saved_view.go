@@ -0,0 +1,97 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SavedViewFilters 保存的列表/搜索参数。Query 非空时按全文搜索执行，否则按列表筛选执行
+type SavedViewFilters struct {
+	Query         string             `json:"query,omitempty"` // 搜索关键词（SearchApplications）
+	Status        *ApplicationStatus `json:"status,omitempty"`
+	Stage         string             `json:"stage,omitempty"` // 仅列表视图
+	TagIDs        []int              `json:"tag_ids,omitempty"`
+	CustomFilters map[string]string  `json:"custom_filters,omitempty"` // 仅列表视图，键为自定义字段 key
+	StartDate     string             `json:"start_date,omitempty"`     // 投递日期范围 YYYY-MM-DD
+	EndDate       string             `json:"end_date,omitempty"`
+	SalaryMin     *float64           `json:"salary_min,omitempty"` // 月薪筛选（K），与月薪区间有交集即命中
+	SalaryMax     *float64           `json:"salary_max,omitempty"`
+	SortBy        string             `json:"sort_by,omitempty"`
+	SortDir       string             `json:"sort_dir,omitempty"`
+	PageSize      int                `json:"page_size,omitempty"`
+}
+
+// IsSearch 是否为搜索视图
+func (f SavedViewFilters) IsSearch() bool {
+	return f.Query != ""
+}
+
+// PaginationRequest 转换为列表/搜索接口的分页参数
+func (f SavedViewFilters) PaginationRequest(page int) PaginationRequest {
+	return PaginationRequest{
+		Page:          page,
+		PageSize:      f.PageSize,
+		SortBy:        f.SortBy,
+		SortDir:       f.SortDir,
+		Status:        f.Status,
+		TagIDs:        f.TagIDs,
+		CustomFilters: f.CustomFilters,
+		StartDate:     f.StartDate,
+		EndDate:       f.EndDate,
+		SalaryMin:     f.SalaryMin,
+		SalaryMax:     f.SalaryMax,
+	}
+}
+
+// Value 实现 JSONB 字段的数据库写入（以文本传递，供 ::jsonb 使用）
+func (f SavedViewFilters) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现 JSONB 字段的数据库读取
+func (f *SavedViewFilters) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into SavedViewFilters", value)
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// SavedView 用户保存的视图（常用筛选组合）
+type SavedView struct {
+	ID         int              `json:"id"`
+	UserID     uint             `json:"user_id"`
+	Name       string           `json:"name"`
+	Filters    SavedViewFilters `json:"filters"`
+	Pinned     bool             `json:"pinned"` // 固定到仪表板
+	SortOrder  int              `json:"sort_order"`
+	Count      *int64           `json:"count,omitempty"` // 当前匹配的申请数（实时计算）
+	LastUsedAt *time.Time       `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// CreateSavedViewRequest 创建视图请求
+type CreateSavedViewRequest struct {
+	Name      string           `json:"name" binding:"required"`
+	Filters   SavedViewFilters `json:"filters"`
+	Pinned    bool             `json:"pinned"`
+	SortOrder int              `json:"sort_order"`
+}
+
+// UpdateSavedViewRequest 更新视图请求，Filters 非空时整体替换
+type UpdateSavedViewRequest struct {
+	Name      *string           `json:"name"`
+	Filters   *SavedViewFilters `json:"filters"`
+	Pinned    *bool             `json:"pinned"`
+	SortOrder *int              `json:"sort_order"`
+}
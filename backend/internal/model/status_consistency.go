@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// StatusDriftType 状态历史存储不一致类型
+type StatusDriftType string
+
+const (
+	DriftCountMismatch         StatusDriftType = "count_mismatch"          // JSONB 历史条数与 job_status_history 不一致
+	DriftCurrentStatusMismatch StatusDriftType = "current_status_mismatch" // 当前状态与最后一条历史不一致
+	DriftNegativeDuration      StatusDriftType = "negative_duration"       // 出现负数停留时长
+	DriftVersionGap            StatusDriftType = "version_gap"             // status_version 与历史变更次数不匹配
+	DriftSummaryMismatch       StatusDriftType = "summary_mismatch"        // JSONB 汇总信息与历史不一致
+)
+
+// StatusDriftIssue 单个不一致问题
+type StatusDriftIssue struct {
+	Type     StatusDriftType `json:"type"`
+	Message  string          `json:"message"`
+	Expected interface{}     `json:"expected,omitempty"`
+	Actual   interface{}     `json:"actual,omitempty"`
+	// Repairable 是否可通过从 job_status_history 重建 JSONB 投影修复
+	Repairable bool `json:"repairable"`
+}
+
+// StatusConsistencyReport 单个岗位申请的一致性检查结果
+type StatusConsistencyReport struct {
+	JobApplicationID int                `json:"job_application_id"`
+	UserID           uint               `json:"user_id"`
+	CompanyName      string             `json:"company_name"`
+	PositionTitle    string             `json:"position_title"`
+	Issues           []StatusDriftIssue `json:"issues"`
+	Repaired         bool               `json:"repaired"`
+}
+
+// StatusReconcileRequest 一致性检查/修复请求
+type StatusReconcileRequest struct {
+	UserID *uint `json:"user_id,omitempty"` // 为空表示检查全部用户（仅CLI/任务使用）
+	DryRun bool  `json:"dry_run"`           // 仅报告，不写入
+	Limit  int   `json:"limit,omitempty"`   // 最多检查的记录数，0 表示不限制
+}
+
+// StatusReconcileResult 一致性检查/修复汇总
+type StatusReconcileResult struct {
+	DryRun      bool                      `json:"dry_run"`
+	Checked     int                       `json:"checked"`
+	Drifted     int                       `json:"drifted"`
+	Repaired    int                       `json:"repaired"`
+	IssueCounts map[StatusDriftType]int   `json:"issue_counts"`
+	Reports     []StatusConsistencyReport `json:"reports"`
+	StartedAt   time.Time                 `json:"started_at"`
+	FinishedAt  time.Time                 `json:"finished_at"`
+}
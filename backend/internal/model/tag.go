@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Tag 用户自定义标签
+type Tag struct {
+	ID               int       `json:"id"`
+	UserID           uint      `json:"user_id"`
+	Name             string    `json:"name"`
+	Color            string    `json:"color"`
+	ApplicationCount int       `json:"application_count"` // 使用该标签的申请数（不含回收站）
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ApplicationTag 投递记录上附带的标签
+type ApplicationTag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// CreateTagRequest 创建标签请求，color 为空时使用默认颜色
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+// UpdateTagRequest 更新标签请求
+type UpdateTagRequest struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// SetApplicationTagsRequest 设置申请的标签（整体替换）
+type SetApplicationTagsRequest struct {
+	TagIDs []int `json:"tag_ids"`
+}
+
+// TagStatistics 单个标签下申请的状态分布
+type TagStatistics struct {
+	TagID      int     `json:"tag_id"`
+	Name       string  `json:"name"`
+	Color      string  `json:"color"`
+	Total      int     `json:"total"`
+	InProgress int     `json:"in_progress"`
+	Passed     int     `json:"passed"`
+	Failed     int     `json:"failed"`
+	PassRate   float64 `json:"pass_rate"` // passed / (passed + failed)，百分比
+}
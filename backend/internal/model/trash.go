@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// TrashItem 回收站中的投递记录
+type TrashItem struct {
+	ID              int               `json:"id"`
+	CompanyName     string            `json:"company_name"`
+	PositionTitle   string            `json:"position_title"`
+	ApplicationDate string            `json:"application_date"`
+	Status          ApplicationStatus `json:"status"`
+	DeletedAt       time.Time         `json:"deleted_at"`
+	PurgeAt         *time.Time        `json:"purge_at,omitempty"` // 保留期到期后自动彻底删除的时间
+}
+
+// TrashIDsRequest 批量恢复/彻底删除请求
+type TrashIDsRequest struct {
+	IDs []int `json:"ids" binding:"required"`
+}
+
+// TrashOperationResponse 回收站操作结果
+type TrashOperationResponse struct {
+	Affected int64 `json:"affected"`
+}
@@ -0,0 +1,209 @@
+package querylang
+
+import (
+	"sort"
+	"strings"
+
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/salary"
+	"jobView-backend/internal/taxonomy"
+	"jobView-backend/internal/utils"
+)
+
+// FieldKind 字段类型，决定支持的操作符与值格式
+type FieldKind string
+
+const (
+	KindEnum   FieldKind = "enum"   // 枚举值（状态、阶段、岗位类别、职级）
+	KindText   FieldKind = "text"   // 文本，: 为包含，= 为精确匹配
+	KindTag    FieldKind = "tag"    // 标签名
+	KindDate   FieldKind = "date"   // 日期 YYYY-MM-DD，支持 a..b 区间
+	KindNumber FieldKind = "number" // 数值，支持 a..b 区间
+)
+
+// field 字段定义
+type field struct {
+	Name        string
+	Aliases     []string
+	Kind        FieldKind
+	Column      string // 比较使用的 SQL 表达式（仅包含常量，不拼接用户输入）
+	Description string
+	Example     string
+}
+
+// salaryUpperExpr 月薪上限（K/月），取自由 salary_range 解析出的结构化薪酬（见 salary 包）。
+// 金额按人民币比较，其他币种的薪资为 NULL，不参与比较
+const salaryUpperExpr = `(CASE WHEN salary_currency = '` + salary.DefaultCurrency + `' THEN salary_max_monthly / 1000 END)`
+
+// annualUpperExpr 年薪上限（K/年），同样只比较人民币薪资
+const annualUpperExpr = `(CASE WHEN salary_currency = '` + salary.DefaultCurrency + `' THEN salary_annual_max / 1000 END)`
+
+// fields 支持的字段，顺序即目录展示顺序
+var fields = []field{
+	{Name: "status", Aliases: []string{"状态"}, Kind: KindEnum, Column: "status",
+		Description: "申请状态，多个值用逗号分隔表示任一", Example: "status:一面中,二面中"},
+	{Name: "stage", Aliases: []string{"阶段"}, Kind: KindEnum, Column: "status",
+		Description: "流程阶段（按状态归类）", Example: "stage:interviews"},
+	{Name: "company", Aliases: []string{"公司"}, Kind: KindText, Column: "company_name",
+		Description: "公司名称，: 为包含，= 为精确匹配（含该公司的其他写法）", Example: `company:"字节"`},
+	{Name: "position", Aliases: []string{"title", "职位", "岗位"}, Kind: KindText, Column: "position_title",
+		Description: "职位名称", Example: "position:后端"},
+	{Name: "role", Aliases: []string{"family", "岗位类别"}, Kind: KindEnum, Column: "role_family",
+		Description: "由职位名称归一化的岗位类别，值可用 key 或中文名", Example: "role:backend"},
+	{Name: "level", Aliases: []string{"职级"}, Kind: KindEnum, Column: "role_level",
+		Description: "由职位名称归一化的职级", Example: "level:senior,staff"},
+	{Name: "location", Aliases: []string{"地点", "城市"}, Kind: KindText, Column: "work_location",
+		Description: "工作地点", Example: "location:北京"},
+	{Name: "notes", Aliases: []string{"备注"}, Kind: KindText, Column: "notes",
+		Description: "备注内容", Example: "notes:内推"},
+	{Name: "hr", Aliases: []string{"hr_name"}, Kind: KindText, Column: "hr_name",
+		Description: "HR 姓名", Example: "hr:王"},
+	{Name: "tag", Aliases: []string{"tags", "标签"}, Kind: KindTag,
+		Description: "标签名（不区分大小写），多个值表示任一", Example: "tag:referral"},
+	{Name: "applied", Aliases: []string{"date", "投递"}, Kind: KindDate, Column: "application_date",
+		Description: "投递日期，支持 >、>=、<、<= 与 a..b 区间", Example: "applied>=2025-09-01"},
+	{Name: "created", Aliases: []string{"创建"}, Kind: KindDate, Column: "created_at::date",
+		Description: "记录创建日期", Example: "created:2025-09-01..2025-09-30"},
+	{Name: "updated", Aliases: []string{"更新"}, Kind: KindDate, Column: "updated_at::date",
+		Description: "最近更新日期", Example: "updated<2025-10-01"},
+	{Name: "interview", Aliases: []string{"面试"}, Kind: KindDate, Column: "interview_time::date",
+		Description: "面试日期", Example: "interview>=2025-10-01"},
+	{Name: "salary", Aliases: []string{"薪资"}, Kind: KindNumber, Column: salaryUpperExpr,
+		Description: "月薪上限（单位 K，人民币），值支持 25k、2.5w、25000", Example: "salary>=25k"},
+	{Name: "annual", Aliases: []string{"年薪"}, Kind: KindNumber, Column: annualUpperExpr,
+		Description: "年薪上限（单位 K，人民币，含多薪），值支持 400k、40w、400000", Example: "annual>=40w"},
+}
+
+// fieldIndex 字段名与别名（小写）到字段定义的索引
+var fieldIndex = func() map[string]*field {
+	index := make(map[string]*field)
+	for i := range fields {
+		f := &fields[i]
+		index[f.Name] = f
+		for _, alias := range f.Aliases {
+			index[strings.ToLower(alias)] = f
+		}
+	}
+	return index
+}()
+
+// lookupField 按名称或别名查找字段
+func lookupField(name string) (*field, bool) {
+	f, ok := fieldIndex[strings.ToLower(name)]
+	return f, ok
+}
+
+// operators 返回字段类型支持的操作符
+func (k FieldKind) operators() []Operator {
+	switch k {
+	case KindDate, KindNumber:
+		return []Operator{OpMatch, OpEq, OpGt, OpGte, OpLt, OpLte}
+	default:
+		return []Operator{OpMatch, OpEq}
+	}
+}
+
+// supports 是否支持该操作符
+func (k FieldKind) supports(op Operator) bool {
+	for _, candidate := range k.operators() {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldInfo 字段目录项，供前端自动补全使用
+type FieldInfo struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Type        string   `json:"type"`
+	Operators   []string `json:"operators"`
+	Values      []string `json:"values,omitempty"` // 可选值（枚举字段、用户标签）
+	Description string   `json:"description"`
+	Example     string   `json:"example"`
+}
+
+// Catalog 返回字段目录。tagNames 为用户的标签名，会作为 tag 字段的候选值
+func Catalog(tagNames []string) []FieldInfo {
+	catalog := make([]FieldInfo, 0, len(fields))
+	for _, f := range fields {
+		info := FieldInfo{
+			Name:        f.Name,
+			Aliases:     f.Aliases,
+			Type:        string(f.Kind),
+			Description: f.Description,
+			Example:     f.Example,
+		}
+		for _, op := range f.Kind.operators() {
+			info.Operators = append(info.Operators, string(op))
+		}
+		switch f.Name {
+		case "status":
+			for _, status := range model.AllStatuses() {
+				info.Values = append(info.Values, string(status))
+			}
+		case "stage":
+			info.Values = append(info.Values, model.ApplicationStages...)
+		case "role":
+			for _, family := range taxonomy.Active().Families {
+				info.Values = append(info.Values, family.Key)
+			}
+		case "level":
+			for _, level := range taxonomy.Active().Levels {
+				info.Values = append(info.Values, level.Key)
+			}
+		case "tag":
+			info.Values = append(info.Values, tagNames...)
+		}
+		catalog = append(catalog, info)
+	}
+	return catalog
+}
+
+// suggest 按编辑距离返回最接近的候选项（最多 3 个）
+func suggest(input string, candidates []string) []string {
+	type scored struct {
+		value    string
+		distance int
+	}
+	input = strings.ToLower(input)
+	maxDistance := len([]rune(input))/3 + 1
+	if maxDistance > 3 {
+		maxDistance = 3
+	}
+
+	var matches []scored
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		distance := utils.Levenshtein(input, lower)
+		if strings.HasPrefix(lower, input) || strings.Contains(lower, input) {
+			distance = 0
+		}
+		if distance <= maxDistance {
+			matches = append(matches, scored{candidate, distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].distance < matches[j].distance })
+
+	var result []string
+	for i := 0; i < len(matches) && i < 3; i++ {
+		result = append(result, matches[i].value)
+	}
+	return result
+}
+
+// fieldNames 全部字段名与别名
+func fieldNames() []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+		names = append(names, f.Aliases...)
+	}
+	return names
+}
@@ -0,0 +1,408 @@
+// Package querylang 实现申请列表的查询语言，例如：
+//
+//	status:一面中,二面中 company:"字节" applied>=2025-09-01 salary>=25k tag:referral -status:已拒绝
+//
+// 以空白分隔的各项按 AND 组合；field:v1,v2 表示任一值匹配；前缀 - 表示取反；
+// 不带字段名的词或引号短语为自由文本。解析结果由 Conditions 编译为参数化 SQL 条件。
+package querylang
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+)
+
+const (
+	// MaxQueryLength 查询最大字符数
+	MaxQueryLength = 500
+	// MaxTerms 查询最多包含的条件数
+	MaxTerms = 30
+	// maxValues 单个条件最多包含的值个数
+	maxValues = 20
+)
+
+// Operator 比较操作符
+type Operator string
+
+const (
+	OpMatch Operator = ":"  // 包含/任一匹配
+	OpEq    Operator = "="  // 精确匹配
+	OpGt    Operator = ">"  // 大于
+	OpGte   Operator = ">=" // 大于等于
+	OpLt    Operator = "<"  // 小于
+	OpLte   Operator = "<=" // 小于等于
+)
+
+// Error 查询解析错误，Position 为出错位置（按字符计数，从 0 开始）
+type Error struct {
+	Position    int      `json:"position"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean: %s?)", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// Term 单个查询条件。Field 为空表示自由文本
+type Term struct {
+	Field    string   `json:"field,omitempty"`
+	Operator Operator `json:"operator,omitempty"`
+	Values   []string `json:"values"`
+	Negated  bool     `json:"negated,omitempty"`
+	Position int      `json:"position"`
+}
+
+// Query 解析后的查询
+type Query struct {
+	Terms []Term `json:"terms"`
+}
+
+// IsEmpty 是否不含任何条件
+func (q *Query) IsEmpty() bool {
+	return q == nil || len(q.Terms) == 0
+}
+
+// FreeText 返回未取反的自由文本（单词或引号短语），供全文搜索使用
+func (q *Query) FreeText() []string {
+	if q == nil {
+		return nil
+	}
+	var parts []string
+	for _, term := range q.Terms {
+		if term.Field == "" && !term.Negated {
+			parts = append(parts, term.Values...)
+		}
+	}
+	return parts
+}
+
+// WithoutFreeText 返回去掉未取反自由文本后的查询（由调用方改用全文搜索处理）
+func (q *Query) WithoutFreeText() *Query {
+	result := &Query{}
+	if q == nil {
+		return result
+	}
+	for _, term := range q.Terms {
+		if term.Field == "" && !term.Negated {
+			continue
+		}
+		result.Terms = append(result.Terms, term)
+	}
+	return result
+}
+
+// Parse 解析查询字符串，空查询返回不含条件的 Query
+func Parse(input string) (*Query, error) {
+	runes := []rune(input)
+	if len(runes) > MaxQueryLength {
+		return nil, &Error{Position: MaxQueryLength, Message: fmt.Sprintf("query exceeds %d characters", MaxQueryLength)}
+	}
+
+	p := &parser{input: runes}
+	query := &Query{}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if len(query.Terms) == MaxTerms {
+			return nil, &Error{Position: term.Position, Message: fmt.Sprintf("query has more than %d terms", MaxTerms)}
+		}
+		query.Terms = append(query.Terms, term)
+	}
+	return query, nil
+}
+
+// parser 逐字符扫描的解析器
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.input) }
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// isOperatorStart 字段名在遇到这些字符时结束
+func isOperatorStart(r rune) bool {
+	return r == ':' || r == '=' || r == '<' || r == '>'
+}
+
+// parseTerm 解析一个条件：[-]field op values | [-]"phrase" | [-]word
+func (p *parser) parseTerm() (Term, error) {
+	term := Term{Position: p.pos}
+	if p.peek() == '-' && p.pos+1 < len(p.input) && !unicode.IsSpace(p.input[p.pos+1]) {
+		term.Negated = true
+		p.pos++
+	}
+
+	if p.peek() == '"' {
+		phrase, err := p.parseQuoted()
+		if err != nil {
+			return term, err
+		}
+		if phrase == "" {
+			return term, &Error{Position: term.Position, Message: "empty phrase"}
+		}
+		term.Values = []string{phrase}
+		return term, nil
+	}
+
+	nameStart := p.pos
+	for !p.eof() && !unicode.IsSpace(p.peek()) && !isOperatorStart(p.peek()) && p.peek() != '"' {
+		p.pos++
+	}
+	name := string(p.input[nameStart:p.pos])
+
+	if p.eof() || !isOperatorStart(p.peek()) {
+		// 自由文本：读到空白为止
+		for !p.eof() && !unicode.IsSpace(p.peek()) {
+			p.pos++
+		}
+		word := string(p.input[nameStart:p.pos])
+		if word == "" {
+			return term, &Error{Position: term.Position, Message: "expected a term after '-'"}
+		}
+		term.Values = []string{word}
+		return term, nil
+	}
+
+	if name == "" {
+		return term, &Error{Position: p.pos, Message: "missing field name before operator"}
+	}
+	f, ok := lookupField(name)
+	if !ok {
+		return term, &Error{
+			Position:    nameStart,
+			Message:     fmt.Sprintf("unknown field %q", name),
+			Suggestions: suggest(name, fieldNames()),
+		}
+	}
+	term.Field = f.Name
+
+	opPos := p.pos
+	term.Operator = p.parseOperator()
+	if !f.Kind.supports(term.Operator) {
+		return term, &Error{
+			Position: opPos,
+			Message:  fmt.Sprintf("operator %q is not supported by field %s", term.Operator, f.Name),
+		}
+	}
+
+	valuesPos := p.pos
+	values, err := p.parseValues()
+	if err != nil {
+		return term, err
+	}
+	if len(values) == 0 {
+		return term, &Error{Position: valuesPos, Message: fmt.Sprintf("missing value for field %s", f.Name)}
+	}
+	if len(values) > maxValues {
+		return term, &Error{Position: valuesPos, Message: fmt.Sprintf("field %s accepts at most %d values", f.Name, maxValues)}
+	}
+	if len(values) > 1 && term.Operator != OpMatch && term.Operator != OpEq {
+		return term, &Error{Position: valuesPos, Message: fmt.Sprintf("operator %q takes a single value", term.Operator)}
+	}
+	term.Values = values
+
+	if err := validateValues(f, term.Operator, values, valuesPos); err != nil {
+		return term, err
+	}
+	return term, nil
+}
+
+// parseOperator 读取操作符。":" 后可再跟比较符，如 salary:>=25k
+func (p *parser) parseOperator() Operator {
+	if p.peek() == ':' {
+		p.pos++
+		if p.peek() == '>' || p.peek() == '<' || p.peek() == '=' {
+			return p.parseOperator()
+		}
+		return OpMatch
+	}
+	first := p.peek()
+	p.pos++
+	if (first == '>' || first == '<') && p.peek() == '=' {
+		p.pos++
+		return Operator(string(first) + "=")
+	}
+	return Operator(string(first))
+}
+
+// parseValues 读取以逗号分隔的值列表，值可加双引号
+func (p *parser) parseValues() ([]string, error) {
+	var values []string
+	for {
+		var value string
+		if p.peek() == '"' {
+			quoted, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			value = quoted
+		} else {
+			start := p.pos
+			for !p.eof() && !unicode.IsSpace(p.peek()) && p.peek() != ',' {
+				p.pos++
+			}
+			value = string(p.input[start:p.pos])
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+		if p.peek() != ',' {
+			return values, nil
+		}
+		p.pos++
+	}
+}
+
+// parseQuoted 读取双引号字符串，支持 \" 与 \\ 转义
+func (p *parser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++ // 跳过开头引号
+	var b strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		switch r {
+		case '\\':
+			if !p.eof() {
+				b.WriteRune(p.peek())
+				p.pos++
+			}
+		case '"':
+			return b.String(), nil
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", &Error{Position: start, Message: "unterminated quoted string"}
+}
+
+// validateValues 校验值格式
+func validateValues(f *field, op Operator, values []string, pos int) error {
+	for _, value := range values {
+		switch f.Kind {
+		case KindEnum:
+			if f.Name == "status" && !model.ApplicationStatus(value).IsValid() {
+				var candidates []string
+				for _, status := range model.AllStatuses() {
+					candidates = append(candidates, string(status))
+				}
+				return &Error{Position: pos, Message: fmt.Sprintf("unknown status %q", value), Suggestions: suggest(value, candidates)}
+			}
+			if f.Name == "stage" && len(model.StatusesForStage(value)) == 0 {
+				return &Error{Position: pos, Message: fmt.Sprintf("unknown stage %q", value), Suggestions: suggest(value, model.ApplicationStages)}
+			}
+			if f.Name == "role" {
+				if _, ok := taxonomy.Active().ResolveFamily(value); !ok {
+					var candidates []string
+					for _, family := range taxonomy.Active().Families {
+						candidates = append(candidates, family.Key, family.Name)
+					}
+					return &Error{Position: pos, Message: fmt.Sprintf("unknown role %q", value), Suggestions: suggest(value, candidates)}
+				}
+			}
+			if f.Name == "level" {
+				if _, ok := taxonomy.Active().ResolveLevel(value); !ok {
+					var candidates []string
+					for _, level := range taxonomy.Active().Levels {
+						candidates = append(candidates, level.Key, level.Name)
+					}
+					return &Error{Position: pos, Message: fmt.Sprintf("unknown level %q", value), Suggestions: suggest(value, candidates)}
+				}
+			}
+		case KindDate, KindNumber:
+			low, high, isRange := splitRange(value)
+			if isRange && op != OpMatch {
+				return &Error{Position: pos, Message: fmt.Sprintf("range %q is only allowed with ':'", value)}
+			}
+			for _, bound := range []string{low, high} {
+				if bound == "" {
+					continue
+				}
+				if _, err := parseBound(f.Kind, bound); err != nil {
+					return &Error{Position: pos, Message: err.Error()}
+				}
+			}
+			if isRange && low == "" && high == "" {
+				return &Error{Position: pos, Message: "range needs at least one bound"}
+			}
+		}
+	}
+	return nil
+}
+
+// splitRange 拆分 a..b 形式的区间，任一端可省略
+func splitRange(value string) (string, string, bool) {
+	low, high, ok := strings.Cut(value, "..")
+	if !ok {
+		return value, value, false
+	}
+	return low, high, true
+}
+
+// parseBound 校验并规范化日期或数值
+func parseBound(kind FieldKind, value string) (interface{}, error) {
+	if kind == KindDate {
+		if !isDate(value) {
+			return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+		}
+		return value, nil
+	}
+	return parseSalary(value)
+}
+
+// isDate 校验 YYYY-MM-DD 格式且为真实存在的日期（如拒绝 2025-99-99、2025-02-30）
+func isDate(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+// parseSalary 将薪资值统一换算为 K/月：25k、2.5w、2.5万、25000
+func parseSalary(value string) (float64, error) {
+	lower := strings.ToLower(strings.TrimSpace(value))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(lower, "k"), strings.HasSuffix(lower, "千"):
+		lower = strings.TrimSuffix(strings.TrimSuffix(lower, "k"), "千")
+	case strings.HasSuffix(lower, "w"), strings.HasSuffix(lower, "万"):
+		lower = strings.TrimSuffix(strings.TrimSuffix(lower, "w"), "万")
+		multiplier = 10
+	}
+	number, err := strconv.ParseFloat(lower, 64)
+	if err != nil || number < 0 || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("invalid salary %q, expected e.g. 25k, 2.5w or 25000", value)
+	}
+	if multiplier == 1 && !strings.HasSuffix(strings.ToLower(value), "k") && !strings.HasSuffix(value, "千") && number >= 1000 {
+		multiplier = 0.001
+	}
+	return number * multiplier, nil
+}
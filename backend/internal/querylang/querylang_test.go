@@ -0,0 +1,176 @@
+package querylang
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`status:一面中,二面中 company:"字节 跳动" applied>=2025-09-01 salary:>=25k tag:referral -status:已拒绝 后端 "大 模型"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.Terms) != 8 {
+		t.Fatalf("expected 8 terms, got %d: %+v", len(q.Terms), q.Terms)
+	}
+
+	status := q.Terms[0]
+	if status.Field != "status" || status.Operator != OpMatch || len(status.Values) != 2 || status.Values[1] != "二面中" {
+		t.Fatalf("unexpected status term %+v", status)
+	}
+	if q.Terms[1].Values[0] != "字节 跳动" {
+		t.Fatalf("expected quoted value to keep spaces, got %+v", q.Terms[1])
+	}
+	if q.Terms[2].Operator != OpGte || q.Terms[3].Operator != OpGte {
+		t.Fatalf("expected >= operators, got %q and %q", q.Terms[2].Operator, q.Terms[3].Operator)
+	}
+	if !q.Terms[5].Negated || q.Terms[5].Field != "status" {
+		t.Fatalf("expected negated status term, got %+v", q.Terms[5])
+	}
+	if freeText := q.FreeText(); len(freeText) != 2 || freeText[0] != "后端" || freeText[1] != "大 模型" {
+		t.Fatalf("unexpected free text %q", freeText)
+	}
+	if len(q.WithoutFreeText().Terms) != 6 {
+		t.Fatalf("expected free text to be removed, got %+v", q.WithoutFreeText().Terms)
+	}
+
+	aliased, err := Parse("公司=Shopee 标签:Remote")
+	if err != nil || aliased.Terms[0].Field != "company" || aliased.Terms[1].Field != "tag" {
+		t.Fatalf("expected aliases to resolve, got %+v %v", aliased, err)
+	}
+
+	empty, err := Parse("   ")
+	if err != nil || !empty.IsEmpty() {
+		t.Fatalf("expected empty query, got %+v %v", empty, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		input      string
+		position   int
+		suggestion string
+	}{
+		{input: "stauts:一面中", position: 0, suggestion: "status"},
+		{input: "status:一面", position: 7, suggestion: "一面中"},
+		{input: "stage:interview", position: 6, suggestion: "interviews"},
+		{input: `company:"字节`, position: 8},
+		{input: "company>5", position: 7},
+		{input: "applied>=2025/09/01", position: 9},
+		{input: "salary>=lots", position: 8},
+		{input: "applied>=2025-99-99", position: 9},
+		{input: "applied<2025-02-30", position: 8},
+		{input: "salary>=nan", position: 8},
+		{input: "salary<=inf", position: 8},
+		{input: "salary>=+Inf", position: 8},
+		{input: "applied>2025-09-01,2025-10-01", position: 8},
+		{input: "applied>=2025-09-01..2025-10-01", position: 9},
+		{input: "status:", position: 7},
+		{input: ":foo", position: 0},
+	}
+	for _, tc := range cases {
+		_, err := Parse(tc.input)
+		var queryErr *Error
+		if !errors.As(err, &queryErr) {
+			t.Fatalf("%q: expected *Error, got %v", tc.input, err)
+		}
+		if !strings.HasPrefix(err.Error(), "invalid query") {
+			t.Fatalf("%q: expected invalid prefix, got %q", tc.input, err.Error())
+		}
+		if queryErr.Position != tc.position {
+			t.Fatalf("%q: expected position %d, got %d (%s)", tc.input, tc.position, queryErr.Position, queryErr.Message)
+		}
+		if tc.suggestion != "" && (len(queryErr.Suggestions) == 0 || queryErr.Suggestions[0] != tc.suggestion) {
+			t.Fatalf("%q: expected suggestion %q, got %v", tc.input, tc.suggestion, queryErr.Suggestions)
+		}
+	}
+
+	if _, err := Parse(strings.Repeat("a ", MaxTerms+1)); err == nil {
+		t.Fatalf("expected too many terms to be rejected")
+	}
+	if _, err := Parse(strings.Repeat("a", MaxQueryLength+1)); err == nil {
+		t.Fatalf("expected long query to be rejected")
+	}
+}
+
+func TestConditions(t *testing.T) {
+	q, err := Parse(`stage:screening company:"50%_off" position=后端 -status:已拒绝 applied:2025-09-01..2025-09-30 salary>=2.5w tag:Referral 面经`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cond, args, next := q.Conditions(3)
+
+	expected := []string{
+		" AND status IN ($3, $4)",
+		" AND (company_name ILIKE $5)",
+		" AND (LOWER(position_title) = LOWER($6))",
+		" AND NOT COALESCE((status IN ($7)), FALSE)",
+		" AND ((application_date >= $8 AND application_date <= $9))",
+		" AND ((CASE WHEN salary_currency = 'CNY' THEN salary_max_monthly / 1000 END) >= $10)",
+		"LOWER(t.name) IN ($11)",
+		" AND (company_name ILIKE $12 OR position_title ILIKE $12 OR work_location ILIKE $12 OR hr_name ILIKE $12 OR notes ILIKE $12 OR job_description ILIKE $12)",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(cond, fragment) {
+			t.Fatalf("expected condition to contain %q, got %s", fragment, cond)
+		}
+	}
+	if next != 13 || len(args) != 10 {
+		t.Fatalf("expected 10 args and next index 13, got %d args, next %d", len(args), next)
+	}
+	if args[2] != `%50\%\_off%` {
+		t.Fatalf("expected LIKE wildcards to be escaped, got %v", args[2])
+	}
+	if args[7] != 25.0 || args[8] != "referral" {
+		t.Fatalf("unexpected salary/tag args %v %v", args[7], args[8])
+	}
+
+	company, _ := Parse("company=ByteDance")
+	if cond, _, _ := company.Conditions(1); !strings.Contains(cond, "a.alias_key = company_name_key($1)") {
+		t.Fatalf("expected exact company match to include aliases, got %s", cond)
+	}
+
+	role, err := Parse("role:后端开发,frontend 职级:高级")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cond, args, _ := role.Conditions(1); !strings.Contains(cond, "role_family IN ($1, $2)") || !strings.Contains(cond, "role_level IN ($3)") ||
+		args[0] != "backend" || args[1] != "frontend" || args[2] != "senior" {
+		t.Fatalf("expected role and level names to resolve to keys, got %s %v", cond, args)
+	}
+	if _, err := Parse("role:backnd"); err == nil || !strings.Contains(err.Error(), "unknown role") {
+		t.Fatalf("expected unknown role error, got %v", err)
+	}
+
+	if cond, args, next := (&Query{}).Conditions(2); cond != "" || args != nil || next != 2 {
+		t.Fatalf("expected empty query to produce no condition")
+	}
+}
+
+func TestParseSalary(t *testing.T) {
+	cases := map[string]float64{"25k": 25, "25K": 25, "2.5w": 25, "3万": 30, "25000": 25, "30": 30}
+	for input, want := range cases {
+		got, err := parseSalary(input)
+		if err != nil || got != want {
+			t.Fatalf("parseSalary(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	catalog := Catalog([]string{"referral"})
+	if len(catalog) != len(fields) {
+		t.Fatalf("expected %d fields, got %d", len(fields), len(catalog))
+	}
+	byName := make(map[string]FieldInfo)
+	for _, info := range catalog {
+		byName[info.Name] = info
+	}
+	if len(byName["status"].Values) == 0 || len(byName["tag"].Values) != 1 {
+		t.Fatalf("expected status and tag candidate values, got %+v %+v", byName["status"], byName["tag"])
+	}
+	if len(byName["applied"].Operators) != 6 || len(byName["company"].Operators) != 2 {
+		t.Fatalf("unexpected operators %v %v", byName["applied"].Operators, byName["company"].Operators)
+	}
+}
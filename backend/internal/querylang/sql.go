@@ -0,0 +1,168 @@
+package querylang
+
+import (
+	"fmt"
+	"strings"
+
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+	"jobView-backend/internal/utils"
+)
+
+// freeTextColumns 自由文本匹配的列（与搜索文档覆盖的字段一致）
+var freeTextColumns = []string{"company_name", "position_title", "work_location", "hr_name", "notes", "job_description"}
+
+// Conditions 将查询编译为 " AND ..." 形式的 WHERE 片段，占位符从 argIndex 开始编号。
+// 条件作用于 job_applications 表，所有用户输入都以参数传递。
+func (q *Query) Conditions(argIndex int) (string, []interface{}, int) {
+	if q.IsEmpty() {
+		return "", nil, argIndex
+	}
+
+	var condition strings.Builder
+	var args []interface{}
+	for _, term := range q.Terms {
+		var expr string
+		var termArgs []interface{}
+		expr, termArgs, argIndex = term.sql(argIndex)
+		if term.Negated {
+			expr = fmt.Sprintf("NOT COALESCE((%s), FALSE)", expr)
+		}
+		condition.WriteString(" AND " + expr)
+		args = append(args, termArgs...)
+	}
+	return condition.String(), args, argIndex
+}
+
+// sql 编译单个条件，多个值之间为 OR
+func (t Term) sql(argIndex int) (string, []interface{}, int) {
+	if t.Field == "" {
+		parts := make([]string, len(freeTextColumns))
+		for i, column := range freeTextColumns {
+			parts[i] = fmt.Sprintf("%s ILIKE $%d", column, argIndex)
+		}
+		return "(" + strings.Join(parts, " OR ") + ")", []interface{}{"%" + utils.EscapeLike(t.Values[0]) + "%"}, argIndex + 1
+	}
+
+	f, _ := lookupField(t.Field)
+	switch f.Kind {
+	case KindEnum:
+		return enumSQL(f, t.Values, argIndex)
+	case KindTag:
+		return tagSQL(t.Values, argIndex)
+	case KindText:
+		return textSQL(f, t.Operator, t.Values, argIndex)
+	default:
+		return compareSQL(f, t.Operator, t.Values, argIndex)
+	}
+}
+
+// placeholders 为一组值生成占位符列表
+func placeholders(values []string, argIndex int) (string, []interface{}, int) {
+	marks := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		marks[i] = fmt.Sprintf("$%d", argIndex)
+		args[i] = value
+		argIndex++
+	}
+	return strings.Join(marks, ", "), args, argIndex
+}
+
+// enumSQL 状态直接匹配，阶段展开为对应的状态集合，岗位类别/职级的中文名换算为 key
+func enumSQL(f *field, values []string, argIndex int) (string, []interface{}, int) {
+	matched := values
+	switch f.Name {
+	case "role", "level":
+		matched = make([]string, len(values))
+		for i, value := range values {
+			if f.Name == "role" {
+				matched[i], _ = taxonomy.Active().ResolveFamily(value)
+			} else {
+				matched[i], _ = taxonomy.Active().ResolveLevel(value)
+			}
+		}
+	case "stage":
+		matched = nil
+		seen := make(map[string]bool)
+		for _, stage := range values {
+			for _, status := range model.StatusesForStage(stage) {
+				if !seen[status] {
+					seen[status] = true
+					matched = append(matched, status)
+				}
+			}
+		}
+	}
+	inClause, args, next := placeholders(matched, argIndex)
+	return fmt.Sprintf("%s IN (%s)", f.Column, inClause), args, next
+}
+
+// tagSQL 按标签名（不区分大小写）匹配，命中任一即可
+func tagSQL(values []string, argIndex int) (string, []interface{}, int) {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	inClause, args, next := placeholders(lowered, argIndex)
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM job_application_tags jt JOIN tags t ON t.id = jt.tag_id
+		WHERE jt.job_application_id = job_applications.id AND t.user_id = job_applications.user_id
+		AND LOWER(t.name) IN (%s))`, inClause), args, next
+}
+
+// textSQL ":" 为包含匹配，"=" 为不区分大小写的精确匹配；company= 同时匹配该公司的全部别名
+func textSQL(f *field, op Operator, values []string, argIndex int) (string, []interface{}, int) {
+	parts := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		if op == OpEq && f.Name == "company" {
+			parts[i] = fmt.Sprintf(`LOWER(company_name) = LOWER($%[1]d) OR company_id IN (
+				SELECT a.company_id FROM company_aliases a
+				WHERE a.user_id = job_applications.user_id AND a.alias_key = company_name_key($%[1]d))`, argIndex)
+			args[i] = value
+		} else if op == OpEq {
+			parts[i] = fmt.Sprintf("LOWER(%s) = LOWER($%d)", f.Column, argIndex)
+			args[i] = value
+		} else {
+			parts[i] = fmt.Sprintf("%s ILIKE $%d", f.Column, argIndex)
+			args[i] = "%" + utils.EscapeLike(value) + "%"
+		}
+		argIndex++
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", args, argIndex
+}
+
+// compareSQL 日期与数值比较，":" 支持 a..b 区间
+func compareSQL(f *field, op Operator, values []string, argIndex int) (string, []interface{}, int) {
+	var parts []string
+	var args []interface{}
+	add := func(sqlOp, value string) string {
+		bound, _ := parseBound(f.Kind, value) // Parse 阶段已校验
+		args = append(args, bound)
+		expr := fmt.Sprintf("%s %s $%d", f.Column, sqlOp, argIndex)
+		argIndex++
+		return expr
+	}
+
+	for _, value := range values {
+		switch op {
+		case OpGt, OpGte, OpLt, OpLte:
+			parts = append(parts, add(string(op), value))
+		default:
+			low, high, isRange := splitRange(value)
+			if !isRange {
+				parts = append(parts, add("=", value))
+				continue
+			}
+			var bounds []string
+			if low != "" {
+				bounds = append(bounds, add(">=", low))
+			}
+			if high != "" {
+				bounds = append(bounds, add("<=", high))
+			}
+			parts = append(parts, "("+strings.Join(bounds, " AND ")+")")
+		}
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", args, argIndex
+}
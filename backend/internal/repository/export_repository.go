@@ -9,6 +9,7 @@ import (
 
     "jobView-backend/internal/database"
     "jobView-backend/internal/model"
+    "jobView-backend/internal/querylang"
 )
 
 // ExportRepository 导出相关的数据访问
@@ -164,7 +165,8 @@ func (r *exportRepo) GetExportDataCount(userID uint, filters *model.ExportFilter
     if r.db == nil || r.db.ORM == nil { return 0, fmt.Errorf("gorm not initialized") }
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
     defer cancel()
-    query, args := buildCountQueryInternal(userID, filters)
+    query, args, err := buildCountQueryInternal(userID, filters)
+    if err != nil { return 0, err }
     var count int
     if err := r.db.ORM.WithContext(ctx).Raw(query, args...).Row().Scan(&count); err != nil { return 0, err }
     return count, nil
@@ -174,7 +176,8 @@ func (r *exportRepo) GetExportData(userID uint, filters *model.ExportFilters, of
     if r.db == nil || r.db.ORM == nil { return nil, fmt.Errorf("gorm not initialized") }
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
     defer cancel()
-    query, args := buildDataQueryInternal(userID, filters, offset, limit)
+    query, args, err := buildDataQueryInternal(userID, filters, offset, limit)
+    if err != nil { return nil, err }
     rows, err := r.db.ORM.WithContext(ctx).Raw(query, args...).Rows()
     if err != nil { return nil, err }
     defer rows.Close()
@@ -188,8 +191,8 @@ func (r *exportRepo) GetExportData(userID uint, filters *model.ExportFilters, of
 }
 
 // 内部构造 SQL 与参数（与服务层保持一致逻辑）
-func buildCountQueryInternal(userID uint, filters *model.ExportFilters) (string, []interface{}) {
-    query := "SELECT COUNT(*) FROM job_applications WHERE user_id = $1"
+func buildCountQueryInternal(userID uint, filters *model.ExportFilters) (string, []interface{}, error) {
+    query := "SELECT COUNT(*) FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL"
     args := []interface{}{userID}
     argIndex := 2
     if len(filters.Status) > 0 {
@@ -209,13 +212,18 @@ func buildCountQueryInternal(userID uint, filters *model.ExportFilters) (string,
     }
     if filters.Keywords != "" {
         query += fmt.Sprintf(" AND (company_name ILIKE $%d OR position_title ILIKE $%d OR notes ILIKE $%d)", argIndex, argIndex, argIndex)
-        kw := "%" + filters.Keywords + "%"; args = append(args, kw)
+        kw := "%" + filters.Keywords + "%"; args = append(args, kw); argIndex++
     }
-    return query, args
+    // 查询语句与列表/搜索共用同一构造器
+    parsed, err := querylang.Parse(filters.Query)
+    if err != nil { return "", nil, err }
+    queryCondition, queryArgs, argIndex := parsed.Conditions(argIndex)
+    query += queryCondition; args = append(args, queryArgs...)
+    return query, args, nil
 }
 
-func buildDataQueryInternal(userID uint, filters *model.ExportFilters, offset, limit int) (string, []interface{}) {
-    query := "SELECT id, user_id, company_name, position_title, application_date, status, job_description, salary_range, work_location, contact_info, notes, interview_time, reminder_time, reminder_enabled, follow_up_date, hr_name, hr_phone, hr_email, interview_location, interview_type, created_at, updated_at FROM job_applications WHERE user_id = $1"
+func buildDataQueryInternal(userID uint, filters *model.ExportFilters, offset, limit int) (string, []interface{}, error) {
+    query := "SELECT id, user_id, company_name, position_title, application_date, status, job_description, salary_range, work_location, contact_info, notes, interview_time, reminder_time, reminder_enabled, follow_up_date, hr_name, hr_phone, hr_email, interview_location, interview_type, created_at, updated_at FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL"
     args := []interface{}{userID}
     argIndex := 2
     if len(filters.Status) > 0 {
@@ -235,14 +243,19 @@ func buildDataQueryInternal(userID uint, filters *model.ExportFilters, offset, l
     }
     if filters.Keywords != "" {
         query += fmt.Sprintf(" AND (company_name ILIKE $%d OR position_title ILIKE $%d OR notes ILIKE $%d)", argIndex, argIndex, argIndex)
-        kw := "%" + filters.Keywords + "%"; args = append(args, kw)
+        kw := "%" + filters.Keywords + "%"; args = append(args, kw); argIndex++
     }
+    // 查询语句与列表/搜索共用同一构造器
+    parsed, err := querylang.Parse(filters.Query)
+    if err != nil { return "", nil, err }
+    queryCondition, queryArgs, argIndex := parsed.Conditions(argIndex)
+    query += queryCondition; args = append(args, queryArgs...)
     query += " ORDER BY application_date DESC, created_at DESC"
     if limit > 0 {
         query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
         args = append(args, limit, offset)
     }
-    return query, args
+    return query, args, nil
 }
 
 // 复用格式化（轻量）
@@ -8,6 +8,8 @@ import (
 
     "jobView-backend/internal/database"
     "jobView-backend/internal/model"
+    "jobView-backend/internal/salary"
+    "jobView-backend/internal/taxonomy"
 )
 
 // JobApplicationRepository 提供 JobApplication 的GORM Raw实现
@@ -37,12 +39,15 @@ func (r *jobAppRepo) Create(userID uint, req *model.CreateJobApplicationRequest)
         user_id, company_name, position_title, application_date, status,
         job_description, salary_range, work_location, contact_info, notes,
         interview_time, reminder_time, reminder_enabled, follow_up_date,
-        hr_name, hr_phone, hr_email, interview_location, interview_type
-    ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)
+        hr_name, hr_phone, hr_email, interview_location, interview_type, custom_fields,
+        role_family, role_level, tech_stack, role_taxonomy_version,
+        salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency, salary_parser_version
+    ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20::jsonb,$21,$22,$23::jsonb,$24,$25,$26,$27,$28,$29,$30,$31)
     RETURNING id, created_at, updated_at`
+    rules := taxonomy.Active()
+    position := rules.Normalize(req.PositionTitle)
 
-    var job model.JobApplication
-    row := r.db.ORM.Raw(query,
+    args := []interface{}{
         userID,
         req.CompanyName,
         req.PositionTitle,
@@ -62,7 +67,16 @@ func (r *jobAppRepo) Create(userID uint, req *model.CreateJobApplicationRequest)
         req.HREmail,
         req.InterviewLocation,
         req.InterviewType,
-    ).Row()
+        req.CustomFields,
+        nullIfEmpty(position.RoleFamily),
+        nullIfEmpty(position.RoleLevel),
+        model.StringList(position.TechStack),
+        rules.Version(),
+    }
+    args = append(args, salaryValues(req.SalaryRange)...)
+
+    var job model.JobApplication
+    row := r.db.ORM.Raw(query, args...).Row()
     if err := row.Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt); err != nil { return nil, fmt.Errorf("failed to create job application: %w", err) }
 
     job.UserID = userID
@@ -84,6 +98,10 @@ func (r *jobAppRepo) Create(userID uint, req *model.CreateJobApplicationRequest)
     job.HREmail = req.HREmail
     job.InterviewLocation = req.InterviewLocation
     job.InterviewType = req.InterviewType
+    job.CustomFields = req.CustomFields
+    if position.RoleFamily != "" { job.RoleFamily = &position.RoleFamily }
+    if position.RoleLevel != "" { job.RoleLevel = &position.RoleLevel }
+    job.TechStack = model.StringList(position.TechStack)
     return &job, nil
 }
 
@@ -93,7 +111,7 @@ func (r *jobAppRepo) GetByID(userID uint, id int) (*model.JobApplication, error)
         job_description, salary_range, work_location, contact_info, notes,
         interview_time, reminder_time, reminder_enabled, follow_up_date,
         hr_name, hr_phone, hr_email, interview_location, interview_type,
-        created_at, updated_at FROM job_applications WHERE id=$1 AND user_id=$2`
+        created_at, updated_at, ` + model.SuccessProbabilitySortExpr + `, custom_fields FROM job_applications WHERE id=$1 AND user_id=$2 AND deleted_at IS NULL`
     var job model.JobApplication
     row := r.db.ORM.Raw(query, id, userID).Row()
     if err := row.Scan(
@@ -101,7 +119,7 @@ func (r *jobAppRepo) GetByID(userID uint, id int) (*model.JobApplication, error)
         &job.JobDescription,&job.SalaryRange,&job.WorkLocation,&job.ContactInfo,&job.Notes,
         &job.InterviewTime,&job.ReminderTime,&job.ReminderEnabled,&job.FollowUpDate,
         &job.HRName,&job.HRPhone,&job.HREmail,&job.InterviewLocation,&job.InterviewType,
-        &job.CreatedAt,&job.UpdatedAt,
+        &job.CreatedAt,&job.UpdatedAt,&job.SuccessProbability,&job.CustomFields,
     ); err != nil {
         if err == sql.ErrNoRows { return nil, fmt.Errorf("job application not found") }
         return nil, fmt.Errorf("failed to get job application: %w", err)
@@ -115,7 +133,7 @@ func (r *jobAppRepo) GetAll(userID uint) ([]model.JobApplication, error) {
         job_description, salary_range, work_location, contact_info, notes,
         interview_time, reminder_time, reminder_enabled, follow_up_date,
         hr_name, hr_phone, hr_email, interview_location, interview_type,
-        created_at, updated_at FROM job_applications WHERE user_id = $1
+        created_at, updated_at, custom_fields FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL
         ORDER BY application_date DESC, created_at DESC LIMIT 500`
     rows, err := r.db.ORM.Raw(query, userID).Rows()
     if err != nil { return nil, fmt.Errorf("failed to get job applications: %w", err) }
@@ -127,7 +145,7 @@ func (r *jobAppRepo) GetAll(userID uint) ([]model.JobApplication, error) {
             &job.JobDescription,&job.SalaryRange,&job.WorkLocation,&job.ContactInfo,&job.Notes,
             &job.InterviewTime,&job.ReminderTime,&job.ReminderEnabled,&job.FollowUpDate,
             &job.HRName,&job.HRPhone,&job.HREmail,&job.InterviewLocation,&job.InterviewType,
-            &job.CreatedAt,&job.UpdatedAt); err != nil { return nil, fmt.Errorf("failed to scan job application: %w", err) }
+            &job.CreatedAt,&job.UpdatedAt,&job.CustomFields); err != nil { return nil, fmt.Errorf("failed to scan job application: %w", err) }
         list = append(list, job)
     }
     return list, nil
@@ -136,7 +154,7 @@ func (r *jobAppRepo) GetAll(userID uint) ([]model.JobApplication, error) {
 func (r *jobAppRepo) GetAllPaginated(userID uint, req model.PaginationRequest) (*model.PaginationResponse, error) {
     if r.db.ORM == nil { return nil, fmt.Errorf("gorm not initialized") }
     req.ValidateAndSetDefaults()
-    where := "WHERE user_id = $1"
+    where := "WHERE user_id = $1 AND deleted_at IS NULL"
     args := []interface{}{userID}
     idx := 2
     if req.Status != nil { where += fmt.Sprintf(" AND status = $%d", idx); args = append(args, *req.Status); idx++ }
@@ -146,14 +164,14 @@ func (r *jobAppRepo) GetAllPaginated(userID uint, req model.PaginationRequest) (
     if err := r.db.ORM.Raw(countSQL, args...).Row().Scan(&total); err != nil { return nil, fmt.Errorf("failed to count job applications: %w", err) }
     if total == 0 { return &model.PaginationResponse{Data: []model.JobApplication{}, Total: 0, Page: req.Page, PageSize: req.PageSize}, nil }
 
-    allowed := map[string]bool{"application_date":true,"created_at":true,"updated_at":true,"company_name":true,"position_title":true,"status":true}
+    allowed := map[string]bool{"application_date":true,"created_at":true,"updated_at":true,"company_name":true,"position_title":true,"status":true,"success_probability":true}
     if !allowed[req.SortBy] { req.SortBy = "application_date" }
     dataSQL := fmt.Sprintf(`SELECT id, user_id, company_name, position_title, application_date, status,
         job_description, salary_range, work_location, contact_info, notes,
         interview_time, reminder_time, reminder_enabled, follow_up_date,
         hr_name, hr_phone, hr_email, interview_location, interview_type,
-        created_at, updated_at FROM job_applications %s ORDER BY %s %s, created_at DESC LIMIT $%d OFFSET $%d`,
-        where, req.SortBy, req.SortDir, idx, idx+1)
+        created_at, updated_at, custom_fields FROM job_applications %s ORDER BY %s, created_at DESC LIMIT $%d OFFSET $%d`,
+        where, req.SortExpression(), idx, idx+1)
     args = append(args, req.PageSize, req.GetOffset())
     rows, err := r.db.ORM.Raw(dataSQL, args...).Rows()
     if err != nil { return nil, fmt.Errorf("failed to get job applications: %w", err) }
@@ -165,7 +183,7 @@ func (r *jobAppRepo) GetAllPaginated(userID uint, req model.PaginationRequest) (
             &job.JobDescription,&job.SalaryRange,&job.WorkLocation,&job.ContactInfo,&job.Notes,
             &job.InterviewTime,&job.ReminderTime,&job.ReminderEnabled,&job.FollowUpDate,
             &job.HRName,&job.HRPhone,&job.HREmail,&job.InterviewLocation,&job.InterviewType,
-            &job.CreatedAt,&job.UpdatedAt); err != nil { return nil, fmt.Errorf("failed to scan job application: %w", err) }
+            &job.CreatedAt,&job.UpdatedAt,&job.CustomFields); err != nil { return nil, fmt.Errorf("failed to scan job application: %w", err) }
         jobs = append(jobs, job)
     }
     totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
@@ -179,10 +197,23 @@ func (r *jobAppRepo) Update(userID uint, id int, req *model.UpdateJobApplication
     idx := 1
     if req.CompanyName != nil { setParts = append(setParts, fmt.Sprintf("company_name=$%d", idx)); args = append(args, *req.CompanyName); idx++ }
     if req.PositionTitle != nil { setParts = append(setParts, fmt.Sprintf("position_title=$%d", idx)); args = append(args, *req.PositionTitle); idx++ }
+    if req.PositionTitle != nil {
+        rules := taxonomy.Active()
+        position := rules.Normalize(*req.PositionTitle)
+        setParts = append(setParts, fmt.Sprintf("role_family=$%d, role_level=$%d, tech_stack=$%d::jsonb, role_taxonomy_version=$%d", idx, idx+1, idx+2, idx+3))
+        args = append(args, nullIfEmpty(position.RoleFamily), nullIfEmpty(position.RoleLevel), model.StringList(position.TechStack), rules.Version())
+        idx += 4
+    }
     if req.ApplicationDate != nil { setParts = append(setParts, fmt.Sprintf("application_date=$%d", idx)); args = append(args, *req.ApplicationDate); idx++ }
     if req.Status != nil { setParts = append(setParts, fmt.Sprintf("status=$%d", idx)); args = append(args, *req.Status); idx++ }
     if req.JobDescription != nil { setParts = append(setParts, fmt.Sprintf("job_description=$%d", idx)); args = append(args, *req.JobDescription); idx++ }
     if req.SalaryRange != nil { setParts = append(setParts, fmt.Sprintf("salary_range=$%d", idx)); args = append(args, *req.SalaryRange); idx++ }
+    if req.SalaryRange != nil {
+        setParts = append(setParts, fmt.Sprintf("salary_min_monthly=$%d, salary_max_monthly=$%d, salary_months=$%d, salary_annual_min=$%d, salary_annual_max=$%d, salary_currency=$%d, salary_parser_version=$%d",
+            idx, idx+1, idx+2, idx+3, idx+4, idx+5, idx+6))
+        args = append(args, salaryValues(req.SalaryRange)...)
+        idx += 7
+    }
     if req.WorkLocation != nil { setParts = append(setParts, fmt.Sprintf("work_location=$%d", idx)); args = append(args, *req.WorkLocation); idx++ }
     if req.ContactInfo != nil { setParts = append(setParts, fmt.Sprintf("contact_info=$%d", idx)); args = append(args, *req.ContactInfo); idx++ }
     if req.Notes != nil { setParts = append(setParts, fmt.Sprintf("notes=$%d", idx)); args = append(args, *req.Notes); idx++ }
@@ -195,17 +226,18 @@ func (r *jobAppRepo) Update(userID uint, id int, req *model.UpdateJobApplication
     if req.HREmail != nil { setParts = append(setParts, fmt.Sprintf("hr_email=$%d", idx)); args = append(args, *req.HREmail); idx++ }
     if req.InterviewLocation != nil { setParts = append(setParts, fmt.Sprintf("interview_location=$%d", idx)); args = append(args, *req.InterviewLocation); idx++ }
     if req.InterviewType != nil { setParts = append(setParts, fmt.Sprintf("interview_type=$%d", idx)); args = append(args, *req.InterviewType); idx++ }
+    if len(req.CustomFields) > 0 { setParts = append(setParts, fmt.Sprintf("custom_fields=jsonb_strip_nulls(custom_fields || $%d::jsonb)", idx)); args = append(args, req.CustomFields); idx++ }
     if len(setParts) == 0 { return r.GetByID(userID, id) }
     setParts = append(setParts, fmt.Sprintf("updated_at=$%d", idx)); args = append(args, time.Now()); idx++
     args = append(args, id, userID)
-    query := fmt.Sprintf(`UPDATE job_applications SET %s WHERE id=$%d AND user_id=$%d RETURNING id, user_id, company_name, position_title, application_date, status,
+    query := fmt.Sprintf(`UPDATE job_applications SET %s WHERE id=$%d AND user_id=$%d AND deleted_at IS NULL RETURNING id, user_id, company_name, position_title, application_date, status,
         job_description, salary_range, work_location, contact_info, notes,
         interview_time, reminder_time, reminder_enabled, follow_up_date,
         hr_name, hr_phone, hr_email, interview_location, interview_type,
-        created_at, updated_at`, strings.Join(setParts, ", "), idx, idx+1)
+        created_at, updated_at, custom_fields`, strings.Join(setParts, ", "), idx, idx+1)
     var job model.JobApplication
     row := r.db.ORM.Raw(query, args...).Row()
-    if err := row.Scan(&job.ID,&job.UserID,&job.CompanyName,&job.PositionTitle,&job.ApplicationDate,&job.Status,&job.JobDescription,&job.SalaryRange,&job.WorkLocation,&job.ContactInfo,&job.Notes,&job.InterviewTime,&job.ReminderTime,&job.ReminderEnabled,&job.FollowUpDate,&job.HRName,&job.HRPhone,&job.HREmail,&job.InterviewLocation,&job.InterviewType,&job.CreatedAt,&job.UpdatedAt); err != nil {
+    if err := row.Scan(&job.ID,&job.UserID,&job.CompanyName,&job.PositionTitle,&job.ApplicationDate,&job.Status,&job.JobDescription,&job.SalaryRange,&job.WorkLocation,&job.ContactInfo,&job.Notes,&job.InterviewTime,&job.ReminderTime,&job.ReminderEnabled,&job.FollowUpDate,&job.HRName,&job.HRPhone,&job.HREmail,&job.InterviewLocation,&job.InterviewType,&job.CreatedAt,&job.UpdatedAt,&job.CustomFields); err != nil {
         if err == sql.ErrNoRows { return nil, fmt.Errorf("job application not found") }
         return nil, fmt.Errorf("failed to update job application: %w", err)
     }
@@ -214,9 +246,24 @@ func (r *jobAppRepo) Update(userID uint, id int, req *model.UpdateJobApplication
 
 func (r *jobAppRepo) Delete(userID uint, id int) error {
     if r.db.ORM == nil { return fmt.Errorf("gorm not initialized") }
-    res := r.db.ORM.Exec("DELETE FROM job_applications WHERE id = $1 AND user_id = $2", id, userID)
+    res := r.db.ORM.Exec("UPDATE job_applications SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", id, userID)
     if res.Error != nil { return fmt.Errorf("failed to delete job application: %w", res.Error) }
     if res.RowsAffected == 0 { return fmt.Errorf("job application not found") }
     return nil
 }
 
+// nullIfEmpty 空字符串写入为 NULL
+func nullIfEmpty(value string) interface{} {
+    if value == "" { return nil }
+    return value
+}
+
+// salaryValues 解析薪资描述，返回结构化薪酬七列的写入值；无法解析时薪酬列为 NULL
+func salaryValues(raw *string) []interface{} {
+    if raw != nil {
+        if parsed, ok := salary.Parse(*raw); ok {
+            return []interface{}{parsed.MinMonthly, parsed.MaxMonthly, parsed.Months, parsed.AnnualMin, parsed.AnnualMax, parsed.Currency, salary.ParserVersion}
+        }
+    }
+    return []interface{}{nil, nil, nil, nil, nil, nil, salary.ParserVersion}
+}
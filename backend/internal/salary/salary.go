@@ -0,0 +1,329 @@
+// Package salary 将自由填写的薪资描述解析为结构化薪酬，例如：
+//
+//	"20-30K·15薪" -> 月薪 20000-30000，15 薪，年薪 300000-450000
+//	"25k*16"      -> 月薪 25000，16 薪，年薪 400000
+//	"30-45万/年"   -> 年薪 300000-450000，按 12 薪折算月薪 25000-37500
+//	"$120k"       -> USD 年薪 120000，月薪 10000
+//
+// 金额均以币种的基本单位（元、美元）表示。无法识别（如“面议”）时返回 false。
+package salary
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParserVersion 解析规则版本，规则变更后递增以触发历史数据重新解析
+const ParserVersion = 2
+
+// DefaultMonths 未注明薪数时按 12 薪计算
+const DefaultMonths = 12.0
+
+// 折算系数：日薪按每月 21.75 个工作日，时薪按每天 8 小时
+const (
+	workDaysPerMonth = 21.75
+	hoursPerDay      = 8.0
+)
+
+// DefaultCurrency 未注明币种时的默认币种
+const DefaultCurrency = "CNY"
+
+// Parsed 结构化薪酬
+type Parsed struct {
+	MinMonthly float64 `json:"min_monthly"`
+	MaxMonthly float64 `json:"max_monthly"`
+	Months     float64 `json:"months"`
+	AnnualMin  float64 `json:"annual_min"`
+	AnnualMax  float64 `json:"annual_max"`
+	Currency   string  `json:"currency"`
+}
+
+// period 薪资描述对应的计薪周期
+type period int
+
+const (
+	periodUnknown period = iota
+	periodMonth
+	periodYear
+	periodDay
+	periodHour
+)
+
+var (
+	// normalizer 统一全角符号与区间连接符
+	normalizer = strings.NewReplacer(
+		"～", "-", "~", "-", "—", "-", "–", "-", "至", "-", "到", "-",
+		"＊", "*", "×", "*", "￥", "¥", "＄", "$", "Ｋ", "k", "ｋ", "k", "／", "/",
+	)
+	// thousandsSeparator 数字中的千分位逗号
+	thousandsSeparator = regexp.MustCompile(`(\d),(\d{3})`)
+	// monthsPattern 薪数：“15薪”或“25k*16”
+	monthsPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*薪|\*\s*(\d+(?:\.\d+)?)`)
+	// amountPattern 金额或金额区间，单位可省略（区间只写一个单位时两端共用）
+	amountPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(k|千|w|万)?\s*(?:-\s*(?:[$¥€£]\s*)?(\d+(?:\.\d+)?)\s*(k|千|w|万)?)?`)
+)
+
+// currencyMarkers 币种标记，按顺序匹配（HK$、S$ 需先于 $）
+var currencyMarkers = []struct {
+	marker   string
+	currency string
+}{
+	{"hk$", "HKD"}, {"hkd", "HKD"}, {"港币", "HKD"},
+	{"s$", "SGD"}, {"sgd", "SGD"}, {"新币", "SGD"},
+	{"us$", "USD"}, {"usd", "USD"}, {"美元", "USD"}, {"$", "USD"},
+	{"€", "EUR"}, {"eur", "EUR"}, {"欧元", "EUR"},
+	{"£", "GBP"}, {"gbp", "GBP"}, {"英镑", "GBP"},
+	{"rmb", "CNY"}, {"cny", "CNY"}, {"¥", "CNY"}, {"元", "CNY"},
+}
+
+// periodMarker 计薪周期标记
+type periodMarker struct {
+	marker string
+	period period
+}
+
+// periodPrefixes 紧挨在金额前的计薪周期标记，如“年薪40万”
+var periodPrefixes = []periodMarker{
+	{"年薪", periodYear}, {"年包", periodYear}, {"annual", periodYear},
+	{"月薪", periodMonth}, {"日薪", periodDay}, {"时薪", periodHour},
+}
+
+// periodSuffixes 紧跟在金额（及币种）后的计薪周期标记，如“30-45万/年”“200元/天”。
+// 只认金额旁边的标记，“年终奖3个月”“3年经验”等描述中的年/月不影响计薪周期
+var periodSuffixes = []periodMarker{
+	{"/年", periodYear}, {"每年", periodYear}, {"年薪", periodYear}, {"/yr", periodYear}, {"/year", periodYear},
+	{"per year", periodYear}, {"a year", periodYear}, {"p.a", periodYear}, {"annual", periodYear}, {"yearly", periodYear},
+	{"/月", periodMonth}, {"每月", periodMonth}, {"月薪", periodMonth}, {"/mo", periodMonth},
+	{"per month", periodMonth}, {"a month", periodMonth}, {"monthly", periodMonth},
+	{"/天", periodDay}, {"/日", periodDay}, {"每天", periodDay}, {"日薪", periodDay}, {"/day", periodDay},
+	{"per day", periodDay}, {"a day", periodDay}, {"daily", periodDay},
+	{"/小时", periodHour}, {"/时", periodHour}, {"每小时", periodHour}, {"时薪", periodHour}, {"/h", periodHour},
+	{"per hour", periodHour}, {"an hour", periodHour}, {"hourly", periodHour},
+}
+
+// durationSuffixes 表示时长而非金额的数字后缀，如“3年经验”“3个月”
+var durationSuffixes = []string{"年", "个月", "月", "天", "周", "year", "yr", "month", "week", "day"}
+
+// Parse 解析薪资描述
+func Parse(raw string) (Parsed, bool) {
+	text := strings.ToLower(strings.TrimSpace(normalizer.Replace(raw)))
+	text = thousandsSeparator.ReplaceAllString(text, "$1$2")
+	if text == "" || strings.Contains(text, "面议") || strings.Contains(text, "negotiable") {
+		return Parsed{}, false
+	}
+
+	result := Parsed{Currency: DefaultCurrency, Months: DefaultMonths}
+	for _, c := range currencyMarkers {
+		if strings.Contains(text, c.marker) {
+			result.Currency = c.currency
+			break
+		}
+	}
+
+	if m := monthsPattern.FindStringSubmatchIndex(text); m != nil {
+		group := 1
+		if m[2] < 0 {
+			group = 2
+		}
+		if months, err := strconv.ParseFloat(text[m[2*group]:m[2*group+1]], 64); err == nil && months >= 1 && months <= 24 {
+			result.Months = months
+		}
+		text = text[:m[0]] + " " + text[m[1]:]
+	}
+
+	var m []string
+	p := periodUnknown
+	for _, loc := range amountPattern.FindAllStringSubmatchIndex(text, -1) {
+		candidate := submatches(text, loc)
+		if isDuration(candidate, text[loc[1]:]) {
+			continue
+		}
+		m = candidate
+		p = amountPeriod(text[:loc[0]], text[loc[1]:])
+		break
+	}
+	if m == nil {
+		return Parsed{}, false
+	}
+	lowUnit, highUnit := m[2], m[4]
+	if lowUnit == "" {
+		lowUnit = highUnit
+	}
+	low := amount(m[1], lowUnit)
+	high := low
+	if m[3] != "" {
+		high = amount(m[3], highUnit)
+	}
+	if low > high {
+		low, high = high, low
+	}
+	if high <= 0 {
+		return Parsed{}, false
+	}
+
+	// 无单位的小数字按千元理解（“20-30” 即 20-30K），与按天/小时计薪的描述区分
+	if lowUnit == "" && highUnit == "" && high < 1000 && (p == periodUnknown || p == periodMonth) {
+		low, high = low*1000, high*1000
+	}
+
+	// 未注明周期：人民币默认月薪，达到 10 万按年薪；外币默认年薪，不足 2 万按月薪
+	if p == periodUnknown {
+		switch {
+		case result.Currency == DefaultCurrency && high >= 100000:
+			p = periodYear
+		case result.Currency == DefaultCurrency:
+			p = periodMonth
+		case high < 20000:
+			p = periodMonth
+		default:
+			p = periodYear
+		}
+	}
+
+	switch p {
+	case periodYear:
+		result.AnnualMin, result.AnnualMax = low, high
+		result.MinMonthly, result.MaxMonthly = low/result.Months, high/result.Months
+	default:
+		factor := 1.0
+		if p == periodDay {
+			factor = workDaysPerMonth
+		} else if p == periodHour {
+			factor = workDaysPerMonth * hoursPerDay
+		}
+		result.MinMonthly, result.MaxMonthly = low*factor, high*factor
+		result.AnnualMin, result.AnnualMax = result.MinMonthly*result.Months, result.MaxMonthly*result.Months
+	}
+
+	result.MinMonthly = round2(result.MinMonthly)
+	result.MaxMonthly = round2(result.MaxMonthly)
+	result.AnnualMin = round2(result.AnnualMin)
+	result.AnnualMax = round2(result.AnnualMax)
+	return result, true
+}
+
+// submatches 按匹配位置取出各分组，未匹配的分组为空串
+func submatches(text string, loc []int) []string {
+	groups := make([]string, len(loc)/2)
+	for i := range groups {
+		if loc[2*i] >= 0 {
+			groups[i] = text[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return groups
+}
+
+// isDuration 不带单位的单个数字后紧跟时长（“3年经验”“3个月”）时不是金额
+func isDuration(m []string, after string) bool {
+	if m[2] != "" || m[3] != "" {
+		return false
+	}
+	after = strings.TrimLeft(after, " ")
+	if strings.HasPrefix(after, "年薪") {
+		return false
+	}
+	for _, suffix := range durationSuffixes {
+		if strings.HasPrefix(after, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// amountPeriod 根据金额紧邻的前后文判断计薪周期，中间可以有空白、冒号与币种标记
+func amountPeriod(before, after string) period {
+	before = strings.TrimRight(before, " :：")
+	for _, c := range currencyMarkers {
+		if strings.HasSuffix(before, c.marker) {
+			before = strings.TrimRight(strings.TrimSuffix(before, c.marker), " :：")
+			break
+		}
+	}
+	for _, prefix := range periodPrefixes {
+		if strings.HasSuffix(before, prefix.marker) {
+			return prefix.period
+		}
+	}
+
+	after = strings.TrimLeft(after, " ")
+	for _, c := range currencyMarkers {
+		if strings.HasPrefix(after, c.marker) {
+			after = strings.TrimLeft(strings.TrimPrefix(after, c.marker), " ")
+			break
+		}
+	}
+	for _, suffix := range periodSuffixes {
+		if strings.HasPrefix(after, suffix.marker) {
+			return suffix.period
+		}
+	}
+	return periodUnknown
+}
+
+// amount 按单位换算金额
+func amount(value, unit string) float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	switch unit {
+	case "k", "千":
+		return n * 1000
+	case "w", "万":
+		return n * 10000
+	}
+	return n
+}
+
+// MidMonthly 月薪中位值
+func (p Parsed) MidMonthly() float64 {
+	return round2((p.MinMonthly + p.MaxMonthly) / 2)
+}
+
+// MidAnnual 年薪中位值
+func (p Parsed) MidAnnual() float64 {
+	return round2((p.AnnualMin + p.AnnualMax) / 2)
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// monthlyBucketBounds 月薪分布区间边界（千），最后一档无上限
+var monthlyBucketBounds = []float64{0, 10, 15, 20, 25, 30, 40, 50}
+
+// MonthlyBucket 返回月薪（基本单位）所在分布区间的序号与标签，如 "20-25K"、"50K+"
+func MonthlyBucket(monthly float64) (int, string) {
+	k := monthly / 1000
+	for i := len(monthlyBucketBounds) - 1; i > 0; i-- {
+		if k >= monthlyBucketBounds[i] {
+			return i, BucketLabel(i)
+		}
+	}
+	return 0, BucketLabel(0)
+}
+
+// BucketCount 月薪分布区间数量
+func BucketCount() int {
+	return len(monthlyBucketBounds)
+}
+
+// BucketLabel 分布区间标签
+func BucketLabel(i int) string {
+	if i >= len(monthlyBucketBounds)-1 {
+		return strconv.FormatFloat(monthlyBucketBounds[len(monthlyBucketBounds)-1], 'f', -1, 64) + "K+"
+	}
+	return strconv.FormatFloat(monthlyBucketBounds[i], 'f', -1, 64) + "-" +
+		strconv.FormatFloat(monthlyBucketBounds[i+1], 'f', -1, 64) + "K"
+}
+
+// BucketRange 分布区间的月薪范围（基本单位），最后一档上限为 0 表示无上限
+func BucketRange(i int) (float64, float64) {
+	if i >= len(monthlyBucketBounds)-1 {
+		return monthlyBucketBounds[len(monthlyBucketBounds)-1] * 1000, 0
+	}
+	return monthlyBucketBounds[i] * 1000, monthlyBucketBounds[i+1] * 1000
+}
@@ -0,0 +1,62 @@
+package salary
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want Parsed
+	}{
+		{"20-30K·15薪", Parsed{MinMonthly: 20000, MaxMonthly: 30000, Months: 15, AnnualMin: 300000, AnnualMax: 450000, Currency: "CNY"}},
+		{"25k*16", Parsed{MinMonthly: 25000, MaxMonthly: 25000, Months: 16, AnnualMin: 400000, AnnualMax: 400000, Currency: "CNY"}},
+		{"30-45万/年", Parsed{MinMonthly: 25000, MaxMonthly: 37500, Months: 12, AnnualMin: 300000, AnnualMax: 450000, Currency: "CNY"}},
+		{"$120k", Parsed{MinMonthly: 10000, MaxMonthly: 10000, Months: 12, AnnualMin: 120000, AnnualMax: 120000, Currency: "USD"}},
+		{"20k～30k", Parsed{MinMonthly: 20000, MaxMonthly: 30000, Months: 12, AnnualMin: 240000, AnnualMax: 360000, Currency: "CNY"}},
+		{"15-25", Parsed{MinMonthly: 15000, MaxMonthly: 25000, Months: 12, AnnualMin: 180000, AnnualMax: 300000, Currency: "CNY"}},
+		{"20,000-30,000元/月 13薪", Parsed{MinMonthly: 20000, MaxMonthly: 30000, Months: 13, AnnualMin: 260000, AnnualMax: 390000, Currency: "CNY"}},
+		{"2.5w", Parsed{MinMonthly: 25000, MaxMonthly: 25000, Months: 12, AnnualMin: 300000, AnnualMax: 300000, Currency: "CNY"}},
+		{"年薪40万 16薪", Parsed{MinMonthly: 25000, MaxMonthly: 25000, Months: 16, AnnualMin: 400000, AnnualMax: 400000, Currency: "CNY"}},
+		{"200元/天", Parsed{MinMonthly: 4350, MaxMonthly: 4350, Months: 12, AnnualMin: 52200, AnnualMax: 52200, Currency: "CNY"}},
+		{"HK$40k-50k/month", Parsed{MinMonthly: 40000, MaxMonthly: 50000, Months: 12, AnnualMin: 480000, AnnualMax: 600000, Currency: "HKD"}},
+		// 只有紧挨金额的标记决定计薪周期，描述中其他位置的年/月/天不影响
+		{"15-25K·13薪 年终奖3个月", Parsed{MinMonthly: 15000, MaxMonthly: 25000, Months: 13, AnnualMin: 195000, AnnualMax: 325000, Currency: "CNY"}},
+		{"20-30k 3年经验", Parsed{MinMonthly: 20000, MaxMonthly: 30000, Months: 12, AnnualMin: 240000, AnnualMax: 360000, Currency: "CNY"}},
+		{"3年经验 20-30k", Parsed{MinMonthly: 20000, MaxMonthly: 30000, Months: 12, AnnualMin: 240000, AnnualMax: 360000, Currency: "CNY"}},
+		{"20k 每周工作5天", Parsed{MinMonthly: 20000, MaxMonthly: 20000, Months: 12, AnnualMin: 240000, AnnualMax: 240000, Currency: "CNY"}},
+		{"年薪：¥40万", Parsed{MinMonthly: 33333.33, MaxMonthly: 33333.33, Months: 12, AnnualMin: 400000, AnnualMax: 400000, Currency: "CNY"}},
+		{"30万元/年", Parsed{MinMonthly: 25000, MaxMonthly: 25000, Months: 12, AnnualMin: 300000, AnnualMax: 300000, Currency: "CNY"}},
+		{"日薪 500", Parsed{MinMonthly: 10875, MaxMonthly: 10875, Months: 12, AnnualMin: 130500, AnnualMax: 130500, Currency: "CNY"}},
+		{"$50/hour", Parsed{MinMonthly: 8700, MaxMonthly: 8700, Months: 12, AnnualMin: 104400, AnnualMax: 104400, Currency: "USD"}},
+	}
+	for _, c := range cases {
+		got, ok := Parse(c.raw)
+		if !ok || got != c.want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", c.raw, got, ok, c.want)
+		}
+	}
+
+	for _, raw := range []string{"", "面议", "negotiable", "看能力"} {
+		if _, ok := Parse(raw); ok {
+			t.Errorf("expected %q to be unparseable", raw)
+		}
+	}
+}
+
+func TestMonthlyBucket(t *testing.T) {
+	cases := []struct {
+		monthly float64
+		label   string
+	}{
+		{5000, "0-10K"},
+		{10000, "10-15K"},
+		{24999, "20-25K"},
+		{35000, "30-40K"},
+		{50000, "50K+"},
+		{120000, "50K+"},
+	}
+	for _, c := range cases {
+		if _, label := MonthlyBucket(c.monthly); label != c.label {
+			t.Errorf("MonthlyBucket(%v) = %q, want %q", c.monthly, label, c.label)
+		}
+	}
+}
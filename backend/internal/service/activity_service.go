@@ -0,0 +1,529 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/activity_service.go
+// This file implements the unified per-application activity timeline.
+// It merges job_status_history, field edits from job_application_audit_logs and application_activities
+// (notes, exports, interview scheduling, fired reminders, offer deadline reminders) into one feed with
+// stable cursor pagination. Interview changes are recorded by a trigger on interview_time and fired
+// reminders by StartReminderJob, so reschedules and earlier reminders stay on the timeline.
+// Applications have no attachments, so there is no attachment entry type.
+
+package service
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimelineLimit = 20
+	maxTimelineLimit     = 100
+	// activityInsertChunk 单条 INSERT 中的最大申请ID数量
+	activityInsertChunk = 1000
+	// reminderCheckInterval 检查到期申请提醒的间隔
+	reminderCheckInterval = 5 * time.Minute
+)
+
+// 时间线数据来源，同一时间点按来源排序，保证游标稳定
+const (
+	timelineSourceStatus = iota + 1
+	timelineSourceActivity
+	timelineSourceAudit
+)
+
+type ActivityService struct {
+	db *database.DB
+}
+
+func NewActivityService(db *database.DB) *ActivityService {
+	return &ActivityService{db: db}
+}
+
+// activityExecer 可执行 SQL 的对象（*database.DB 或 *sql.Tx）
+type activityExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// recordActivity 为用户名下的申请写入活动记录，不属于该用户的ID会被忽略
+func recordActivity(db activityExecer, userID uint, jobIDs []int, activityType model.ActivityType, summary string, payload map[string]interface{}) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity payload: %w", err)
+	}
+
+	for start := 0; start < len(jobIDs); start += activityInsertChunk {
+		end := start + activityInsertChunk
+		if end > len(jobIDs) {
+			end = len(jobIDs)
+		}
+		args := []interface{}{userID, string(activityType), summary, string(payloadJSON)}
+		placeholders := make([]string, 0, end-start)
+		for _, id := range jobIDs[start:end] {
+			args = append(args, id)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		query := fmt.Sprintf(`
+			INSERT INTO application_activities (job_application_id, user_id, activity_type, summary, payload)
+			SELECT id, user_id, $2, $3, $4::jsonb
+			FROM job_applications
+			WHERE user_id = $1 AND deleted_at IS NULL AND id IN (%s)
+		`, strings.Join(placeholders, ", "))
+		if _, err := db.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to record %s activity: %w", activityType, err)
+		}
+	}
+	return nil
+}
+
+// AddNote 为申请添加一条备注
+func (s *ActivityService) AddNote(userID uint, jobApplicationID int, content string) (*model.TimelineItem, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("invalid note: content is required")
+	}
+	if len([]rune(content)) > 2000 {
+		return nil, fmt.Errorf("invalid note: too long (max 2000 characters)")
+	}
+
+	var id int64
+	var occurredAt time.Time
+	err := s.db.QueryRow(`
+		INSERT INTO application_activities (job_application_id, user_id, activity_type, summary, payload)
+		SELECT id, user_id, $3, $4, '{}'::jsonb
+		FROM job_applications
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING id, occurred_at
+	`, jobApplicationID, userID, string(model.ActivityNote), content).Scan(&id, &occurredAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("job application not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to add note: %w", err)
+	}
+
+	return &model.TimelineItem{
+		ID:         fmt.Sprintf("%s:%d", model.ActivityNote, id),
+		Type:       model.ActivityNote,
+		OccurredAt: occurredAt,
+		Summary:    content,
+	}, nil
+}
+
+// StartReminderJob 定期把已到期的申请提醒记入时间线
+func (s *ActivityService) StartReminderJob() {
+	go func() {
+		ticker := time.NewTicker(reminderCheckInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			recorded, err := s.RecordDueReminders()
+			if err != nil {
+				log.Printf("Warning: application reminders failed: %v", err)
+				continue
+			}
+			if recorded > 0 {
+				log.Printf("[REMINDER] reminders=%d", recorded)
+			}
+		}
+	}()
+}
+
+// RecordDueReminders 为所有用户已到期的申请提醒写入时间线，发生时间为提醒时间；返回记录条数。
+// 每个提醒时间只记录一次，修改提醒时间后到期会再次记录
+func (s *ActivityService) RecordDueReminders() (int, error) {
+	result, err := s.db.Exec(`
+		INSERT INTO application_activities (job_application_id, user_id, activity_type, summary, payload, occurred_at)
+		SELECT ja.id, ja.user_id, $1, '提醒已触发', jsonb_build_object('reminder_time', ja.reminder_time), ja.reminder_time
+		FROM job_applications ja
+		WHERE ja.deleted_at IS NULL AND ja.reminder_enabled = TRUE AND ja.reminder_time <= NOW()
+		  AND NOT EXISTS (
+		      SELECT 1 FROM application_activities aa
+		      WHERE aa.job_application_id = ja.id AND aa.activity_type = $1
+		        AND (aa.payload->>'reminder_time')::timestamptz = ja.reminder_time
+		  )
+	`, string(model.ActivityReminder))
+	if err != nil {
+		return 0, fmt.Errorf("failed to record reminder activities: %w", err)
+	}
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count reminder activities: %w", err)
+	}
+	return int(recorded), nil
+}
+
+// timelineCursor 游标：上一页最后一条的 (时间, 来源, ID)
+type timelineCursor struct {
+	At     time.Time
+	Source int
+	ID     int64
+}
+
+func encodeTimelineCursor(c timelineCursor) string {
+	raw := fmt.Sprintf("%s|%d|%d", c.At.UTC().Format(time.RFC3339Nano), c.Source, c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTimelineCursor(s string) (*timelineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	at, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	source, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &timelineCursor{At: at, Source: source, ID: id}, nil
+}
+
+// before 判断记录是否排在游标之后（时间线按时间、来源、ID倒序）
+func (c *timelineCursor) before(at time.Time, source int, id int64) bool {
+	if c == nil {
+		return true
+	}
+	if !at.Equal(c.At) {
+		return at.Before(c.At)
+	}
+	if source != c.Source {
+		return source < c.Source
+	}
+	return id < c.ID
+}
+
+// sqlCondition 生成某一来源在游标之后的 SQL 条件
+func (c *timelineCursor) sqlCondition(source int, timeCol, idCol string, args *[]interface{}) string {
+	if c == nil {
+		return ""
+	}
+	*args = append(*args, c.At)
+	timeArg := len(*args)
+	switch {
+	case source < c.Source:
+		return fmt.Sprintf(" AND %s <= $%d", timeCol, timeArg)
+	case source > c.Source:
+		return fmt.Sprintf(" AND %s < $%d", timeCol, timeArg)
+	}
+	*args = append(*args, c.ID)
+	return fmt.Sprintf(" AND (%s < $%d OR (%s = $%d AND %s < $%d))", timeCol, timeArg, timeCol, timeArg, idCol, len(*args))
+}
+
+// timelineEntry 带排序键的时间线条目
+type timelineEntry struct {
+	Source int
+	RowID  int64
+	Item   model.TimelineItem
+}
+
+// GetTimeline 获取申请的统一活动时间线
+func (s *ActivityService) GetTimeline(userID uint, jobApplicationID int, req *model.ActivityTimelineRequest) (*model.ActivityTimelineResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = defaultTimelineLimit
+	}
+	if req.Limit > maxTimelineLimit {
+		req.Limit = maxTimelineLimit
+	}
+	for _, t := range req.Types {
+		if !t.IsValid() {
+			return nil, fmt.Errorf("invalid activity type: %s", t)
+		}
+	}
+	var cursor *timelineCursor
+	if req.Cursor != "" {
+		c, err := decodeTimelineCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+	}
+	wants := func(t model.ActivityType) bool {
+		if len(req.Types) == 0 {
+			return true
+		}
+		for _, v := range req.Types {
+			if v == t {
+				return true
+			}
+		}
+		return false
+	}
+
+	// 校验申请归属
+	var exists bool
+	if err := s.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)
+	`, jobApplicationID, userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to get job application: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("job application not found or access denied")
+	}
+
+	var entries []timelineEntry
+	fetch := req.Limit + 1
+
+	if wants(model.ActivityStatusChange) {
+		statusEntries, err := s.loadStatusEntries(userID, jobApplicationID, cursor, fetch)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, statusEntries...)
+	}
+
+	if wants(model.ActivityFieldEdit) {
+		auditEntries, err := s.loadFieldEditEntries(userID, jobApplicationID, cursor, fetch)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, auditEntries...)
+	}
+
+	var activityTypes []model.ActivityType
+	for _, t := range []model.ActivityType{model.ActivityNote, model.ActivityInterview, model.ActivityReminder, model.ActivityExport, model.ActivityDeadlineReminder} {
+		if wants(t) {
+			activityTypes = append(activityTypes, t)
+		}
+	}
+	if len(activityTypes) > 0 {
+		activityEntries, err := s.loadActivityEntries(userID, jobApplicationID, activityTypes, cursor, fetch)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, activityEntries...)
+	}
+
+	items, next := paginateTimeline(entries, cursor, req.Limit)
+	return &model.ActivityTimelineResponse{
+		JobApplicationID: jobApplicationID,
+		Items:            items,
+		NextCursor:       next,
+		HasMore:          next != "",
+	}, nil
+}
+
+// loadStatusEntries 读取状态变更记录
+func (s *ActivityService) loadStatusEntries(userID uint, jobApplicationID int, cursor *timelineCursor, limit int) ([]timelineEntry, error) {
+	args := []interface{}{jobApplicationID, userID}
+	cond := cursor.sqlCondition(timelineSourceStatus, "status_changed_at", "id", &args)
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT id, old_status, new_status, status_changed_at, duration_minutes, metadata
+		FROM job_status_history
+		WHERE job_application_id = $1 AND user_id = $2%s
+		ORDER BY status_changed_at DESC, id DESC
+		LIMIT $%d
+	`, cond, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []timelineEntry
+	for rows.Next() {
+		var (
+			id           int64
+			oldStatus    sql.NullString
+			newStatus    string
+			changedAt    time.Time
+			duration     sql.NullInt64
+			metadataJSON []byte
+		)
+		if err := rows.Scan(&id, &oldStatus, &newStatus, &changedAt, &duration, &metadataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan status history: %w", err)
+		}
+		data := map[string]interface{}{"new_status": newStatus}
+		summary := "状态变更为 " + newStatus
+		if oldStatus.Valid {
+			data["old_status"] = oldStatus.String
+			summary = oldStatus.String + " → " + newStatus
+		}
+		if duration.Valid {
+			data["duration_minutes"] = duration.Int64
+		}
+		var metadata map[string]interface{}
+		if len(metadataJSON) > 0 && json.Unmarshal(metadataJSON, &metadata) == nil && len(metadata) > 0 {
+			data["metadata"] = metadata
+		}
+		entries = append(entries, timelineEntry{
+			Source: timelineSourceStatus,
+			RowID:  id,
+			Item: model.TimelineItem{
+				ID:         fmt.Sprintf("%s:%d", model.ActivityStatusChange, id),
+				Type:       model.ActivityStatusChange,
+				OccurredAt: changedAt,
+				Summary:    summary,
+				Data:       data,
+			},
+		})
+	}
+	return entries, rows.Err()
+}
+
+// loadFieldEditEntries 从审计日志读取字段编辑（不含仅状态变化的记录，状态变更单独展示）
+func (s *ActivityService) loadFieldEditEntries(userID uint, jobApplicationID int, cursor *timelineCursor, limit int) ([]timelineEntry, error) {
+	args := []interface{}{jobApplicationID, userID}
+	cond := cursor.sqlCondition(timelineSourceAudit, "created_at", "id", &args)
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT id, action, changes - 'status', created_at
+		FROM job_application_audit_logs
+		WHERE job_application_id = $1 AND user_id = $2
+		  AND action IN ('update', 'batch_update')
+		  AND changes - 'status' <> '{}'::jsonb%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d
+	`, cond, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query field edits: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []timelineEntry
+	for rows.Next() {
+		var (
+			id          int64
+			action      string
+			changesJSON []byte
+			createdAt   time.Time
+		)
+		if err := rows.Scan(&id, &action, &changesJSON, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan field edit: %w", err)
+		}
+		var changes map[string]model.FieldChange
+		if err := json.Unmarshal(changesJSON, &changes); err != nil {
+			return nil, fmt.Errorf("failed to decode field edit: %w", err)
+		}
+		fields := make([]string, 0, len(changes))
+		for field := range changes {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		entries = append(entries, timelineEntry{
+			Source: timelineSourceAudit,
+			RowID:  id,
+			Item: model.TimelineItem{
+				ID:         fmt.Sprintf("%s:%d", model.ActivityFieldEdit, id),
+				Type:       model.ActivityFieldEdit,
+				OccurredAt: createdAt,
+				Summary:    fmt.Sprintf("更新了字段：%s", strings.Join(fields, ", ")),
+				Data:       map[string]interface{}{"action": action, "changes": changes},
+			},
+		})
+	}
+	return entries, rows.Err()
+}
+
+// loadActivityEntries 读取 application_activities 中的记录
+func (s *ActivityService) loadActivityEntries(userID uint, jobApplicationID int, types []model.ActivityType, cursor *timelineCursor, limit int) ([]timelineEntry, error) {
+	args := []interface{}{jobApplicationID, userID}
+	placeholders := make([]string, len(types))
+	for i, t := range types {
+		args = append(args, string(t))
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	cond := cursor.sqlCondition(timelineSourceActivity, "occurred_at", "id", &args)
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT id, activity_type, summary, payload, occurred_at
+		FROM application_activities
+		WHERE job_application_id = $1 AND user_id = $2 AND activity_type IN (%s)%s
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $%d
+	`, strings.Join(placeholders, ", "), cond, len(args))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activities: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []timelineEntry
+	for rows.Next() {
+		var (
+			id          int64
+			typ         string
+			summary     string
+			payloadJSON []byte
+			occurredAt  time.Time
+		)
+		if err := rows.Scan(&id, &typ, &summary, &payloadJSON, &occurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		var payload map[string]interface{}
+		if len(payloadJSON) > 0 {
+			_ = json.Unmarshal(payloadJSON, &payload)
+		}
+		if len(payload) == 0 {
+			payload = nil
+		}
+		entries = append(entries, timelineEntry{
+			Source: timelineSourceActivity,
+			RowID:  id,
+			Item: model.TimelineItem{
+				ID:         fmt.Sprintf("%s:%d", typ, id),
+				Type:       model.ActivityType(typ),
+				OccurredAt: occurredAt,
+				Summary:    summary,
+				Data:       payload,
+			},
+		})
+	}
+	return entries, rows.Err()
+}
+
+// paginateTimeline 合并各来源，按时间倒序取一页并生成下一页游标
+func paginateTimeline(entries []timelineEntry, cursor *timelineCursor, limit int) ([]model.TimelineItem, string) {
+	filtered := entries[:0]
+	for _, e := range entries {
+		if cursor.before(e.Item.OccurredAt, e.Source, e.RowID) {
+			filtered = append(filtered, e)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if !a.Item.OccurredAt.Equal(b.Item.OccurredAt) {
+			return a.Item.OccurredAt.After(b.Item.OccurredAt)
+		}
+		if a.Source != b.Source {
+			return a.Source > b.Source
+		}
+		return a.RowID > b.RowID
+	})
+
+	items := make([]model.TimelineItem, 0, limit)
+	for i := 0; i < len(filtered) && i < limit; i++ {
+		items = append(items, filtered[i].Item)
+	}
+	if len(filtered) <= limit {
+		return items, ""
+	}
+	last := filtered[limit-1]
+	return items, encodeTimelineCursor(timelineCursor{At: last.Item.OccurredAt, Source: last.Source, ID: last.RowID})
+}
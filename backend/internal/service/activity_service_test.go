@@ -0,0 +1,48 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestPaginateTimeline(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 123000, time.UTC)
+	entries := []timelineEntry{
+		{Source: timelineSourceStatus, RowID: 1, Item: model.TimelineItem{ID: "status_change:1", OccurredAt: base}},
+		{Source: timelineSourceActivity, RowID: 7, Item: model.TimelineItem{ID: "note:7", OccurredAt: base}},
+		{Source: timelineSourceActivity, RowID: 8, Item: model.TimelineItem{ID: "note:8", OccurredAt: base.Add(time.Hour)}},
+		{Source: timelineSourceAudit, RowID: 3, Item: model.TimelineItem{ID: "field_edit:3", OccurredAt: base.Add(-time.Hour)}},
+	}
+
+	var seen []string
+	var cursor *timelineCursor
+	for page := 0; page < 4; page++ {
+		items, next := paginateTimeline(append([]timelineEntry(nil), entries...), cursor, 2)
+		for _, item := range items {
+			seen = append(seen, item.ID)
+		}
+		if next == "" {
+			break
+		}
+		c, err := decodeTimelineCursor(next)
+		if err != nil {
+			t.Fatalf("decode cursor: %v", err)
+		}
+		cursor = c
+	}
+
+	want := []string{"note:8", "note:7", "status_change:1", "field_edit:3"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, seen)
+		}
+	}
+
+	if _, err := decodeTimelineCursor("not-a-cursor"); err == nil {
+		t.Fatalf("expected invalid cursor error")
+	}
+}
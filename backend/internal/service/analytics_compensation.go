@@ -0,0 +1,249 @@
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/salary"
+	"jobView-backend/internal/taxonomy"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// compensationCompanyLimit 公司维度最多返回的分组数
+const compensationCompanyLimit = 20
+
+// currencyCodePattern ISO 4217 币种代码
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// compensationSample 薪酬分析样本，Parsed 为 false 时薪酬字段无意义
+type compensationSample struct {
+	ID         int
+	Company    string
+	RoleFamily string
+	HasSalary  bool // 是否填写了薪资描述
+	Parsed     bool
+	Currency   string
+	MinMonthly float64
+	MaxMonthly float64
+	AnnualMin  float64
+	AnnualMax  float64
+	Reached    []bool // 到达的漏斗阶段，与 model.FunnelStages 对应
+}
+
+// GetCompensationAnalytics 按所选币种汇总结构化薪酬：整体统计、月薪分布以及按漏斗阶段、公司、岗位类别的对比
+func (s *AnalyticsService) GetCompensationAnalytics(userID uint, req *model.CompensationAnalyticsRequest) (*model.CompensationAnalyticsResponse, error) {
+	if req.StartDate != "" && !isValidDate(req.StartDate) {
+		return nil, fmt.Errorf("invalid start date format: %s", req.StartDate)
+	}
+	if req.EndDate != "" && !isValidDate(req.EndDate) {
+		return nil, fmt.Errorf("invalid end date format: %s", req.EndDate)
+	}
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if req.Currency == "" {
+		req.Currency = salary.DefaultCurrency
+	}
+	if !currencyCodePattern.MatchString(req.Currency) {
+		return nil, fmt.Errorf("invalid currency: %s", req.Currency)
+	}
+	if req.MinSamples <= 0 {
+		req.MinSamples = 1
+	}
+
+	apps, err := s.loadFunnelApplications(userID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	reached := make(map[int][]bool, len(apps))
+	for _, app := range apps {
+		reached[app.ID], _ = funnelReach(app)
+	}
+
+	whereClause := "WHERE ja.user_id = $1 AND ja.deleted_at IS NULL"
+	args := []interface{}{userID}
+	argIndex := 2
+	if req.StartDate != "" {
+		whereClause += fmt.Sprintf(" AND ja.application_date >= $%d", argIndex)
+		args = append(args, req.StartDate)
+		argIndex++
+	}
+	if req.EndDate != "" {
+		whereClause += fmt.Sprintf(" AND ja.application_date <= $%d", argIndex)
+		args = append(args, req.EndDate)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT ja.id, COALESCE(c.name, ja.company_name), COALESCE(ja.role_family, ''),
+			   COALESCE(btrim(ja.salary_range), '') <> '',
+			   ja.salary_min_monthly, ja.salary_max_monthly, ja.salary_annual_min, ja.salary_annual_max,
+			   COALESCE(ja.salary_currency, '')
+		FROM job_applications ja
+		LEFT JOIN companies c ON c.id = ja.company_id
+		`+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query compensation: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []compensationSample
+	for rows.Next() {
+		var sample compensationSample
+		var minMonthly, maxMonthly, annualMin, annualMax *float64
+		if err := rows.Scan(&sample.ID, &sample.Company, &sample.RoleFamily, &sample.HasSalary,
+			&minMonthly, &maxMonthly, &annualMin, &annualMax, &sample.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan compensation: %w", err)
+		}
+		if minMonthly != nil && maxMonthly != nil && annualMin != nil && annualMax != nil {
+			sample.Parsed = true
+			sample.MinMonthly, sample.MaxMonthly = *minMonthly, *maxMonthly
+			sample.AnnualMin, sample.AnnualMax = *annualMin, *annualMax
+		}
+		sample.Reached = reached[sample.ID]
+		samples = append(samples, sample)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate compensation: %w", err)
+	}
+
+	return buildCompensationAnalytics(samples, req.Currency, req.MinSamples, taxonomy.Active()), nil
+}
+
+// buildCompensationAnalytics 汇总薪酬样本；分组中样本数低于 minSamples 的公司/岗位类别不返回
+func buildCompensationAnalytics(samples []compensationSample, currency string, minSamples int, rules *taxonomy.Taxonomy) *model.CompensationAnalyticsResponse {
+	resp := &model.CompensationAnalyticsResponse{
+		Currency:        currency,
+		Total:           len(samples),
+		OtherCurrencies: make(map[string]int),
+		Distribution:    make([]model.CompensationBucket, salary.BucketCount()),
+		ByStage:         []model.CompensationGroup{},
+		ByCompany:       []model.CompensationGroup{},
+		ByRole:          []model.CompensationGroup{},
+	}
+	for i := range resp.Distribution {
+		low, high := salary.BucketRange(i)
+		resp.Distribution[i] = model.CompensationBucket{Label: salary.BucketLabel(i), MinMonthly: low}
+		if high > 0 {
+			resp.Distribution[i].MaxMonthly = &high
+		}
+	}
+
+	var matched []compensationSample
+	for _, sample := range samples {
+		switch {
+		case !sample.HasSalary:
+			resp.Missing++
+		case !sample.Parsed:
+			resp.Unparsed++
+		case sample.Currency != currency:
+			resp.OtherCurrencies[sample.Currency]++
+		default:
+			matched = append(matched, sample)
+		}
+	}
+	resp.Parsed = len(matched)
+	resp.Summary = compensationStats(matched)
+
+	for _, sample := range matched {
+		i, _ := salary.MonthlyBucket((sample.MinMonthly + sample.MaxMonthly) / 2)
+		resp.Distribution[i].Count++
+	}
+
+	for i, stage := range model.FunnelStages {
+		var group []compensationSample
+		for _, sample := range matched {
+			if i < len(sample.Reached) && sample.Reached[i] {
+				group = append(group, sample)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		resp.ByStage = append(resp.ByStage, model.CompensationGroup{
+			Key: stage.Key, Name: stage.Name, CompensationStats: compensationStats(group),
+		})
+	}
+
+	byCompany := make(map[string][]compensationSample)
+	byRole := make(map[string][]compensationSample)
+	for _, sample := range matched {
+		byCompany[sample.Company] = append(byCompany[sample.Company], sample)
+		byRole[sample.RoleFamily] = append(byRole[sample.RoleFamily], sample)
+	}
+	resp.ByCompany = compensationGroups(byCompany, minSamples, func(key string) string { return key })
+	if len(resp.ByCompany) > compensationCompanyLimit {
+		resp.ByCompany = resp.ByCompany[:compensationCompanyLimit]
+	}
+	resp.ByRole = compensationGroups(byRole, minSamples, func(key string) string {
+		if key == "" {
+			return model.UnclassifiedRoleName
+		}
+		return rules.FamilyName(key)
+	})
+	return resp
+}
+
+// compensationGroups 按月薪中位值降序生成分组统计，中位值相同时按名称排序
+func compensationGroups(groups map[string][]compensationSample, minSamples int, name func(string) string) []model.CompensationGroup {
+	result := []model.CompensationGroup{}
+	for key, group := range groups {
+		if len(group) < minSamples {
+			continue
+		}
+		result = append(result, model.CompensationGroup{Key: key, Name: name(key), CompensationStats: compensationStats(group)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MedianMonthly != result[j].MedianMonthly {
+			return result[i].MedianMonthly > result[j].MedianMonthly
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// compensationStats 计算一组样本的薪酬统计，月薪/年薪取区间中位值
+func compensationStats(samples []compensationSample) model.CompensationStats {
+	stats := model.CompensationStats{Samples: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	monthly := make([]float64, len(samples))
+	annual := make([]float64, len(samples))
+	stats.MinMonthly, stats.MaxMonthly = samples[0].MinMonthly, samples[0].MaxMonthly
+	var monthlySum, annualSum float64
+	for i, sample := range samples {
+		monthly[i] = (sample.MinMonthly + sample.MaxMonthly) / 2
+		annual[i] = (sample.AnnualMin + sample.AnnualMax) / 2
+		monthlySum += monthly[i]
+		annualSum += annual[i]
+		stats.MinMonthly = math.Min(stats.MinMonthly, sample.MinMonthly)
+		stats.MaxMonthly = math.Max(stats.MaxMonthly, sample.MaxMonthly)
+	}
+	sort.Float64s(monthly)
+	sort.Float64s(annual)
+
+	stats.P25Monthly = roundMoney(quantile(monthly, 0.25))
+	stats.MedianMonthly = roundMoney(quantile(monthly, 0.5))
+	stats.P75Monthly = roundMoney(quantile(monthly, 0.75))
+	stats.AverageMonthly = roundMoney(monthlySum / float64(len(samples)))
+	stats.MedianAnnual = roundMoney(quantile(annual, 0.5))
+	stats.AverageAnnual = roundMoney(annualSum / float64(len(samples)))
+	return stats
+}
+
+// quantile 已排序数据的分位数（线性插值）
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+// roundMoney 金额保留两位小数
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
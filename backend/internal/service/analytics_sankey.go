@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/model"
+	"sort"
+	"time"
+)
+
+// sankeyOtherNode 低频流转合并后的目标节点
+const sankeyOtherNode = "其他"
+
+// sankeyTransition 一种状态流转及其次数
+type sankeyTransition struct {
+	From  model.ApplicationStatus
+	To    model.ApplicationStatus
+	Count int
+}
+
+// GetSankeyFlow 汇总状态流转（old_status -> new_status）生成桑基图数据
+func (s *AnalyticsService) GetSankeyFlow(userID uint, req *model.SankeyRequest) (*model.SankeyResponse, error) {
+	if req.StartDate != "" && !isValidDate(req.StartDate) {
+		return nil, fmt.Errorf("invalid start date format: %s", req.StartDate)
+	}
+	if req.EndDate != "" && !isValidDate(req.EndDate) {
+		return nil, fmt.Errorf("invalid end date format: %s", req.EndDate)
+	}
+	if req.MinCount < 1 {
+		req.MinCount = 1
+	}
+
+	whereClause := "WHERE h.user_id = $1 AND ja.deleted_at IS NULL AND h.old_status IS NOT NULL AND h.old_status <> h.new_status"
+	args := []interface{}{userID}
+	argIndex := 2
+	if req.StartDate != "" {
+		whereClause += fmt.Sprintf(" AND h.status_changed_at >= $%d::date", argIndex)
+		args = append(args, req.StartDate)
+		argIndex++
+	}
+	if req.EndDate != "" {
+		end, _ := time.Parse("2006-01-02", req.EndDate)
+		whereClause += fmt.Sprintf(" AND h.status_changed_at < $%d::date", argIndex)
+		args = append(args, end.AddDate(0, 0, 1).Format("2006-01-02"))
+		argIndex++
+	}
+	if len(req.CompanyNames) > 0 {
+		companyCondition, companyArgs, _ := companyNamesCondition("ja.", req.CompanyNames, argIndex)
+		whereClause += " AND " + companyCondition
+		args = append(args, companyArgs...)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT h.old_status, h.new_status, COUNT(*)
+		FROM job_status_history h
+		JOIN job_applications ja ON ja.id = h.job_application_id
+		%s
+		GROUP BY h.old_status, h.new_status
+	`, whereClause)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []sankeyTransition
+	for rows.Next() {
+		var t sankeyTransition
+		if err := rows.Scan(&t.From, &t.To, &t.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan status transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status transitions: %w", err)
+	}
+
+	return buildSankeyFlow(transitions, req.MinCount), nil
+}
+
+// isBackwardFlow 按漏斗阶段判断是否为回退流转
+func isBackwardFlow(from, to model.ApplicationStatus) bool {
+	fromIdx, toIdx := model.FunnelStageIndex(from), model.FunnelStageIndex(to)
+	return fromIdx >= 0 && toIdx >= 0 && toIdx < fromIdx
+}
+
+// buildSankeyFlow 生成节点与连线，低于 minCount 的流转按来源合并到“其他”
+func buildSankeyFlow(transitions []sankeyTransition, minCount int) *model.SankeyResponse {
+	response := &model.SankeyResponse{
+		Nodes:    []model.SankeyNode{},
+		Links:    []model.SankeyLink{},
+		MinCount: minCount,
+	}
+
+	collapsed := make(map[string]int)
+	nodes := make(map[string]bool)
+	for _, t := range transitions {
+		response.TotalTransitions += t.Count
+		from, to := string(t.From), string(t.To)
+		if t.Count < minCount {
+			collapsed[from] += t.Count
+			response.Collapsed += t.Count
+			continue
+		}
+		nodes[from] = true
+		nodes[to] = true
+		response.Links = append(response.Links, model.SankeyLink{
+			Source:   from,
+			Target:   to,
+			Value:    t.Count,
+			Backward: isBackwardFlow(t.From, t.To),
+		})
+	}
+	for from, count := range collapsed {
+		nodes[from] = true
+		nodes[sankeyOtherNode] = true
+		response.Links = append(response.Links, model.SankeyLink{Source: from, Target: sankeyOtherNode, Value: count})
+	}
+
+	// 节点按流程顺序排列，便于前端布局
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ri, rj := sankeyNodeRank(ids[i]), sankeyNodeRank(ids[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ids[i] < ids[j]
+	})
+	for _, id := range ids {
+		response.Nodes = append(response.Nodes, model.SankeyNode{ID: id, Name: id})
+	}
+
+	sort.Slice(response.Links, func(i, j int) bool {
+		if response.Links[i].Value != response.Links[j].Value {
+			return response.Links[i].Value > response.Links[j].Value
+		}
+		if response.Links[i].Source != response.Links[j].Source {
+			return response.Links[i].Source < response.Links[j].Source
+		}
+		return response.Links[i].Target < response.Links[j].Target
+	})
+	return response
+}
+
+// sankeyNodeRank 节点排序：按漏斗阶段，无阶段的终态（已拒绝/流程结束/其他）排在最后
+func sankeyNodeRank(id string) int {
+	if idx := model.FunnelStageIndex(model.ApplicationStatus(id)); idx >= 0 {
+		return idx
+	}
+	return len(model.FunnelStages)
+}
@@ -0,0 +1,285 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/analytics_service.go
+// This file implements history-based analytics for JobView (cohort funnels and related reports).
+// Unlike StatusTrackingService.GetStatusAnalytics, results are derived by walking job_status_history
+// so that applications are credited with every stage they actually reached.
+
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"sort"
+	"time"
+)
+
+type AnalyticsService struct {
+	db     *database.DB
+	scorer *SuccessProbabilityService
+}
+
+func NewAnalyticsService(db *database.DB) *AnalyticsService {
+	return &AnalyticsService{db: db, scorer: NewSuccessProbabilityService(db)}
+}
+
+// RefreshSuccessProbabilities 重新计算用户全部申请的成功率并返回各阶段模型
+func (s *AnalyticsService) RefreshSuccessProbabilities(userID uint) (*model.SuccessScoreResult, error) {
+	return s.scorer.RefreshUserScores(userID)
+}
+
+// funnelApplication 漏斗计算所需的单条申请数据
+type funnelApplication struct {
+	ID              int
+	ApplicationDate string
+	Status          model.ApplicationStatus
+	Visited         []model.ApplicationStatus // 历史中出现过的状态
+	Tags            []string                  // 仅按标签分组时加载
+}
+
+// funnelUntaggedCohort 按标签分组时未打标签申请所在的队列
+const funnelUntaggedCohort = "未打标签"
+
+// GetFunnelAnalytics 按投递周/月或标签分组计算漏斗。
+// 申请未记录投递渠道，暂不支持按渠道分组
+func (s *AnalyticsService) GetFunnelAnalytics(userID uint, req *model.FunnelAnalyticsRequest) (*model.FunnelAnalyticsResponse, error) {
+	if req.GroupBy == "" {
+		req.GroupBy = "month"
+	}
+	if req.GroupBy != "week" && req.GroupBy != "month" && req.GroupBy != "tag" {
+		return nil, fmt.Errorf("invalid group_by: %s (supported: week, month, tag)", req.GroupBy)
+	}
+	if req.StartDate != "" && !isValidDate(req.StartDate) {
+		return nil, fmt.Errorf("invalid start date format: %s", req.StartDate)
+	}
+	if req.EndDate != "" && !isValidDate(req.EndDate) {
+		return nil, fmt.Errorf("invalid end date format: %s", req.EndDate)
+	}
+
+	apps, err := s.loadFunnelApplications(userID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if req.GroupBy == "tag" {
+		if err := s.loadFunnelTags(userID, apps); err != nil {
+			return nil, err
+		}
+	}
+
+	return buildFunnelAnalytics(apps, req.GroupBy), nil
+}
+
+// loadFunnelApplications 读取申请及其历史中经过的状态
+func (s *AnalyticsService) loadFunnelApplications(userID uint, startDate, endDate string) ([]funnelApplication, error) {
+	whereClause := "WHERE user_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{userID}
+	argIndex := 2
+	if startDate != "" {
+		whereClause += fmt.Sprintf(" AND application_date >= $%d", argIndex)
+		args = append(args, startDate)
+		argIndex++
+	}
+	if endDate != "" {
+		whereClause += fmt.Sprintf(" AND application_date <= $%d", argIndex)
+		args = append(args, endDate)
+	}
+
+	rows, err := s.db.Query("SELECT id, application_date, status FROM job_applications "+whereClause+" ORDER BY application_date, id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job applications: %w", err)
+	}
+	defer rows.Close()
+
+	var apps []funnelApplication
+	index := make(map[int]int)
+	for rows.Next() {
+		var app funnelApplication
+		if err := rows.Scan(&app.ID, &app.ApplicationDate, &app.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan job application: %w", err)
+		}
+		index[app.ID] = len(apps)
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate job applications: %w", err)
+	}
+	if len(apps) == 0 {
+		return apps, nil
+	}
+
+	historyRows, err := s.db.Query(`
+		SELECT DISTINCT job_application_id, new_status
+		FROM job_status_history
+		WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer historyRows.Close()
+
+	for historyRows.Next() {
+		var appID int
+		var status model.ApplicationStatus
+		if err := historyRows.Scan(&appID, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan status history: %w", err)
+		}
+		if i, ok := index[appID]; ok {
+			apps[i].Visited = append(apps[i].Visited, status)
+		}
+	}
+	return apps, historyRows.Err()
+}
+
+// loadFunnelTags 为申请加载标签名称
+func (s *AnalyticsService) loadFunnelTags(userID uint, apps []funnelApplication) error {
+	if len(apps) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(apps))
+	for i, app := range apps {
+		index[app.ID] = i
+	}
+
+	rows, err := s.db.Query(`
+		SELECT jat.job_application_id, t.name
+		FROM job_application_tags jat
+		JOIN tags t ON t.id = jat.tag_id
+		WHERE t.user_id = $1
+		ORDER BY t.name
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to query application tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var appID int
+		var name string
+		if err := rows.Scan(&appID, &name); err != nil {
+			return fmt.Errorf("failed to scan application tag: %w", err)
+		}
+		if i, ok := index[appID]; ok {
+			apps[i].Tags = append(apps[i].Tags, name)
+		}
+	}
+	return rows.Err()
+}
+
+// funnelCohortKeys 计算申请所属队列：按标签分组时一条申请计入其每个标签
+func funnelCohortKeys(app funnelApplication, groupBy string) []string {
+	if groupBy != "tag" {
+		return []string{funnelCohortKey(app.ApplicationDate, groupBy)}
+	}
+	if len(app.Tags) == 0 {
+		return []string{funnelUntaggedCohort}
+	}
+	return app.Tags
+}
+
+// funnelCohortKey 计算申请所属队列（ISO 周或自然月）
+func funnelCohortKey(applicationDate, groupBy string) string {
+	t, err := time.Parse("2006-01-02", applicationDate)
+	if err != nil {
+		return "unknown"
+	}
+	if groupBy == "week" {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01")
+}
+
+// funnelReach 计算单条申请到达的阶段：
+// 必经阶段按最远阶段累计，可选阶段只在实际经过时计入
+func funnelReach(app funnelApplication) ([]bool, int) {
+	reached := make([]bool, len(model.FunnelStages))
+	visited := make(map[int]bool)
+	furthest := 0 // 所有申请至少处于已投递阶段
+	statuses := make([]model.ApplicationStatus, 0, len(app.Visited)+1)
+	statuses = append(statuses, app.Visited...)
+	statuses = append(statuses, app.Status)
+	for _, st := range statuses {
+		idx := model.FunnelStageIndex(st)
+		if idx < 0 {
+			continue
+		}
+		visited[idx] = true
+		// 已接受offer 同时属于 offer 阶段
+		if idx == len(model.FunnelStages)-1 {
+			visited[idx-1] = true
+		}
+		if idx > furthest {
+			furthest = idx
+		}
+	}
+	for i, stage := range model.FunnelStages {
+		if stage.Optional {
+			reached[i] = visited[i]
+		} else {
+			reached[i] = i <= furthest
+		}
+	}
+	return reached, furthest
+}
+
+// buildFunnelCohort 汇总一组申请的漏斗
+func buildFunnelCohort(name string, apps []funnelApplication) model.FunnelCohort {
+	cohort := model.FunnelCohort{
+		Cohort:        name,
+		Total:         len(apps),
+		FurthestStage: make(map[string]int),
+	}
+	counts := make([]int, len(model.FunnelStages))
+	for _, app := range apps {
+		reached, furthest := funnelReach(app)
+		for i, ok := range reached {
+			if ok {
+				counts[i]++
+			}
+		}
+		cohort.FurthestStage[model.FunnelStages[furthest].Key]++
+	}
+
+	prevRequired := -1
+	for i, stage := range model.FunnelStages {
+		stat := model.FunnelStageStat{Key: stage.Key, Name: stage.Name, Count: counts[i]}
+		if cohort.Total > 0 {
+			stat.OverallRate = float64(counts[i]) / float64(cohort.Total) * 100
+		}
+		if prevRequired < 0 {
+			stat.ConversionRate = stat.OverallRate
+		} else if counts[prevRequired] > 0 {
+			stat.ConversionRate = float64(counts[i]) / float64(counts[prevRequired]) * 100
+		}
+		if !stage.Optional {
+			prevRequired = i
+		}
+		cohort.Stages = append(cohort.Stages, stat)
+	}
+	return cohort
+}
+
+// buildFunnelAnalytics 按队列分组并生成漏斗数据
+func buildFunnelAnalytics(apps []funnelApplication, groupBy string) *model.FunnelAnalyticsResponse {
+	groups := make(map[string][]funnelApplication)
+	for _, app := range apps {
+		for _, key := range funnelCohortKeys(app, groupBy) {
+			groups[key] = append(groups[key], app)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	response := &model.FunnelAnalyticsResponse{
+		GroupBy: groupBy,
+		Cohorts: make([]model.FunnelCohort, 0, len(keys)),
+		Overall: buildFunnelCohort("all", apps),
+	}
+	for _, key := range keys {
+		response.Cohorts = append(response.Cohorts, buildFunnelCohort(key, groups[key]))
+	}
+	return response
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+	"testing"
+)
+
+// Test funnel cohorts credit every stage reached in history
+func TestBuildFunnelAnalytics(t *testing.T) {
+	apps := []funnelApplication{
+		{ID: 1, ApplicationDate: "2025-09-02", Status: model.StatusFirstFail,
+			Visited: []model.ApplicationStatus{model.StatusApplied, model.StatusResumeScreening, model.StatusFirstInterview, model.StatusFirstFail}},
+		{ID: 2, ApplicationDate: "2025-09-15", Status: model.StatusOfferReceived,
+			Visited: []model.ApplicationStatus{model.StatusApplied, model.StatusWrittenTest, model.StatusFirstInterview, model.StatusSecondInterview, model.StatusHRInterview, model.StatusOfferReceived}},
+		{ID: 3, ApplicationDate: "2025-10-01", Status: model.StatusApplied},
+	}
+
+	resp := buildFunnelAnalytics(apps, "month")
+	if len(resp.Cohorts) != 2 || resp.Cohorts[0].Cohort != "2025-09" || resp.Cohorts[1].Cohort != "2025-10" {
+		t.Fatalf("unexpected cohorts: %+v", resp.Cohorts)
+	}
+
+	sep := resp.Cohorts[0]
+	counts := map[string]int{}
+	for _, st := range sep.Stages {
+		counts[st.Key] = st.Count
+	}
+	// 两条都至少到达一面；笔试为可选阶段，只统计实际经过的一条
+	if counts["first"] != 2 || counts["written"] != 1 || counts["second"] != 1 || counts["offer"] != 1 || counts["accepted"] != 0 {
+		t.Fatalf("unexpected stage counts: %+v", counts)
+	}
+	if sep.FurthestStage["first"] != 1 || sep.FurthestStage["offer"] != 1 {
+		t.Fatalf("unexpected furthest stages: %+v", sep.FurthestStage)
+	}
+	for _, st := range sep.Stages {
+		if st.Key == "second" && st.ConversionRate != 50 {
+			t.Fatalf("expected 50%% first->second conversion, got %v", st.ConversionRate)
+		}
+	}
+
+	if resp.Overall.Total != 3 {
+		t.Fatalf("expected overall total 3, got %d", resp.Overall.Total)
+	}
+	if key := funnelCohortKey("2025-09-02", "week"); key != "2025-W36" {
+		t.Fatalf("unexpected week cohort key: %s", key)
+	}
+}
+
+// Test tag cohorts count an application under each of its tags
+func TestBuildFunnelAnalyticsByTag(t *testing.T) {
+	apps := []funnelApplication{
+		{ID: 1, ApplicationDate: "2025-09-02", Status: model.StatusFirstInterview, Tags: []string{"内推", "大厂"}},
+		{ID: 2, ApplicationDate: "2025-09-15", Status: model.StatusApplied, Tags: []string{"大厂"}},
+		{ID: 3, ApplicationDate: "2025-10-01", Status: model.StatusApplied},
+	}
+
+	resp := buildFunnelAnalytics(apps, "tag")
+	totals := map[string]int{}
+	for _, cohort := range resp.Cohorts {
+		totals[cohort.Cohort] = cohort.Total
+	}
+	if len(totals) != 3 || totals["内推"] != 1 || totals["大厂"] != 2 || totals[funnelUntaggedCohort] != 1 {
+		t.Fatalf("unexpected tag cohorts: %+v", totals)
+	}
+	if resp.Overall.Total != 3 {
+		t.Fatalf("expected overall total to count each application once, got %d", resp.Overall.Total)
+	}
+}
+
+// Test Kaplan-Meier estimation with censored samples
+func TestKaplanMeier(t *testing.T) {
+	samples := []survivalSample{
+		{Days: 1, Event: true},
+		{Days: 2, Event: false},
+		{Days: 3, Event: true},
+		{Days: 4, Event: true},
+	}
+	est := kaplanMeier("offer", "offer", samples, true)
+	if est.Events != 3 || est.Censored != 1 {
+		t.Fatalf("unexpected event/censor counts: %+v", est)
+	}
+	if est.MedianDays == nil || *est.MedianDays != 3 {
+		t.Fatalf("expected median 3 days, got %v", est.MedianDays)
+	}
+	if p25 := est.Percentiles["p25"]; p25 == nil || *p25 != 1 {
+		t.Fatalf("expected p25 of 1 day, got %v", p25)
+	}
+	if len(est.Curve) != 3 || est.Curve[1].Survival != 0.375 || est.Curve[0].Censored != 1 {
+		t.Fatalf("unexpected curve: %+v", est.Curve)
+	}
+
+	// 全部删失时没有中位数
+	if est := kaplanMeier("offer", "offer", []survivalSample{{Days: 5}}, false); est.MedianDays != nil {
+		t.Fatalf("expected no median when all samples are censored")
+	}
+}
+
+// Test sankey links collapse rare transitions into "其他"
+func TestBuildSankeyFlow(t *testing.T) {
+	transitions := []sankeyTransition{
+		{From: model.StatusApplied, To: model.StatusResumeScreening, Count: 10},
+		{From: model.StatusResumeScreening, To: model.StatusFirstInterview, Count: 6},
+		{From: model.StatusResumeScreening, To: model.StatusWrittenTest, Count: 1},
+		{From: model.StatusResumeScreening, To: model.StatusRejected, Count: 1},
+		{From: model.StatusFirstInterview, To: model.StatusResumeScreening, Count: 2},
+	}
+
+	resp := buildSankeyFlow(transitions, 2)
+	if resp.TotalTransitions != 20 || resp.Collapsed != 2 {
+		t.Fatalf("unexpected totals: %+v", resp)
+	}
+	var other, backward *model.SankeyLink
+	for i := range resp.Links {
+		link := &resp.Links[i]
+		if link.Target == sankeyOtherNode {
+			other = link
+		}
+		if link.Backward {
+			backward = link
+		}
+	}
+	if other == nil || other.Source != string(model.StatusResumeScreening) || other.Value != 2 {
+		t.Fatalf("expected collapsed link from 简历筛选中, got %+v", other)
+	}
+	if backward == nil || backward.Source != string(model.StatusFirstInterview) {
+		t.Fatalf("expected backward link from 一面中, got %+v", backward)
+	}
+	if resp.Nodes[0].ID != string(model.StatusApplied) || resp.Nodes[len(resp.Nodes)-1].ID != sankeyOtherNode {
+		t.Fatalf("unexpected node order: %+v", resp.Nodes)
+	}
+}
+
+// Test compensation analytics filters by currency and groups by stage, company and role
+func TestBuildCompensationAnalytics(t *testing.T) {
+	stages := len(model.FunnelStages)
+	reachedUpTo := func(n int) []bool {
+		reached := make([]bool, stages)
+		for i := 0; i < n; i++ {
+			reached[i] = true
+		}
+		return reached
+	}
+	samples := []compensationSample{
+		{ID: 1, Company: "字节跳动", RoleFamily: "backend", HasSalary: true, Parsed: true, Currency: "CNY",
+			MinMonthly: 30000, MaxMonthly: 40000, AnnualMin: 450000, AnnualMax: 600000, Reached: reachedUpTo(stages - 1)},
+		{ID: 2, Company: "字节跳动", RoleFamily: "frontend", HasSalary: true, Parsed: true, Currency: "CNY",
+			MinMonthly: 20000, MaxMonthly: 30000, AnnualMin: 240000, AnnualMax: 360000, Reached: reachedUpTo(2)},
+		{ID: 3, Company: "腾讯", RoleFamily: "backend", HasSalary: true, Parsed: true, Currency: "CNY",
+			MinMonthly: 10000, MaxMonthly: 12000, AnnualMin: 120000, AnnualMax: 144000, Reached: reachedUpTo(1)},
+		{ID: 4, Company: "Google", HasSalary: true, Parsed: true, Currency: "USD",
+			MinMonthly: 10000, MaxMonthly: 12000, AnnualMin: 120000, AnnualMax: 144000},
+		{ID: 5, Company: "美团", HasSalary: true},
+		{ID: 6, Company: "美团"},
+	}
+
+	resp := buildCompensationAnalytics(samples, "CNY", 1, taxonomy.Default())
+	if resp.Total != 6 || resp.Parsed != 3 || resp.Unparsed != 1 || resp.Missing != 1 || resp.OtherCurrencies["USD"] != 1 {
+		t.Fatalf("unexpected counts: %+v", resp)
+	}
+	// 月薪中位值 11000 / 25000 / 35000
+	if resp.Summary.MedianMonthly != 25000 || resp.Summary.MinMonthly != 10000 || resp.Summary.MaxMonthly != 40000 {
+		t.Fatalf("unexpected summary: %+v", resp.Summary)
+	}
+	counts := map[string]int{}
+	for _, bucket := range resp.Distribution {
+		counts[bucket.Label] = bucket.Count
+	}
+	if counts["10-15K"] != 1 || counts["25-30K"] != 1 || counts["30-40K"] != 1 {
+		t.Fatalf("unexpected distribution: %+v", counts)
+	}
+	if resp.ByStage[0].Key != "applied" || resp.ByStage[0].Samples != 3 || resp.ByStage[len(resp.ByStage)-1].Samples != 1 {
+		t.Fatalf("unexpected stage groups: %+v", resp.ByStage)
+	}
+	if len(resp.ByCompany) != 2 || resp.ByCompany[0].Name != "字节跳动" || resp.ByCompany[0].MedianMonthly != 30000 {
+		t.Fatalf("unexpected company groups: %+v", resp.ByCompany)
+	}
+	if len(resp.ByRole) != 2 || resp.ByRole[0].Name != "前端开发" {
+		t.Fatalf("unexpected role groups: %+v", resp.ByRole)
+	}
+
+	resp = buildCompensationAnalytics(samples, "CNY", 2, taxonomy.Default())
+	if len(resp.ByCompany) != 1 || len(resp.ByRole) != 1 || resp.ByRole[0].Key != "backend" {
+		t.Fatalf("expected min_samples to drop small groups, got %+v / %+v", resp.ByCompany, resp.ByRole)
+	}
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// survivalSample 生存分析样本：Event 为 false 表示在 Days 时被删失
+type survivalSample struct {
+	Days  float64
+	Event bool
+}
+
+// applicationTimeline 单条申请及其按时间排序的状态历史（生存分析与成功率评分共用）
+type applicationTimeline struct {
+	ID          int
+	UserID      uint
+	CompanyName string
+	Status      model.ApplicationStatus
+	StartedAt   time.Time
+	LastChange  time.Time
+	History     []model.StatusHistoryEntry // 按时间升序
+}
+
+// survivalOutcomes 结果型指标
+var survivalOutcomes = []struct {
+	Metric string
+	Label  string
+}{
+	{"first_response", "投递到首次反馈"},
+	{"offer", "投递到收到offer"},
+	{"rejection", "投递到被拒"},
+}
+
+// GetSurvivalAnalytics 计算首次反馈/offer/被拒的 Kaplan-Meier 生存曲线，未结束的申请按删失处理
+func (s *AnalyticsService) GetSurvivalAnalytics(userID uint, req *model.SurvivalAnalyticsRequest) (*model.SurvivalAnalyticsResponse, error) {
+	if req.StartDate != "" && !isValidDate(req.StartDate) {
+		return nil, fmt.Errorf("invalid start date format: %s", req.StartDate)
+	}
+	if req.EndDate != "" && !isValidDate(req.EndDate) {
+		return nil, fmt.Errorf("invalid end date format: %s", req.EndDate)
+	}
+	if req.MinSamples <= 0 {
+		req.MinSamples = 3
+	}
+
+	apps, err := loadApplicationTimelines(s.db, []uint{userID}, req)
+	if err != nil {
+		return nil, err
+	}
+	return buildSurvivalAnalytics(apps, time.Now(), req.MinSamples), nil
+}
+
+// loadApplicationTimelines 读取申请与完整状态历史；userIDs 为空时读取全部用户，filter 可为空
+func loadApplicationTimelines(db *database.DB, userIDs []uint, filter *model.SurvivalAnalyticsRequest) ([]applicationTimeline, error) {
+	whereClause := "WHERE deleted_at IS NULL"
+	args := []interface{}{}
+	argIndex := 1
+	var userFilter interface{}
+	if len(userIDs) > 0 {
+		ids := make([]int64, len(userIDs))
+		for i, id := range userIDs {
+			ids[i] = int64(id)
+		}
+		userFilter = pq.Array(ids)
+		whereClause += fmt.Sprintf(" AND user_id = ANY($%d)", argIndex)
+		args = append(args, userFilter)
+		argIndex++
+	}
+	if filter != nil && filter.StartDate != "" {
+		whereClause += fmt.Sprintf(" AND application_date >= $%d", argIndex)
+		args = append(args, filter.StartDate)
+		argIndex++
+	}
+	if filter != nil && filter.EndDate != "" {
+		whereClause += fmt.Sprintf(" AND application_date <= $%d", argIndex)
+		args = append(args, filter.EndDate)
+		argIndex++
+	}
+	if filter != nil && filter.CompanyName != "" {
+		companyCondition, companyArgs, _ := companyNamesCondition("", []string{filter.CompanyName}, argIndex)
+		whereClause += " AND " + companyCondition
+		args = append(args, companyArgs...)
+	}
+
+	// 按公司统计时使用规范名称，同一公司的不同写法合并计算
+	rows, err := db.Query(`
+		SELECT id, user_id,
+		       COALESCE((SELECT c.name FROM companies c WHERE c.id = job_applications.company_id), company_name),
+		       application_date, status, created_at,
+		       COALESCE(last_status_change, updated_at, created_at)
+		FROM job_applications `+whereClause+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job applications: %w", err)
+	}
+	defer rows.Close()
+
+	var apps []applicationTimeline
+	index := make(map[int]int)
+	for rows.Next() {
+		var app applicationTimeline
+		var applicationDate string
+		var createdAt time.Time
+		if err := rows.Scan(&app.ID, &app.UserID, &app.CompanyName, &applicationDate, &app.Status, &createdAt, &app.LastChange); err != nil {
+			return nil, fmt.Errorf("failed to scan job application: %w", err)
+		}
+		app.StartedAt = createdAt
+		if d, err := time.ParseInLocation("2006-01-02", applicationDate, createdAt.Location()); err == nil {
+			app.StartedAt = d
+		}
+		index[app.ID] = len(apps)
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate job applications: %w", err)
+	}
+	if len(apps) == 0 {
+		return apps, nil
+	}
+
+	historyQuery := `
+		SELECT job_application_id, old_status, new_status, status_changed_at
+		FROM job_status_history
+		ORDER BY job_application_id, status_changed_at ASC, id ASC
+	`
+	historyArgs := []interface{}{}
+	if userFilter != nil {
+		historyQuery = `
+		SELECT job_application_id, old_status, new_status, status_changed_at
+		FROM job_status_history
+		WHERE user_id = ANY($1)
+		ORDER BY job_application_id, status_changed_at ASC, id ASC
+	`
+		historyArgs = append(historyArgs, userFilter)
+	}
+	historyRows, err := db.Query(historyQuery, historyArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer historyRows.Close()
+
+	for historyRows.Next() {
+		var appID int
+		var oldStatus *string
+		var entry model.StatusHistoryEntry
+		if err := historyRows.Scan(&appID, &oldStatus, &entry.NewStatus, &entry.StatusChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status history: %w", err)
+		}
+		if oldStatus != nil {
+			st := model.ApplicationStatus(*oldStatus)
+			entry.OldStatus = &st
+		}
+		if i, ok := index[appID]; ok {
+			apps[i].History = append(apps[i].History, entry)
+		}
+	}
+	return apps, historyRows.Err()
+}
+
+// isOfferStatus 是否已到达offer阶段
+func isOfferStatus(st model.ApplicationStatus) bool {
+	return st == model.StatusOfferWaiting || st == model.StatusOfferReceived || st == model.StatusOfferAccepted
+}
+
+// daysBetween 计算天数，负数按 0 处理
+func daysBetween(from, to time.Time) float64 {
+	d := to.Sub(from).Hours() / 24
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// survivalOutcomeSamples 计算单条申请在各结果指标上的样本
+// 返回的 map 中缺失的指标表示该申请不参与此指标（如直接以终态录入、无任何历史）
+func survivalOutcomeSamples(app applicationTimeline, now time.Time) map[string]survivalSample {
+	samples := make(map[string]survivalSample)
+
+	// 删失时间：进行中的申请截至当前，已结束的截至最后一次状态变更
+	open := !app.Status.IsFailedStatus() && !isOfferStatus(app.Status) && app.Status != model.StatusProcessFinished
+	censorAt := app.LastChange
+	if open {
+		censorAt = now
+	}
+
+	var firstResponse, firstOffer, firstFailure *time.Time
+	for i := range app.History {
+		e := app.History[i]
+		if e.OldStatus != nil && firstResponse == nil {
+			firstResponse = &e.StatusChangedAt
+		}
+		if isOfferStatus(e.NewStatus) && firstOffer == nil {
+			firstOffer = &e.StatusChangedAt
+		}
+		if e.NewStatus.IsFailedStatus() && firstFailure == nil {
+			firstFailure = &e.StatusChangedAt
+		}
+	}
+
+	hasTransitions := firstResponse != nil
+	if !hasTransitions && !open {
+		return samples
+	}
+
+	if firstResponse != nil {
+		samples["first_response"] = survivalSample{Days: daysBetween(app.StartedAt, *firstResponse), Event: true}
+	} else {
+		samples["first_response"] = survivalSample{Days: daysBetween(app.StartedAt, censorAt)}
+	}
+
+	// offer 与被拒互为竞争事件：一方发生时另一方在该时点删失
+	switch {
+	case firstOffer != nil:
+		samples["offer"] = survivalSample{Days: daysBetween(app.StartedAt, *firstOffer), Event: true}
+	case firstFailure != nil:
+		samples["offer"] = survivalSample{Days: daysBetween(app.StartedAt, *firstFailure)}
+	default:
+		samples["offer"] = survivalSample{Days: daysBetween(app.StartedAt, censorAt)}
+	}
+	switch {
+	case firstFailure != nil:
+		samples["rejection"] = survivalSample{Days: daysBetween(app.StartedAt, *firstFailure), Event: true}
+	case firstOffer != nil:
+		samples["rejection"] = survivalSample{Days: daysBetween(app.StartedAt, *firstOffer)}
+	default:
+		samples["rejection"] = survivalSample{Days: daysBetween(app.StartedAt, censorAt)}
+	}
+	return samples
+}
+
+// survivalStageSamples 计算各进行中阶段的停留样本（离开阶段为事件，仍停留为删失）
+func survivalStageSamples(app applicationTimeline, now time.Time) map[string][]survivalSample {
+	samples := make(map[string][]survivalSample)
+	add := func(st model.ApplicationStatus, from, to time.Time, event bool) {
+		if !st.IsInProgressStatus() {
+			return
+		}
+		idx := model.FunnelStageIndex(st)
+		if idx < 0 {
+			return
+		}
+		key := model.FunnelStages[idx].Key
+		samples[key] = append(samples[key], survivalSample{Days: daysBetween(from, to), Event: event})
+	}
+
+	current := model.StatusApplied
+	currentSince := app.StartedAt
+	for _, e := range app.History {
+		if e.OldStatus == nil {
+			current = e.NewStatus
+			continue
+		}
+		add(*e.OldStatus, currentSince, e.StatusChangedAt, true)
+		current = e.NewStatus
+		currentSince = e.StatusChangedAt
+	}
+	if len(app.History) == 0 {
+		current = app.Status
+	}
+	if current == app.Status {
+		add(current, currentSince, now, false)
+	}
+	return samples
+}
+
+// kaplanMeier 计算 Kaplan-Meier 生存曲线及中位数/分位数
+func kaplanMeier(metric, label string, samples []survivalSample, withCurve bool) model.SurvivalEstimate {
+	estimate := model.SurvivalEstimate{
+		Metric:      metric,
+		Label:       label,
+		Samples:     len(samples),
+		Percentiles: map[string]*float64{"p25": nil, "p75": nil, "p90": nil},
+	}
+	if len(samples) == 0 {
+		return estimate
+	}
+
+	sorted := make([]survivalSample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Days < sorted[j].Days })
+
+	survival := 1.0
+	atRisk := len(sorted)
+	targets := []struct {
+		key string
+		p   float64
+	}{{"p25", 0.25}, {"median", 0.5}, {"p75", 0.75}, {"p90", 0.9}}
+
+	for i := 0; i < len(sorted); {
+		t := sorted[i].Days
+		events, censored := 0, 0
+		for ; i < len(sorted) && sorted[i].Days == t; i++ {
+			if sorted[i].Event {
+				events++
+			} else {
+				censored++
+			}
+		}
+		estimate.Events += events
+		estimate.Censored += censored
+
+		if events > 0 {
+			survival *= 1 - float64(events)/float64(atRisk)
+			days := math.Round(t*100) / 100
+			for _, target := range targets {
+				if 1-survival < target.p-1e-9 {
+					continue
+				}
+				if target.key == "median" {
+					if estimate.MedianDays == nil {
+						estimate.MedianDays = &days
+					}
+				} else if estimate.Percentiles[target.key] == nil {
+					estimate.Percentiles[target.key] = &days
+				}
+			}
+			if withCurve {
+				estimate.Curve = append(estimate.Curve, model.SurvivalPoint{
+					TimeDays: days,
+					Survival: math.Round(survival*10000) / 10000,
+					AtRisk:   atRisk,
+					Events:   events,
+					Censored: censored,
+				})
+			}
+		} else if withCurve && len(estimate.Curve) > 0 {
+			estimate.Curve[len(estimate.Curve)-1].Censored += censored
+		}
+		atRisk -= events + censored
+	}
+	return estimate
+}
+
+// buildSurvivalAnalytics 汇总整体、分阶段和分公司的生存估计
+func buildSurvivalAnalytics(apps []applicationTimeline, now time.Time, minSamples int) *model.SurvivalAnalyticsResponse {
+	outcomeSamples := make(map[string][]survivalSample)
+	stageSamples := make(map[string][]survivalSample)
+	companySamples := make(map[string]map[string][]survivalSample)
+
+	for _, app := range apps {
+		for metric, sample := range survivalOutcomeSamples(app, now) {
+			outcomeSamples[metric] = append(outcomeSamples[metric], sample)
+			if companySamples[app.CompanyName] == nil {
+				companySamples[app.CompanyName] = make(map[string][]survivalSample)
+			}
+			companySamples[app.CompanyName][metric] = append(companySamples[app.CompanyName][metric], sample)
+		}
+		for key, samples := range survivalStageSamples(app, now) {
+			stageSamples[key] = append(stageSamples[key], samples...)
+		}
+	}
+
+	response := &model.SurvivalAnalyticsResponse{
+		Outcomes:  []model.SurvivalEstimate{},
+		ByStage:   []model.SurvivalEstimate{},
+		ByCompany: make(map[string][]model.SurvivalEstimate),
+	}
+	for _, outcome := range survivalOutcomes {
+		response.Outcomes = append(response.Outcomes, kaplanMeier(outcome.Metric, outcome.Label, outcomeSamples[outcome.Metric], true))
+	}
+	for _, stage := range model.FunnelStages {
+		if samples, ok := stageSamples[stage.Key]; ok {
+			response.ByStage = append(response.ByStage, kaplanMeier("stage:"+stage.Key, stage.Name+"停留", samples, true))
+		}
+	}
+	for company, metrics := range companySamples {
+		if len(metrics["first_response"]) < minSamples {
+			continue
+		}
+		for _, outcome := range survivalOutcomes {
+			response.ByCompany[company] = append(response.ByCompany[company], kaplanMeier(outcome.Metric, outcome.Label, metrics[outcome.Metric], false))
+		}
+	}
+	return response
+}
@@ -0,0 +1,322 @@
+// This file implements the free-text part of SearchApplications: tokenizing the query
+// (Chinese runs are additionally split into bigrams), building substring / trigram match
+// conditions with a weighted relevance score, and producing highlighted snippets.
+
+package service
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/querylang"
+	"jobView-backend/internal/utils"
+)
+
+const (
+	maxSearchTokens      = 10
+	searchSnippetContext = 20 // 片段中命中位置前后保留的字符数
+	minFuzzyTokenLength  = 3  // 参与模糊匹配的最短检索词（字符数）
+)
+
+// searchField 参与搜索的字段及其相关度权重
+type searchField struct {
+	Column string
+	Weight float64
+	Value  func(app *model.JobApplication) string
+}
+
+// searchFields 与 database.ApplicationSearchDocument 覆盖的字段一致
+var searchFields = []searchField{
+	{Column: "company_name", Weight: 4, Value: func(app *model.JobApplication) string { return app.CompanyName }},
+	{Column: "position_title", Weight: 3, Value: func(app *model.JobApplication) string { return app.PositionTitle }},
+	{Column: "work_location", Weight: 2, Value: func(app *model.JobApplication) string { return stringValue(app.WorkLocation) }},
+	{Column: "hr_name", Weight: 2, Value: func(app *model.JobApplication) string { return stringValue(app.HRName) }},
+	{Column: "notes", Weight: 1.5, Value: func(app *model.JobApplication) string { return stringValue(app.Notes) }},
+	{Column: "job_description", Weight: 1, Value: func(app *model.JobApplication) string { return stringValue(app.JobDescription) }},
+}
+
+// searchToken 检索词。连续 3 个及以上汉字的词额外拆成二元组，允许部分命中
+type searchToken struct {
+	Text    string
+	Phrase  bool // 引号短语，仅做子串匹配
+	Bigrams []string
+}
+
+// fuzzy 是否参与三元组模糊匹配
+func (t searchToken) fuzzy() bool {
+	return !t.Phrase && len(t.Bigrams) == 0 && !isHanText(t.Text) && len([]rune(t.Text)) >= minFuzzyTokenLength
+}
+
+// tokenizeSearch 将自由文本拆分为检索词：按空白与标点切分，并在汉字与其他字符之间断开
+func tokenizeSearch(values []string) []searchToken {
+	var tokens []searchToken
+	seen := make(map[string]bool)
+	add := func(token searchToken) {
+		key := strings.ToLower(token.Text)
+		if key == "" || seen[key] || len(tokens) >= maxSearchTokens {
+			return
+		}
+		seen[key] = true
+		token.Text = key
+		runes := []rune(key)
+		if !token.Phrase && isHanText(key) && len(runes) >= 3 {
+			for i := 0; i+1 < len(runes); i++ {
+				token.Bigrams = append(token.Bigrams, string(runes[i:i+2]))
+			}
+		}
+		tokens = append(tokens, token)
+	}
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if strings.ContainsFunc(value, unicode.IsSpace) {
+			add(searchToken{Text: value, Phrase: true})
+			continue
+		}
+		var current []rune
+		flush := func() {
+			add(searchToken{Text: string(current)})
+			current = nil
+		}
+		for _, r := range value {
+			switch {
+			case isSearchSeparator(r):
+				flush()
+			case len(current) > 0 && unicode.Is(unicode.Han, r) != unicode.Is(unicode.Han, current[len(current)-1]):
+				flush()
+				current = append(current, r)
+			default:
+				current = append(current, r)
+			}
+		}
+		flush()
+	}
+	return tokens
+}
+
+// isSearchSeparator 切分字符；保留 + # . 以支持 C++、C#、.NET 等技术词
+func isSearchSeparator(r rune) bool {
+	if r == '+' || r == '#' || r == '.' {
+		return false
+	}
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// isHanText 是否全部为汉字
+func isHanText(text string) bool {
+	for _, r := range text {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// searchConditions 生成检索条件（各检索词 AND）与相关度表达式，占位符从 argIndex 开始。
+// 每个检索词命中条件：搜索文档包含该词；汉字长词的二元组命中至少 2/3；
+// fuzzy 为 true 时（pg_trgm 可用）西文词的 word_similarity 达到阈值也算命中
+func searchConditions(tokens []searchToken, fuzzy bool, argIndex int) (string, string, []interface{}, int) {
+	doc := database.ApplicationSearchDocument
+	var conditions, rankParts []string
+	var args []interface{}
+
+	for _, token := range tokens {
+		patternIndex := argIndex
+		args = append(args, "%"+utils.EscapeLike(token.Text)+"%")
+		argIndex++
+
+		matches := []string{fmt.Sprintf("%s ILIKE $%d", doc, patternIndex)}
+		for _, field := range searchFields {
+			rankParts = append(rankParts, fmt.Sprintf("(CASE WHEN %s ILIKE $%d THEN %g ELSE 0 END)", field.Column, patternIndex, field.Weight))
+		}
+
+		if len(token.Bigrams) > 0 {
+			bigramHits := make([]string, len(token.Bigrams))
+			for i, bigram := range token.Bigrams {
+				bigramHits[i] = fmt.Sprintf("(%s ILIKE $%d)::int", doc, argIndex)
+				args = append(args, "%"+utils.EscapeLike(bigram)+"%")
+				argIndex++
+			}
+			hits := "(" + strings.Join(bigramHits, " + ") + ")"
+			required := int(math.Ceil(float64(len(token.Bigrams)) * 2 / 3))
+			matches = append(matches, fmt.Sprintf("%s >= %d", hits, required))
+			rankParts = append(rankParts, fmt.Sprintf("%s::float / %d", hits, len(token.Bigrams)))
+		}
+
+		if fuzzy && token.fuzzy() {
+			matches = append(matches, fmt.Sprintf("$%d <%% %s", argIndex, doc))
+			rankParts = append(rankParts, fmt.Sprintf("word_similarity($%d, %s)", argIndex, doc))
+			args = append(args, token.Text)
+			argIndex++
+		}
+
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", "0", nil, argIndex
+	}
+	return " AND " + strings.Join(conditions, " AND "), strings.Join(rankParts, " + "), args, argIndex
+}
+
+// searchFilterCondition 搜索条件：自由文本按子串/二元组与拼写容错匹配，字段条件使用查询语言构造器；
+// 返回条件、相关度表达式与参数。搜索接口与搜索视图的导出共用，保证两者匹配的申请一致
+func (s *JobApplicationService) searchFilterCondition(searchQuery string, argIndex int) (string, string, []interface{}, int, error) {
+	parsed, err := querylang.Parse(searchQuery)
+	if err != nil {
+		return "", "", nil, argIndex, err
+	}
+	condition, rankExpr, args, argIndex := searchConditions(tokenizeSearch(parsed.FreeText()), s.fuzzySearchEnabled(), argIndex)
+	queryCondition, queryArgs, argIndex := parsed.WithoutFreeText().Conditions(argIndex)
+	return condition + queryCondition, rankExpr, append(args, queryArgs...), argIndex, nil
+}
+
+// fuzzySearchEnabled pg_trgm 扩展是否可用，首次调用时检测并缓存
+func (s *JobApplicationService) fuzzySearchEnabled() bool {
+	s.trigramOnce.Do(func() {
+		err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')").Scan(&s.trigramAvailable)
+		if err != nil {
+			log.Printf("Warning: failed to detect pg_trgm, fuzzy search disabled: %v", err)
+		}
+	})
+	return s.trigramAvailable
+}
+
+// buildSearchHighlights 为搜索结果生成命中片段，按字段权重顺序排列
+func buildSearchHighlights(app *model.JobApplication, tokens []searchToken) []model.SearchHighlight {
+	var highlights []model.SearchHighlight
+	for _, field := range searchFields {
+		text := []rune(field.Value(app))
+		if len(text) == 0 {
+			continue
+		}
+		lower := []rune(strings.ToLower(string(text)))
+		if len(lower) != len(text) {
+			lower = text // 大小写转换改变了长度时退化为区分大小写
+		}
+
+		var ranges [][2]int
+		fuzzy := false
+		for _, token := range tokens {
+			found := findAll(lower, []rune(token.Text))
+			if len(found) == 0 {
+				for _, bigram := range token.Bigrams {
+					found = append(found, findAll(lower, []rune(bigram))...)
+				}
+			}
+			ranges = append(ranges, found...)
+		}
+		if len(ranges) == 0 {
+			for _, token := range tokens {
+				if token.fuzzy() {
+					ranges = append(ranges, fuzzyWordMatches(lower, token.Text)...)
+				}
+			}
+			fuzzy = len(ranges) > 0
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+		highlights = append(highlights, model.SearchHighlight{
+			Field:   field.Column,
+			Snippet: highlightSnippet(text, mergeRanges(ranges)),
+			Fuzzy:   fuzzy,
+		})
+	}
+	return highlights
+}
+
+// findAll 返回 needle 在 text 中所有（不重叠）出现位置
+func findAll(text, needle []rune) [][2]int {
+	var ranges [][2]int
+	if len(needle) == 0 {
+		return nil
+	}
+	for i := 0; i+len(needle) <= len(text); {
+		if string(text[i:i+len(needle)]) == string(needle) {
+			ranges = append(ranges, [2]int{i, i + len(needle)})
+			i += len(needle)
+			continue
+		}
+		i++
+	}
+	return ranges
+}
+
+// fuzzyWordMatches 查找与检索词编辑距离足够小的单词（容错命中）
+func fuzzyWordMatches(text []rune, token string) [][2]int {
+	maxDistance := 1
+	if len([]rune(token)) >= 6 {
+		maxDistance = 2
+	}
+	var ranges [][2]int
+	start := -1
+	for i := 0; i <= len(text); i++ {
+		inWord := i < len(text) && (unicode.IsLetter(text[i]) || unicode.IsDigit(text[i])) && !unicode.Is(unicode.Han, text[i])
+		if inWord && start < 0 {
+			start = i
+		}
+		if !inWord && start >= 0 {
+			if utils.Levenshtein(string(text[start:i]), token) <= maxDistance {
+				ranges = append(ranges, [2]int{start, i})
+			}
+			start = -1
+		}
+	}
+	return ranges
+}
+
+// mergeRanges 排序并合并重叠的命中区间
+func mergeRanges(ranges [][2]int) [][2]int {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := [][2]int{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// highlightSnippet 截取首个命中位置附近的文本并用 <mark> 标记命中部分
+func highlightSnippet(text []rune, ranges [][2]int) string {
+	start := max(ranges[0][0]-searchSnippetContext, 0)
+	end := min(ranges[0][1]+searchSnippetContext*3, len(text))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, r := range ranges {
+		if r[0] >= end {
+			break
+		}
+		b.WriteString(html.EscapeString(string(text[pos:r[0]])))
+		b.WriteString("<mark>" + html.EscapeString(string(text[r[0]:min(r[1], end)])) + "</mark>")
+		pos = min(r[1], end)
+	}
+	b.WriteString(html.EscapeString(string(text[pos:end])))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// stringValue 可空字符串取值
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
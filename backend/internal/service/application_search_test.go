@@ -0,0 +1,66 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeSearch(t *testing.T) {
+	tokens := tokenizeSearch([]string{"字节跳动Golang", "C++/Rust", "大 模型", "golang"})
+	var texts []string
+	for _, token := range tokens {
+		texts = append(texts, token.Text)
+	}
+	if strings.Join(texts, "|") != "字节跳动|golang|c++|rust|大 模型" {
+		t.Fatalf("unexpected tokens %q", texts)
+	}
+	if len(tokens[0].Bigrams) != 3 || tokens[0].Bigrams[1] != "节跳" {
+		t.Fatalf("expected Chinese bigrams, got %v", tokens[0].Bigrams)
+	}
+	if !tokens[1].fuzzy() || tokens[0].fuzzy() || tokens[4].fuzzy() || !tokens[4].Phrase {
+		t.Fatalf("unexpected fuzzy/phrase flags %+v", tokens)
+	}
+}
+
+func TestSearchConditions(t *testing.T) {
+	tokens := tokenizeSearch([]string{"后端开发", "golang", "50% off"})
+	cond, rank, args, next := searchConditions(tokens, true, 2)
+	if next != 9 || len(args) != 7 {
+		t.Fatalf("unexpected arg count %d next %d", len(args), next)
+	}
+	if !strings.Contains(cond, ">= 2") || !strings.Contains(cond, "$7 <% ") || strings.Contains(cond, "$8 <%") {
+		t.Fatalf("unexpected condition %s", cond)
+	}
+	if !strings.Contains(rank, "word_similarity($7") || !strings.Contains(rank, "company_name ILIKE $2 THEN 4") {
+		t.Fatalf("unexpected rank %s", rank)
+	}
+	if args[len(args)-1] != `%50\% off%` {
+		t.Fatalf("expected LIKE wildcard to be escaped, got %v", args[len(args)-1])
+	}
+
+	cond, _, _, _ = searchConditions(tokens, false, 2)
+	if strings.Contains(cond, "<%") {
+		t.Fatalf("expected no trigram operator without pg_trgm, got %s", cond)
+	}
+}
+
+func TestBuildSearchHighlights(t *testing.T) {
+	jd := strings.Repeat("负责", 30) + "高并发 <Golang> 服务开发"
+	location := "北京"
+	app := &model.JobApplication{CompanyName: "字节跳动", PositionTitle: "Golnag Engineer", JobDescription: &jd, WorkLocation: &location}
+
+	highlights := buildSearchHighlights(app, tokenizeSearch([]string{"字节", "golang"}))
+	if len(highlights) != 3 {
+		t.Fatalf("expected 3 highlights, got %+v", highlights)
+	}
+	if highlights[0].Field != "company_name" || highlights[0].Snippet != "<mark>字节</mark>跳动" {
+		t.Fatalf("unexpected company highlight %+v", highlights[0])
+	}
+	if highlights[1].Field != "position_title" || !highlights[1].Fuzzy || highlights[1].Snippet != "<mark>Golnag</mark> Engineer" {
+		t.Fatalf("unexpected fuzzy highlight %+v", highlights[1])
+	}
+	if !strings.HasPrefix(highlights[2].Snippet, "…") || !strings.Contains(highlights[2].Snippet, "&lt;<mark>Golang</mark>&gt;") {
+		t.Fatalf("unexpected JD snippet %q", highlights[2].Snippet)
+	}
+}
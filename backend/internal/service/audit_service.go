@@ -0,0 +1,170 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"regexp"
+	"time"
+)
+
+// auditFieldPattern 允许查询的字段名
+var auditFieldPattern = regexp.MustCompile(`^[a-z_]{1,64}$`)
+
+// auditBatchSetting 批量操作前在事务内设置，触发器据此把动作记为 batch_*
+const auditBatchSetting = "SET LOCAL jobview.audit_batch = 'on'"
+
+type AuditService struct {
+	db *database.DB
+}
+
+func NewAuditService(db *database.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// QueryLogs 分页查询用户的审计日志，可按申请、动作、字段和日期过滤
+func (s *AuditService) QueryLogs(userID uint, q *model.AuditLogQuery) (*model.PaginationResponse, error) {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	if q.PageSize <= 0 || q.PageSize > 100 {
+		q.PageSize = 20
+	}
+
+	whereClause := "WHERE user_id = $1"
+	args := []interface{}{userID}
+	argIndex := 2
+	if q.JobApplicationID > 0 {
+		whereClause += fmt.Sprintf(" AND job_application_id = $%d", argIndex)
+		args = append(args, q.JobApplicationID)
+		argIndex++
+	}
+	if q.TransactionID > 0 {
+		whereClause += fmt.Sprintf(" AND transaction_id = $%d", argIndex)
+		args = append(args, q.TransactionID)
+		argIndex++
+	}
+	if q.Action != "" {
+		switch q.Action {
+		case model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDelete,
+			model.AuditActionRestore, model.AuditActionPurge,
+			model.AuditActionBatchCreate, model.AuditActionBatchUpdate, model.AuditActionBatchDelete,
+			model.AuditActionBatchRestore, model.AuditActionBatchPurge:
+		default:
+			return nil, fmt.Errorf("invalid action: %s", q.Action)
+		}
+		whereClause += fmt.Sprintf(" AND action = $%d", argIndex)
+		args = append(args, q.Action)
+		argIndex++
+	}
+	if q.Field != "" {
+		if !auditFieldPattern.MatchString(q.Field) {
+			return nil, fmt.Errorf("invalid field: %s", q.Field)
+		}
+		whereClause += fmt.Sprintf(" AND changes ? $%d", argIndex)
+		args = append(args, q.Field)
+		argIndex++
+	}
+	if q.StartDate != "" {
+		if !isValidDate(q.StartDate) {
+			return nil, fmt.Errorf("invalid start date format: %s", q.StartDate)
+		}
+		whereClause += fmt.Sprintf(" AND created_at >= $%d::date", argIndex)
+		args = append(args, q.StartDate)
+		argIndex++
+	}
+	if q.EndDate != "" {
+		if !isValidDate(q.EndDate) {
+			return nil, fmt.Errorf("invalid end date format: %s", q.EndDate)
+		}
+		whereClause += fmt.Sprintf(" AND created_at < $%d::date + 1", argIndex)
+		args = append(args, q.EndDate)
+		argIndex++
+	}
+
+	var total int64
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM job_application_audit_logs "+whereClause, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, job_application_id, user_id, action, changes, transaction_id, created_at
+		FROM job_application_audit_logs
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+	args = append(args, q.PageSize, (q.Page-1)*q.PageSize)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.AuditLogEntry{}
+	for rows.Next() {
+		var entry model.AuditLogEntry
+		var changesJSON []byte
+		if err := rows.Scan(&entry.ID, &entry.JobApplicationID, &entry.UserID, &entry.Action,
+			&changesJSON, &entry.TransactionID, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		if err := json.Unmarshal(changesJSON, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit logs: %w", err)
+	}
+
+	totalPages := int((total + int64(q.PageSize) - 1) / int64(q.PageSize))
+	return &model.PaginationResponse{
+		Data:       entries,
+		Total:      total,
+		Page:       q.Page,
+		PageSize:   q.PageSize,
+		TotalPages: totalPages,
+		HasNext:    q.Page < totalPages,
+		HasPrev:    q.Page > 1,
+	}, nil
+}
+
+// PurgeExpired 删除超过保留天数的审计日志，retentionDays <= 0 表示永久保留
+func (s *AuditService) PurgeExpired(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	result, err := s.db.Exec(
+		"DELETE FROM job_application_audit_logs WHERE created_at < NOW() - make_interval(days => $1)",
+		retentionDays,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge audit logs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// StartRetentionJob 每天清理一次过期审计日志
+func (s *AuditService) StartRetentionJob(retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			purged, err := s.PurgeExpired(retentionDays)
+			if err != nil {
+				log.Printf("Warning: audit log retention failed: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("[AUDIT-RETENTION] purged=%d retention_days=%d", purged, retentionDays)
+			}
+		}
+	}()
+}
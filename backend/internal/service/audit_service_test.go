@@ -0,0 +1,27 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogQueryValidation(t *testing.T) {
+	s := &AuditService{}
+	cases := []*model.AuditLogQuery{
+		{Action: "drop"},
+		{Field: "notes; DROP TABLE users"},
+		{StartDate: "2025/01/01"},
+		{EndDate: "yesterday"},
+	}
+	for _, q := range cases {
+		_, err := s.QueryLogs(1, q)
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("expected invalid error for %+v, got %v", q, err)
+		}
+	}
+
+	if n, err := s.PurgeExpired(0); err != nil || n != 0 {
+		t.Fatalf("retention 0 should keep everything, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,703 @@
+// This file implements companies as a first-class entity: canonical names with aliases that
+// job applications are linked to automatically (see database.ensureCompanies), merging and
+// splitting of companies, and the per-company aggregate page.
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/utils"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxCompanyNameLength     = 255
+	maxCompanyIndustryLength = 100
+	maxCompanyNotesLength    = 2000
+	maxCompanyAliases        = 20
+	companyColumns           = "c.id, c.user_id, c.name, c.industry, c.size, c.website, c.notes, c.rating, c.created_at, c.updated_at"
+)
+
+type CompanyService struct {
+	db *database.DB
+}
+
+func NewCompanyService(db *database.DB) *CompanyService {
+	return &CompanyService{db: db}
+}
+
+// List 获取用户的公司及关联申请数，q 非空时按名称或别名模糊匹配
+func (s *CompanyService) List(userID uint, q string) ([]model.Company, error) {
+	query := `
+		SELECT ` + companyColumns + `,
+			(SELECT COUNT(*) FROM job_applications ja WHERE ja.company_id = c.id AND ja.deleted_at IS NULL) AS application_count
+		FROM companies c
+		WHERE c.user_id = $1`
+	args := []interface{}{userID}
+	if q = strings.TrimSpace(q); q != "" {
+		query += ` AND (c.name ILIKE $2 OR EXISTS (
+			SELECT 1 FROM company_aliases a WHERE a.company_id = c.id AND a.alias ILIKE $2))`
+		args = append(args, "%"+utils.EscapeLike(q)+"%")
+	}
+	query += " ORDER BY application_count DESC, LOWER(c.name)"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	companies := []model.Company{}
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, *company)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate companies: %w", err)
+	}
+
+	aliases, err := s.aliasesByCompany(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range companies {
+		companies[i].Aliases = aliases[companies[i].ID]
+	}
+	return companies, nil
+}
+
+// Get 获取公司聚合页：申请列表、状态分布、到达的漏斗阶段与响应时间
+func (s *CompanyService) Get(userID uint, id int) (*model.CompanyDetail, error) {
+	company, err := s.load(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, company_name, position_title, status, application_date, updated_at
+		FROM job_applications
+		WHERE company_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY application_date DESC, id DESC
+	`, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query company applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []model.CompanyApplicationSummary{}
+	for rows.Next() {
+		var app model.CompanyApplicationSummary
+		if err := rows.Scan(&app.ID, &app.CompanyName, &app.PositionTitle, &app.Status, &app.ApplicationDate, &app.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan company application: %w", err)
+		}
+		applications = append(applications, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate company applications: %w", err)
+	}
+
+	historyRows, err := s.db.Query(`
+		SELECT h.job_application_id, h.new_status, h.status_changed_at
+		FROM job_status_history h
+		JOIN job_applications ja ON ja.id = h.job_application_id
+		WHERE ja.company_id = $1 AND ja.user_id = $2 AND ja.deleted_at IS NULL
+		ORDER BY h.status_changed_at, h.id
+	`, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query company status history: %w", err)
+	}
+	defer historyRows.Close()
+
+	history := make(map[int][]companyStatusChange)
+	for historyRows.Next() {
+		var appID int
+		var change companyStatusChange
+		if err := historyRows.Scan(&appID, &change.Status, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status history: %w", err)
+		}
+		history[appID] = append(history[appID], change)
+	}
+	if err := historyRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status history: %w", err)
+	}
+
+	return buildCompanyDetail(company, applications, history), nil
+}
+
+// Create 手动创建公司，名称与别名不能已属于其他公司
+func (s *CompanyService) Create(userID uint, req *model.CreateCompanyRequest) (*model.Company, error) {
+	name, err := normalizeCompanyName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Aliases) > maxCompanyAliases {
+		return nil, fmt.Errorf("invalid aliases: at most %d aliases allowed", maxCompanyAliases)
+	}
+	details, err := normalizeCompanyDetails(&model.UpdateCompanyRequest{
+		Industry: req.Industry, Size: req.Size, Website: req.Website, Notes: req.Notes, Rating: req.Rating,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := []string{name}
+	keys := map[string]bool{model.CompanyNameKey(name): true}
+	for _, alias := range req.Aliases {
+		alias, err := normalizeCompanyName(alias)
+		if err != nil {
+			return nil, err
+		}
+		if key := model.CompanyNameKey(alias); !keys[key] {
+			keys[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, alias := range aliases {
+		if err := s.checkAliasAvailable(userID, alias, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO companies (user_id, name, industry, size, website, notes, rating)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, userID, name, details["industry"], details["size"], details["website"], details["notes"], details["rating"]).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create company: %w", err)
+	}
+	for _, alias := range aliases {
+		if _, err := tx.Exec(`
+			INSERT INTO company_aliases (company_id, user_id, alias, alias_key) VALUES ($1, $2, $3, $4)
+		`, id, userID, alias, model.CompanyNameKey(alias)); err != nil {
+			return nil, fmt.Errorf("failed to create company alias: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit company: %w", err)
+	}
+	return s.load(userID, id)
+}
+
+// Update 修改公司信息。改名时新名称自动成为别名，旧名称保留为别名
+func (s *CompanyService) Update(userID uint, id int, req *model.UpdateCompanyRequest) (*model.Company, error) {
+	if _, err := s.load(userID, id); err != nil {
+		return nil, err
+	}
+	details, err := normalizeCompanyDetails(req)
+	if err != nil {
+		return nil, err
+	}
+
+	setParts := []string{}
+	args := []interface{}{}
+	argIndex := 1
+	var name string
+	if req.Name != nil {
+		if name, err = normalizeCompanyName(*req.Name); err != nil {
+			return nil, err
+		}
+		if err := s.checkAliasAvailable(userID, name, id); err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, name)
+		argIndex++
+	}
+	for _, column := range []string{"industry", "size", "website", "notes", "rating"} {
+		if value, ok := details[column]; ok {
+			setParts = append(setParts, fmt.Sprintf("%s = $%d", column, argIndex))
+			args = append(args, value)
+			argIndex++
+		}
+	}
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("invalid request: no fields to update")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("UPDATE companies SET %s, updated_at = NOW() WHERE id = $%d AND user_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	args = append(args, id, userID)
+	if _, err := tx.Exec(query, args...); err != nil {
+		return nil, fmt.Errorf("failed to update company: %w", err)
+	}
+	if name != "" {
+		if _, err := tx.Exec(`
+			INSERT INTO company_aliases (company_id, user_id, alias, alias_key) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, alias_key) DO NOTHING
+		`, id, userID, name, model.CompanyNameKey(name)); err != nil {
+			return nil, fmt.Errorf("failed to add company alias: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit company: %w", err)
+	}
+	return s.load(userID, id)
+}
+
+// Delete 删除公司。仍有申请（含回收站）关联时拒绝，应改用合并
+func (s *CompanyService) Delete(userID uint, id int) error {
+	if _, err := s.load(userID, id); err != nil {
+		return err
+	}
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM job_applications WHERE company_id = $1", id).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count company applications: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("invalid request: company still has %d applications, merge it into another company instead", count)
+	}
+	if _, err := s.db.Exec("DELETE FROM companies WHERE id = $1 AND user_id = $2", id, userID); err != nil {
+		return fmt.Errorf("failed to delete company: %w", err)
+	}
+	return nil
+}
+
+// AddAlias 为公司添加别名，之后以该写法录入的申请会自动关联到此公司
+func (s *CompanyService) AddAlias(userID uint, id int, alias string) (*model.Company, error) {
+	company, err := s.load(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if alias, err = normalizeCompanyName(alias); err != nil {
+		return nil, err
+	}
+	if len(company.Aliases) >= maxCompanyAliases*2 {
+		return nil, fmt.Errorf("invalid request: company already has %d aliases", len(company.Aliases))
+	}
+	if err := s.checkAliasAvailable(userID, alias, 0); err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec(`
+		INSERT INTO company_aliases (company_id, user_id, alias, alias_key) VALUES ($1, $2, $3, $4)
+	`, id, userID, alias, model.CompanyNameKey(alias)); err != nil {
+		return nil, fmt.Errorf("failed to add company alias: %w", err)
+	}
+	return s.load(userID, id)
+}
+
+// RemoveAlias 移除别名。若仍有申请使用该写法，则连同这些申请拆分为一家新公司（撤销错误归并）
+func (s *CompanyService) RemoveAlias(userID uint, id, aliasID int) (*model.Company, error) {
+	company, err := s.load(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	var alias, key string
+	err = s.db.QueryRow("SELECT alias, alias_key FROM company_aliases WHERE id = $1 AND company_id = $2",
+		aliasID, id).Scan(&alias, &key)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("company alias not found or access denied")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company alias: %w", err)
+	}
+	if key == model.CompanyNameKey(company.Name) {
+		return nil, fmt.Errorf("invalid request: cannot remove the alias matching the company name")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 只改申请的 company_id 关联，不作为用户对申请的修改
+	if _, err := tx.Exec(preserveUpdatedAtSetting); err != nil {
+		return nil, fmt.Errorf("failed to preserve updated_at: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(`
+		SELECT COUNT(*) FROM job_applications
+		WHERE user_id = $1 AND company_id = $2 AND company_name_key(company_name) = $3
+	`, userID, id, key).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count alias applications: %w", err)
+	}
+	if count == 0 {
+		if _, err := tx.Exec("DELETE FROM company_aliases WHERE id = $1", aliasID); err != nil {
+			return nil, fmt.Errorf("failed to remove company alias: %w", err)
+		}
+	} else {
+		var newID int
+		if err := tx.QueryRow("INSERT INTO companies (user_id, name) VALUES ($1, $2) RETURNING id",
+			userID, alias).Scan(&newID); err != nil {
+			return nil, fmt.Errorf("failed to split company: %w", err)
+		}
+		if _, err := tx.Exec("UPDATE company_aliases SET company_id = $1 WHERE id = $2", newID, aliasID); err != nil {
+			return nil, fmt.Errorf("failed to move company alias: %w", err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE job_applications SET company_id = $1
+			WHERE user_id = $2 AND company_id = $3 AND company_name_key(company_name) = $4
+		`, newID, userID, id, key); err != nil {
+			return nil, fmt.Errorf("failed to move alias applications: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit company alias removal: %w", err)
+	}
+	return s.load(userID, id)
+}
+
+// Merge 将来源公司合并到目标公司：别名与申请转移到目标公司，目标缺失的信息由来源补齐，来源公司删除
+func (s *CompanyService) Merge(userID uint, targetID int, sourceIDs []int) (*model.Company, error) {
+	if _, err := s.load(userID, targetID); err != nil {
+		return nil, err
+	}
+	var ids []int
+	seen := map[int]bool{targetID: true}
+	for _, id := range sourceIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("invalid request: source_ids must contain at least one other company")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 只改申请的 company_id 关联，不作为用户对申请的修改
+	if _, err := tx.Exec(preserveUpdatedAtSetting); err != nil {
+		return nil, fmt.Errorf("failed to preserve updated_at: %w", err)
+	}
+
+	for _, sourceID := range ids {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM companies WHERE id = $1 AND user_id = $2)",
+			sourceID, userID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check company: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("company %d not found or access denied", sourceID)
+		}
+
+		statements := []string{
+			"UPDATE company_aliases SET company_id = $1 WHERE company_id = $2",
+			"UPDATE job_applications SET company_id = $1 WHERE company_id = $2",
+			`UPDATE companies t SET
+				industry = COALESCE(t.industry, src.industry),
+				size = COALESCE(t.size, src.size),
+				website = COALESCE(t.website, src.website),
+				notes = COALESCE(t.notes, src.notes),
+				rating = COALESCE(t.rating, src.rating),
+				updated_at = NOW()
+			FROM companies src WHERE t.id = $1 AND src.id = $2`,
+			"DELETE FROM companies WHERE id = $2 AND id <> $1",
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement, targetID, sourceID); err != nil {
+				return nil, fmt.Errorf("failed to merge company %d: %w", sourceID, err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit company merge: %w", err)
+	}
+	return s.load(userID, targetID)
+}
+
+// load 获取单个公司（含别名与申请数）
+func (s *CompanyService) load(userID uint, id int) (*model.Company, error) {
+	row := s.db.QueryRow(`
+		SELECT `+companyColumns+`,
+			(SELECT COUNT(*) FROM job_applications ja WHERE ja.company_id = c.id AND ja.deleted_at IS NULL)
+		FROM companies c
+		WHERE c.id = $1 AND c.user_id = $2
+	`, id, userID)
+	company, err := scanCompany(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("company not found or access denied")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT id, alias FROM company_aliases WHERE company_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query company aliases: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var alias model.CompanyAlias
+		if err := rows.Scan(&alias.ID, &alias.Alias); err != nil {
+			return nil, fmt.Errorf("failed to scan company alias: %w", err)
+		}
+		company.Aliases = append(company.Aliases, alias)
+	}
+	return company, rows.Err()
+}
+
+// aliasesByCompany 获取用户全部公司的别名，按公司分组
+func (s *CompanyService) aliasesByCompany(userID uint) (map[int][]model.CompanyAlias, error) {
+	rows, err := s.db.Query("SELECT company_id, id, alias FROM company_aliases WHERE user_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query company aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make(map[int][]model.CompanyAlias)
+	for rows.Next() {
+		var companyID int
+		var alias model.CompanyAlias
+		if err := rows.Scan(&companyID, &alias.ID, &alias.Alias); err != nil {
+			return nil, fmt.Errorf("failed to scan company alias: %w", err)
+		}
+		aliases[companyID] = append(aliases[companyID], alias)
+	}
+	return aliases, rows.Err()
+}
+
+// checkAliasAvailable 检查别名键未被其他公司占用（allowedCompanyID 为允许的归属公司）
+func (s *CompanyService) checkAliasAvailable(userID uint, alias string, allowedCompanyID int) error {
+	var companyID int
+	var companyName string
+	err := s.db.QueryRow(`
+		SELECT c.id, c.name FROM company_aliases a JOIN companies c ON c.id = a.company_id
+		WHERE a.user_id = $1 AND a.alias_key = $2
+	`, userID, model.CompanyNameKey(alias)).Scan(&companyID, &companyName)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check company alias: %w", err)
+	}
+	if companyID == allowedCompanyID {
+		return nil
+	}
+	return fmt.Errorf("company alias %q already exists on company %q (id %d), merge the companies instead", alias, companyName, companyID)
+}
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCompany 扫描 companyColumns 与申请数
+func scanCompany(row rowScanner) (*model.Company, error) {
+	var company model.Company
+	err := row.Scan(&company.ID, &company.UserID, &company.Name, &company.Industry, &company.Size,
+		&company.Website, &company.Notes, &company.Rating, &company.CreatedAt, &company.UpdatedAt,
+		&company.ApplicationCount)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan company: %w", err)
+	}
+	company.Aliases = []model.CompanyAlias{}
+	return &company, nil
+}
+
+// normalizeCompanyName 校验公司名称或别名
+func normalizeCompanyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("invalid name: must not be empty")
+	}
+	if utf8.RuneCountInString(name) > maxCompanyNameLength {
+		return "", fmt.Errorf("invalid name: at most %d characters", maxCompanyNameLength)
+	}
+	return name, nil
+}
+
+// normalizeCompanyDetails 校验可选信息，返回需要写入的列（nil 值表示清空）
+func normalizeCompanyDetails(req *model.UpdateCompanyRequest) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	optional := func(column string, value *string, maxLength int) error {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		if utf8.RuneCountInString(trimmed) > maxLength {
+			return fmt.Errorf("invalid %s: at most %d characters", column, maxLength)
+		}
+		if trimmed == "" {
+			values[column] = nil
+		} else {
+			values[column] = trimmed
+		}
+		return nil
+	}
+	if err := optional("industry", req.Industry, maxCompanyIndustryLength); err != nil {
+		return nil, err
+	}
+	if err := optional("notes", req.Notes, maxCompanyNotesLength); err != nil {
+		return nil, err
+	}
+	if err := optional("size", req.Size, 20); err != nil {
+		return nil, err
+	}
+	if size, ok := values["size"].(string); ok && !containsString(model.CompanySizes, size) {
+		return nil, fmt.Errorf("invalid size: must be one of %s", strings.Join(model.CompanySizes, ", "))
+	}
+	if err := optional("website", req.Website, 255); err != nil {
+		return nil, err
+	}
+	if website, ok := values["website"].(string); ok {
+		if !strings.Contains(website, "://") {
+			website = "https://" + website
+		}
+		parsed, err := url.Parse(website)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid website: must be an http(s) URL")
+		}
+		values["website"] = website
+	}
+	if req.Rating != nil {
+		switch {
+		case *req.Rating == 0:
+			values["rating"] = nil
+		case *req.Rating < 1 || *req.Rating > 5:
+			return nil, fmt.Errorf("invalid rating: must be between 1 and 5")
+		default:
+			values["rating"] = *req.Rating
+		}
+	}
+	return values, nil
+}
+
+// containsString 切片是否包含该值
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// companyStatusChange 一次状态变更
+type companyStatusChange struct {
+	Status    model.ApplicationStatus
+	ChangedAt time.Time
+}
+
+// buildCompanyDetail 根据申请与状态历史计算公司聚合数据
+func buildCompanyDetail(company *model.Company, applications []model.CompanyApplicationSummary,
+	history map[int][]companyStatusChange) *model.CompanyDetail {
+	detail := &model.CompanyDetail{
+		Company:       *company,
+		Applications:  applications,
+		StagesReached: make([]model.CompanyStageReached, len(model.FunnelStages)),
+	}
+	for i, stage := range model.FunnelStages {
+		detail.StagesReached[i] = model.CompanyStageReached{Key: stage.Key, Name: stage.Name}
+	}
+
+	inProgress := model.StatusesForStage("in_progress")
+	failed := model.StatusesForStage("failed")
+	var responseDays []float64
+	for _, app := range applications {
+		switch {
+		case containsString(inProgress, string(app.Status)):
+			detail.InProgress++
+		case containsString(failed, string(app.Status)):
+			detail.Failed++
+		}
+		if app.Status == model.StatusOfferReceived || app.Status == model.StatusOfferAccepted {
+			detail.Offers++
+		}
+
+		// 到达的阶段：可选阶段只统计实际经过的，其余阶段按到达的最远阶段推算
+		visited := map[int]bool{0: true}
+		furthest := max(model.FunnelStageIndex(app.Status), 0)
+		visited[furthest] = true
+		var firstResponse *time.Time
+		for _, change := range history[app.ID] {
+			if idx := model.FunnelStageIndex(change.Status); idx >= 0 {
+				visited[idx] = true
+				furthest = max(furthest, idx)
+			}
+			if firstResponse == nil && change.Status != model.StatusApplied {
+				changedAt := change.ChangedAt
+				firstResponse = &changedAt
+			}
+		}
+		for i, stage := range model.FunnelStages {
+			if visited[i] || (!stage.Optional && furthest >= i) {
+				detail.StagesReached[i].Count++
+			}
+		}
+
+		switch {
+		case firstResponse != nil:
+			applied, err := time.ParseInLocation("2006-01-02", app.ApplicationDate, firstResponse.Location())
+			if err == nil {
+				responseDays = append(responseDays, math.Max(firstResponse.Sub(applied).Hours()/24, 0))
+			}
+		case app.Status == model.StatusApplied:
+			detail.ResponseTime.NoResponse++
+		}
+	}
+
+	detail.ResponseTime.Responded = len(responseDays)
+	if len(responseDays) > 0 {
+		sort.Float64s(responseDays)
+		sum := 0.0
+		for _, days := range responseDays {
+			sum += days
+		}
+		median := responseDays[len(responseDays)/2]
+		if len(responseDays)%2 == 0 {
+			median = (responseDays[len(responseDays)/2-1] + median) / 2
+		}
+		detail.ResponseTime.AverageDays = roundDays(sum / float64(len(responseDays)))
+		detail.ResponseTime.MedianDays = roundDays(median)
+		detail.ResponseTime.FastestDays = roundDays(responseDays[0])
+		detail.ResponseTime.SlowestDays = roundDays(responseDays[len(responseDays)-1])
+	}
+	return detail
+}
+
+// roundDays 天数保留一位小数
+func roundDays(days float64) *float64 {
+	rounded := math.Round(days*10) / 10
+	return &rounded
+}
+
+// companyNamesCondition 按公司名称筛选：既匹配填写的原始名称，也匹配这些名称所属公司的全部写法。
+// prefix 为 job_applications 的表别名前缀（如 "ja."），占位符从 argIndex 开始
+func companyNamesCondition(prefix string, names []string, argIndex int) (string, []interface{}, int) {
+	placeholders := make([]string, len(names))
+	keys := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		placeholders[i] = fmt.Sprintf("$%d", argIndex)
+		keys[i] = fmt.Sprintf("company_name_key($%d)", argIndex)
+		args[i] = name
+		argIndex++
+	}
+	condition := fmt.Sprintf("(%scompany_name IN (%s) OR %scompany_id IN (SELECT company_id FROM company_aliases WHERE alias_key IN (%s)))",
+		prefix, strings.Join(placeholders, ", "), prefix, strings.Join(keys, ", "))
+	return condition, args, argIndex
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"jobView-backend/internal/model"
+)
+
+func TestBuildCompanyDetail(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 12, 0, 0, 0, time.UTC) }
+	applications := []model.CompanyApplicationSummary{
+		{ID: 1, Status: model.StatusSecondInterview, ApplicationDate: "2025-09-01"},
+		{ID: 2, Status: model.StatusFirstFail, ApplicationDate: "2025-09-01"},
+		{ID: 3, Status: model.StatusApplied, ApplicationDate: "2025-09-10"},
+		{ID: 4, Status: model.StatusOfferReceived, ApplicationDate: "2025-09-02"},
+	}
+	history := map[int][]companyStatusChange{
+		1: {{model.StatusResumeScreening, day(3)}, {model.StatusWrittenTest, day(5)}, {model.StatusFirstInterview, day(8)}, {model.StatusSecondInterview, day(12)}},
+		2: {{model.StatusFirstInterview, day(2)}, {model.StatusFirstFail, day(4)}},
+		4: {{model.StatusFirstInterview, day(9)}, {model.StatusOfferReceived, day(20)}},
+	}
+
+	detail := buildCompanyDetail(&model.Company{ID: 7, Name: "字节跳动"}, applications, history)
+	if detail.InProgress != 2 || detail.Failed != 1 || detail.Offers != 1 {
+		t.Fatalf("unexpected status counts: in progress %d, failed %d, offers %d", detail.InProgress, detail.Failed, detail.Offers)
+	}
+
+	reached := make(map[string]int)
+	for _, stage := range detail.StagesReached {
+		reached[stage.Key] = stage.Count
+	}
+	want := map[string]int{"applied": 4, "screening": 3, "written": 1, "first": 3, "second": 2, "third": 0, "hr": 1, "offer": 1, "accepted": 0}
+	for key, count := range want {
+		if reached[key] != count {
+			t.Fatalf("stage %s: expected %d, got %d (%+v)", key, count, reached[key], detail.StagesReached)
+		}
+	}
+
+	rt := detail.ResponseTime
+	if rt.Responded != 3 || rt.NoResponse != 1 {
+		t.Fatalf("unexpected response counts %+v", rt)
+	}
+	// 响应天数：2.5、1.5、7.5
+	if *rt.MedianDays != 2.5 || *rt.FastestDays != 1.5 || *rt.SlowestDays != 7.5 || *rt.AverageDays != 3.8 {
+		t.Fatalf("unexpected response times: avg %v median %v fastest %v slowest %v",
+			*rt.AverageDays, *rt.MedianDays, *rt.FastestDays, *rt.SlowestDays)
+	}
+
+	empty := buildCompanyDetail(&model.Company{ID: 8}, nil, nil)
+	if empty.ResponseTime.AverageDays != nil || len(empty.StagesReached) != len(model.FunnelStages) {
+		t.Fatalf("expected empty aggregates, got %+v", empty)
+	}
+}
+
+func TestNormalizeCompanyDetails(t *testing.T) {
+	website, size, empty := "example.com/careers", "100-499", ""
+	rating, clear := 4, 0
+	values, err := normalizeCompanyDetails(&model.UpdateCompanyRequest{Website: &website, Size: &size, Notes: &empty, Rating: &rating})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["website"] != "https://example.com/careers" || values["size"] != "100-499" || values["rating"] != 4 {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if v, ok := values["notes"]; !ok || v != nil {
+		t.Fatalf("expected empty notes to clear the column, got %v", values)
+	}
+	if values, _ := normalizeCompanyDetails(&model.UpdateCompanyRequest{Rating: &clear}); values["rating"] != nil {
+		t.Fatalf("expected rating 0 to clear the column")
+	}
+
+	badSize, badWebsite, badRating := "huge", "ftp://example.com", 6
+	for _, req := range []*model.UpdateCompanyRequest{{Size: &badSize}, {Website: &badWebsite}, {Rating: &badRating}} {
+		if _, err := normalizeCompanyDetails(req); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("expected invalid error for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestCompanyNamesCondition(t *testing.T) {
+	cond, args, next := companyNamesCondition("ja.", []string{"字节", "ByteDance"}, 3)
+	expected := "(ja.company_name IN ($3, $4) OR ja.company_id IN (SELECT company_id FROM company_aliases WHERE alias_key IN (company_name_key($3), company_name_key($4))))"
+	if cond != expected {
+		t.Fatalf("unexpected condition %s", cond)
+	}
+	if len(args) != 2 || next != 5 {
+		t.Fatalf("expected 2 args and next index 5, got %v, %d", args, next)
+	}
+}
@@ -0,0 +1,665 @@
+// This file implements contacts: recruiters, HR, hiring managers and referrers linked many-to-many
+// to job applications, with a per-contact interaction log (calls, emails, messages) and follow-up
+// reminders. Contacts replace the hr_name/hr_phone/hr_email strings repeated on every application
+// (see database.migrateHRContacts for the one-time migration of existing data). Afterwards a trigger
+// links an hr contact whenever an application's HR fields are written; contacts are never written
+// back to the HR fields, and removing a link or a contact is not undone until the HR fields change.
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxContactNameLength      = 100
+	maxContactNotesLength     = 2000
+	maxContactChannels        = 10
+	maxContactApplications    = 200
+	maxFollowUpNoteLength     = 255
+	maxInteractionLength      = 2000
+	maxContactInteractions    = 200
+	defaultFollowUpWindowDays = 7
+	maxFollowUpWindowDays     = 365
+	contactColumns            = `c.id, c.user_id, c.name, c.role, c.company_id, co.name, c.channels, c.notes,
+		c.last_contacted_at, c.follow_up_at, c.follow_up_note, c.source, c.created_at, c.updated_at,
+		(SELECT COUNT(*) FROM contact_applications ca
+		 JOIN job_applications ja ON ja.id = ca.job_application_id AND ja.deleted_at IS NULL
+		 WHERE ca.contact_id = c.id)`
+	contactFrom = " FROM contacts c LEFT JOIN companies co ON co.id = c.company_id"
+)
+
+// contactWritableColumns 可写列，顺序决定 INSERT/UPDATE 中的参数顺序
+var contactWritableColumns = []string{"name", "role", "company_id", "channels", "notes", "follow_up_at", "follow_up_note"}
+
+type ContactService struct {
+	db *database.DB
+}
+
+func NewContactService(db *database.DB) *ContactService {
+	return &ContactService{db: db}
+}
+
+// List 获取用户的联系人，最近沟通过的在前
+func (s *ContactService) List(userID uint, req *model.ContactListRequest) ([]model.Contact, error) {
+	query := "SELECT " + contactColumns + contactFrom + " WHERE c.user_id = $1"
+	args := []interface{}{userID}
+	if q := strings.TrimSpace(req.Q); q != "" {
+		args = append(args, "%"+q+"%")
+		query += fmt.Sprintf(" AND (c.name ILIKE $%d OR c.channels::text ILIKE $%d)", len(args), len(args))
+	}
+	if req.Role != "" {
+		if !req.Role.IsValid() {
+			return nil, fmt.Errorf("invalid role: %s", req.Role)
+		}
+		args = append(args, string(req.Role))
+		query += fmt.Sprintf(" AND c.role = $%d", len(args))
+	}
+	if req.CompanyID > 0 {
+		args = append(args, req.CompanyID)
+		query += fmt.Sprintf(" AND c.company_id = $%d", len(args))
+	}
+	if req.JobApplicationID > 0 {
+		args = append(args, req.JobApplicationID)
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM contact_applications ca WHERE ca.contact_id = c.id AND ca.job_application_id = $%d)", len(args))
+	}
+	query += " ORDER BY c.last_contacted_at DESC NULLS LAST, c.id DESC"
+	return queryContacts(s.db, query, args...)
+}
+
+// ApplicationContacts 获取申请关联的联系人
+func (s *ContactService) ApplicationContacts(userID uint, jobApplicationID int) ([]model.Contact, error) {
+	if err := checkApplicationOwned(s.db, userID, jobApplicationID); err != nil {
+		return nil, err
+	}
+	return s.List(userID, &model.ContactListRequest{JobApplicationID: jobApplicationID})
+}
+
+// FollowUps 获取 days 天内到期（含已逾期）的跟进提醒，按跟进时间排序
+func (s *ContactService) FollowUps(userID uint, days int) ([]model.Contact, error) {
+	if days == 0 {
+		days = defaultFollowUpWindowDays
+	}
+	if days < 0 || days > maxFollowUpWindowDays {
+		return nil, fmt.Errorf("invalid days: must be between 1 and %d", maxFollowUpWindowDays)
+	}
+	return listContactFollowUps(s.db, userID, days)
+}
+
+// Get 获取联系人详情：关联的申请与沟通记录
+func (s *ContactService) Get(userID uint, id int) (*model.Contact, error) {
+	contact, err := s.load(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT ja.id, ja.company_name, ja.position_title, ja.status, ja.application_date
+		FROM contact_applications ca
+		JOIN job_applications ja ON ja.id = ca.job_application_id AND ja.deleted_at IS NULL
+		WHERE ca.contact_id = $1
+		ORDER BY ja.application_date DESC, ja.id DESC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact applications: %w", err)
+	}
+	defer rows.Close()
+
+	contact.Applications = []model.ContactApplication{}
+	for rows.Next() {
+		var app model.ContactApplication
+		var applicationDate time.Time
+		if err := rows.Scan(&app.ID, &app.CompanyName, &app.PositionTitle, &app.Status, &applicationDate); err != nil {
+			return nil, fmt.Errorf("failed to scan contact application: %w", err)
+		}
+		app.ApplicationDate = applicationDate.Format("2006-01-02")
+		contact.Applications = append(contact.Applications, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate contact applications: %w", err)
+	}
+
+	interactions, err := s.db.Query(`
+		SELECT id, contact_id, job_application_id, interaction_type, direction, summary, occurred_at, created_at
+		FROM contact_interactions
+		WHERE contact_id = $1
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $2
+	`, id, maxContactInteractions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact interactions: %w", err)
+	}
+	defer interactions.Close()
+
+	contact.Interactions = []model.ContactInteraction{}
+	for interactions.Next() {
+		var interaction model.ContactInteraction
+		if err := interactions.Scan(&interaction.ID, &interaction.ContactID, &interaction.JobApplicationID, &interaction.Type,
+			&interaction.Direction, &interaction.Summary, &interaction.OccurredAt, &interaction.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan contact interaction: %w", err)
+		}
+		contact.Interactions = append(contact.Interactions, interaction)
+	}
+	if err := interactions.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate contact interactions: %w", err)
+	}
+	return contact, nil
+}
+
+// Create 创建联系人，可同时关联申请
+func (s *ContactService) Create(userID uint, req *model.CreateContactRequest) (*model.Contact, error) {
+	role := req.Role
+	if role == "" {
+		role = model.ContactOther
+	}
+	channels := req.Channels
+	if channels == nil {
+		channels = []model.ContactChannel{}
+	}
+	values, company, err := normalizeContactFields(&model.UpdateContactRequest{
+		Name: &req.Name, Role: &role, Company: req.Company, Channels: channels, Notes: req.Notes,
+		FollowUpAt: req.FollowUpAt, FollowUpNote: req.FollowUpNote,
+	})
+	if err != nil {
+		return nil, err
+	}
+	jobIDs := uniqueInts(req.JobApplicationIDs)
+	if len(jobIDs) > maxContactApplications {
+		return nil, fmt.Errorf("invalid job_application_ids: at most %d applications", maxContactApplications)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if company != nil && *company != "" {
+		if values["company_id"], err = resolveContactCompany(tx, userID, *company); err != nil {
+			return nil, err
+		}
+	}
+
+	columns := []string{"user_id"}
+	placeholders := []string{"$1"}
+	args := []interface{}{userID}
+	for _, column := range contactWritableColumns {
+		value, ok := values[column]
+		if !ok {
+			continue
+		}
+		args = append(args, value)
+		columns = append(columns, column)
+		placeholders = append(placeholders, contactPlaceholder(column, len(args)))
+	}
+
+	var id int
+	if err := tx.QueryRow(fmt.Sprintf("INSERT INTO contacts (%s) VALUES (%s) RETURNING id",
+		strings.Join(columns, ", "), strings.Join(placeholders, ", ")), args...).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to create contact: %w", err)
+	}
+	if err := linkContactApplications(tx, userID, id, jobIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit contact: %w", err)
+	}
+	return s.Get(userID, id)
+}
+
+// Update 修改联系人
+func (s *ContactService) Update(userID uint, id int, req *model.UpdateContactRequest) (*model.Contact, error) {
+	if _, err := s.load(userID, id); err != nil {
+		return nil, err
+	}
+	values, company, err := normalizeContactFields(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 && company == nil {
+		return nil, fmt.Errorf("invalid request: no fields to update")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if company != nil {
+		values["company_id"] = nil
+		if *company != "" {
+			if values["company_id"], err = resolveContactCompany(tx, userID, *company); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	setParts := []string{}
+	args := []interface{}{}
+	for _, column := range contactWritableColumns {
+		value, ok := values[column]
+		if !ok {
+			continue
+		}
+		args = append(args, value)
+		setParts = append(setParts, column+" = "+contactPlaceholder(column, len(args)))
+	}
+	query := fmt.Sprintf("UPDATE contacts SET %s, updated_at = NOW() WHERE id = $%d AND user_id = $%d",
+		strings.Join(setParts, ", "), len(args)+1, len(args)+2)
+	args = append(args, id, userID)
+	if _, err := tx.Exec(query, args...); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit contact: %w", err)
+	}
+	return s.Get(userID, id)
+}
+
+// Delete 删除联系人及其沟通记录，关联的申请不受影响
+func (s *ContactService) Delete(userID uint, id int) error {
+	result, err := s.db.Exec("DELETE FROM contacts WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("contact not found or access denied")
+	}
+	return nil
+}
+
+// SetApplications 替换联系人关联的申请（回收站中申请的关联保留）
+func (s *ContactService) SetApplications(userID uint, id int, jobApplicationIDs []int) (*model.Contact, error) {
+	if _, err := s.load(userID, id); err != nil {
+		return nil, err
+	}
+	jobIDs := uniqueInts(jobApplicationIDs)
+	if len(jobIDs) > maxContactApplications {
+		return nil, fmt.Errorf("invalid job_application_ids: at most %d applications", maxContactApplications)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		DELETE FROM contact_applications ca
+		USING job_applications ja
+		WHERE ca.contact_id = $1 AND ja.id = ca.job_application_id AND ja.deleted_at IS NULL
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to clear contact applications: %w", err)
+	}
+	if err := linkContactApplications(tx, userID, id, jobIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit contact applications: %w", err)
+	}
+	return s.Get(userID, id)
+}
+
+// AddInteraction 记录一次沟通并更新最近沟通时间。指定 next_follow_up_at 时设置下次跟进，
+// 否则不晚于本次沟通的跟进提醒视为已完成并清除；涉及的申请自动与联系人关联
+func (s *ContactService) AddInteraction(userID uint, id int, req *model.CreateContactInteractionRequest) (*model.Contact, error) {
+	if _, err := s.load(userID, id); err != nil {
+		return nil, err
+	}
+	if !req.Type.IsValid() {
+		return nil, fmt.Errorf("invalid type: %s", req.Type)
+	}
+	direction := req.Direction
+	if direction == "" {
+		direction = model.InteractionOutbound
+	}
+	if direction != model.InteractionInbound && direction != model.InteractionOutbound {
+		return nil, fmt.Errorf("invalid direction: %s", req.Direction)
+	}
+	summary := strings.TrimSpace(req.Summary)
+	if summary == "" {
+		return nil, fmt.Errorf("invalid summary: must not be empty")
+	}
+	if utf8.RuneCountInString(summary) > maxInteractionLength {
+		return nil, fmt.Errorf("invalid summary: at most %d characters", maxInteractionLength)
+	}
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		occurredAt = *req.OccurredAt
+	}
+	followUpNote, err := normalizeFollowUpNote(req.NextFollowUpNote)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if req.JobApplicationID != nil {
+		if err := linkContactApplications(tx, userID, id, []int{*req.JobApplicationID}); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO contact_interactions (contact_id, user_id, job_application_id, interaction_type, direction, summary, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, id, userID, req.JobApplicationID, string(req.Type), string(direction), summary, occurredAt); err != nil {
+		return nil, fmt.Errorf("failed to record contact interaction: %w", err)
+	}
+
+	if req.NextFollowUpAt != nil {
+		_, err = tx.Exec(`
+			UPDATE contacts
+			SET last_contacted_at = GREATEST(COALESCE(last_contacted_at, $1), $1),
+			    follow_up_at = $2, follow_up_note = $3, updated_at = NOW()
+			WHERE id = $4
+		`, occurredAt, *req.NextFollowUpAt, followUpNote, id)
+	} else {
+		_, err = tx.Exec(`
+			UPDATE contacts
+			SET last_contacted_at = GREATEST(COALESCE(last_contacted_at, $1), $1),
+			    follow_up_note = CASE WHEN follow_up_at <= $1 THEN NULL ELSE follow_up_note END,
+			    follow_up_at = CASE WHEN follow_up_at <= $1 THEN NULL ELSE follow_up_at END,
+			    updated_at = NOW()
+			WHERE id = $2
+		`, occurredAt, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit contact interaction: %w", err)
+	}
+	return s.Get(userID, id)
+}
+
+// DeleteInteraction 删除沟通记录，并按剩余记录重新计算最近沟通时间
+func (s *ContactService) DeleteInteraction(userID uint, id, interactionID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM contact_interactions WHERE id = $1 AND contact_id = $2 AND user_id = $3",
+		interactionID, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete contact interaction: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("contact interaction not found or access denied")
+	}
+	if _, err := tx.Exec(`
+		UPDATE contacts
+		SET last_contacted_at = (SELECT MAX(occurred_at) FROM contact_interactions WHERE contact_id = $1),
+		    updated_at = NOW()
+		WHERE id = $1
+	`, id); err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// load 读取联系人（校验归属）
+func (s *ContactService) load(userID uint, id int) (*model.Contact, error) {
+	contact, err := scanContact(s.db.QueryRow("SELECT "+contactColumns+contactFrom+" WHERE c.id = $1 AND c.user_id = $2", id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("contact not found or access denied")
+	}
+	return contact, err
+}
+
+// getContactFollowUps 仪表板上 7 天内到期（含已逾期）的联系人跟进提醒
+func (s *JobApplicationService) getContactFollowUps(userID uint) ([]model.Contact, error) {
+	return listContactFollowUps(s.db, userID, defaultFollowUpWindowDays)
+}
+
+// listContactFollowUps 读取 days 天内到期（含已逾期）的跟进提醒，按跟进时间排序
+func listContactFollowUps(db *database.DB, userID uint, days int) ([]model.Contact, error) {
+	return queryContacts(db, "SELECT "+contactColumns+contactFrom+`
+		WHERE c.user_id = $1 AND c.follow_up_at IS NOT NULL AND c.follow_up_at <= NOW() + make_interval(days => $2)
+		ORDER BY c.follow_up_at, c.id`, userID, days)
+}
+
+// queryContacts 执行联系人查询
+func queryContacts(db *database.DB, query string, args ...interface{}) ([]model.Contact, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	defer rows.Close()
+
+	contacts := []model.Contact{}
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, *contact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate contacts: %w", err)
+	}
+	return contacts, nil
+}
+
+// scanContact 扫描 contactColumns
+func scanContact(row rowScanner) (*model.Contact, error) {
+	var contact model.Contact
+	err := row.Scan(&contact.ID, &contact.UserID, &contact.Name, &contact.Role, &contact.CompanyID, &contact.CompanyName,
+		&contact.Channels, &contact.Notes, &contact.LastContactedAt, &contact.FollowUpAt, &contact.FollowUpNote,
+		&contact.Source, &contact.CreatedAt, &contact.UpdatedAt, &contact.ApplicationCount)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan contact: %w", err)
+	}
+	if contact.Channels == nil {
+		contact.Channels = model.ContactChannels{}
+	}
+	contact.FollowUpDue = contact.FollowUpAt != nil && !contact.FollowUpAt.After(time.Now())
+	return &contact, nil
+}
+
+// checkApplicationOwned 校验申请存在且属于用户（不含回收站）
+func checkApplicationOwned(db *database.DB, userID uint, jobApplicationID int) error {
+	var exists bool
+	if err := db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)",
+		jobApplicationID, userID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check job application: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("job application not found or access denied")
+	}
+	return nil
+}
+
+// linkContactApplications 关联联系人与申请，申请须属于用户且不在回收站中；已存在的关联忽略
+func linkContactApplications(tx *sql.Tx, userID uint, contactID int, jobIDs []int) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(jobIDs))
+	args := []interface{}{userID}
+	for i, jobID := range jobIDs {
+		args = append(args, jobID)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	inClause := strings.Join(placeholders, ", ")
+
+	// 锁定申请，避免校验后被移入回收站
+	var owned int
+	if err := tx.QueryRow(fmt.Sprintf(`
+		SELECT COUNT(*) FROM (
+			SELECT id FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL AND id IN (%s) FOR SHARE
+		) t`, inClause),
+		args...).Scan(&owned); err != nil {
+		return fmt.Errorf("failed to check job applications: %w", err)
+	}
+	if owned != len(jobIDs) {
+		return fmt.Errorf("job application not found or access denied")
+	}
+
+	args = append(args, contactID)
+	if _, err := tx.Exec(fmt.Sprintf(`
+		INSERT INTO contact_applications (contact_id, job_application_id)
+		SELECT $%d, id FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL AND id IN (%s)
+		ON CONFLICT DO NOTHING
+	`, len(args), inClause), args...); err != nil {
+		return fmt.Errorf("failed to link contact applications: %w", err)
+	}
+	return nil
+}
+
+// resolveContactCompany 按公司名称解析公司：先按别名键、再按常见公司分组查找，
+// 都未命中时创建公司，规则与申请的自动关联一致
+func resolveContactCompany(tx *sql.Tx, userID uint, name string) (int, error) {
+	var id, priority int
+	err := tx.QueryRow(`
+		SELECT company_id, 0 FROM company_aliases WHERE user_id = $1 AND alias_key = company_name_key($2)
+		UNION ALL
+		SELECT a.company_id, 1
+		FROM company_alias_seeds s
+		JOIN company_alias_seeds g ON g.group_name = s.group_name
+		JOIN company_aliases a ON a.alias_key = g.alias_key AND a.user_id = $1
+		WHERE s.alias_key = company_name_key($2)
+		ORDER BY 2, 1
+		LIMIT 1
+	`, userID, name).Scan(&id, &priority)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to resolve company: %w", err)
+	}
+
+	if err := tx.QueryRow("INSERT INTO companies (user_id, name) VALUES ($1, $2) RETURNING id", userID, name).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to create company: %w", err)
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO company_aliases (company_id, user_id, alias, alias_key)
+		VALUES ($1, $2, $3, company_name_key($3))
+		ON CONFLICT (user_id, alias_key) DO NOTHING
+	`, id, userID, name); err != nil {
+		return 0, fmt.Errorf("failed to create company alias: %w", err)
+	}
+	return id, nil
+}
+
+// contactPlaceholder 参数占位符，JSONB 列附带类型转换
+func contactPlaceholder(column string, index int) string {
+	if column == "channels" {
+		return fmt.Sprintf("$%d::jsonb", index)
+	}
+	return fmt.Sprintf("$%d", index)
+}
+
+// normalizeContactFields 校验联系人字段，返回需要写入的列（nil 值表示清空）与公司名称（空串表示清空）
+func normalizeContactFields(req *model.UpdateContactRequest) (map[string]interface{}, *string, error) {
+	values := make(map[string]interface{})
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, nil, fmt.Errorf("invalid name: must not be empty")
+		}
+		if utf8.RuneCountInString(name) > maxContactNameLength {
+			return nil, nil, fmt.Errorf("invalid name: at most %d characters", maxContactNameLength)
+		}
+		values["name"] = name
+	}
+	if req.Role != nil {
+		if !req.Role.IsValid() {
+			return nil, nil, fmt.Errorf("invalid role: %s", *req.Role)
+		}
+		values["role"] = string(*req.Role)
+	}
+
+	var company *string
+	if req.Company != nil {
+		name := strings.TrimSpace(*req.Company)
+		if utf8.RuneCountInString(name) > maxCompanyNameLength {
+			return nil, nil, fmt.Errorf("invalid company: at most %d characters", maxCompanyNameLength)
+		}
+		company = &name
+	}
+
+	if req.Channels != nil {
+		if len(req.Channels) > maxContactChannels {
+			return nil, nil, fmt.Errorf("invalid channels: at most %d channels", maxContactChannels)
+		}
+		channels := model.ContactChannels{}
+		seen := make(map[string]bool)
+		for _, channel := range req.Channels {
+			normalized, err := model.NormalizeContactChannel(channel)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !seen[normalized.Key()] {
+				seen[normalized.Key()] = true
+				channels = append(channels, normalized)
+			}
+		}
+		values["channels"] = channels
+	}
+
+	if req.Notes != nil {
+		notes := strings.TrimSpace(*req.Notes)
+		if utf8.RuneCountInString(notes) > maxContactNotesLength {
+			return nil, nil, fmt.Errorf("invalid notes: at most %d characters", maxContactNotesLength)
+		}
+		values["notes"] = nil
+		if notes != "" {
+			values["notes"] = notes
+		}
+	}
+
+	if req.ClearFollowUp {
+		if req.FollowUpAt != nil {
+			return nil, nil, fmt.Errorf("invalid follow_up_at: cannot be set together with clear_follow_up")
+		}
+		values["follow_up_at"] = nil
+		values["follow_up_note"] = nil
+	}
+	if req.FollowUpAt != nil {
+		values["follow_up_at"] = *req.FollowUpAt
+	}
+	if req.FollowUpNote != nil && !req.ClearFollowUp {
+		note, err := normalizeFollowUpNote(req.FollowUpNote)
+		if err != nil {
+			return nil, nil, err
+		}
+		values["follow_up_note"] = note
+	}
+	return values, company, nil
+}
+
+// normalizeFollowUpNote 校验跟进备注，空白备注返回 nil
+func normalizeFollowUpNote(note *string) (interface{}, error) {
+	if note == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(trimmed) > maxFollowUpNoteLength {
+		return nil, fmt.Errorf("invalid follow_up_note: at most %d characters", maxFollowUpNoteLength)
+	}
+	return trimmed, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"jobView-backend/internal/model"
+)
+
+func TestNormalizeContactFields(t *testing.T) {
+	role := model.ContactRecruiter
+	req := &model.UpdateContactRequest{
+		Name:    stringPtr("  王女士 "),
+		Role:    &role,
+		Company: stringPtr(" 字节跳动 "),
+		Channels: []model.ContactChannel{
+			{Type: model.ChannelEmail, Value: "Wang@Example.com"},
+			{Type: model.ChannelEmail, Value: "wang@example.com "},
+			{Type: model.ChannelWeChat, Value: "wang_hr"},
+		},
+		Notes:        stringPtr("  "),
+		FollowUpNote: stringPtr(" 询问二面结果 "),
+	}
+	values, company, err := normalizeContactFields(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["name"] != "王女士" || values["role"] != "recruiter" {
+		t.Fatalf("unexpected name/role: %v %v", values["name"], values["role"])
+	}
+	if company == nil || *company != "字节跳动" {
+		t.Fatalf("unexpected company: %v", company)
+	}
+	if channels := values["channels"].(model.ContactChannels); len(channels) != 2 {
+		t.Fatalf("expected duplicate channels to be merged, got %+v", channels)
+	}
+	if notes, ok := values["notes"]; !ok || notes != nil {
+		t.Fatalf("expected blank notes to clear the column, got %v", notes)
+	}
+	if values["follow_up_note"] != "询问二面结果" {
+		t.Fatalf("unexpected follow_up_note: %v", values["follow_up_note"])
+	}
+}
+
+func TestNormalizeContactFieldsInvalid(t *testing.T) {
+	badRole := model.ContactRole("boss")
+	followUp := time.Now().Add(24 * time.Hour)
+	cases := map[string]*model.UpdateContactRequest{
+		"empty name":    {Name: stringPtr("  ")},
+		"long name":     {Name: stringPtr(strings.Repeat("王", maxContactNameLength+1))},
+		"bad role":      {Role: &badRole},
+		"bad channel":   {Channels: []model.ContactChannel{{Type: model.ChannelPhone, Value: "n/a"}}},
+		"long company":  {Company: stringPtr(strings.Repeat("a", maxCompanyNameLength+1))},
+		"clear and set": {FollowUpAt: &followUp, ClearFollowUp: true},
+	}
+	for name, req := range cases {
+		if _, _, err := normalizeContactFields(req); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("%s: expected invalid error, got %v", name, err)
+		}
+	}
+}
+
+func TestNormalizeContactFieldsClearFollowUp(t *testing.T) {
+	values, _, err := normalizeContactFields(&model.UpdateContactRequest{ClearFollowUp: true, FollowUpNote: stringPtr("ignored")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := values["follow_up_at"]; !ok || v != nil {
+		t.Fatalf("expected follow_up_at to be cleared, got %v", v)
+	}
+	if v, ok := values["follow_up_note"]; !ok || v != nil {
+		t.Fatalf("expected follow_up_note to be cleared, got %v", v)
+	}
+}
@@ -0,0 +1,433 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/custom_field_service.go
+// This file implements per-user custom field definitions and the validation of custom field
+// values on job applications, plus the SQL helpers used to filter, sort and export by them.
+
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxCustomFieldsPerUser   = 30
+	maxCustomFieldLabel      = 100
+	maxCustomFieldTextLength = 500
+	maxCustomFieldURLLength  = 2000
+	maxCustomFieldOptions    = 50
+	maxCustomFieldOptionLen  = 50
+)
+
+// customFieldKeyPattern 字段键只允许小写字母、数字和下划线，会直接拼接进 JSONB 路径表达式
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type CustomFieldService struct {
+	db *database.DB
+}
+
+func NewCustomFieldService(db *database.DB) *CustomFieldService {
+	return &CustomFieldService{db: db}
+}
+
+// List 获取用户的自定义字段定义
+func (s *CustomFieldService) List(userID uint) ([]model.CustomFieldDefinition, error) {
+	return loadCustomFieldDefinitions(s.db, userID)
+}
+
+// Create 创建自定义字段定义
+func (s *CustomFieldService) Create(userID uint, req *model.CreateCustomFieldRequest) (*model.CustomFieldDefinition, error) {
+	if !customFieldKeyPattern.MatchString(req.Key) {
+		return nil, fmt.Errorf("invalid key: must start with a lowercase letter and contain only a-z, 0-9 and _ (max 50)")
+	}
+	if !req.FieldType.IsValid() {
+		return nil, fmt.Errorf("invalid field_type: %s", req.FieldType)
+	}
+	label, err := normalizeCustomFieldLabel(req.Label)
+	if err != nil {
+		return nil, err
+	}
+	options, err := normalizeCustomFieldOptions(req.FieldType, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM custom_field_definitions WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count custom fields: %w", err)
+	}
+	if count >= maxCustomFieldsPerUser {
+		return nil, fmt.Errorf("invalid request: at most %d custom fields allowed", maxCustomFieldsPerUser)
+	}
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM custom_field_definitions WHERE user_id = $1 AND field_key = $2)",
+		userID, req.Key).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check custom field key uniqueness: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("custom field '%s' already exists", req.Key)
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+	def := &model.CustomFieldDefinition{
+		UserID:    userID,
+		Key:       req.Key,
+		Label:     label,
+		FieldType: req.FieldType,
+		Options:   options,
+		Required:  req.Required,
+		SortOrder: req.SortOrder,
+	}
+	err = s.db.QueryRow(`
+		INSERT INTO custom_field_definitions (user_id, field_key, label, field_type, options, required, sort_order)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7)
+		RETURNING id, created_at, updated_at
+	`, userID, req.Key, label, req.FieldType, string(optionsJSON), req.Required, req.SortOrder).Scan(&def.ID, &def.CreatedAt, &def.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create custom field: %w", err)
+	}
+	return def, nil
+}
+
+// Update 修改字段名称、可选值、必填和排序；键和类型不可修改
+func (s *CustomFieldService) Update(userID uint, id int, req *model.UpdateCustomFieldRequest) (*model.CustomFieldDefinition, error) {
+	def, err := s.get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		if def.Label, err = normalizeCustomFieldLabel(*req.Label); err != nil {
+			return nil, err
+		}
+	}
+	if req.Options != nil {
+		if def.Options, err = normalizeCustomFieldOptions(def.FieldType, req.Options); err != nil {
+			return nil, err
+		}
+	}
+	if req.Required != nil {
+		def.Required = *req.Required
+	}
+	if req.SortOrder != nil {
+		def.SortOrder = *req.SortOrder
+	}
+
+	optionsJSON, err := json.Marshal(def.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+	if err := s.db.QueryRow(`
+		UPDATE custom_field_definitions
+		SET label = $1, options = $2::jsonb, required = $3, sort_order = $4, updated_at = NOW()
+		WHERE id = $5 AND user_id = $6
+		RETURNING updated_at
+	`, def.Label, string(optionsJSON), def.Required, def.SortOrder, id, userID).Scan(&def.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to update custom field: %w", err)
+	}
+	return def, nil
+}
+
+// Delete 删除字段定义，并移除所有申请上该字段的值
+func (s *CustomFieldService) Delete(userID uint, id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var key string
+	err = tx.QueryRow("DELETE FROM custom_field_definitions WHERE id = $1 AND user_id = $2 RETURNING field_key",
+		id, userID).Scan(&key)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("custom field not found or access denied")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+
+	if _, err := tx.Exec(auditBatchSetting); err != nil {
+		return fmt.Errorf("failed to mark batch operation: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE job_applications SET custom_fields = custom_fields - $1 WHERE user_id = $2 AND custom_fields ? $1",
+		key, userID); err != nil {
+		return fmt.Errorf("failed to remove custom field values: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *CustomFieldService) get(userID uint, id int) (*model.CustomFieldDefinition, error) {
+	defs, err := loadCustomFieldDefinitions(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range defs {
+		if defs[i].ID == id {
+			return &defs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("custom field not found or access denied")
+}
+
+// loadCustomFieldDefinitions 读取用户的字段定义，按 sort_order 排序
+func loadCustomFieldDefinitions(db *database.DB, userID uint) ([]model.CustomFieldDefinition, error) {
+	rows, err := db.Query(`
+		SELECT id, user_id, field_key, label, field_type, options, required, sort_order, created_at, updated_at
+		FROM custom_field_definitions
+		WHERE user_id = $1
+		ORDER BY sort_order, id
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	defs := []model.CustomFieldDefinition{}
+	for rows.Next() {
+		var def model.CustomFieldDefinition
+		var options []byte
+		if err := rows.Scan(&def.ID, &def.UserID, &def.Key, &def.Label, &def.FieldType, &options,
+			&def.Required, &def.SortOrder, &def.CreatedAt, &def.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		if len(options) > 0 {
+			if err := json.Unmarshal(options, &def.Options); err != nil {
+				return nil, fmt.Errorf("failed to decode custom field options: %w", err)
+			}
+		}
+		defs = append(defs, def)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate custom fields: %w", err)
+	}
+	return defs, nil
+}
+
+// findCustomField 按键查找字段定义
+func findCustomField(defs []model.CustomFieldDefinition, key string) (*model.CustomFieldDefinition, bool) {
+	for i := range defs {
+		if defs[i].Key == key {
+			return &defs[i], true
+		}
+	}
+	return nil, false
+}
+
+func normalizeCustomFieldLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return "", fmt.Errorf("invalid label: label is required")
+	}
+	if utf8.RuneCountInString(label) > maxCustomFieldLabel {
+		return "", fmt.Errorf("invalid label: at most %d characters", maxCustomFieldLabel)
+	}
+	return label, nil
+}
+
+// normalizeCustomFieldOptions enum 类型必须提供不重复的可选值，其他类型不允许设置
+func normalizeCustomFieldOptions(fieldType model.CustomFieldType, options []string) ([]string, error) {
+	if fieldType != model.CustomFieldEnum {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("invalid options: only enum fields have options")
+		}
+		return nil, nil
+	}
+	if len(options) == 0 || len(options) > maxCustomFieldOptions {
+		return nil, fmt.Errorf("invalid options: enum fields need 1 to %d options", maxCustomFieldOptions)
+	}
+	seen := make(map[string]bool, len(options))
+	normalized := make([]string, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || utf8.RuneCountInString(option) > maxCustomFieldOptionLen {
+			return nil, fmt.Errorf("invalid options: each option must be 1 to %d characters", maxCustomFieldOptionLen)
+		}
+		if seen[option] {
+			return nil, fmt.Errorf("invalid options: duplicate option %q", option)
+		}
+		seen[option] = true
+		normalized = append(normalized, option)
+	}
+	return normalized, nil
+}
+
+// validateCustomFieldValues 按字段定义校验并规范化取值。
+// partial 为 true 时（更新）不检查缺失的必填字段，值为 null 表示删除该字段；创建时 null 等同于未填写。
+func validateCustomFieldValues(defs []model.CustomFieldDefinition, values model.CustomFieldValues, partial bool) (model.CustomFieldValues, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	normalized := make(model.CustomFieldValues, len(values))
+	for _, key := range keys {
+		def, ok := findCustomField(defs, key)
+		if !ok {
+			return nil, fmt.Errorf("invalid custom field: unknown field %q", key)
+		}
+		value, err := normalizeCustomFieldValue(def, values[key])
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			if def.Required {
+				return nil, fmt.Errorf("invalid custom field %s: value is required", key)
+			}
+			if partial {
+				normalized[key] = nil
+			}
+			continue
+		}
+		normalized[key] = value
+	}
+
+	if !partial {
+		for _, def := range defs {
+			if _, ok := normalized[def.Key]; def.Required && !ok {
+				return nil, fmt.Errorf("invalid custom field %s: value is required", def.Key)
+			}
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeCustomFieldValue 校验单个值，空字符串视为未填写（返回 nil）
+func normalizeCustomFieldValue(def *model.CustomFieldDefinition, raw interface{}) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if str, ok := raw.(string); ok && strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+
+	switch def.FieldType {
+	case model.CustomFieldNumber:
+		switch v := raw.(type) {
+		case float64:
+			return v, nil
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid custom field %s: must be a number", def.Key)
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid custom field %s: must be a string", def.Key)
+	}
+	str = strings.TrimSpace(str)
+
+	switch def.FieldType {
+	case model.CustomFieldText:
+		if utf8.RuneCountInString(str) > maxCustomFieldTextLength {
+			return nil, fmt.Errorf("invalid custom field %s: at most %d characters", def.Key, maxCustomFieldTextLength)
+		}
+	case model.CustomFieldDate:
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			return nil, fmt.Errorf("invalid custom field %s: date must be YYYY-MM-DD", def.Key)
+		}
+	case model.CustomFieldEnum:
+		for _, option := range def.Options {
+			if option == str {
+				return str, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid custom field %s: %q is not one of %v", def.Key, str, def.Options)
+	case model.CustomFieldURL:
+		u, err := url.Parse(str)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(str) > maxCustomFieldURLLength {
+			return nil, fmt.Errorf("invalid custom field %s: must be an http(s) URL", def.Key)
+		}
+	}
+	return str, nil
+}
+
+// customFieldExpr 字段在 SQL 中的取值表达式，数字和日期转换为可比较的类型
+func customFieldExpr(def *model.CustomFieldDefinition) string {
+	expr := fmt.Sprintf("(custom_fields->>'%s')", def.Key)
+	switch def.FieldType {
+	case model.CustomFieldNumber:
+		return expr + "::numeric"
+	case model.CustomFieldDate:
+		return expr + "::date"
+	}
+	return expr
+}
+
+// customFieldSortExpression ORDER BY 表达式，未填写的排在最后
+func customFieldSortExpression(def *model.CustomFieldDefinition, sortDir string) string {
+	return customFieldExpr(def) + " " + sortDir + " NULLS LAST"
+}
+
+// customFieldCondition 构建单个字段的筛选条件：
+// text/url 为包含匹配，enum 为精确匹配，number/date 支持精确值或 "from..to" 区间（任一端可省略）
+func customFieldCondition(def *model.CustomFieldDefinition, raw string, argIndex int) (string, []interface{}, int, error) {
+	raw = strings.TrimSpace(raw)
+	expr := customFieldExpr(def)
+
+	switch def.FieldType {
+	case model.CustomFieldText, model.CustomFieldURL:
+		return fmt.Sprintf(" AND %s ILIKE $%d", expr, argIndex), []interface{}{"%" + raw + "%"}, argIndex + 1, nil
+	case model.CustomFieldEnum:
+		return fmt.Sprintf(" AND %s = $%d", expr, argIndex), []interface{}{raw}, argIndex + 1, nil
+	}
+
+	parse := func(value string) (interface{}, error) {
+		if def.FieldType == model.CustomFieldNumber {
+			return strconv.ParseFloat(value, 64)
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	from, to, isRange := strings.Cut(raw, "..")
+	if !isRange {
+		value, err := parse(raw)
+		if err != nil {
+			return "", nil, argIndex, fmt.Errorf("invalid custom field filter %s: %q", def.Key, raw)
+		}
+		return fmt.Sprintf(" AND %s = $%d", expr, argIndex), []interface{}{value}, argIndex + 1, nil
+	}
+
+	condition := ""
+	var args []interface{}
+	for _, bound := range []struct {
+		value, op string
+	}{{strings.TrimSpace(from), ">="}, {strings.TrimSpace(to), "<="}} {
+		if bound.value == "" {
+			continue
+		}
+		value, err := parse(bound.value)
+		if err != nil {
+			return "", nil, argIndex, fmt.Errorf("invalid custom field filter %s: %q", def.Key, raw)
+		}
+		condition += fmt.Sprintf(" AND %s %s $%d", expr, bound.op, argIndex)
+		args = append(args, value)
+		argIndex++
+	}
+	if condition == "" {
+		return "", nil, argIndex, fmt.Errorf("invalid custom field filter %s: %q", def.Key, raw)
+	}
+	return condition, args, argIndex, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func testCustomFieldDefs() []model.CustomFieldDefinition {
+	return []model.CustomFieldDefinition{
+		{Key: "team", FieldType: model.CustomFieldText},
+		{Key: "base", FieldType: model.CustomFieldNumber, Required: true},
+		{Key: "deadline", FieldType: model.CustomFieldDate},
+		{Key: "level", FieldType: model.CustomFieldEnum, Options: []string{"P6", "P7"}},
+		{Key: "job_link", FieldType: model.CustomFieldURL},
+	}
+}
+
+func TestValidateCustomFieldValuesOnCreate(t *testing.T) {
+	defs := testCustomFieldDefs()
+
+	values, err := validateCustomFieldValues(defs, model.CustomFieldValues{
+		"team":     " 基础架构 ",
+		"base":     "35000",
+		"deadline": "2026-11-01",
+		"level":    "P7",
+		"job_link": "https://example.com/jobs/1",
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["team"] != "基础架构" || values["base"] != float64(35000) || values["level"] != "P7" {
+		t.Fatalf("unexpected normalized values: %v", values)
+	}
+
+	// 空字符串和 null 视为未填写
+	values, err = validateCustomFieldValues(defs, model.CustomFieldValues{"base": 1.5, "team": "", "level": nil}, false)
+	if err != nil || len(values) != 1 {
+		t.Fatalf("expected only base to be kept, got %v %v", values, err)
+	}
+
+	invalid := []model.CustomFieldValues{
+		{"team": "x"}, // 缺少必填的 base
+		{"base": 1, "unknown": "x"},
+		{"base": "abc"},
+		{"base": 1, "deadline": "2026/11/01"},
+		{"base": 1, "level": "P8"},
+		{"base": 1, "job_link": "ftp://example.com"},
+		{"base": 1, "job_link": "not a url"},
+		{"base": 1, "team": 42},
+		{"base": 1, "team": strings.Repeat("a", maxCustomFieldTextLength+1)},
+	}
+	for _, v := range invalid {
+		if _, err := validateCustomFieldValues(defs, v, false); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("%v: expected invalid error, got %v", v, err)
+		}
+	}
+}
+
+func TestValidateCustomFieldValuesOnUpdate(t *testing.T) {
+	defs := testCustomFieldDefs()
+
+	// 更新时不要求必填字段，null 保留用于删除
+	values, err := validateCustomFieldValues(defs, model.CustomFieldValues{"team": nil, "level": "P6"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := values["team"]; !ok || v != nil || values["level"] != "P6" {
+		t.Fatalf("unexpected values: %v", values)
+	}
+	if _, err := validateCustomFieldValues(defs, model.CustomFieldValues{"base": nil}, true); err == nil {
+		t.Fatalf("clearing a required field should be rejected")
+	}
+
+	setParts, args, err := updateSetClauses(&model.UpdateJobApplicationRequest{CustomFields: values})
+	if err != nil || len(setParts) != 1 || len(args) != 1 {
+		t.Fatalf("unexpected set clauses %v %v %v", setParts, args, err)
+	}
+	if !strings.HasPrefix(setParts[0], "custom_fields = jsonb_strip_nulls(custom_fields || $1::jsonb)") {
+		t.Fatalf("unexpected custom fields clause %q", setParts[0])
+	}
+}
+
+func TestCustomFieldOptions(t *testing.T) {
+	if options, err := normalizeCustomFieldOptions(model.CustomFieldEnum, []string{" A ", "B"}); err != nil || options[0] != "A" {
+		t.Fatalf("unexpected options %v %v", options, err)
+	}
+	for _, options := range [][]string{nil, {"A", "A"}, {""}} {
+		if _, err := normalizeCustomFieldOptions(model.CustomFieldEnum, options); err == nil {
+			t.Fatalf("options %v should be rejected for enum", options)
+		}
+	}
+	if _, err := normalizeCustomFieldOptions(model.CustomFieldText, []string{"A"}); err == nil {
+		t.Fatalf("options should be rejected for text fields")
+	}
+
+	for _, key := range []string{"team_name", "a", "bonus2"} {
+		if !customFieldKeyPattern.MatchString(key) {
+			t.Fatalf("key %q should be valid", key)
+		}
+	}
+	for _, key := range []string{"", "Team", "1st", "team-name", "a'b", strings.Repeat("a", 51)} {
+		if customFieldKeyPattern.MatchString(key) {
+			t.Fatalf("key %q should be invalid", key)
+		}
+	}
+}
+
+func TestCustomFieldConditionAndSort(t *testing.T) {
+	defs := testCustomFieldDefs()
+	base, _ := findCustomField(defs, "base")
+	deadline, _ := findCustomField(defs, "deadline")
+	team, _ := findCustomField(defs, "team")
+	level, _ := findCustomField(defs, "level")
+
+	cond, args, next, err := customFieldCondition(base, "30000..40000", 3)
+	if err != nil || next != 5 || len(args) != 2 {
+		t.Fatalf("unexpected range result %q %v %d %v", cond, args, next, err)
+	}
+	if cond != " AND (custom_fields->>'base')::numeric >= $3 AND (custom_fields->>'base')::numeric <= $4" {
+		t.Fatalf("unexpected range condition %q", cond)
+	}
+
+	cond, args, next, err = customFieldCondition(deadline, "..2026-12-31", 2)
+	if err != nil || next != 3 || cond != " AND (custom_fields->>'deadline')::date <= $2" || args[0] != "2026-12-31" {
+		t.Fatalf("unexpected open range %q %v %d %v", cond, args, next, err)
+	}
+
+	cond, args, _, _ = customFieldCondition(team, "infra", 2)
+	if cond != " AND (custom_fields->>'team') ILIKE $2" || args[0] != "%infra%" {
+		t.Fatalf("unexpected text condition %q %v", cond, args)
+	}
+	cond, _, _, _ = customFieldCondition(level, "P7", 2)
+	if cond != " AND (custom_fields->>'level') = $2" {
+		t.Fatalf("unexpected enum condition %q", cond)
+	}
+
+	for _, raw := range []string{"abc", "..", "1..x"} {
+		if _, _, _, err := customFieldCondition(base, raw, 1); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("filter %q: expected invalid error, got %v", raw, err)
+		}
+	}
+
+	if expr := customFieldSortExpression(base, "DESC"); expr != "(custom_fields->>'base')::numeric DESC NULLS LAST" {
+		t.Fatalf("unexpected sort expression %q", expr)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"jobView-backend/internal/database"
 	"jobView-backend/internal/excel"
 	"jobView-backend/internal/model"
+	"jobView-backend/internal/salary"
+	"jobView-backend/internal/taxonomy"
 	"os"
 	"path/filepath"
 	"strings"
@@ -52,6 +54,19 @@ func NewExportService(db *database.DB, jobApplicationService *JobApplicationServ
 
 // StartExport 开始导出任务
 func (s *ExportService) StartExport(userID uint, request *model.ExportRequest) (*model.ExportResponse, error) {
+	// 使用保存的视图作为筛选条件
+	if request.ViewID != nil {
+		view, err := loadSavedView(s.db, userID, *request.ViewID)
+		if err != nil {
+			return nil, err
+		}
+		filters, err := savedViewExportFilters(&view.Filters)
+		if err != nil {
+			return nil, err
+		}
+		request.Filters = *filters
+	}
+
 	// 验证导出请求
 	if err := request.ValidateExportRequest(); err != nil {
 		return nil, fmt.Errorf("导出请求验证失败: %v", err)
@@ -138,7 +153,13 @@ func (s *ExportService) processSyncExport(task *model.ExportTask, request *model
 	}
 
 	// 生成文件
-	filePath, fileSize, err := s.generateExcelFile(task.TaskID, applications, &request.Options)
+	customColumns, err := s.customExportColumns(task.UserID, request.Fields)
+	if err != nil {
+		s.handleExportError(task, err.Error())
+		return nil, err
+	}
+
+	filePath, fileSize, err := s.generateExcelFile(task.TaskID, applications, customColumns, &request.Options)
 	if err != nil {
 		task.Status = model.TaskStatusFailed
 		errorMsg := fmt.Sprintf("生成Excel文件失败: %v", err)
@@ -161,6 +182,7 @@ func (s *ExportService) processSyncExport(task *model.ExportTask, request *model
 	task.Filename = &filename
 
 	s.updateExportTask(task)
+	s.recordExportActivity(task, applications)
 
 	// 生成下载URL
 	downloadURL := fmt.Sprintf("/api/v1/export/download/%s", task.TaskID)
@@ -187,9 +209,16 @@ func (s *ExportService) processAsyncExport(task *model.ExportTask, request *mode
 	batchSize := 1000
 	totalRecords := *task.TotalRecords
 	
+	customColumns, err := s.customExportColumns(task.UserID, request.Fields)
+	if err != nil {
+		s.handleExportError(task, err.Error())
+		return
+	}
+
 	// 创建临时文件
 	generator := excel.NewGenerator()
 	defer generator.Close()
+	generator.SetCustomColumns(customColumns)
 
 	if err := generator.InitializeWorkbook(); err != nil {
 		s.handleExportError(task, fmt.Sprintf("初始化Excel工作簿失败: %v", err))
@@ -265,6 +294,22 @@ func (s *ExportService) processAsyncExport(task *model.ExportTask, request *mode
 	task.Filename = &filename
 
 	s.updateExportTask(task)
+	s.recordExportActivity(task, allApplications)
+}
+
+// recordExportActivity 在导出的每条申请的活动时间线中记录本次导出
+func (s *ExportService) recordExportActivity(task *model.ExportTask, applications []model.JobApplication) {
+	ids := make([]int, 0, len(applications))
+	for _, app := range applications {
+		ids = append(ids, app.ID)
+	}
+	payload := map[string]interface{}{"task_id": task.TaskID, "export_type": task.ExportType}
+	if task.Filename != nil {
+		payload["filename"] = *task.Filename
+	}
+	if err := recordActivity(s.db, task.UserID, ids, model.ActivityExport, "包含在导出文件中", payload); err != nil {
+		fmt.Printf("记录导出活动失败: %v\n", err)
+	}
 }
 
 // GetTaskStatus 获取任务状态
@@ -564,10 +609,12 @@ func (s *ExportService) checkUserExportLimits(userID uint) error {
 
 // getExportDataCount 获取导出数据总数
 func (s *ExportService) getExportDataCount(userID uint, filters *model.ExportFilters) (int, error) {
-    query, args := s.buildCountQuery(userID, filters)
+    query, args, err := s.buildCountQuery(userID, filters)
+    if err != nil {
+        return 0, err
+    }
     
     var count int
-    var err error
     if s.db != nil && s.db.UseGorm && s.db.ORM != nil {
         err = s.db.ORM.Raw(query, args...).Row().Scan(&count)
     } else {
@@ -582,10 +629,12 @@ func (s *ExportService) getExportDataCount(userID uint, filters *model.ExportFil
 
 // getExportData 获取导出数据
 func (s *ExportService) getExportData(userID uint, filters *model.ExportFilters, offset, limit int) ([]model.JobApplication, error) {
-    query, args := s.buildDataQuery(userID, filters, offset, limit)
+    query, args, err := s.buildDataQuery(userID, filters, offset, limit)
+    if err != nil {
+        return nil, err
+    }
     
     var rows *sql.Rows
-    var err error
     if s.db != nil && s.db.UseGorm && s.db.ORM != nil {
         rows, err = s.db.ORM.Raw(query, args...).Rows()
     } else {
@@ -622,6 +671,16 @@ func (s *ExportService) getExportData(userID uint, filters *model.ExportFilters,
 			&app.InterviewType,
 			&app.CreatedAt,
 			&app.UpdatedAt,
+			&app.CustomFields,
+			&app.RoleFamily,
+			&app.RoleLevel,
+			&app.TechStack,
+			&app.SalaryMinMonthly,
+			&app.SalaryMaxMonthly,
+			&app.SalaryMonths,
+			&app.SalaryAnnualMin,
+			&app.SalaryAnnualMax,
+			&app.SalaryCurrency,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("扫描数据失败: %v", err)
@@ -629,12 +688,16 @@ func (s *ExportService) getExportData(userID uint, filters *model.ExportFilters,
 		applications = append(applications, app)
 	}
 
+	if err := attachApplicationTags(s.db, userID, applications); err != nil {
+		return nil, fmt.Errorf("查询标签失败: %v", err)
+	}
+
 	return applications, nil
 }
 
 // buildCountQuery 构建计数查询
-func (s *ExportService) buildCountQuery(userID uint, filters *model.ExportFilters) (string, []interface{}) {
-	query := "SELECT COUNT(*) FROM job_applications WHERE user_id = $1"
+func (s *ExportService) buildCountQuery(userID uint, filters *model.ExportFilters) (string, []interface{}, error) {
+	query := "SELECT COUNT(*) FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{userID}
 	argIndex := 2
 
@@ -656,13 +719,10 @@ func (s *ExportService) buildCountQuery(userID uint, filters *model.ExportFilter
 	}
 
 	if len(filters.CompanyNames) > 0 {
-		companyPlaceholders := make([]string, len(filters.CompanyNames))
-		for i, company := range filters.CompanyNames {
-			companyPlaceholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, company)
-			argIndex++
-		}
-		query += " AND company_name IN (" + strings.Join(companyPlaceholders, ",") + ")"
+		companyCondition, companyArgs, nextIndex := companyNamesCondition("", filters.CompanyNames, argIndex)
+		query += " AND " + companyCondition
+		args = append(args, companyArgs...)
+		argIndex = nextIndex
 	}
 
 	if filters.Keywords != "" {
@@ -670,21 +730,55 @@ func (s *ExportService) buildCountQuery(userID uint, filters *model.ExportFilter
 							 argIndex, argIndex, argIndex)
 		keyword := "%" + filters.Keywords + "%"
 		args = append(args, keyword)
+		argIndex++
+	}
+
+	tagCondition, tagArgs, argIndex := tagFilterCondition(filters.TagIDs, argIndex)
+	query += tagCondition
+	args = append(args, tagArgs...)
+
+	salaryCondition, salaryArgs, argIndex, err := salaryRangeCondition(filters.SalaryMin, filters.SalaryMax, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	query += salaryCondition
+	args = append(args, salaryArgs...)
+
+	customCondition, customArgs, argIndex, err := s.customFilterCondition(userID, filters, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	query += customCondition
+	args = append(args, customArgs...)
+
+	queryCondition, queryArgs, argIndex, err := queryFilterCondition(filters.Query, argIndex)
+	if err != nil {
+		return "", nil, err
 	}
+	query += queryCondition
+	args = append(args, queryArgs...)
 
-	return query, args
+	searchCondition, searchArgs, _, err := s.searchFilterCondition(filters.Search, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	query += searchCondition
+	args = append(args, searchArgs...)
+
+	return query, args, nil
 }
 
 // buildDataQuery 构建数据查询
-func (s *ExportService) buildDataQuery(userID uint, filters *model.ExportFilters, offset, limit int) (string, []interface{}) {
+func (s *ExportService) buildDataQuery(userID uint, filters *model.ExportFilters, offset, limit int) (string, []interface{}, error) {
 	query := `
 		SELECT id, user_id, company_name, position_title, application_date, status,
 			   job_description, salary_range, work_location, contact_info, notes,
 			   interview_time, reminder_time, reminder_enabled, follow_up_date,
 			   hr_name, hr_phone, hr_email, interview_location, interview_type,
-			   created_at, updated_at
+			   created_at, updated_at, custom_fields, role_family, role_level, tech_stack,
+			   salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency
 		FROM job_applications 
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 	args := []interface{}{userID}
 	argIndex := 2
@@ -707,13 +801,10 @@ func (s *ExportService) buildDataQuery(userID uint, filters *model.ExportFilters
 	}
 
 	if len(filters.CompanyNames) > 0 {
-		companyPlaceholders := make([]string, len(filters.CompanyNames))
-		for i, company := range filters.CompanyNames {
-			companyPlaceholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, company)
-			argIndex++
-		}
-		query += " AND company_name IN (" + strings.Join(companyPlaceholders, ",") + ")"
+		companyCondition, companyArgs, nextIndex := companyNamesCondition("", filters.CompanyNames, argIndex)
+		query += " AND " + companyCondition
+		args = append(args, companyArgs...)
+		argIndex = nextIndex
 	}
 
 	if filters.Keywords != "" {
@@ -721,7 +812,40 @@ func (s *ExportService) buildDataQuery(userID uint, filters *model.ExportFilters
 							 argIndex, argIndex, argIndex)
 		keyword := "%" + filters.Keywords + "%"
 		args = append(args, keyword)
+		argIndex++
+	}
+
+	tagCondition, tagArgs, argIndex := tagFilterCondition(filters.TagIDs, argIndex)
+	query += tagCondition
+	args = append(args, tagArgs...)
+
+	salaryCondition, salaryArgs, argIndex, err := salaryRangeCondition(filters.SalaryMin, filters.SalaryMax, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	query += salaryCondition
+	args = append(args, salaryArgs...)
+
+	customCondition, customArgs, argIndex, err := s.customFilterCondition(userID, filters, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	query += customCondition
+	args = append(args, customArgs...)
+
+	queryCondition, queryArgs, argIndex, err := queryFilterCondition(filters.Query, argIndex)
+	if err != nil {
+		return "", nil, err
 	}
+	query += queryCondition
+	args = append(args, queryArgs...)
+
+	searchCondition, searchArgs, argIndex, err := s.searchFilterCondition(filters.Search, argIndex)
+	if err != nil {
+		return "", nil, err
+	}
+	query += searchCondition
+	args = append(args, searchArgs...)
 
 	// 添加排序和分页
 	query += " ORDER BY application_date DESC, created_at DESC"
@@ -730,13 +854,33 @@ func (s *ExportService) buildDataQuery(userID uint, filters *model.ExportFilters
 		args = append(args, limit, offset)
 	}
 
-	return query, args
+	return query, args, nil
+}
+
+// customFilterCondition 构建自定义字段筛选条件，规则与列表筛选一致
+func (s *ExportService) customFilterCondition(userID uint, filters *model.ExportFilters, argIndex int) (string, []interface{}, int, error) {
+	if len(filters.CustomFilters) == 0 {
+		return "", nil, argIndex, nil
+	}
+	condition, args, next, _, err := s.jobApplicationService.customFieldListFilters(userID,
+		&model.PaginationRequest{CustomFilters: filters.CustomFilters}, argIndex)
+	return condition, args, next, err
+}
+
+// searchFilterCondition 搜索视图的关键词条件，匹配规则与全文搜索一致
+func (s *ExportService) searchFilterCondition(search string, argIndex int) (string, []interface{}, int, error) {
+	if search == "" {
+		return "", nil, argIndex, nil
+	}
+	condition, _, args, next, err := s.jobApplicationService.searchFilterCondition(search, argIndex)
+	return condition, args, next, err
 }
 
 // generateExcelFile 生成Excel文件
-func (s *ExportService) generateExcelFile(taskID string, applications []model.JobApplication, options *model.ExportOptions) (string, int64, error) {
+func (s *ExportService) generateExcelFile(taskID string, applications []model.JobApplication, customColumns []model.CustomFieldDefinition, options *model.ExportOptions) (string, int64, error) {
 	generator := excel.NewGenerator()
 	defer generator.Close()
+	generator.SetCustomColumns(customColumns)
 
 	if err := generator.InitializeWorkbook(); err != nil {
 		return "", 0, fmt.Errorf("初始化工作簿失败: %v", err)
@@ -770,16 +914,72 @@ func (s *ExportService) generateExcelFile(taskID string, applications []model.Jo
 	return filePath, fileInfo.Size(), nil
 }
 
+// GetCustomExportFields 获取用户可导出的自定义字段
+func (s *ExportService) GetCustomExportFields(userID uint) ([]model.CustomFieldDefinition, error) {
+	return loadCustomFieldDefinitions(s.db, userID)
+}
+
+// customExportColumns 按导出字段中 cf.<key> 的顺序选出自定义字段列，未知字段忽略
+func (s *ExportService) customExportColumns(userID uint, fields []string) ([]model.CustomFieldDefinition, error) {
+	var keys []string
+	for _, field := range fields {
+		if key, ok := strings.CutPrefix(field, model.CustomFieldPrefix); ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	defs, err := loadCustomFieldDefinitions(s.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询自定义字段失败: %v", err)
+	}
+	var columns []model.CustomFieldDefinition
+	for _, key := range keys {
+		if def, ok := findCustomField(defs, key); ok {
+			columns = append(columns, *def)
+		}
+	}
+	return columns, nil
+}
+
 // generateStatistics 生成统计信息
 func (s *ExportService) generateStatistics(applications []model.JobApplication) map[string]interface{} {
 	stats := make(map[string]interface{})
 	statusDistribution := make(map[string]int)
+	roleDistribution := make(map[string]int)
+	levelDistribution := make(map[string]int)
+	salaryDistribution := make(map[string]int)
+	rules := taxonomy.Active()
 
 	for _, app := range applications {
 		statusDistribution[string(app.Status)]++
+		roleName, levelName := model.UnclassifiedRoleName, model.UnclassifiedRoleName
+		if app.RoleFamily != nil {
+			roleName = rules.FamilyName(*app.RoleFamily)
+		}
+		if app.RoleLevel != nil {
+			levelName = rules.LevelName(*app.RoleLevel)
+		}
+		roleDistribution[roleName]++
+		levelDistribution[levelName]++
+
+		// 月薪按区间中位值分档，外币档位前加币种；未填写或无法解析的归入“未识别”
+		salaryBucket := "未识别"
+		if app.SalaryMinMonthly != nil && app.SalaryMaxMonthly != nil {
+			_, salaryBucket = salary.MonthlyBucket((*app.SalaryMinMonthly + *app.SalaryMaxMonthly) / 2)
+			if app.SalaryCurrency != nil && *app.SalaryCurrency != salary.DefaultCurrency {
+				salaryBucket = *app.SalaryCurrency + " " + salaryBucket
+			}
+		}
+		salaryDistribution[salaryBucket]++
 	}
 
 	stats["statusDistribution"] = statusDistribution
+	stats["roleDistribution"] = roleDistribution
+	stats["levelDistribution"] = levelDistribution
+	stats["salaryDistribution"] = salaryDistribution
 	stats["totalCount"] = len(applications)
 
 	return stats
@@ -0,0 +1,120 @@
+// This file implements typeahead suggestions for company, position and location: the user's
+// existing values are grouped by a normalized key (case and whitespace insensitive), ranked by
+// match quality (prefix > word prefix > substring > trigram similarity) weighted by how often
+// each group is used, and returned with the most common spelling as the canonical form.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/utils"
+)
+
+const (
+	defaultAutocompleteLimit  = 10
+	maxAutocompleteLimit      = 50
+	maxAutocompleteQuery      = 100
+	autocompleteMinSimilarity = 0.3 // 模糊候选的最低 similarity
+)
+
+// Autocomplete 根据用户已有的公司/职位/地点给出联想候选，q 为空时按使用频率返回
+func (s *JobApplicationService) Autocomplete(userID uint, field model.AutocompleteField, q string, limit int) ([]model.AutocompleteSuggestion, error) {
+	column := field.Column()
+	if column == "" {
+		return nil, fmt.Errorf("invalid field: %s (expected company, position or location)", field)
+	}
+	q = normalizeAutocompleteValue(q)
+	if len([]rune(q)) > maxAutocompleteQuery {
+		return nil, fmt.Errorf("invalid query: at most %d characters", maxAutocompleteQuery)
+	}
+	if limit <= 0 {
+		limit = defaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+
+	query, args := autocompleteQuery(userID, column, q, s.fuzzySearchEnabled(), limit)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query autocomplete suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []model.AutocompleteSuggestion{}
+	for rows.Next() {
+		var suggestion model.AutocompleteSuggestion
+		var spellings []byte
+		if err := rows.Scan(&spellings, &suggestion.Count, &suggestion.LastUsedAt, &suggestion.MatchType, &suggestion.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan autocomplete suggestion: %w", err)
+		}
+		var values []string
+		if err := json.Unmarshal(spellings, &values); err != nil {
+			return nil, fmt.Errorf("failed to decode autocomplete spellings: %w", err)
+		}
+		suggestion.Value = values[0]
+		suggestion.Variants = values[1:]
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, rows.Err()
+}
+
+// autocompleteQuery 构建联想查询，q 为已规范化的输入。
+// 匹配质量：完整前缀 1.0、词首前缀 0.8、包含 0.6、三元组相似度（fuzzy 为 true 时），再乘以 1+ln(使用次数)
+func autocompleteQuery(userID uint, column, q string, fuzzy bool, limit int) (string, []interface{}) {
+	matchExpr := "1.0"
+	matchType := "'frequent'"
+	condition := ""
+	args := []interface{}{userID}
+	if q != "" {
+		escaped := utils.EscapeLike(q)
+		args = append(args, escaped+"%", "% "+escaped+"%", "%"+escaped+"%")
+		similarity := "0.0"
+		if fuzzy {
+			args = append(args, q)
+			similarity = "similarity(norm, $5)"
+		}
+		matchExpr = fmt.Sprintf(`CASE WHEN norm LIKE $2 THEN 1.0 WHEN norm LIKE $3 THEN 0.8 WHEN norm LIKE $4 THEN 0.6 ELSE %s END`, similarity)
+		matchType = `CASE WHEN norm LIKE $2 THEN 'prefix' WHEN norm LIKE $3 THEN 'word_prefix' WHEN norm LIKE $4 THEN 'contains' ELSE 'fuzzy' END`
+		condition = "WHERE norm LIKE $4"
+		if fuzzy {
+			condition += fmt.Sprintf(" OR similarity(norm, $5) >= %g", autocompleteMinSimilarity)
+		}
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		WITH variants AS (
+			SELECT BTRIM(%[1]s) AS value,
+				LOWER(regexp_replace(BTRIM(%[1]s), '\s+', ' ', 'g')) AS norm,
+				COUNT(*) AS uses, MAX(updated_at) AS last_used_at
+			FROM job_applications
+			WHERE user_id = $1 AND deleted_at IS NULL AND %[1]s IS NOT NULL AND BTRIM(%[1]s) <> ''
+			GROUP BY BTRIM(%[1]s)
+		), groups AS (
+			SELECT norm, SUM(uses)::int AS uses, MAX(last_used_at) AS last_used_at,
+				json_agg(value ORDER BY uses DESC, last_used_at DESC) AS spellings
+			FROM variants
+			GROUP BY norm
+		), scored AS (
+			SELECT norm, uses, last_used_at, spellings, (%[2]s) AS match, %[3]s AS match_type
+			FROM groups
+			%[4]s
+		)
+		SELECT spellings, uses, last_used_at, match_type, match * (1 + LN(uses)) AS score
+		FROM scored
+		ORDER BY score DESC, uses DESC, last_used_at DESC
+		LIMIT $%[5]d
+	`, column, matchExpr, matchType, condition, len(args))
+
+	return query, args
+}
+
+// normalizeAutocompleteValue 与 SQL 中的分组键一致：去首尾空白、折叠连续空白并转小写
+func normalizeAutocompleteValue(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestAutocompleteQuery(t *testing.T) {
+	query, args := autocompleteQuery(7, "company_name", normalizeAutocompleteValue("  Byte   Dance_ "), true, 10)
+	if len(args) != 6 || args[0] != uint(7) || args[1] != `byte dance\_%` || args[2] != `% byte dance\_%` || args[4] != "byte dance_" || args[5] != 10 {
+		t.Fatalf("unexpected args %v", args)
+	}
+	for _, fragment := range []string{"similarity(norm, $5) >= 0.3", "LIMIT $6", "GROUP BY BTRIM(company_name)"} {
+		if !strings.Contains(query, fragment) {
+			t.Fatalf("expected query to contain %q:\n%s", fragment, query)
+		}
+	}
+
+	query, args = autocompleteQuery(7, "work_location", "北京", false, 5)
+	if len(args) != 5 || strings.Contains(query, "similarity") || !strings.Contains(query, "LIMIT $5") {
+		t.Fatalf("expected substring-only query without pg_trgm, got %v\n%s", args, query)
+	}
+
+	query, args = autocompleteQuery(7, "position_title", "", true, 5)
+	if len(args) != 2 || !strings.Contains(query, "'frequent'") || strings.Contains(query, "WHERE norm") {
+		t.Fatalf("expected frequency-only query for empty input, got %v\n%s", args, query)
+	}
+
+	if model.AutocompleteField("salary").Column() != "" {
+		t.Fatalf("expected unknown field to have no column")
+	}
+}
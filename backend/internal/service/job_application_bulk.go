@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"jobView-backend/internal/model"
+	"strings"
+)
+
+const (
+	maxBulkCreateItems = 50
+	maxBulkItems       = 100
+)
+
+// errBulkAborted 原子模式下有条目失败，用于回滚整个事务
+var errBulkAborted = errors.New("bulk operation aborted")
+
+// bulkAbortedMessage 原子模式下因其他条目失败而未执行的条目
+const bulkAbortedMessage = "not applied: another item in the atomic request failed"
+
+// BulkCreate 批量创建。itemErrors 为调用方逐条校验的结果（可为 nil），有错误的条目不会执行
+func (s *JobApplicationService) BulkCreate(userID uint, mode model.BulkMode, reqs []model.CreateJobApplicationRequest, itemErrors []error) (*model.BulkOperationResponse, error) {
+	mode, err := normalizeBulkMode(mode, len(reqs), maxBulkCreateItems)
+	if err != nil {
+		return nil, err
+	}
+	resp := newBulkResponse(mode, len(reqs), itemErrors)
+	for i := range reqs {
+		if status := reqs[i].Status; resp.Results[i].Error == "" && status != "" && !status.IsValid() {
+			resp.Results[i].Error = fmt.Sprintf("invalid status: %s", status)
+		}
+	}
+	if mode == model.BulkModeBestEffort || !bulkHasErrors(resp) {
+		defs, err := loadCustomFieldDefinitions(s.db, userID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range reqs {
+			if resp.Results[i].Error != "" {
+				continue
+			}
+			if _, err := validateCustomFieldValues(defs, reqs[i].CustomFields, false); err != nil {
+				resp.Results[i].Error = err.Error()
+			}
+		}
+	}
+
+	if mode == model.BulkModeAtomic {
+		if bulkHasErrors(resp) {
+			return abortBulk(resp), nil
+		}
+		jobs, err := s.BatchCreate(userID, reqs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range jobs {
+			resp.Results[i].ID = jobs[i].ID
+			resp.Results[i].Data = &jobs[i]
+		}
+		return finalizeBulk(resp), nil
+	}
+
+	for i := range reqs {
+		if resp.Results[i].Error != "" {
+			continue
+		}
+		job, err := s.Create(userID, &reqs[i])
+		if err != nil {
+			resp.Results[i].Error = bulkItemError(err, "failed to create job application")
+			continue
+		}
+		resp.Results[i].ID = job.ID
+		resp.Results[i].Data = job
+	}
+	return finalizeBulk(resp), nil
+}
+
+// BulkUpdate 批量更新，原子模式下所有条目在同一事务内执行
+func (s *JobApplicationService) BulkUpdate(userID uint, mode model.BulkMode, items []model.BulkUpdateItem, itemErrors []error) (*model.BulkOperationResponse, error) {
+	mode, err := normalizeBulkMode(mode, len(items), maxBulkItems)
+	if err != nil {
+		return nil, err
+	}
+	resp := newBulkResponse(mode, len(items), itemErrors)
+
+	var defs []model.CustomFieldDefinition
+	for i := range items {
+		if items[i].CustomFields != nil {
+			if defs, err = loadCustomFieldDefinitions(s.db, userID); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	setParts := make([][]string, len(items))
+	args := make([][]interface{}, len(items))
+	for i := range items {
+		resp.Results[i].ID = items[i].ID
+		if resp.Results[i].Error != "" {
+			continue
+		}
+		if items[i].ID <= 0 {
+			resp.Results[i].Error = fmt.Sprintf("invalid id: %d", items[i].ID)
+			continue
+		}
+		if items[i].CustomFields != nil {
+			if items[i].CustomFields, err = validateCustomFieldValues(defs, items[i].CustomFields, true); err != nil {
+				resp.Results[i].Error = err.Error()
+				continue
+			}
+		}
+		setParts[i], args[i], err = updateSetClauses(&items[i].UpdateJobApplicationRequest)
+		if err != nil {
+			resp.Results[i].Error = err.Error()
+		} else if len(setParts[i]) == 0 {
+			resp.Results[i].Error = "invalid request: no fields to update"
+		}
+	}
+
+	if mode == model.BulkModeAtomic {
+		if bulkHasErrors(resp) {
+			return abortBulk(resp), nil
+		}
+		err := s.withBatchTx(func(q operationQueryer) error {
+			for i := range items {
+				job, err := updateReturning(q, userID, items[i].ID, setParts[i], args[i])
+				if err != nil {
+					resp.Results[i].Error = bulkItemError(err, "failed to update job application")
+					return errBulkAborted
+				}
+				resp.Results[i].Data = job
+			}
+			return nil
+		})
+		if err == errBulkAborted {
+			return abortBulk(resp), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to bulk update job applications: %w", err)
+		}
+		return finalizeBulk(resp), nil
+	}
+
+	for i := range items {
+		if resp.Results[i].Error != "" {
+			continue
+		}
+		job, err := s.Update(userID, items[i].ID, &items[i].UpdateJobApplicationRequest)
+		if err != nil {
+			resp.Results[i].Error = bulkItemError(err, "failed to update job application")
+			continue
+		}
+		resp.Results[i].Data = job
+	}
+	return finalizeBulk(resp), nil
+}
+
+// BulkDelete 批量删除（移入回收站），成功删除的记录作为一个可撤销的操作
+func (s *JobApplicationService) BulkDelete(userID uint, mode model.BulkMode, ids []int) (*model.BulkOperationResponse, error) {
+	mode, err := normalizeBulkMode(mode, len(ids), maxBulkItems)
+	if err != nil {
+		return nil, err
+	}
+	resp := newBulkResponse(mode, len(ids), nil)
+
+	var valid []int
+	for i, id := range ids {
+		resp.Results[i].ID = id
+		if id <= 0 {
+			resp.Results[i].Error = fmt.Sprintf("invalid id: %d", id)
+			continue
+		}
+		valid = append(valid, id)
+	}
+	if mode == model.BulkModeAtomic && bulkHasErrors(resp) {
+		return abortBulk(resp), nil
+	}
+	if len(valid) == 0 {
+		return finalizeBulk(resp), nil
+	}
+
+	var (
+		result  *model.BatchOperationResult
+		missing []int
+	)
+	err = s.withBatchTx(func(q operationQueryer) error {
+		var err error
+		result, missing, err = softDeleteInTx(q, userID, valid)
+		if err != nil {
+			return err
+		}
+		if mode == model.BulkModeAtomic && len(missing) > 0 {
+			return errBulkAborted
+		}
+		return nil
+	})
+	if err != nil && err != errBulkAborted {
+		return nil, fmt.Errorf("failed to bulk delete job applications: %w", err)
+	}
+
+	missingSet := make(map[int]bool, len(missing))
+	for _, id := range missing {
+		missingSet[id] = true
+	}
+	for i, id := range ids {
+		if resp.Results[i].Error == "" && missingSet[id] {
+			resp.Results[i].Error = "job application not found"
+		}
+	}
+	if err == errBulkAborted {
+		return abortBulk(resp), nil
+	}
+	resp.OperationID = result.OperationID
+	return finalizeBulk(resp), nil
+}
+
+// normalizeBulkMode 校验执行模式和条目数量，默认原子模式
+func normalizeBulkMode(mode model.BulkMode, n, max int) (model.BulkMode, error) {
+	if mode == "" {
+		mode = model.BulkModeAtomic
+	}
+	if !mode.IsValid() {
+		return "", fmt.Errorf("invalid mode: %s", mode)
+	}
+	if n == 0 {
+		return "", fmt.Errorf("invalid request: at least one item is required")
+	}
+	if n > max {
+		return "", fmt.Errorf("invalid request: at most %d items allowed, got %d", max, n)
+	}
+	return mode, nil
+}
+
+// newBulkResponse 初始化逐条结果并填入调用方的校验错误
+func newBulkResponse(mode model.BulkMode, n int, itemErrors []error) *model.BulkOperationResponse {
+	resp := &model.BulkOperationResponse{Mode: mode, Total: n, Results: make([]model.BulkItemResult, n)}
+	for i := range resp.Results {
+		resp.Results[i].Index = i
+		if i < len(itemErrors) && itemErrors[i] != nil {
+			resp.Results[i].Error = itemErrors[i].Error()
+		}
+	}
+	return resp
+}
+
+func bulkHasErrors(resp *model.BulkOperationResponse) bool {
+	for _, r := range resp.Results {
+		if r.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// abortBulk 原子模式失败：所有条目均未生效
+func abortBulk(resp *model.BulkOperationResponse) *model.BulkOperationResponse {
+	for i := range resp.Results {
+		resp.Results[i].Data = nil
+		if resp.Results[i].Error == "" {
+			resp.Results[i].Error = bulkAbortedMessage
+		}
+	}
+	resp.OperationID = 0
+	return finalizeBulk(resp)
+}
+
+// finalizeBulk 根据错误信息统计成功/失败数
+func finalizeBulk(resp *model.BulkOperationResponse) *model.BulkOperationResponse {
+	resp.Succeeded, resp.Failed = 0, 0
+	for i := range resp.Results {
+		resp.Results[i].Success = resp.Results[i].Error == ""
+		if resp.Results[i].Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp
+}
+
+// bulkItemError 单条错误的对外信息：校验错误和不存在原样返回，其余使用通用信息
+func bulkItemError(err error, fallback string) string {
+	msg := err.Error()
+	if strings.HasPrefix(msg, "invalid") || strings.Contains(msg, "not found") {
+		return msg
+	}
+	return fallback
+}
@@ -0,0 +1,489 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/utils"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const (
+	bulkEditSampleSize = 10
+	maxBulkEditRows    = 1000
+)
+
+// errBulkEditChanged 执行时匹配数与预览不一致
+var errBulkEditChanged = errors.New("matched applications changed since preview")
+
+// PreviewBulkEdit 预览按条件批量编辑：返回匹配数量和部分申请的字段变化
+func (s *JobApplicationService) PreviewBulkEdit(userID uint, req *model.BulkEditRequest) (*model.BulkEditPreview, error) {
+	customFields, err := s.normalizeCustomFields(userID, req.Patch.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+	req.Patch.CustomFields = customFields
+	tags, err := s.bulkEditTags(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := bulkEditPatch(&req.Patch, !tags.empty()); err != nil {
+		return nil, err
+	}
+	fields, after, err := bulkEditChanges(&req.Patch)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, args, err := s.bulkEditWhere(userID, &req.Filter, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &model.BulkEditPreview{Fields: bulkEditResultFields(fields, tags), Sample: []model.BulkEditSample{}}
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM job_applications "+whereClause, args...).Scan(&preview.MatchedCount); err != nil {
+		return nil, fmt.Errorf("failed to count matched applications: %w", err)
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT id, company_name, position_title, status, to_jsonb(job_applications)
+		FROM job_applications
+		%s
+		ORDER BY updated_at DESC, id DESC
+		LIMIT %d
+	`, whereClause, bulkEditSampleSize), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query matched applications: %w", err)
+	}
+	defer rows.Close()
+
+	var sampleIDs []int
+	for rows.Next() {
+		var sample model.BulkEditSample
+		var rowJSON []byte
+		if err := rows.Scan(&sample.ID, &sample.CompanyName, &sample.PositionTitle, &sample.Status, &rowJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan matched application: %w", err)
+		}
+		var current map[string]interface{}
+		if err := json.Unmarshal(rowJSON, &current); err != nil {
+			return nil, fmt.Errorf("failed to decode matched application: %w", err)
+		}
+		sample.Changes = make(map[string]model.FieldChange, len(preview.Fields))
+		for _, field := range fields {
+			change := model.FieldChange{Before: current[field], After: after[field]}
+			if field == "custom_fields" {
+				change.After = mergeCustomFieldPreview(current[field], req.Patch.CustomFields)
+			}
+			sample.Changes[field] = change
+		}
+		preview.Sample = append(preview.Sample, sample)
+		sampleIDs = append(sampleIDs, sample.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate matched applications: %w", err)
+	}
+
+	if !tags.empty() && len(sampleIDs) > 0 {
+		current, err := loadApplicationTags(s.db, userID, sampleIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range preview.Sample {
+			before, after := tags.preview(current[preview.Sample[i].ID])
+			preview.Sample[i].Changes["tags"] = model.FieldChange{Before: before, After: after}
+		}
+	}
+	return preview, nil
+}
+
+// ApplyBulkEdit 在一个事务内对所有匹配的申请执行修改（含标签增删）；匹配数与预览不一致时拒绝执行。
+// 审计日志记为 batch_update，所有条目共享返回的 transaction_id。
+func (s *JobApplicationService) ApplyBulkEdit(userID uint, req *model.BulkEditRequest) (*model.BulkEditResult, error) {
+	if req.ExpectedCount == nil {
+		return nil, fmt.Errorf("invalid request: expected_count is required, preview the edit first")
+	}
+	customFields, err := s.normalizeCustomFields(userID, req.Patch.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+	req.Patch.CustomFields = customFields
+	tags, err := s.bulkEditTags(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	setParts, setArgs, err := bulkEditPatch(&req.Patch, !tags.empty())
+	if err != nil {
+		return nil, err
+	}
+	fields, _, err := bulkEditChanges(&req.Patch)
+	if err != nil {
+		return nil, err
+	}
+	whereClause, whereArgs, err := s.bulkEditWhere(userID, &req.Filter, 1)
+	if err != nil {
+		return nil, err
+	}
+	if *req.ExpectedCount > maxBulkEditRows {
+		return nil, fmt.Errorf("invalid request: at most %d applications can be edited at once", maxBulkEditRows)
+	}
+
+	result := &model.BulkEditResult{Fields: bulkEditResultFields(fields, tags)}
+	err = s.withBatchTx(func(q operationQueryer) error {
+		// 锁定匹配的记录并记下ID，字段修改与标签增删都只作用于这批申请
+		var ids pq.Int64Array
+		if err := q.QueryRow(fmt.Sprintf(`
+			SELECT COALESCE(array_agg(id ORDER BY id), '{}')
+			FROM (SELECT id FROM job_applications %s ORDER BY id FOR UPDATE) t
+		`, whereClause), whereArgs...).Scan(&ids); err != nil {
+			return err
+		}
+		if int64(len(ids)) != *req.ExpectedCount {
+			return errBulkEditChanged
+		}
+		result.UpdatedCount = int64(len(ids))
+		if len(ids) == 0 {
+			return q.QueryRow("SELECT txid_current()").Scan(&result.TransactionID)
+		}
+
+		if len(setParts) > 0 {
+			query := fmt.Sprintf("UPDATE job_applications SET %s, updated_at = NOW() WHERE user_id = $%d AND id = ANY($%d)",
+				strings.Join(setParts, ", "), len(setArgs)+1, len(setArgs)+2)
+			if _, err := q.Exec(query, append(setArgs, userID, ids)...); err != nil {
+				return err
+			}
+		}
+		if !tags.empty() {
+			if err := applyBulkEditTags(q, tags, ids); err != nil {
+				return err
+			}
+		}
+		return q.QueryRow("SELECT txid_current()").Scan(&result.TransactionID)
+	})
+	if err == errBulkEditChanged {
+		return nil, err
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "invalid") {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply bulk edit: %w", err)
+	}
+	return result, nil
+}
+
+// bulkEditPatch 校验修改内容；状态变更需走批量状态接口（流转校验与撤销）。
+// 只增删标签时允许字段修改为空
+func bulkEditPatch(patch *model.UpdateJobApplicationRequest, hasTagChanges bool) ([]string, []interface{}, error) {
+	if patch.Status != nil {
+		return nil, nil, fmt.Errorf("invalid patch: status cannot be bulk edited, use the batch status endpoint")
+	}
+	setParts, args, err := updateSetClauses(patch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(setParts) == 0 && !hasTagChanges {
+		return nil, nil, fmt.Errorf("invalid patch: no fields to update")
+	}
+	return setParts, args, nil
+}
+
+// bulkEditChanges 请求中要修改的字段（按字段名排序）及修改后的值；请求字段名与列名一致。
+// 字段来自请求而非 SET 子句，随职位名称、薪资同步更新的派生列不单独列出
+func bulkEditChanges(patch *model.UpdateJobApplicationRequest) ([]string, map[string]interface{}, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode patch: %w", err)
+	}
+	fields := []string{}
+	after := make(map[string]interface{})
+	for field, value := range all {
+		if value == nil {
+			continue
+		}
+		if values, ok := value.(map[string]interface{}); ok && len(values) == 0 {
+			continue
+		}
+		fields = append(fields, field)
+		after[field] = value
+	}
+	sort.Strings(fields)
+	return fields, after, nil
+}
+
+// bulkEditResultFields 返回给调用方的修改字段，包含标签增删时追加 tags
+func bulkEditResultFields(fields []string, tags *bulkEditTagChange) []string {
+	if tags.empty() {
+		return fields
+	}
+	return append(append([]string{}, fields...), "tags")
+}
+
+// mergeCustomFieldPreview 自定义字段合并后的值：与现有值合并，null 删除对应字段
+func mergeCustomFieldPreview(current interface{}, patch model.CustomFieldValues) map[string]interface{} {
+	merged := make(map[string]interface{})
+	if values, ok := current.(map[string]interface{}); ok {
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// bulkEditTagChange 批量编辑中的标签增删
+type bulkEditTagChange struct {
+	Add    []int
+	Remove []int
+	Names  map[int]string // 涉及标签的名称，用于预览
+}
+
+func (c *bulkEditTagChange) empty() bool {
+	return len(c.Add) == 0 && len(c.Remove) == 0
+}
+
+// preview 根据申请现有标签计算增删前后的标签名称
+func (c *bulkEditTagChange) preview(current []model.ApplicationTag) ([]string, []string) {
+	before := make([]string, 0, len(current))
+	after := make([]string, 0, len(current)+len(c.Add))
+	present := make(map[int]bool, len(current))
+	for _, tag := range current {
+		before = append(before, tag.Name)
+		present[tag.ID] = true
+		if !containsInt(c.Remove, tag.ID) {
+			after = append(after, tag.Name)
+		}
+	}
+	for _, id := range c.Add {
+		if !present[id] {
+			after = append(after, c.Names[id])
+		}
+	}
+	return before, after
+}
+
+// normalizeBulkEditTags 校验并去重要增删的标签，同一标签不能同时添加和移除
+func normalizeBulkEditTags(req *model.BulkEditRequest) (*bulkEditTagChange, error) {
+	add, err := normalizeTagIDs(req.AddTagIDs)
+	if err != nil {
+		return nil, err
+	}
+	remove, err := normalizeTagIDs(req.RemoveTagIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range remove {
+		if containsInt(add, id) {
+			return nil, fmt.Errorf("invalid request: tag %d cannot be both added and removed", id)
+		}
+	}
+	if len(add) > maxTagsPerApplication {
+		return nil, fmt.Errorf("invalid request: at most %d tags per application", maxTagsPerApplication)
+	}
+	return &bulkEditTagChange{Add: add, Remove: remove, Names: make(map[int]string)}, nil
+}
+
+// bulkEditTags 校验要增删的标签并读取名称，标签必须属于当前用户
+func (s *JobApplicationService) bulkEditTags(userID uint, req *model.BulkEditRequest) (*bulkEditTagChange, error) {
+	change, err := normalizeBulkEditTags(req)
+	if err != nil || change.empty() {
+		return change, err
+	}
+
+	ids := append(append([]int{}, change.Add...), change.Remove...)
+	inClause, args, _ := tagIDPlaceholders(ids, 2)
+	rows, err := s.db.Query(fmt.Sprintf("SELECT id, name FROM tags WHERE user_id = $1 AND id IN (%s)", inClause),
+		append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tags: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		change.Names[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+	if len(change.Names) != len(ids) {
+		return nil, fmt.Errorf("tag not found or access denied")
+	}
+	return change, nil
+}
+
+// applyBulkEditTags 在批量编辑事务内为已锁定的申请增删标签
+func applyBulkEditTags(q operationQueryer, tags *bulkEditTagChange, ids pq.Int64Array) error {
+	if len(tags.Remove) > 0 {
+		inClause, tagArgs, _ := tagIDPlaceholders(tags.Remove, 2)
+		query := fmt.Sprintf("DELETE FROM job_application_tags WHERE job_application_id = ANY($1) AND tag_id IN (%s)", inClause)
+		if _, err := q.Exec(query, append([]interface{}{ids}, tagArgs...)...); err != nil {
+			return fmt.Errorf("failed to remove application tags: %w", err)
+		}
+	}
+	if len(tags.Add) == 0 {
+		return nil
+	}
+
+	inClause, tagArgs, _ := tagIDPlaceholders(tags.Add, 2)
+	query := fmt.Sprintf(`
+		INSERT INTO job_application_tags (job_application_id, tag_id)
+		SELECT m.id, t.id FROM unnest($1::int[]) AS m(id) CROSS JOIN tags t
+		WHERE t.id IN (%s)
+		ON CONFLICT DO NOTHING
+	`, inClause)
+	if _, err := q.Exec(query, append([]interface{}{ids}, tagArgs...)...); err != nil {
+		return fmt.Errorf("failed to add application tags: %w", err)
+	}
+
+	var exceeded int
+	if err := q.QueryRow(fmt.Sprintf(`
+		SELECT COUNT(*) FROM (
+			SELECT jt.job_application_id FROM job_application_tags jt
+			WHERE jt.job_application_id = ANY($1)
+			GROUP BY jt.job_application_id
+			HAVING COUNT(*) > %d
+		) t
+	`, maxTagsPerApplication), ids).Scan(&exceeded); err != nil {
+		return fmt.Errorf("failed to check application tag count: %w", err)
+	}
+	if exceeded > 0 {
+		return fmt.Errorf("invalid request: %d applications would exceed %d tags", exceeded, maxTagsPerApplication)
+	}
+	return nil
+}
+
+// containsInt 判断切片是否包含指定整数
+func containsInt(values []int, value int) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// bulkEditWhere 构建筛选条件，userID 使用 $startIndex，其余参数依次递增
+func (s *JobApplicationService) bulkEditWhere(userID uint, filter *model.BulkEditFilter, startIndex int) (string, []interface{}, error) {
+	whereClause := fmt.Sprintf("WHERE user_id = $%d AND deleted_at IS NULL", startIndex)
+	args := []interface{}{userID}
+	argIndex := startIndex + 1
+
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, len(filter.Status))
+		for i, status := range filter.Status {
+			if !status.IsValid() {
+				return "", nil, fmt.Errorf("invalid status: %s", status)
+			}
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, status)
+			argIndex++
+		}
+		whereClause += " AND status IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if filter.Stage != "" {
+		stageStatuses := s.getStatusesByStage(filter.Stage)
+		if len(stageStatuses) == 0 {
+			return "", nil, fmt.Errorf("invalid stage: %s", filter.Stage)
+		}
+		placeholders := make([]string, len(stageStatuses))
+		for i, status := range stageStatuses {
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, status)
+			argIndex++
+		}
+		whereClause += " AND status IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if filter.DateRange != nil {
+		if filter.DateRange.Start != "" {
+			if !isValidDate(filter.DateRange.Start) {
+				return "", nil, fmt.Errorf("invalid start date format: %s", filter.DateRange.Start)
+			}
+			whereClause += fmt.Sprintf(" AND application_date >= $%d", argIndex)
+			args = append(args, filter.DateRange.Start)
+			argIndex++
+		}
+		if filter.DateRange.End != "" {
+			if !isValidDate(filter.DateRange.End) {
+				return "", nil, fmt.Errorf("invalid end date format: %s", filter.DateRange.End)
+			}
+			whereClause += fmt.Sprintf(" AND application_date <= $%d", argIndex)
+			args = append(args, filter.DateRange.End)
+			argIndex++
+		}
+	}
+
+	if len(filter.CompanyNames) > 0 {
+		companyCondition, companyArgs, nextIndex := companyNamesCondition("", filter.CompanyNames, argIndex)
+		whereClause += " AND " + companyCondition
+		args = append(args, companyArgs...)
+		argIndex = nextIndex
+	}
+
+	if filter.Keywords != "" {
+		whereClause += fmt.Sprintf(" AND (company_name ILIKE $%d OR position_title ILIKE $%d OR notes ILIKE $%d)",
+			argIndex, argIndex, argIndex)
+		args = append(args, "%"+utils.EscapeLike(filter.Keywords)+"%")
+		argIndex++
+	}
+
+	if filter.Query != "" {
+		queryCondition, queryArgs, next, err := queryFilterCondition(filter.Query, argIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		whereClause += queryCondition
+		args = append(args, queryArgs...)
+		argIndex = next
+	}
+
+	if len(filter.TagIDs) > 0 {
+		tagIDs, err := normalizeTagIDs(filter.TagIDs)
+		if err != nil {
+			return "", nil, err
+		}
+		tagCondition, tagArgs, next := tagFilterCondition(tagIDs, argIndex)
+		whereClause += tagCondition
+		args = append(args, tagArgs...)
+		argIndex = next
+	}
+
+	if filter.SalaryMin != nil || filter.SalaryMax != nil {
+		salaryCondition, salaryArgs, next, err := salaryRangeCondition(filter.SalaryMin, filter.SalaryMax, argIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		whereClause += salaryCondition
+		args = append(args, salaryArgs...)
+		argIndex = next
+	}
+
+	if len(filter.CustomFilters) > 0 {
+		customCondition, customArgs, _, _, err := s.customFieldListFilters(userID,
+			&model.PaginationRequest{CustomFilters: filter.CustomFilters}, argIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		whereClause += customCondition
+		args = append(args, customArgs...)
+	}
+
+	return whereClause, args, nil
+}
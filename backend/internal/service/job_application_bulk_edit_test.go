@@ -0,0 +1,129 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestBulkEditWhereNumbering(t *testing.T) {
+	s := &JobApplicationService{}
+	filter := &model.BulkEditFilter{
+		ExportFilters: model.ExportFilters{
+			Status:       []model.ApplicationStatus{model.StatusApplied},
+			DateRange:    &model.DateRange{Start: "2025-01-01"},
+			CompanyNames: []string{"字节跳动"},
+			Keywords:     "后端",
+		},
+	}
+	where, args, err := s.bulkEditWhere(7, filter, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"user_id = $3", "status IN ($4)", "application_date >= $5", "company_name IN ($6)", "notes ILIKE $7"} {
+		if !strings.Contains(where, want) {
+			t.Fatalf("where clause %q missing %q", where, want)
+		}
+	}
+	if len(args) != 5 || args[0] != uint(7) || args[4] != "%后端%" {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	if _, _, err := s.bulkEditWhere(7, &model.BulkEditFilter{Stage: "unknown"}, 1); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected invalid stage error, got %v", err)
+	}
+	bad := &model.BulkEditFilter{ExportFilters: model.ExportFilters{DateRange: &model.DateRange{End: "2025/01/01"}}}
+	if _, _, err := s.bulkEditWhere(7, bad, 1); err == nil {
+		t.Fatalf("expected invalid date error")
+	}
+}
+
+func TestBulkEditPatch(t *testing.T) {
+	status := model.StatusApplied
+	if _, _, err := bulkEditPatch(&model.UpdateJobApplicationRequest{Status: &status}, false); err == nil {
+		t.Fatalf("status patch should be rejected")
+	}
+	if _, _, err := bulkEditPatch(&model.UpdateJobApplicationRequest{}, false); err == nil {
+		t.Fatalf("empty patch should be rejected")
+	}
+	if _, _, err := bulkEditPatch(&model.UpdateJobApplicationRequest{}, true); err != nil {
+		t.Fatalf("tag-only edit should be allowed, got %v", err)
+	}
+
+	disabled := false
+	notes := "已放弃"
+	patch := &model.UpdateJobApplicationRequest{ReminderEnabled: &disabled, Notes: &notes}
+	setParts, args, err := bulkEditPatch(patch, false)
+	if err != nil || len(args) != 2 {
+		t.Fatalf("unexpected result %v %v %v", setParts, args, err)
+	}
+	fields, after, err := bulkEditChanges(patch)
+	if err != nil || strings.Join(fields, ",") != "notes,reminder_enabled" {
+		t.Fatalf("unexpected fields %v %v", fields, err)
+	}
+	if after["reminder_enabled"] != false || after["notes"] != notes || len(after) != 2 {
+		t.Fatalf("unexpected after values %v", after)
+	}
+
+	if _, err := (&JobApplicationService{}).ApplyBulkEdit(1, &model.BulkEditRequest{Patch: *patch}); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("apply without expected_count should be rejected, got %v", err)
+	}
+}
+
+// Derived columns (taxonomy, structured salary) must not show up as wiped fields in the preview
+func TestBulkEditChangesSkipsDerivedColumns(t *testing.T) {
+	title := "高级后端工程师"
+	salary := "20-30K·14薪"
+	patch := &model.UpdateJobApplicationRequest{PositionTitle: &title, SalaryRange: &salary}
+	setParts, _, err := bulkEditPatch(patch, false)
+	if err != nil || len(setParts) <= 2 {
+		t.Fatalf("expected derived columns in SET clauses, got %v %v", setParts, err)
+	}
+	fields, after, err := bulkEditChanges(patch)
+	if err != nil || strings.Join(fields, ",") != "position_title,salary_range" {
+		t.Fatalf("unexpected preview fields %v %v", fields, err)
+	}
+	if after["position_title"] != title || after["salary_range"] != salary {
+		t.Fatalf("unexpected after values %v", after)
+	}
+
+	merged := mergeCustomFieldPreview(map[string]interface{}{"level": "P6", "team": "infra"},
+		model.CustomFieldValues{"level": "P7", "team": nil})
+	if len(merged) != 1 || merged["level"] != "P7" {
+		t.Fatalf("unexpected merged custom fields %v", merged)
+	}
+}
+
+func TestBulkEditTags(t *testing.T) {
+	if _, err := normalizeBulkEditTags(&model.BulkEditRequest{AddTagIDs: []int{1, 2}, RemoveTagIDs: []int{2}}); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected conflicting tag ids to be rejected, got %v", err)
+	}
+	if _, err := normalizeBulkEditTags(&model.BulkEditRequest{AddTagIDs: []int{0}}); err == nil {
+		t.Fatalf("expected invalid tag id to be rejected")
+	}
+
+	change, err := normalizeBulkEditTags(&model.BulkEditRequest{AddTagIDs: []int{3, 3}, RemoveTagIDs: []int{1}})
+	if err != nil || len(change.Add) != 1 || change.empty() {
+		t.Fatalf("unexpected change %+v %v", change, err)
+	}
+	change.Names = map[int]string{1: "待跟进", 3: "大厂"}
+	before, after := change.preview([]model.ApplicationTag{{ID: 1, Name: "待跟进"}, {ID: 2, Name: "内推"}})
+	if strings.Join(before, ",") != "待跟进,内推" || strings.Join(after, ",") != "内推,大厂" {
+		t.Fatalf("unexpected tag preview %v -> %v", before, after)
+	}
+	if fields := bulkEditResultFields([]string{"notes"}, change); strings.Join(fields, ",") != "notes,tags" {
+		t.Fatalf("unexpected result fields %v", fields)
+	}
+}
+
+func TestBulkEditWhereEscapesKeywords(t *testing.T) {
+	s := &JobApplicationService{}
+	filter := &model.BulkEditFilter{ExportFilters: model.ExportFilters{Keywords: "100%_offer"}}
+	_, args, err := s.bulkEditWhere(7, filter, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args[1] != `%100\%\_offer%` {
+		t.Fatalf("expected escaped keyword pattern, got %v", args[1])
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestBulkAtomicValidationAbortsAllItems(t *testing.T) {
+	s := &JobApplicationService{}
+	reqs := []model.CreateJobApplicationRequest{
+		{CompanyName: "A", PositionTitle: "Go"},
+		{CompanyName: "B", PositionTitle: "Go", Status: "不存在的状态"},
+		{CompanyName: "", PositionTitle: "Go"},
+	}
+	itemErrors := []error{nil, nil, errors.New("公司名称不能为空")}
+
+	resp, err := s.BulkCreate(1, "", reqs, itemErrors)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Mode != model.BulkModeAtomic || resp.Succeeded != 0 || resp.Failed != 3 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	if resp.Results[0].Error != bulkAbortedMessage {
+		t.Fatalf("valid item should be reported as not applied, got %q", resp.Results[0].Error)
+	}
+	if !strings.HasPrefix(resp.Results[1].Error, "invalid status") || resp.Results[2].Error != "公司名称不能为空" {
+		t.Fatalf("item errors not kept by index: %+v", resp.Results)
+	}
+	for i, r := range resp.Results {
+		if r.Index != i || r.Success {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+	}
+}
+
+func TestBulkUpdateItemValidation(t *testing.T) {
+	s := &JobApplicationService{}
+	notes := "ok"
+	items := []model.BulkUpdateItem{
+		{ID: 0, UpdateJobApplicationRequest: model.UpdateJobApplicationRequest{Notes: &notes}},
+		{ID: 2},
+	}
+	resp, err := s.BulkUpdate(1, model.BulkModeAtomic, items, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Results[0].Error != "invalid id: 0" || !strings.Contains(resp.Results[1].Error, "no fields") || resp.Results[1].ID != 2 {
+		t.Fatalf("unexpected results: %+v", resp.Results)
+	}
+}
+
+func TestNormalizeBulkMode(t *testing.T) {
+	if _, err := normalizeBulkMode("partial", 1, 10); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected invalid mode error, got %v", err)
+	}
+	if _, err := normalizeBulkMode(model.BulkModeBestEffort, 11, 10); err == nil {
+		t.Fatalf("expected size limit error")
+	}
+	if _, err := normalizeBulkMode(model.BulkModeBestEffort, 0, 10); err == nil {
+		t.Fatalf("expected empty request error")
+	}
+	if mode, err := normalizeBulkMode("", 3, 10); err != nil || mode != model.BulkModeAtomic {
+		t.Fatalf("expected atomic default, got %s %v", mode, err)
+	}
+}
@@ -3,16 +3,25 @@ package service
 import (
     "database/sql"
     "fmt"
+    "sort"
     "jobView-backend/internal/database"
     "jobView-backend/internal/repository"
     "jobView-backend/internal/model"
+    "jobView-backend/internal/querylang"
     "strings"
+    "sync"
     "time"
+
+    "gorm.io/gorm"
 )
 
 type JobApplicationService struct {
     db *database.DB
     repo repository.JobApplicationRepository
+
+    // pg_trgm 可用性（模糊搜索），首次搜索时检测
+    trigramOnce      sync.Once
+    trigramAvailable bool
 }
 
 func NewJobApplicationService(db *database.DB) *JobApplicationService {
@@ -25,6 +34,14 @@ func NewJobApplicationService(db *database.DB) *JobApplicationService {
 
 // Create 创建新的投递记录
 func (s *JobApplicationService) Create(userID uint, req *model.CreateJobApplicationRequest) (*model.JobApplication, error) {
+	customFields, err := s.normalizeCustomFields(userID, req.CustomFields, false)
+	if err != nil {
+		return nil, err
+	}
+	normalized := *req
+	normalized.CustomFields = customFields
+	req = &normalized
+
     if s.db.UseGorm && s.repo != nil {
         // 复用原有校验
         status := req.Status
@@ -60,13 +77,14 @@ func (s *JobApplicationService) Create(userID uint, req *model.CreateJobApplicat
 			user_id, company_name, position_title, application_date, status, 
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
-			hr_name, hr_phone, hr_email, interview_location, interview_type
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
-		RETURNING id, created_at, updated_at
+			hr_name, hr_phone, hr_email, interview_location, interview_type, custom_fields,
+			role_family, role_level, tech_stack, role_taxonomy_version, ` + salaryColumns + `
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20::jsonb,
+			$21, $22, $23::jsonb, $24, $25, $26, $27, $28, $29, $30, $31)
+		RETURNING id, created_at, updated_at, company_id
 	`
 
-	var job model.JobApplication
-	err := s.db.QueryRow(query,
+	args := []interface{}{
 		userID,
 		req.CompanyName,
 		req.PositionTitle,
@@ -86,7 +104,13 @@ func (s *JobApplicationService) Create(userID uint, req *model.CreateJobApplicat
 		req.HREmail,
 		req.InterviewLocation,
 		req.InterviewType,
-	).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+		req.CustomFields,
+	}
+	args = append(args, positionTaxonomyValues(req.PositionTitle)...)
+	args = append(args, salaryColumnValues(req.SalaryRange)...)
+
+	var job model.JobApplication
+	err = s.db.QueryRow(query, args...).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt, &job.CompanyID)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create job application: %w", err)
@@ -112,6 +136,9 @@ func (s *JobApplicationService) Create(userID uint, req *model.CreateJobApplicat
 	job.HREmail = req.HREmail
 	job.InterviewLocation = req.InterviewLocation
 	job.InterviewType = req.InterviewType
+	job.CustomFields = req.CustomFields
+	applyPositionTaxonomy(&job)
+	applyParsedSalary(&job)
 
 	return &job, nil
 }
@@ -124,9 +151,11 @@ func (s *JobApplicationService) GetByID(userID uint, id int) (*model.JobApplicat
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at
+			created_at, updated_at, ` + model.SuccessProbabilitySortExpr + `, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD')
 		FROM job_applications
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	var job model.JobApplication
@@ -153,6 +182,19 @@ func (s *JobApplicationService) GetByID(userID uint, id int) (*model.JobApplicat
 		&job.InterviewType,
 		&job.CreatedAt,
 		&job.UpdatedAt,
+		&job.SuccessProbability,
+		&job.CustomFields,
+		&job.CompanyID,
+		&job.RoleFamily,
+		&job.RoleLevel,
+		&job.TechStack,
+		&job.SalaryMinMonthly,
+		&job.SalaryMaxMonthly,
+		&job.SalaryMonths,
+		&job.SalaryAnnualMin,
+		&job.SalaryAnnualMax,
+		&job.SalaryCurrency,
+		&job.DecisionDeadline,
 	)
 
 	if err != nil {
@@ -172,7 +214,7 @@ func (s *JobApplicationService) GetAllPaginated(userID uint, req model.Paginatio
 	req.ValidateAndSetDefaults()
 
 	// 构建WHERE条件
-	whereClause := "WHERE user_id = $1"
+	whereClause := "WHERE user_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{userID}
 	argIndex := 2
 
@@ -211,12 +253,13 @@ func (s *JobApplicationService) GetAllPaginated(userID uint, req model.Paginatio
 
 	// 2. 验证排序字段安全性（防止SQL注入）
 	allowedSortFields := map[string]bool{
-		"application_date": true,
-		"created_at":       true,
-		"updated_at":       true,
-		"company_name":     true,
-		"position_title":   true,
-		"status":           true,
+		"application_date":    true,
+		"created_at":          true,
+		"updated_at":          true,
+		"company_name":        true,
+		"position_title":      true,
+		"status":              true,
+		"success_probability": true,
 	}
 	if !allowedSortFields[req.SortBy] {
 		req.SortBy = "application_date" // 默认排序字段
@@ -228,12 +271,14 @@ func (s *JobApplicationService) GetAllPaginated(userID uint, req model.Paginatio
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at
+			created_at, updated_at, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD')
 		FROM job_applications 
 		%s 
-		ORDER BY %s %s, created_at DESC 
+		ORDER BY %s, created_at DESC 
 		LIMIT $%d OFFSET $%d
-	`, whereClause, req.SortBy, req.SortDir, argIndex, argIndex+1)
+	`, whereClause, req.SortExpression(), argIndex, argIndex+1)
 
 	// 添加LIMIT和OFFSET参数
 	args = append(args, req.PageSize, req.GetOffset())
@@ -277,6 +322,18 @@ func (s *JobApplicationService) GetAllPaginated(userID uint, req model.Paginatio
 			&job.InterviewType,
 			&job.CreatedAt,
 			&job.UpdatedAt,
+			&job.CustomFields,
+			&job.CompanyID,
+			&job.RoleFamily,
+			&job.RoleLevel,
+			&job.TechStack,
+			&job.SalaryMinMonthly,
+			&job.SalaryMaxMonthly,
+			&job.SalaryMonths,
+			&job.SalaryAnnualMin,
+			&job.SalaryAnnualMax,
+			&job.SalaryCurrency,
+			&job.DecisionDeadline,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job application: %w", err)
@@ -311,9 +368,11 @@ func (s *JobApplicationService) GetAll(userID uint) ([]model.JobApplication, err
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at
+			created_at, updated_at, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD')
 		FROM job_applications
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY application_date DESC, created_at DESC
 		LIMIT 500
 	`
@@ -350,6 +409,18 @@ func (s *JobApplicationService) GetAll(userID uint) ([]model.JobApplication, err
 			&job.InterviewType,
 			&job.CreatedAt,
 			&job.UpdatedAt,
+			&job.CustomFields,
+			&job.CompanyID,
+			&job.RoleFamily,
+			&job.RoleLevel,
+			&job.TechStack,
+			&job.SalaryMinMonthly,
+			&job.SalaryMaxMonthly,
+			&job.SalaryMonths,
+			&job.SalaryAnnualMin,
+			&job.SalaryAnnualMax,
+			&job.SalaryCurrency,
+			&job.DecisionDeadline,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job application: %w", err)
@@ -362,7 +433,30 @@ func (s *JobApplicationService) GetAll(userID uint) ([]model.JobApplication, err
 
 // Update 更新投递记录（带用户权限检查）- 优化版，避免N+1查询问题
 func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJobApplicationRequest) (*model.JobApplication, error) {
+	customFields, err := s.normalizeCustomFields(userID, req.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+	normalized := *req
+	normalized.CustomFields = customFields
+	req = &normalized
+
     if s.db.UseGorm && s.repo != nil { return s.repo.Update(userID, id, req) }
+	setParts, args, err := updateSetClauses(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 如果没有需要更新的字段，直接返回现有记录
+	if len(setParts) == 0 {
+		return s.GetByID(userID, id)
+	}
+
+	return updateReturning(s.db, userID, id, setParts, args)
+}
+
+// updateSetClauses 根据更新请求构建 SET 子句与参数（占位符从 $1 开始）
+func updateSetClauses(req *model.UpdateJobApplicationRequest) ([]string, []interface{}, error) {
 	setParts := []string{}
 	args := []interface{}{}
 	argIndex := 1
@@ -377,6 +471,13 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 		setParts = append(setParts, fmt.Sprintf("position_title = $%d", argIndex))
 		args = append(args, *req.PositionTitle)
 		argIndex++
+
+		// 职位名称变化时同步重新归一化岗位类别、职级与技术栈
+		setParts = append(setParts, fmt.Sprintf(
+			"role_family = $%d, role_level = $%d, tech_stack = $%d::jsonb, role_taxonomy_version = $%d",
+			argIndex, argIndex+1, argIndex+2, argIndex+3))
+		args = append(args, positionTaxonomyValues(*req.PositionTitle)...)
+		argIndex += 4
 	}
 
 	if req.ApplicationDate != nil {
@@ -388,7 +489,7 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 	if req.Status != nil {
 		// 验证状态是否有效
 		if !req.Status.IsValid() {
-			return nil, fmt.Errorf("invalid status: %s", *req.Status)
+			return nil, nil, fmt.Errorf("invalid status: %s", *req.Status)
 		}
 		setParts = append(setParts, fmt.Sprintf("status = $%d", argIndex))
 		args = append(args, *req.Status)
@@ -405,6 +506,13 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 		setParts = append(setParts, fmt.Sprintf("salary_range = $%d", argIndex))
 		args = append(args, *req.SalaryRange)
 		argIndex++
+
+		// 薪资描述变化时同步重新解析结构化薪酬
+		for _, column := range strings.Split(salaryColumns, ", ") {
+			setParts = append(setParts, fmt.Sprintf("%s = $%d", column, argIndex))
+			argIndex++
+		}
+		args = append(args, salaryColumnValues(req.SalaryRange)...)
 	}
 
 	if req.WorkLocation != nil {
@@ -479,10 +587,73 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 		argIndex++
 	}
 
-	// 如果没有需要更新的字段，直接返回现有记录
-	if len(setParts) == 0 {
-		return s.GetByID(userID, id)
+	// 自定义字段与现有值合并，null 值删除对应字段（调用方需先完成校验）
+	if len(req.CustomFields) > 0 {
+		setParts = append(setParts, fmt.Sprintf("custom_fields = jsonb_strip_nulls(custom_fields || $%d::jsonb)", argIndex))
+		args = append(args, req.CustomFields)
+		argIndex++
+	}
+
+	return setParts, args, nil
+}
+
+// customFieldListFilters 构建自定义字段筛选条件（cf.<key>=value）及按自定义字段排序的表达式；
+// 排序字段不存在时返回空表达式，由调用方回退到默认排序
+func (s *JobApplicationService) customFieldListFilters(userID uint, req *model.PaginationRequest, argIndex int) (string, []interface{}, int, string, error) {
+	sortKey, sortByCustom := strings.CutPrefix(req.SortBy, model.CustomFieldPrefix)
+	if len(req.CustomFilters) == 0 && !sortByCustom {
+		return "", nil, argIndex, "", nil
+	}
+	defs, err := loadCustomFieldDefinitions(s.db, userID)
+	if err != nil {
+		return "", nil, argIndex, "", err
+	}
+
+	keys := make([]string, 0, len(req.CustomFilters))
+	for key := range req.CustomFilters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	condition := ""
+	var args []interface{}
+	for _, key := range keys {
+		def, ok := findCustomField(defs, key)
+		if !ok {
+			return "", nil, argIndex, "", fmt.Errorf("invalid custom field filter: unknown field %q", key)
+		}
+		var fieldCondition string
+		var fieldArgs []interface{}
+		fieldCondition, fieldArgs, argIndex, err = customFieldCondition(def, req.CustomFilters[key], argIndex)
+		if err != nil {
+			return "", nil, argIndex, "", err
+		}
+		condition += fieldCondition
+		args = append(args, fieldArgs...)
+	}
+
+	sortExpr := ""
+	if def, ok := findCustomField(defs, sortKey); ok && sortByCustom {
+		sortExpr = customFieldSortExpression(def, req.SortDir)
+	}
+	return condition, args, argIndex, sortExpr, nil
+}
+
+// normalizeCustomFields 按用户的字段定义校验自定义字段；更新时未提交自定义字段则不查询定义
+func (s *JobApplicationService) normalizeCustomFields(userID uint, values model.CustomFieldValues, partial bool) (model.CustomFieldValues, error) {
+	if partial && values == nil {
+		return nil, nil
+	}
+	defs, err := loadCustomFieldDefinitions(s.db, userID)
+	if err != nil {
+		return nil, err
 	}
+	return validateCustomFieldValues(defs, values, partial)
+}
+
+// updateReturning 执行 UPDATE ... RETURNING，一次SQL完成更新并返回结果
+func updateReturning(q operationQueryer, userID uint, id int, setParts []string, args []interface{}) (*model.JobApplication, error) {
+	argIndex := len(args) + 1
 
 	// 添加updated_at更新
 	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
@@ -492,20 +663,21 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 	// 添加WHERE条件的ID和用户ID
 	args = append(args, id, userID)
 
-	// 优化：使用UPDATE ... RETURNING避免额外查询，一次SQL完成更新并返回结果
 	query := fmt.Sprintf(`
 		UPDATE job_applications
 		SET %s
-		WHERE id = $%d AND user_id = $%d
+		WHERE id = $%d AND user_id = $%d AND deleted_at IS NULL
 		RETURNING id, user_id, company_name, position_title, application_date, status,
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at
+			created_at, updated_at, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD')
 	`, strings.Join(setParts, ", "), argIndex, argIndex+1)
 
 	var job model.JobApplication
-	err := s.db.QueryRow(query, args...).Scan(
+	err := q.QueryRow(query, args...).Scan(
 		&job.ID,
 		&job.UserID,
 		&job.CompanyName,
@@ -528,6 +700,18 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 		&job.InterviewType,
 		&job.CreatedAt,
 		&job.UpdatedAt,
+		&job.CustomFields,
+		&job.CompanyID,
+		&job.RoleFamily,
+		&job.RoleLevel,
+		&job.TechStack,
+		&job.SalaryMinMonthly,
+		&job.SalaryMaxMonthly,
+		&job.SalaryMonths,
+		&job.SalaryAnnualMin,
+		&job.SalaryAnnualMax,
+		&job.SalaryCurrency,
+		&job.DecisionDeadline,
 	)
 
 	if err != nil {
@@ -540,10 +724,10 @@ func (s *JobApplicationService) Update(userID uint, id int, req *model.UpdateJob
 	return &job, nil
 }
 
-// Delete 删除投递记录（带用户权限检查）
+// Delete 删除投递记录（带用户权限检查）：软删除，移入回收站，可在保留期内恢复
 func (s *JobApplicationService) Delete(userID uint, id int) error {
     if s.db.UseGorm && s.repo != nil { return s.repo.Delete(userID, id) }
-	query := "DELETE FROM job_applications WHERE id = $1 AND user_id = $2"
+	query := "UPDATE job_applications SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL"
 	result, err := s.db.Exec(query, id, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete job application: %w", err)
@@ -568,7 +752,7 @@ func (s *JobApplicationService) GetStatusStatistics(userID uint) (map[string]int
         query := `
             SELECT status, COUNT(*) as count
             FROM job_applications
-            WHERE user_id = $1
+            WHERE user_id = $1 AND deleted_at IS NULL
             GROUP BY status
             ORDER BY count DESC
         `
@@ -627,7 +811,7 @@ func (s *JobApplicationService) GetStatusStatistics(userID uint) (map[string]int
 	query := `
 		SELECT status, COUNT(*) as count
 		FROM job_applications
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		GROUP BY status
 		ORDER BY count DESC
 	`
@@ -702,12 +886,18 @@ func (s *JobApplicationService) BatchCreate(userID uint, applications []model.Cr
 		return nil, fmt.Errorf("batch size too large: maximum 50 applications allowed, got %d", len(applications))
 	}
 
+	defs, err := loadCustomFieldDefinitions(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	customFields := make([]model.CustomFieldValues, len(applications))
+
 	// 构建批量插入SQL
 	var valueStrings []string
 	var valueArgs []interface{}
 	argIndex := 1
 
-	for _, req := range applications {
+	for i, req := range applications {
 		// 验证和设置默认值
 		applicationDate := req.ApplicationDate
 		if applicationDate == "" {
@@ -723,6 +913,10 @@ func (s *JobApplicationService) BatchCreate(userID uint, applications []model.Cr
 			return nil, fmt.Errorf("invalid status: %s", status)
 		}
 
+		if customFields[i], err = validateCustomFieldValues(defs, req.CustomFields, false); err != nil {
+			return nil, err
+		}
+
 		reminderEnabled := false
 		if req.ReminderEnabled != nil {
 			reminderEnabled = *req.ReminderEnabled
@@ -730,9 +924,11 @@ func (s *JobApplicationService) BatchCreate(userID uint, applications []model.Cr
 
 		// 构建单个记录的值占位符
 		valueStrings = append(valueStrings, fmt.Sprintf(
-			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			"($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d::jsonb, $%d, $%d, $%d::jsonb, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
 			argIndex, argIndex+1, argIndex+2, argIndex+3, argIndex+4, argIndex+5, argIndex+6, argIndex+7, argIndex+8,
 			argIndex+9, argIndex+10, argIndex+11, argIndex+12, argIndex+13, argIndex+14, argIndex+15, argIndex+16, argIndex+17, argIndex+18,
+			argIndex+19, argIndex+20, argIndex+21, argIndex+22, argIndex+23,
+			argIndex+24, argIndex+25, argIndex+26, argIndex+27, argIndex+28, argIndex+29, argIndex+30,
 		))
 
 		// 添加参数值
@@ -756,9 +952,12 @@ func (s *JobApplicationService) BatchCreate(userID uint, applications []model.Cr
 			req.HREmail,
 			req.InterviewLocation,
 			req.InterviewType,
+			customFields[i],
 		)
+		valueArgs = append(valueArgs, positionTaxonomyValues(req.PositionTitle)...)
+		valueArgs = append(valueArgs, salaryColumnValues(req.SalaryRange)...)
 
-		argIndex += 19
+		argIndex += 31
 	}
 
 	// 执行批量插入
@@ -767,76 +966,108 @@ func (s *JobApplicationService) BatchCreate(userID uint, applications []model.Cr
 			user_id, company_name, position_title, application_date, status, 
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
-			hr_name, hr_phone, hr_email, interview_location, interview_type
+			hr_name, hr_phone, hr_email, interview_location, interview_type, custom_fields,
+			role_family, role_level, tech_stack, role_taxonomy_version, ` + salaryColumns + `
 		) VALUES %s
-		RETURNING id, created_at, updated_at
+		RETURNING id, created_at, updated_at, company_id
 	`, strings.Join(valueStrings, ", "))
 
-    var rows *sql.Rows
-    var err error
-    if s.db.UseGorm && s.db.ORM != nil {
-        rows, err = s.db.ORM.Raw(query, valueArgs...).Rows()
-    } else {
-        rows, err = s.db.Query(query, valueArgs...)
-    }
-    if err != nil {
-        return nil, fmt.Errorf("failed to batch create job applications: %w", err)
-    }
-	defer rows.Close()
-
 	// 收集返回的ID和时间戳
 	var results []model.JobApplication
-	i := 0
-	for rows.Next() {
-		if i >= len(applications) {
-			return nil, fmt.Errorf("unexpected number of returned rows")
-		}
+	scanResults := func(rows *sql.Rows) error {
+		defer rows.Close()
+		i := 0
+		for rows.Next() {
+			if i >= len(applications) {
+				return fmt.Errorf("unexpected number of returned rows")
+			}
 
-		var job model.JobApplication
-		var id int
-		var createdAt, updatedAt time.Time
+			var job model.JobApplication
+			var id int
+			var createdAt, updatedAt time.Time
 
-		if err := rows.Scan(&id, &createdAt, &updatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan batch create result: %w", err)
-		}
+			if err := rows.Scan(&id, &createdAt, &updatedAt, &job.CompanyID); err != nil {
+				return fmt.Errorf("failed to scan batch create result: %w", err)
+			}
 
-		// 填充完整的应用数据
-		req := applications[i]
-		job.ID = id
-		job.UserID = userID
-		job.CompanyName = req.CompanyName
-		job.PositionTitle = req.PositionTitle
-		job.ApplicationDate = req.ApplicationDate
-		if job.ApplicationDate == "" {
-			job.ApplicationDate = time.Now().Format("2006-01-02")
+			// 填充完整的应用数据
+			req := applications[i]
+			job.ID = id
+			job.UserID = userID
+			job.CompanyName = req.CompanyName
+			job.PositionTitle = req.PositionTitle
+			job.ApplicationDate = req.ApplicationDate
+			if job.ApplicationDate == "" {
+				job.ApplicationDate = time.Now().Format("2006-01-02")
+			}
+			job.Status = req.Status
+			if job.Status == "" {
+				job.Status = model.StatusApplied
+			}
+			job.JobDescription = req.JobDescription
+			job.SalaryRange = req.SalaryRange
+			job.WorkLocation = req.WorkLocation
+			job.ContactInfo = req.ContactInfo
+			job.Notes = req.Notes
+			job.InterviewTime = req.InterviewTime
+			job.ReminderTime = req.ReminderTime
+			job.ReminderEnabled = req.ReminderEnabled != nil && *req.ReminderEnabled
+			job.FollowUpDate = req.FollowUpDate
+			job.HRName = req.HRName
+			job.HRPhone = req.HRPhone
+			job.HREmail = req.HREmail
+			job.InterviewLocation = req.InterviewLocation
+			job.InterviewType = req.InterviewType
+			job.CustomFields = customFields[i]
+			applyPositionTaxonomy(&job)
+			applyParsedSalary(&job)
+			job.CreatedAt = createdAt
+			job.UpdatedAt = updatedAt
+
+			results = append(results, job)
+			i++
 		}
-		job.Status = req.Status
-		if job.Status == "" {
-			job.Status = model.StatusApplied
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating batch create results: %w", err)
 		}
-		job.JobDescription = req.JobDescription
-		job.SalaryRange = req.SalaryRange
-		job.WorkLocation = req.WorkLocation
-		job.ContactInfo = req.ContactInfo
-		job.Notes = req.Notes
-		job.InterviewTime = req.InterviewTime
-		job.ReminderTime = req.ReminderTime
-		job.ReminderEnabled = req.ReminderEnabled != nil && *req.ReminderEnabled
-		job.FollowUpDate = req.FollowUpDate
-		job.HRName = req.HRName
-		job.HRPhone = req.HRPhone
-		job.HREmail = req.HREmail
-		job.InterviewLocation = req.InterviewLocation
-		job.InterviewType = req.InterviewType
-		job.CreatedAt = createdAt
-		job.UpdatedAt = updatedAt
-
-		results = append(results, job)
-		i++
+		return nil
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating batch create results: %w", err)
+	// 在事务内执行，审计日志记为 batch_create
+    if s.db.UseGorm && s.db.ORM != nil {
+        err := s.db.ORM.Transaction(func(tx *gorm.DB) error {
+            if err := tx.Exec(auditBatchSetting).Error; err != nil {
+                return err
+            }
+            rows, err := tx.Raw(query, valueArgs...).Rows()
+            if err != nil {
+                return fmt.Errorf("failed to batch create job applications: %w", err)
+            }
+            return scanResults(rows)
+        })
+        if err != nil {
+            return nil, err
+        }
+        return results, nil
+    }
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(auditBatchSetting); err != nil {
+		return nil, fmt.Errorf("failed to mark batch operation: %w", err)
+	}
+	rows, err := tx.Query(query, valueArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch create job applications: %w", err)
+	}
+	if err := scanResults(rows); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit batch create: %w", err)
 	}
 
 	return results, nil
@@ -878,33 +1109,17 @@ func (s *JobApplicationService) BatchUpdateStatus(userID uint, updates []model.B
 		SET status = updates.status::VARCHAR, updated_at = NOW()
 		FROM updates 
 		WHERE job_applications.id = updates.id 
-		AND job_applications.user_id = $%d
+		AND job_applications.user_id = $%d AND job_applications.deleted_at IS NULL
 	`, strings.Join(valueStrings, ", "), argIndex)
 
 	// 添加 userID 参数
 	valueArgs = append(valueArgs, userID)
 
-    if s.db.UseGorm && s.db.ORM != nil {
-        res := s.db.ORM.Exec(query, valueArgs...)
-        if res.Error != nil {
-            return fmt.Errorf("failed to batch update status: %w", res.Error)
-        }
-        if res.RowsAffected == 0 {
-            return fmt.Errorf("no job applications were updated (check user permissions and record existence)")
-        }
-        return nil
-    }
-    result, err := s.db.Exec(query, valueArgs...)
+	rowsAffected, err := s.execBatch(query, valueArgs...)
 	if err != nil {
 		return fmt.Errorf("failed to batch update status: %w", err)
 	}
 
-	// 检查更新的行数
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
 	if rowsAffected == 0 {
 		return fmt.Errorf("no job applications were updated (check user permissions and record existence)")
 	}
@@ -912,72 +1127,136 @@ func (s *JobApplicationService) BatchUpdateStatus(userID uint, updates []model.B
 	return nil
 }
 
-// BatchDelete 批量删除记录 - 高性能批量删除
-func (s *JobApplicationService) BatchDelete(userID uint, ids []int) error {
+// BatchDelete 批量删除记录（移入回收站），记录为可撤销的批量操作
+func (s *JobApplicationService) BatchDelete(userID uint, ids []int) (*model.BatchOperationResult, error) {
 	if len(ids) == 0 {
-		return nil
+		return &model.BatchOperationResult{}, nil
 	}
 
 	// 限制批量操作的数量
 	if len(ids) > 100 {
-		return fmt.Errorf("batch size too large: maximum 100 deletions allowed, got %d", len(ids))
+		return nil, fmt.Errorf("batch size too large: maximum 100 deletions allowed, got %d", len(ids))
+	}
+
+	var result *model.BatchOperationResult
+	err := s.withBatchTx(func(q operationQueryer) error {
+		var err error
+		result, _, err = softDeleteInTx(q, userID, ids)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch delete job applications: %w", err)
+	}
+
+	if result.AffectedCount == 0 {
+		return nil, fmt.Errorf("no job applications were deleted (check user permissions and record existence)")
 	}
 
-	// 构建 IN 子句的占位符
+	return result, nil
+}
+
+// softDeleteInTx 在事务内软删除（移入回收站）并记录为可撤销的操作，返回不存在或无权限的ID
+func softDeleteInTx(q operationQueryer, userID uint, ids []int) (*model.BatchOperationResult, []int, error) {
+	// 先记录快照，只处理尚未删除的记录
+	recorder := newOperationRecorder(q, userID)
 	var placeholders []string
-	var args []interface{}
-	
-	for i, id := range ids {
-		placeholders = append(placeholders, fmt.Sprintf("$%d", i+2)) // +2 因为 $1 是 userID
+	var missing []int
+	args := []interface{}{userID}
+	for _, id := range ids {
+		found, err := recorder.capture(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			missing = append(missing, id)
+			continue
+		}
 		args = append(args, id)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	if len(placeholders) == 0 {
+		return &model.BatchOperationResult{}, missing, nil
 	}
 
-	// 构建删除查询
+	// 构建删除查询（软删除，移入回收站）
 	query := fmt.Sprintf(`
-		DELETE FROM job_applications 
-		WHERE user_id = $1 AND id IN (%s)
+		UPDATE job_applications
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NULL AND id IN (%s)
 	`, strings.Join(placeholders, ", "))
+	if _, err := q.Exec(query, args...); err != nil {
+		return nil, nil, err
+	}
 
-	// userID 作为第一个参数
-	allArgs := append([]interface{}{userID}, args...)
+	result, err := recorder.save(model.OperationBatchDelete)
+	return result, missing, err
+}
 
+// execBatch 在事务内执行批量语句并标记为批量操作（审计日志记为 batch_*），返回影响行数
+func (s *JobApplicationService) execBatch(query string, args ...interface{}) (int64, error) {
+	var affected int64
+	err := s.withBatchTx(func(q operationQueryer) error {
+		result, err := q.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+// withBatchTx 在标记为批量操作的事务内执行 fn
+func (s *JobApplicationService) withBatchTx(fn func(q operationQueryer) error) error {
     if s.db.UseGorm && s.db.ORM != nil {
-        res := s.db.ORM.Exec(query, allArgs...)
-        if res.Error != nil { return fmt.Errorf("failed to batch delete job applications: %w", res.Error) }
-        if res.RowsAffected == 0 { return fmt.Errorf("no job applications were deleted (check user permissions and record existence)") }
-        return nil
+        return s.db.ORM.Transaction(func(tx *gorm.DB) error {
+            if err := tx.Exec(auditBatchSetting).Error; err != nil {
+                return err
+            }
+            return fn(gormOperationTx{tx: tx})
+        })
     }
-    result, err := s.db.Exec(query, allArgs...)
+	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to batch delete job applications: %w", err)
+		return err
 	}
-
-	// 检查删除的行数
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	defer tx.Rollback()
+	if _, err := tx.Exec(auditBatchSetting); err != nil {
+		return err
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("no job applications were deleted (check user permissions and record existence)")
+	if err := fn(tx); err != nil {
+		return err
 	}
-
-	return nil
+	return tx.Commit()
 }
 
-// SearchApplications 全文搜索投递记录 - 使用 GIN 索引优化
+// SearchApplications 全文搜索投递记录 - 使用搜索文档的三元组 GIN 索引优化
 func (s *JobApplicationService) SearchApplications(userID uint, searchQuery string, req model.PaginationRequest) (*model.PaginationResponse, error) {
 	// 验证并设置默认值
 	req.ValidateAndSetDefaults()
 	
-	if searchQuery == "" {
+	// 解析查询语句：自由文本走全文检索，字段条件与列表筛选共用同一构造器
+	parsed, err := querylang.Parse(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	tokens := tokenizeSearch(parsed.FreeText())
+	if len(tokens) == 0 {
+		req.Query = searchQuery
+		if !parsed.IsEmpty() || len(req.TagIDs) > 0 || req.StartDate != "" || req.EndDate != "" || req.SalaryMin != nil || req.SalaryMax != nil {
+			return s.GetJobApplicationsWithStatusFilters(userID, req.Status, nil, req)
+		}
 		return s.GetAllPaginated(userID, req)
 	}
 
-	// 构建全文搜索查询
-	whereClause := "WHERE user_id = $1 AND to_tsvector('simple', COALESCE(company_name, '') || ' ' || COALESCE(position_title, '')) @@ plainto_tsquery('simple', $2)"
-	args := []interface{}{userID, searchQuery}
-	argIndex := 3
+	// 构建检索条件：覆盖公司、职位、地点、HR、备注与 JD，支持中文子串/二元组与拼写容错
+	// 查询语句中的字段条件一并加入
+	searchCondition, rankExpr, args, argIndex, err := s.searchFilterCondition(searchQuery, 2)
+	if err != nil {
+		return nil, err
+	}
+	whereClause := "WHERE user_id = $1 AND deleted_at IS NULL" + searchCondition
+	args = append([]interface{}{userID}, args...)
 
 	// 添加状态筛选
 	if req.Status != nil {
@@ -986,10 +1265,30 @@ func (s *JobApplicationService) SearchApplications(userID uint, searchQuery stri
 		argIndex++
 	}
 
+	// 添加标签筛选
+	tagCondition, tagArgs, argIndex := tagFilterCondition(req.TagIDs, argIndex)
+	whereClause += tagCondition
+	args = append(args, tagArgs...)
+
+	// 添加投递日期范围筛选
+	dateCondition, dateArgs, argIndex, err := dateRangeCondition(req.StartDate, req.EndDate, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	whereClause += dateCondition
+	args = append(args, dateArgs...)
+
+	// 添加月薪区间筛选
+	salaryCondition, salaryArgs, argIndex, err := salaryRangeCondition(req.SalaryMin, req.SalaryMax, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	whereClause += salaryCondition
+	args = append(args, salaryArgs...)
+
 	// 1. 计数查询
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM job_applications %s", whereClause)
 	var total int64
-    var err error
     if s.db.UseGorm && s.db.ORM != nil {
         err = s.db.ORM.Raw(countQuery, args...).Row().Scan(&total)
     } else {
@@ -1031,14 +1330,14 @@ func (s *JobApplicationService) SearchApplications(userID uint, searchQuery stri
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at,
-			ts_rank_cd(to_tsvector('simple', COALESCE(company_name, '') || ' ' || COALESCE(position_title, '')), 
-					  plainto_tsquery('simple', $2)) as rank
+			created_at, updated_at, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD'), (%s) AS rank
 		FROM job_applications 
 		%s 
 		ORDER BY rank DESC, %s %s, created_at DESC 
 		LIMIT $%d OFFSET $%d
-	`, whereClause, req.SortBy, req.SortDir, argIndex, argIndex+1)
+	`, rankExpr, whereClause, req.SortBy, req.SortDir, argIndex, argIndex+1)
 
 	// 添加LIMIT和OFFSET参数
 	args = append(args, req.PageSize, req.GetOffset())
@@ -1083,13 +1382,30 @@ func (s *JobApplicationService) SearchApplications(userID uint, searchQuery stri
 			&job.InterviewType,
 			&job.CreatedAt,
 			&job.UpdatedAt,
+			&job.CustomFields,
+			&job.CompanyID,
+			&job.RoleFamily,
+			&job.RoleLevel,
+			&job.TechStack,
+			&job.SalaryMinMonthly,
+			&job.SalaryMaxMonthly,
+			&job.SalaryMonths,
+			&job.SalaryAnnualMin,
+			&job.SalaryAnnualMax,
+			&job.SalaryCurrency,
+			&job.DecisionDeadline,
 			&rank, // 搜索相关度分数
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan search result: %w", err)
 		}
+		job.SearchScore = &rank
+		job.Highlights = buildSearchHighlights(&job, tokens)
 		jobs = append(jobs, job)
 	}
+	if err := attachApplicationTags(s.db, userID, jobs); err != nil {
+		return nil, err
+	}
 
 	// 4. 计算分页信息
 	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
@@ -1121,7 +1437,7 @@ func (s *JobApplicationService) GetApplicationsByDateRange(userID uint, startDat
 	}
 
 	// 构建WHERE条件
-	whereClause := "WHERE user_id = $1"
+	whereClause := "WHERE user_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{userID}
 	argIndex := 2
 
@@ -1186,7 +1502,9 @@ func (s *JobApplicationService) GetApplicationsByDateRange(userID uint, startDat
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at
+			created_at, updated_at, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD')
 		FROM job_applications 
 		%s 
 		ORDER BY %s %s, created_at DESC 
@@ -1230,6 +1548,18 @@ func (s *JobApplicationService) GetApplicationsByDateRange(userID uint, startDat
 			&job.InterviewType,
 			&job.CreatedAt,
 			&job.UpdatedAt,
+			&job.CustomFields,
+			&job.CompanyID,
+			&job.RoleFamily,
+			&job.RoleLevel,
+			&job.TechStack,
+			&job.SalaryMinMonthly,
+			&job.SalaryMaxMonthly,
+			&job.SalaryMonths,
+			&job.SalaryAnnualMin,
+			&job.SalaryAnnualMax,
+			&job.SalaryCurrency,
+			&job.DecisionDeadline,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan application by date range: %w", err)
@@ -1272,13 +1602,65 @@ func isValidDate(date string) bool {
 	return true
 }
 
+// dateRangeCondition 构建投递日期范围条件，任一端可省略
+func dateRangeCondition(startDate, endDate string, argIndex int) (string, []interface{}, int, error) {
+	condition := ""
+	var args []interface{}
+	if startDate != "" {
+		if !isValidDate(startDate) {
+			return "", nil, argIndex, fmt.Errorf("invalid start date format: %s", startDate)
+		}
+		condition += fmt.Sprintf(" AND application_date >= $%d", argIndex)
+		args = append(args, startDate)
+		argIndex++
+	}
+	if endDate != "" {
+		if !isValidDate(endDate) {
+			return "", nil, argIndex, fmt.Errorf("invalid end date format: %s", endDate)
+		}
+		condition += fmt.Sprintf(" AND application_date <= $%d", argIndex)
+		args = append(args, endDate)
+		argIndex++
+	}
+	return condition, args, argIndex, nil
+}
+
+// queryFilterCondition 解析查询语句并生成 WHERE 片段，语法错误以 *querylang.Error 返回
+func queryFilterCondition(input string, argIndex int) (string, []interface{}, int, error) {
+	query, err := querylang.Parse(input)
+	if err != nil {
+		return "", nil, argIndex, err
+	}
+	condition, args, next := query.Conditions(argIndex)
+	return condition, args, next, nil
+}
+
+// QueryFields 返回查询语言的字段目录，tag 字段的候选值为用户的标签名
+func (s *JobApplicationService) QueryFields(userID uint) ([]querylang.FieldInfo, error) {
+	rows, err := s.db.Query("SELECT name FROM tags WHERE user_id = $1 ORDER BY LOWER(name)", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tagNames []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tagNames = append(tagNames, name)
+	}
+	return querylang.Catalog(tagNames), rows.Err()
+}
+
 // GetJobApplicationsWithStatusFilters 根据状态和阶段筛选岗位申请
 func (s *JobApplicationService) GetJobApplicationsWithStatusFilters(userID uint, status *model.ApplicationStatus, stage *string, req model.PaginationRequest) (*model.PaginationResponse, error) {
 	// 验证并设置默认值
 	req.ValidateAndSetDefaults()
 
 	// 构建WHERE条件
-	whereClause := "WHERE user_id = $1"
+	whereClause := "WHERE user_id = $1 AND deleted_at IS NULL"
 	args := []interface{}{userID}
 	argIndex := 2
 
@@ -1303,10 +1685,47 @@ func (s *JobApplicationService) GetJobApplicationsWithStatusFilters(userID uint,
 		}
 	}
 
+	// 添加标签筛选
+	tagCondition, tagArgs, argIndex := tagFilterCondition(req.TagIDs, argIndex)
+	whereClause += tagCondition
+	args = append(args, tagArgs...)
+
+	// 添加投递日期范围筛选
+	dateCondition, dateArgs, argIndex, err := dateRangeCondition(req.StartDate, req.EndDate, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	whereClause += dateCondition
+	args = append(args, dateArgs...)
+
+	// 添加月薪区间筛选
+	salaryCondition, salaryArgs, argIndex, err := salaryRangeCondition(req.SalaryMin, req.SalaryMax, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	whereClause += salaryCondition
+	args = append(args, salaryArgs...)
+
+	// 添加自定义字段筛选
+	customCondition, customArgs, argIndex, customSort, err := s.customFieldListFilters(userID, &req, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	whereClause += customCondition
+	args = append(args, customArgs...)
+
+	// 添加查询语句条件
+	queryCondition, queryArgs, argIndex, err := queryFilterCondition(req.Query, argIndex)
+	if err != nil {
+		return nil, err
+	}
+	whereClause += queryCondition
+	args = append(args, queryArgs...)
+
 	// 1. 计数查询
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM job_applications %s", whereClause)
 	var total int64
-	err := s.db.QueryRow(countQuery, args...).Scan(&total)
+	err = s.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count filtered job applications: %w", err)
 	}
@@ -1326,15 +1745,20 @@ func (s *JobApplicationService) GetJobApplicationsWithStatusFilters(userID uint,
 
 	// 2. 验证排序字段安全性
 	allowedSortFields := map[string]bool{
-		"application_date": true,
-		"created_at":       true,
-		"updated_at":       true,
-		"company_name":     true,
-		"position_title":   true,
-		"status":           true,
-	}
-	if !allowedSortFields[req.SortBy] {
-		req.SortBy = "application_date"
+		"application_date":    true,
+		"created_at":          true,
+		"updated_at":          true,
+		"company_name":        true,
+		"position_title":      true,
+		"status":              true,
+		"success_probability": true,
+	}
+	orderBy := customSort
+	if orderBy == "" {
+		if !allowedSortFields[req.SortBy] {
+			req.SortBy = "application_date"
+		}
+		orderBy = req.SortExpression()
 	}
 
 	// 3. 数据查询
@@ -1343,12 +1767,14 @@ func (s *JobApplicationService) GetJobApplicationsWithStatusFilters(userID uint,
 			job_description, salary_range, work_location, contact_info, notes,
 			interview_time, reminder_time, reminder_enabled, follow_up_date,
 			hr_name, hr_phone, hr_email, interview_location, interview_type,
-			created_at, updated_at
+			created_at, updated_at, custom_fields, company_id, role_family, role_level, tech_stack,
+			salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency,
+			to_char(decision_deadline, 'YYYY-MM-DD')
 		FROM job_applications 
 		%s 
-		ORDER BY %s %s, created_at DESC 
+		ORDER BY %s, created_at DESC 
 		LIMIT $%d OFFSET $%d
-	`, whereClause, req.SortBy, req.SortDir, argIndex, argIndex+1)
+	`, whereClause, orderBy, argIndex, argIndex+1)
 
 	// 添加LIMIT和OFFSET参数
 	args = append(args, req.PageSize, req.GetOffset())
@@ -1387,12 +1813,27 @@ func (s *JobApplicationService) GetJobApplicationsWithStatusFilters(userID uint,
 			&job.InterviewType,
 			&job.CreatedAt,
 			&job.UpdatedAt,
+			&job.CustomFields,
+			&job.CompanyID,
+			&job.RoleFamily,
+			&job.RoleLevel,
+			&job.TechStack,
+			&job.SalaryMinMonthly,
+			&job.SalaryMaxMonthly,
+			&job.SalaryMonths,
+			&job.SalaryAnnualMin,
+			&job.SalaryAnnualMax,
+			&job.SalaryCurrency,
+			&job.DecisionDeadline,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan filtered job application: %w", err)
 		}
 		jobs = append(jobs, job)
 	}
+	if err := attachApplicationTags(s.db, userID, jobs); err != nil {
+		return nil, err
+	}
 
 	// 4. 计算分页信息
 	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
@@ -1412,29 +1853,7 @@ func (s *JobApplicationService) GetJobApplicationsWithStatusFilters(userID uint,
 
 // getStatusesByStage 根据阶段获取对应的状态列表
 func (s *JobApplicationService) getStatusesByStage(stage string) []string {
-	stageMap := map[string][]string{
-		"application": {"已投递"},
-		"screening": {"简历筛选中", "简历筛选未通过"},
-		"written_test": {"笔试中", "笔试通过", "笔试未通过"},
-		"interviews": {
-			"一面中", "一面通过", "一面未通过",
-			"二面中", "二面通过", "二面未通过",
-			"三面中", "三面通过", "三面未通过",
-			"HR面中", "HR面通过", "HR面未通过",
-		},
-		"final": {
-			"待发offer", "已收到offer", "已接受offer",
-			"已拒绝", "流程结束",
-		},
-		"in_progress": {"已投递", "简历筛选中", "笔试中", "一面中", "二面中", "三面中", "HR面中"},
-		"passed": {"笔试通过", "一面通过", "二面通过", "三面通过", "HR面通过", "待发offer", "已收到offer", "已接受offer", "流程结束"},
-		"failed": {"简历筛选未通过", "笔试未通过", "一面未通过", "二面未通过", "三面未通过", "HR面未通过", "已拒绝"},
-	}
-
-	if statuses, exists := stageMap[stage]; exists {
-		return statuses
-	}
-	return []string{}
+	return model.StatusesForStage(stage)
 }
 
 // GetDashboardData 获取仪表板数据
@@ -1449,7 +1868,7 @@ func (s *JobApplicationService) GetDashboardData(userID uint) (map[string]interf
 	recentQuery := `
 		SELECT id, company_name, position_title, status, updated_at
 		FROM job_applications
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 		LIMIT 10
 	`
@@ -1489,7 +1908,7 @@ func (s *JobApplicationService) GetDashboardData(userID uint) (map[string]interf
 	upcomingQuery := `
 		SELECT id, company_name, position_title, interview_time, interview_type
 		FROM job_applications
-		WHERE user_id = $1 AND interview_time > NOW() AND interview_time <= NOW() + INTERVAL '7 days'
+		WHERE user_id = $1 AND deleted_at IS NULL AND interview_time > NOW() AND interview_time <= NOW() + INTERVAL '7 days'
 		ORDER BY interview_time ASC
 		LIMIT 5
 	`
@@ -1535,7 +1954,7 @@ func (s *JobApplicationService) GetDashboardData(userID uint) (map[string]interf
 	dailyStatsQuery := `
 		SELECT DATE(created_at) as date, COUNT(*) as count
 		FROM job_applications
-		WHERE user_id = $1 AND created_at >= CURRENT_DATE - INTERVAL '30 days'
+		WHERE user_id = $1 AND deleted_at IS NULL AND created_at >= CURRENT_DATE - INTERVAL '30 days'
 		GROUP BY DATE(created_at)
 		ORDER BY date DESC
 	`
@@ -1567,14 +1986,90 @@ func (s *JobApplicationService) GetDashboardData(userID uint) (map[string]interf
 		})
 	}
 
+	// 获取按标签的统计
+	tagStatistics, err := s.getTagStatistics(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag statistics: %w", err)
+	}
+
+	// 获取固定到仪表板的视图
+	pinnedViews, err := s.getPinnedSavedViews(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned views: %w", err)
+	}
+
+	// 获取待答复的 offer
+	pendingDecisions, err := s.getPendingDecisions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending decisions: %w", err)
+	}
+
+	// 获取 7 天内到期的联系人跟进提醒
+	contactFollowUps, err := s.getContactFollowUps(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact follow-ups: %w", err)
+	}
+
 	// 构建仪表板数据
 	dashboard := map[string]interface{}{
 		"statistics":         statistics,
 		"recent_applications": recentApplications,
 		"upcoming_interviews": upcomingInterviews,
 		"daily_stats":        dailyStats,
+		"tag_statistics":     tagStatistics,
+		"pinned_views":       pinnedViews,
+		"pending_decisions":  pendingDecisions,
+		"contact_follow_ups": contactFollowUps,
 		"generated_at":       time.Now(),
 	}
 
 	return dashboard, nil
 }
+
+// getTagStatistics 按标签统计进行中/已通过/未通过的申请数，阶段划分与 getStatusesByStage 一致
+func (s *JobApplicationService) getTagStatistics(userID uint) ([]model.TagStatistics, error) {
+	args := []interface{}{userID}
+	argIndex := 2
+	stageFilters := make([]string, 0, 3)
+	for _, stage := range []string{"in_progress", "passed", "failed"} {
+		statuses := s.getStatusesByStage(stage)
+		placeholders := make([]string, len(statuses))
+		for i, status := range statuses {
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, status)
+			argIndex++
+		}
+		stageFilters = append(stageFilters,
+			fmt.Sprintf("COUNT(ja.id) FILTER (WHERE ja.status IN (%s))", strings.Join(placeholders, ", ")))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.name, t.color, COUNT(ja.id), %s
+		FROM tags t
+		LEFT JOIN job_application_tags jt ON jt.tag_id = t.id
+		LEFT JOIN job_applications ja ON ja.id = jt.job_application_id AND ja.deleted_at IS NULL
+		WHERE t.user_id = $1
+		GROUP BY t.id, t.name, t.color
+		ORDER BY COUNT(ja.id) DESC, LOWER(t.name)
+	`, strings.Join(stageFilters, ", "))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []model.TagStatistics{}
+	for rows.Next() {
+		var stat model.TagStatistics
+		if err := rows.Scan(&stat.TagID, &stat.Name, &stat.Color, &stat.Total,
+			&stat.InProgress, &stat.Passed, &stat.Failed); err != nil {
+			return nil, err
+		}
+		if finished := stat.Passed + stat.Failed; finished > 0 {
+			stat.PassRate = float64(stat.Passed) / float64(finished) * 100
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
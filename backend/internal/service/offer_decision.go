@@ -0,0 +1,342 @@
+// This file implements offer decision tracking: the response deadline of applications that have
+// received an offer, escalating reminders 7/3/1 days before it, the pending-decisions list shown
+// on the dashboard, and recording accept/decline, which moves the application to 已接受offer or
+// 流程结束 with a note.
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// offerReminderInterval 答复截止提醒的检查间隔
+const offerReminderInterval = time.Hour
+
+// offerDeadlineReminder 一条即将到期的答复截止日期及其已发送过的提醒
+type offerDeadlineReminder struct {
+	JobApplicationID int
+	UserID           uint
+	CompanyName      string
+	PositionTitle    string
+	Deadline         string
+	DaysLeft         int
+	Sent             []int // 针对当前截止日期已发送的提前天数
+}
+
+// SetDecisionDeadline 设置或清除申请的答复截止日期，返回写入后的日期（清除时为 nil）。
+// 只有已收到offer的申请可以设置截止日期，清除不受限制
+func (s *OfferService) SetDecisionDeadline(userID uint, jobApplicationID int, deadline *string) (*string, error) {
+	values, err := normalizeOfferTerms(&model.UpdateOfferRequest{Deadline: deadline})
+	if err != nil {
+		return nil, err
+	}
+	value := values["deadline"]
+	status, err := s.applicationStatus(userID, jobApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDecisionDeadline(status, value); err != nil {
+		return nil, err
+	}
+	if err := setDecisionDeadline(s.db, userID, jobApplicationID, value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	date := value.(string)
+	return &date, nil
+}
+
+// RecordDecision 记录接受/拒绝 offer：申请推进到已接受offer或流程结束并添加备注，
+// 已登记的 offer 条款同步更新决策状态；三者在同一事务内完成
+func (s *OfferService) RecordDecision(userID uint, jobApplicationID int, req *model.OfferDecisionRequest) (*model.JobApplication, error) {
+	if _, _, ok := offerDecisionTransition(req.Decision); !ok {
+		return nil, fmt.Errorf("invalid decision: must be %s or %s", model.OfferAccepted, model.OfferDeclined)
+	}
+	note, err := normalizeOfferNote(&req.Note)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.applicationStatus(userID, jobApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if status != model.StatusOfferReceived {
+		return nil, fmt.Errorf("invalid decision: application status is %s, only %s can be accepted or declined",
+			status, model.StatusOfferReceived)
+	}
+
+	return s.moveToDecision(userID, jobApplicationID, req.Decision, note)
+}
+
+// applyOfferDecision offer 决策状态改为 accepted/declined 时推进申请状态；
+// 申请已不处于已收到offer时只保留 offer 上的决策状态
+func (s *OfferService) applyOfferDecision(userID uint, jobApplicationID int, decision model.OfferDecisionStatus, note *string) error {
+	if _, _, ok := offerDecisionTransition(decision); !ok {
+		return nil
+	}
+	status, err := s.applicationStatus(userID, jobApplicationID)
+	if err != nil {
+		return err
+	}
+	if status != model.StatusOfferReceived {
+		return nil
+	}
+	_, err = s.moveToDecision(userID, jobApplicationID, decision, note)
+	return err
+}
+
+// moveToDecision 按决策推进申请状态，并在同一事务内将备注写入状态历史与时间线、同步 offer 的决策状态
+func (s *OfferService) moveToDecision(userID uint, jobApplicationID int, decision model.OfferDecisionStatus, note *string) (*model.JobApplication, error) {
+	target, text, _ := offerDecisionTransition(decision)
+	if note != nil {
+		text = *note
+	}
+	payload := map[string]interface{}{"source": "offer_decision", "decision": string(decision)}
+	job, err := s.statusService.updateJobStatusWith(userID, jobApplicationID, &model.StatusUpdateRequest{
+		Status:   target,
+		Note:     &text,
+		Metadata: map[string]interface{}{"source": "offer_decision", "decision": string(decision), "note": text},
+	}, func(q operationQueryer) error {
+		if err := recordActivity(q, userID, []int{jobApplicationID}, model.ActivityNote, text, payload); err != nil {
+			return err
+		}
+		if _, err := q.Exec(`
+			UPDATE offers SET decision_status = $1, updated_at = NOW()
+			WHERE job_application_id = $2 AND user_id = $3 AND decision_status <> $1
+		`, string(decision), jobApplicationID, userID); err != nil {
+			return fmt.Errorf("failed to update offer decision: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "status transition not allowed") {
+			return nil, fmt.Errorf("invalid decision: %v", err)
+		}
+		return nil, fmt.Errorf("failed to update application status: %w", err)
+	}
+	return job, nil
+}
+
+// applicationStatus 读取申请当前状态（校验归属）
+func (s *OfferService) applicationStatus(userID uint, jobApplicationID int) (model.ApplicationStatus, error) {
+	var status model.ApplicationStatus
+	err := s.db.QueryRow("SELECT status FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		jobApplicationID, userID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("job application not found or access denied")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get job application status: %w", err)
+	}
+	return status, nil
+}
+
+// StartReminderJob 每小时检查一次答复截止日期，按 7/3/1 天逐级发送提醒
+func (s *OfferService) StartReminderJob() {
+	go func() {
+		ticker := time.NewTicker(offerReminderInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			sent, err := s.SendDeadlineReminders()
+			if err != nil {
+				log.Printf("Warning: offer deadline reminders failed: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("[OFFER-DEADLINE] reminders=%d", sent)
+			}
+		}
+	}()
+}
+
+// SendDeadlineReminders 为所有用户即将到期的待答复 offer 发送提醒，提醒记入申请时间线；返回发送条数。
+// 每个截止日期的每一级提醒只发送一次，修改截止日期后重新计算
+func (s *OfferService) SendDeadlineReminders() (int, error) {
+	rows, err := s.db.Query(`
+		SELECT ja.id, ja.user_id, ja.company_name, ja.position_title,
+		       to_char(ja.decision_deadline, 'YYYY-MM-DD'), ja.decision_deadline - CURRENT_DATE,
+		       ARRAY(
+		           SELECT (aa.payload->>'days_before')::int
+		           FROM application_activities aa
+		           WHERE aa.job_application_id = ja.id AND aa.activity_type = $2
+		             AND aa.payload->>'deadline' = to_char(ja.decision_deadline, 'YYYY-MM-DD')
+		       )
+		FROM job_applications ja
+		WHERE ja.deleted_at IS NULL AND ja.status = $1
+		  AND ja.decision_deadline BETWEEN CURRENT_DATE AND CURRENT_DATE + $3::int
+	`, string(model.StatusOfferReceived), string(model.ActivityDeadlineReminder), model.OfferReminderDays[0])
+	if err != nil {
+		return 0, fmt.Errorf("failed to query decision deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []offerDeadlineReminder
+	for rows.Next() {
+		var reminder offerDeadlineReminder
+		var sent pq.Int64Array
+		if err := rows.Scan(&reminder.JobApplicationID, &reminder.UserID, &reminder.CompanyName, &reminder.PositionTitle,
+			&reminder.Deadline, &reminder.DaysLeft, &sent); err != nil {
+			return 0, fmt.Errorf("failed to scan decision deadline: %w", err)
+		}
+		for _, days := range sent {
+			reminder.Sent = append(reminder.Sent, int(days))
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate decision deadlines: %w", err)
+	}
+	rows.Close()
+
+	count := 0
+	for _, reminder := range reminders {
+		daysBefore, ok := dueDeadlineReminder(reminder.DaysLeft, reminder.Sent)
+		if !ok {
+			continue
+		}
+		summary := fmt.Sprintf("%s %s 的 offer 还有 %d 天截止答复（%s）",
+			reminder.CompanyName, reminder.PositionTitle, reminder.DaysLeft, reminder.Deadline)
+		if reminder.DaysLeft == 0 {
+			summary = fmt.Sprintf("%s %s 的 offer 今天截止答复", reminder.CompanyName, reminder.PositionTitle)
+		}
+		payload := map[string]interface{}{
+			"deadline":    reminder.Deadline,
+			"days_before": daysBefore,
+			"days_left":   reminder.DaysLeft,
+		}
+		if err := recordActivity(s.db, reminder.UserID, []int{reminder.JobApplicationID},
+			model.ActivityDeadlineReminder, summary, payload); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// getPendingDecisions 仪表板上的待答复 offer
+func (s *JobApplicationService) getPendingDecisions(userID uint) ([]model.PendingDecision, error) {
+	return listPendingDecisions(s.db, userID)
+}
+
+// listPendingDecisions 读取处于已收到offer的申请，按截止日期排序，未设置截止日期的在后
+func listPendingDecisions(db *database.DB, userID uint) ([]model.PendingDecision, error) {
+	rows, err := db.Query(`
+		SELECT ja.id, ja.company_name, ja.position_title, to_char(ja.decision_deadline, 'YYYY-MM-DD'),
+		       ja.decision_deadline - CURRENT_DATE, o.id, o.decision_status
+		FROM job_applications ja
+		LEFT JOIN offers o ON o.job_application_id = ja.id
+		WHERE ja.user_id = $1 AND ja.deleted_at IS NULL AND ja.status = $2
+		ORDER BY ja.decision_deadline NULLS LAST, ja.updated_at DESC
+	`, userID, string(model.StatusOfferReceived))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending decisions: %w", err)
+	}
+	defer rows.Close()
+
+	decisions := []model.PendingDecision{}
+	for rows.Next() {
+		var decision model.PendingDecision
+		var daysLeft sql.NullInt64
+		var offerID sql.NullInt64
+		var offerStatus sql.NullString
+		if err := rows.Scan(&decision.JobApplicationID, &decision.CompanyName, &decision.PositionTitle,
+			&decision.DecisionDeadline, &daysLeft, &offerID, &offerStatus); err != nil {
+			return nil, fmt.Errorf("failed to scan pending decision: %w", err)
+		}
+		if daysLeft.Valid {
+			days := int(daysLeft.Int64)
+			decision.DaysLeft = &days
+		}
+		if offerID.Valid {
+			id := int(offerID.Int64)
+			status := model.OfferDecisionStatus(offerStatus.String)
+			decision.OfferID, decision.OfferStatus = &id, &status
+		}
+		decision.Urgency = decisionUrgency(decision.DaysLeft)
+		decisions = append(decisions, decision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// checkDecisionDeadline 只有已收到offer的申请可以设置答复截止日期，deadline 为 nil 表示清除
+func checkDecisionDeadline(status model.ApplicationStatus, deadline interface{}) error {
+	if deadline != nil && status != model.StatusOfferReceived {
+		return fmt.Errorf("invalid deadline: only applications in %s can have a decision deadline", model.StatusOfferReceived)
+	}
+	return nil
+}
+
+// setDecisionDeadline 写入申请的答复截止日期，deadline 为 nil 时清除
+func setDecisionDeadline(db activityExecer, userID uint, jobApplicationID int, deadline interface{}) error {
+	if _, err := db.Exec(`
+		UPDATE job_applications SET decision_deadline = $1::date, updated_at = NOW()
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+	`, deadline, jobApplicationID, userID); err != nil {
+		return fmt.Errorf("failed to update decision deadline: %w", err)
+	}
+	return nil
+}
+
+// offerDecisionTransition 决策对应的申请目标状态与默认备注
+func offerDecisionTransition(decision model.OfferDecisionStatus) (model.ApplicationStatus, string, bool) {
+	switch decision {
+	case model.OfferAccepted:
+		return model.StatusOfferAccepted, "接受 offer", true
+	case model.OfferDeclined:
+		return model.StatusProcessFinished, "拒绝 offer", true
+	}
+	return "", "", false
+}
+
+// dueDeadlineReminder 距截止日期 daysLeft 天时应发送的提醒级别（提前天数）。
+// 只发送当前最紧急的一级，已发送过或已逾期时不再发送；截止前 1 天与当天都属于 1 天这一级
+func dueDeadlineReminder(daysLeft int, sent []int) (int, bool) {
+	if daysLeft < 0 {
+		return 0, false
+	}
+	due := 0
+	for _, days := range model.OfferReminderDays {
+		if daysLeft <= days && (due == 0 || days < due) {
+			due = days
+		}
+	}
+	if due == 0 {
+		return 0, false
+	}
+	for _, days := range sent {
+		if days <= due {
+			return 0, false
+		}
+	}
+	return due, true
+}
+
+// decisionUrgency 按剩余天数划分紧急程度，未设置截止日期视为 low
+func decisionUrgency(daysLeft *int) model.DecisionUrgency {
+	switch {
+	case daysLeft == nil:
+		return model.UrgencyLow
+	case *daysLeft < 0:
+		return model.UrgencyOverdue
+	case *daysLeft <= 1:
+		return model.UrgencyCritical
+	case *daysLeft <= 3:
+		return model.UrgencyHigh
+	case *daysLeft <= 7:
+		return model.UrgencyMedium
+	}
+	return model.UrgencyLow
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"jobView-backend/internal/model"
+)
+
+func TestDueDeadlineReminder(t *testing.T) {
+	cases := []struct {
+		daysLeft int
+		sent     []int
+		want     int
+		ok       bool
+	}{
+		{daysLeft: 10, want: 0, ok: false},
+		{daysLeft: 7, want: 7, ok: true},
+		{daysLeft: 5, sent: []int{7}, want: 0, ok: false},
+		{daysLeft: 3, sent: []int{7}, want: 3, ok: true},
+		{daysLeft: 2, want: 3, ok: true}, // 截止日期设置得较晚时直接发送当前一级
+		{daysLeft: 1, sent: []int{7, 3}, want: 1, ok: true},
+		{daysLeft: 0, sent: []int{7, 3}, want: 1, ok: true},
+		{daysLeft: 0, sent: []int{1}, want: 0, ok: false},
+		{daysLeft: -1, want: 0, ok: false},
+	}
+	for _, c := range cases {
+		got, ok := dueDeadlineReminder(c.daysLeft, c.sent)
+		if got != c.want || ok != c.ok {
+			t.Errorf("dueDeadlineReminder(%d, %v) = %d, %v; want %d, %v", c.daysLeft, c.sent, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestDecisionUrgency(t *testing.T) {
+	days := func(v int) *int { return &v }
+	cases := []struct {
+		daysLeft *int
+		want     model.DecisionUrgency
+	}{
+		{nil, model.UrgencyLow},
+		{days(-2), model.UrgencyOverdue},
+		{days(0), model.UrgencyCritical},
+		{days(1), model.UrgencyCritical},
+		{days(3), model.UrgencyHigh},
+		{days(7), model.UrgencyMedium},
+		{days(8), model.UrgencyLow},
+	}
+	for _, c := range cases {
+		if got := decisionUrgency(c.daysLeft); got != c.want {
+			t.Errorf("decisionUrgency(%v) = %s, want %s", c.daysLeft, got, c.want)
+		}
+	}
+}
+
+func TestOfferDecisionTransition(t *testing.T) {
+	if status, note, ok := offerDecisionTransition(model.OfferAccepted); !ok || status != model.StatusOfferAccepted || note == "" {
+		t.Fatalf("unexpected transition for accepted: %s %q %v", status, note, ok)
+	}
+	if status, _, ok := offerDecisionTransition(model.OfferDeclined); !ok || status != model.StatusProcessFinished {
+		t.Fatalf("unexpected transition for declined: %s %v", status, ok)
+	}
+	if _, _, ok := offerDecisionTransition(model.OfferNegotiating); ok {
+		t.Fatalf("expected no transition for negotiating")
+	}
+
+	if err := checkDecisionDeadline(model.StatusHRInterview, "2025-10-20"); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected deadline to be rejected outside offer status, got %v", err)
+	}
+	if err := checkDecisionDeadline(model.StatusHRInterview, nil); err != nil {
+		t.Fatalf("expected clearing deadline to be allowed, got %v", err)
+	}
+	if err := checkDecisionDeadline(model.StatusOfferReceived, "2025-10-20"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,757 @@
+// This file implements offers attached to job applications: offer terms with an automatically
+// recorded negotiation history, and a weighted side-by-side comparison of several offers that
+// can be downloaded as an Excel decision matrix.
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/excel"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/salary"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+const (
+	maxOfferNotesLength   = 2000
+	maxOfferBenefits      = 30
+	maxOfferBenefitLength = 50
+	maxOfferRatings       = 20
+	maxOfferCriteria      = 20
+	maxComparedOffers     = 10
+	offerColumns          = `o.id, o.user_id, o.job_application_id, ja.company_name, ja.position_title, o.currency,
+		o.base_monthly, o.salary_months, o.annual_bonus, o.sign_on_bonus, o.stock_value, o.vesting_years,
+		o.vesting_schedule, o.benefits, o.location, to_char(o.start_date, 'YYYY-MM-DD'), to_char(ja.decision_deadline, 'YYYY-MM-DD'),
+		o.decision_status, o.ratings, o.notes, o.created_at, o.updated_at`
+	offerFrom = " FROM offers o JOIN job_applications ja ON ja.id = o.job_application_id AND ja.deleted_at IS NULL"
+)
+
+// offerTermColumns 薪酬条款列，修改时记录到谈判历史
+var offerTermColumns = []string{"currency", "base_monthly", "salary_months", "annual_bonus", "sign_on_bonus", "stock_value", "vesting_years"}
+
+// ratingKeyPattern 自定义评分维度 key
+var ratingKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,29}$`)
+
+type OfferService struct {
+	db            *database.DB
+	statusService *StatusTrackingService
+}
+
+func NewOfferService(db *database.DB, statusService *StatusTrackingService) *OfferService {
+	return &OfferService{db: db, statusService: statusService}
+}
+
+// List 获取用户的 offer，status 非空时按决策状态过滤；按答复截止日期排序，未设置截止日期的在后
+func (s *OfferService) List(userID uint, status string) ([]model.Offer, error) {
+	query := "SELECT " + offerColumns + offerFrom + " WHERE o.user_id = $1"
+	args := []interface{}{userID}
+	if status != "" {
+		if !model.OfferDecisionStatus(status).IsValid() {
+			return nil, fmt.Errorf("invalid decision_status: %s", status)
+		}
+		query += " AND o.decision_status = $2"
+		args = append(args, status)
+	}
+	query += " ORDER BY ja.decision_deadline NULLS LAST, o.created_at DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query offers: %w", err)
+	}
+	defer rows.Close()
+
+	offers := []model.Offer{}
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, *offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate offers: %w", err)
+	}
+	return offers, nil
+}
+
+// Get 获取 offer 详情（含谈判记录）
+func (s *OfferService) Get(userID uint, id int) (*model.Offer, error) {
+	offer, err := s.load(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, offer_id, note, changes, created_at
+		FROM offer_negotiations
+		WHERE offer_id = $1
+		ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query offer negotiations: %w", err)
+	}
+	defer rows.Close()
+
+	offer.Negotiations = []model.OfferNegotiation{}
+	for rows.Next() {
+		var negotiation model.OfferNegotiation
+		if err := rows.Scan(&negotiation.ID, &negotiation.OfferID, &negotiation.Note, &negotiation.Changes, &negotiation.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan offer negotiation: %w", err)
+		}
+		offer.Negotiations = append(offer.Negotiations, negotiation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate offer negotiations: %w", err)
+	}
+	return offer, nil
+}
+
+// Create 为申请登记 offer，每个申请至多一个 offer
+func (s *OfferService) Create(userID uint, req *model.CreateOfferRequest) (*model.Offer, error) {
+	if req.BaseMonthly <= 0 {
+		return nil, fmt.Errorf("invalid base_monthly: must be greater than 0")
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = salary.DefaultCurrency
+	}
+	ratings := req.Ratings
+	if ratings == nil {
+		ratings = map[string]float64{}
+	}
+	values, err := normalizeOfferTerms(&model.UpdateOfferRequest{
+		Currency: &currency, BaseMonthly: &req.BaseMonthly, SalaryMonths: req.SalaryMonths,
+		AnnualBonus: req.AnnualBonus, SignOnBonus: req.SignOnBonus, StockValue: req.StockValue,
+		VestingYears: req.VestingYears, VestingSchedule: req.VestingSchedule, Benefits: req.Benefits,
+		Location: req.Location, StartDate: req.StartDate, Deadline: req.Deadline,
+		DecisionStatus: req.DecisionStatus, Ratings: ratings, Notes: req.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := values["salary_months"]; !ok {
+		values["salary_months"] = 12.0
+	}
+	if _, ok := values["decision_status"]; !ok {
+		values["decision_status"] = string(model.OfferPending)
+	}
+	if _, ok := values["benefits"]; !ok {
+		values["benefits"] = model.StringList{}
+	}
+
+	deadline, hasDeadline := values["deadline"]
+	delete(values, "deadline")
+	status, err := s.applicationStatus(userID, req.JobApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if hasDeadline {
+		if err := checkDecisionDeadline(status, deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	columns := []string{"user_id", "job_application_id"}
+	placeholders := []string{"$1", "$2"}
+	args := []interface{}{userID, req.JobApplicationID}
+	for _, column := range offerWritableColumns {
+		value, ok := values[column]
+		if !ok {
+			continue
+		}
+		args = append(args, value)
+		columns = append(columns, column)
+		placeholders = append(placeholders, offerPlaceholder(column, len(args)))
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(fmt.Sprintf("INSERT INTO offers (%s) VALUES (%s) RETURNING id",
+		strings.Join(columns, ", "), strings.Join(placeholders, ", ")), args...).Scan(&id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("offer for this job application already exists")
+		}
+		return nil, fmt.Errorf("failed to create offer: %w", err)
+	}
+	if hasDeadline {
+		if err := setDecisionDeadline(tx, userID, req.JobApplicationID, deadline); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit offer: %w", err)
+	}
+	return s.Get(userID, id)
+}
+
+// Update 修改 offer。薪酬条款的变化连同 negotiation_note 记录为一条谈判记录；
+// decision_status 改为 accepted/declined 时按 RecordDecision 的规则推进申请状态
+func (s *OfferService) Update(userID uint, id int, req *model.UpdateOfferRequest) (*model.Offer, error) {
+	current, err := s.load(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	values, err := normalizeOfferTerms(req)
+	if err != nil {
+		return nil, err
+	}
+	note, err := normalizeOfferNote(req.NegotiationNote)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 && note == nil {
+		return nil, fmt.Errorf("invalid request: no fields to update")
+	}
+
+	changes := offerTermChanges(current, values)
+	deadline, hasDeadline := values["deadline"]
+	delete(values, "deadline")
+	if hasDeadline {
+		status, err := s.applicationStatus(userID, current.JobApplicationID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkDecisionDeadline(status, deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(values) > 0 {
+		setParts := []string{}
+		args := []interface{}{}
+		for _, column := range offerWritableColumns {
+			value, ok := values[column]
+			if !ok {
+				continue
+			}
+			args = append(args, value)
+			setParts = append(setParts, column+" = "+offerPlaceholder(column, len(args)))
+		}
+		query := fmt.Sprintf("UPDATE offers SET %s, updated_at = NOW() WHERE id = $%d AND user_id = $%d",
+			strings.Join(setParts, ", "), len(args)+1, len(args)+2)
+		args = append(args, id, userID)
+		if _, err := tx.Exec(query, args...); err != nil {
+			return nil, fmt.Errorf("failed to update offer: %w", err)
+		}
+	}
+	if hasDeadline {
+		if err := setDecisionDeadline(tx, userID, current.JobApplicationID, deadline); err != nil {
+			return nil, err
+		}
+	}
+	if len(changes) > 0 || note != nil {
+		if _, err := tx.Exec(`
+			INSERT INTO offer_negotiations (offer_id, user_id, note, changes) VALUES ($1, $2, $3, $4::jsonb)
+		`, id, userID, note, changes); err != nil {
+			return nil, fmt.Errorf("failed to record offer negotiation: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit offer: %w", err)
+	}
+
+	// 通过 offer 记录接受/拒绝时同步推进申请状态
+	if status, ok := values["decision_status"].(string); ok && status != string(current.DecisionStatus) {
+		if err := s.applyOfferDecision(userID, current.JobApplicationID, model.OfferDecisionStatus(status), note); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(userID, id)
+}
+
+// AddNegotiation 添加一条谈判沟通备注（不修改条款）
+func (s *OfferService) AddNegotiation(userID uint, id int, note string) (*model.Offer, error) {
+	if _, err := s.load(userID, id); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeOfferNote(&note)
+	if err != nil {
+		return nil, err
+	}
+	if normalized == nil {
+		return nil, fmt.Errorf("invalid note: must not be empty")
+	}
+	if _, err := s.db.Exec(`
+		INSERT INTO offer_negotiations (offer_id, user_id, note) VALUES ($1, $2, $3)
+	`, id, userID, *normalized); err != nil {
+		return nil, fmt.Errorf("failed to record offer negotiation: %w", err)
+	}
+	return s.Get(userID, id)
+}
+
+// Delete 删除 offer 及其谈判记录
+func (s *OfferService) Delete(userID uint, id int) error {
+	result, err := s.db.Exec("DELETE FROM offers WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete offer: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("offer not found or access denied")
+	}
+	return nil
+}
+
+// Compare 按加权维度对比多个 offer，返回排序后的决策矩阵
+func (s *OfferService) Compare(userID uint, req *model.OfferComparisonRequest) (*model.OfferComparison, error) {
+	ids := uniqueInts(req.OfferIDs)
+	if len(ids) < 2 || len(ids) > maxComparedOffers {
+		return nil, fmt.Errorf("invalid offer_ids: compare between 2 and %d offers", maxComparedOffers)
+	}
+
+	rows, err := s.db.Query("SELECT "+offerColumns+offerFrom+" WHERE o.user_id = $1 AND o.id = ANY($2)",
+		userID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query offers: %w", err)
+	}
+	defer rows.Close()
+
+	var offers []model.Offer
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, *offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate offers: %w", err)
+	}
+	if len(offers) != len(ids) {
+		return nil, fmt.Errorf("offer not found or access denied")
+	}
+
+	return buildOfferComparison(offers, req.Criteria)
+}
+
+// ExportComparison 生成 offer 对比的 Excel 决策矩阵
+func (s *OfferService) ExportComparison(userID uint, req *model.OfferComparisonRequest) ([]byte, error) {
+	comparison, err := s.Compare(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := excel.NewGenerator()
+	defer generator.Close()
+	if err := generator.WriteOfferComparison(comparison); err != nil {
+		return nil, fmt.Errorf("failed to write offer comparison: %w", err)
+	}
+	return generator.GetBuffer()
+}
+
+// load 读取 offer（校验归属）
+func (s *OfferService) load(userID uint, id int) (*model.Offer, error) {
+	offer, err := scanOffer(s.db.QueryRow("SELECT "+offerColumns+offerFrom+" WHERE o.id = $1 AND o.user_id = $2", id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("offer not found or access denied")
+	}
+	return offer, err
+}
+
+// scanOffer 扫描一行 offer 并计算年化薪酬
+func scanOffer(row rowScanner) (*model.Offer, error) {
+	var offer model.Offer
+	var vestingYears sql.NullInt64
+	err := row.Scan(&offer.ID, &offer.UserID, &offer.JobApplicationID, &offer.CompanyName, &offer.PositionTitle,
+		&offer.Currency, &offer.BaseMonthly, &offer.SalaryMonths, &offer.AnnualBonus, &offer.SignOnBonus,
+		&offer.StockValue, &vestingYears, &offer.VestingSchedule, &offer.Benefits, &offer.Location,
+		&offer.StartDate, &offer.Deadline, &offer.DecisionStatus, &offer.Ratings, &offer.Notes,
+		&offer.CreatedAt, &offer.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan offer: %w", err)
+	}
+	if vestingYears.Valid {
+		years := int(vestingYears.Int64)
+		offer.VestingYears = &years
+	}
+	if offer.Benefits == nil {
+		offer.Benefits = model.StringList{}
+	}
+	offer.Compute()
+	return &offer, nil
+}
+
+// offerWritableColumns 可写列，顺序决定 INSERT/UPDATE 中的参数顺序
+var offerWritableColumns = []string{
+	"currency", "base_monthly", "salary_months", "annual_bonus", "sign_on_bonus", "stock_value",
+	"vesting_years", "vesting_schedule", "benefits", "location", "start_date",
+	"decision_status", "ratings", "notes",
+}
+
+// offerPlaceholder 参数占位符，JSONB 与日期列附带类型转换
+func offerPlaceholder(column string, index int) string {
+	switch column {
+	case "benefits", "ratings":
+		return fmt.Sprintf("$%d::jsonb", index)
+	case "start_date":
+		return fmt.Sprintf("$%d::date", index)
+	}
+	return fmt.Sprintf("$%d", index)
+}
+
+// normalizeOfferTerms 校验条款，返回需要写入的列（nil 值表示清空）；deadline 写入申请的 decision_deadline
+func normalizeOfferTerms(req *model.UpdateOfferRequest) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if req.Currency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*req.Currency))
+		if !currencyCodePattern.MatchString(currency) {
+			return nil, fmt.Errorf("invalid currency: %s", *req.Currency)
+		}
+		values["currency"] = currency
+	}
+	if req.BaseMonthly != nil {
+		if *req.BaseMonthly <= 0 {
+			return nil, fmt.Errorf("invalid base_monthly: must be greater than 0")
+		}
+		values["base_monthly"] = *req.BaseMonthly
+	}
+	if req.SalaryMonths != nil {
+		if *req.SalaryMonths < 1 || *req.SalaryMonths > 24 {
+			return nil, fmt.Errorf("invalid salary_months: must be between 1 and 24")
+		}
+		values["salary_months"] = *req.SalaryMonths
+	}
+	amounts := []struct {
+		column string
+		value  *float64
+	}{
+		{"annual_bonus", req.AnnualBonus},
+		{"sign_on_bonus", req.SignOnBonus},
+		{"stock_value", req.StockValue},
+	}
+	for _, amount := range amounts {
+		switch {
+		case amount.value == nil:
+		case *amount.value < 0:
+			return nil, fmt.Errorf("invalid %s: must not be negative", amount.column)
+		case *amount.value == 0:
+			values[amount.column] = nil
+		default:
+			values[amount.column] = *amount.value
+		}
+	}
+	if req.VestingYears != nil {
+		switch {
+		case *req.VestingYears == 0:
+			values["vesting_years"] = nil
+		case *req.VestingYears < 1 || *req.VestingYears > 10:
+			return nil, fmt.Errorf("invalid vesting_years: must be between 1 and 10")
+		default:
+			values["vesting_years"] = *req.VestingYears
+		}
+	}
+
+	optional := func(column string, value *string, maxLength int) error {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		if utf8.RuneCountInString(trimmed) > maxLength {
+			return fmt.Errorf("invalid %s: at most %d characters", column, maxLength)
+		}
+		if trimmed == "" {
+			values[column] = nil
+		} else {
+			values[column] = trimmed
+		}
+		return nil
+	}
+	if err := optional("vesting_schedule", req.VestingSchedule, 255); err != nil {
+		return nil, err
+	}
+	if err := optional("location", req.Location, 255); err != nil {
+		return nil, err
+	}
+	if err := optional("notes", req.Notes, maxOfferNotesLength); err != nil {
+		return nil, err
+	}
+	for _, date := range []struct {
+		column string
+		value  *string
+	}{{"start_date", req.StartDate}, {"deadline", req.Deadline}} {
+		if err := optional(date.column, date.value, 10); err != nil {
+			return nil, err
+		}
+		if value, ok := values[date.column].(string); ok && !isValidDate(value) {
+			return nil, fmt.Errorf("invalid %s: must be YYYY-MM-DD", date.column)
+		}
+	}
+
+	if req.Benefits != nil {
+		if len(req.Benefits) > maxOfferBenefits {
+			return nil, fmt.Errorf("invalid benefits: at most %d items", maxOfferBenefits)
+		}
+		benefits := model.StringList{}
+		for _, benefit := range req.Benefits {
+			benefit = strings.TrimSpace(benefit)
+			if benefit == "" || containsString(benefits, benefit) {
+				continue
+			}
+			if utf8.RuneCountInString(benefit) > maxOfferBenefitLength {
+				return nil, fmt.Errorf("invalid benefits: each item at most %d characters", maxOfferBenefitLength)
+			}
+			benefits = append(benefits, benefit)
+		}
+		values["benefits"] = benefits
+	}
+	if req.DecisionStatus != nil {
+		if !req.DecisionStatus.IsValid() {
+			return nil, fmt.Errorf("invalid decision_status: %s", *req.DecisionStatus)
+		}
+		values["decision_status"] = string(*req.DecisionStatus)
+	}
+	if req.Ratings != nil {
+		if len(req.Ratings) > maxOfferRatings {
+			return nil, fmt.Errorf("invalid ratings: at most %d criteria", maxOfferRatings)
+		}
+		for key, score := range req.Ratings {
+			if !ratingKeyPattern.MatchString(key) || model.IsOfferMetric(key) {
+				return nil, fmt.Errorf("invalid ratings: key %q must be lowercase letters, digits or underscores and not a built-in metric", key)
+			}
+			if score < 0 || score > 10 {
+				return nil, fmt.Errorf("invalid ratings: %s must be between 0 and 10", key)
+			}
+		}
+		values["ratings"] = model.OfferRatings(req.Ratings)
+	}
+	return values, nil
+}
+
+// normalizeOfferNote 校验谈判备注，空白备注返回 nil
+func normalizeOfferNote(note *string) (*string, error) {
+	if note == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(trimmed) > maxOfferNotesLength {
+		return nil, fmt.Errorf("invalid note: at most %d characters", maxOfferNotesLength)
+	}
+	return &trimmed, nil
+}
+
+// offerTermChanges 比较当前条款与待写入的值，返回发生变化的薪酬条款
+func offerTermChanges(current *model.Offer, values map[string]interface{}) model.OfferTermChanges {
+	before := map[string]interface{}{
+		"currency":      current.Currency,
+		"base_monthly":  current.BaseMonthly,
+		"salary_months": current.SalaryMonths,
+		"annual_bonus":  floatValue(current.AnnualBonus),
+		"sign_on_bonus": floatValue(current.SignOnBonus),
+		"stock_value":   floatValue(current.StockValue),
+		"vesting_years": nil,
+	}
+	if current.VestingYears != nil {
+		before["vesting_years"] = *current.VestingYears
+	}
+
+	changes := model.OfferTermChanges{}
+	for _, column := range offerTermColumns {
+		after, ok := values[column]
+		if !ok || fmt.Sprint(after) == fmt.Sprint(before[column]) {
+			continue
+		}
+		changes[column] = model.OfferTermChange{From: before[column], To: after}
+	}
+	return changes
+}
+
+// floatValue 解引用可空数值，空值返回 nil
+func floatValue(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// uniqueInts 去重并保持原顺序
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// offerMetricValue offer 在某维度上的原始值：内置指标取薪酬，其余取自定义评分，未评分返回 nil
+func offerMetricValue(offer *model.Offer, key string) *float64 {
+	var value float64
+	switch key {
+	case "first_year_total":
+		value = offer.Compensation.FirstYearTotal
+	case "annual_total":
+		value = offer.Compensation.AnnualTotal
+	case "base_annual":
+		value = offer.Compensation.BaseAnnual
+	case "stock_annual":
+		value = offer.Compensation.StockAnnual
+	case "annual_bonus":
+		if offer.AnnualBonus != nil {
+			value = *offer.AnnualBonus
+		}
+	case "sign_on_bonus":
+		if offer.SignOnBonus != nil {
+			value = *offer.SignOnBonus
+		}
+	default:
+		score, ok := offer.Ratings[key]
+		if !ok {
+			return nil
+		}
+		value = score
+	}
+	return &value
+}
+
+// normalizeOfferCriteria 校验对比维度并将权重归一化为占比；未指定时使用默认维度
+func normalizeOfferCriteria(criteria []model.OfferCriterion, offers []model.Offer) ([]model.OfferCriterion, error) {
+	if len(criteria) == 0 {
+		criteria = model.DefaultOfferCriteria
+	}
+	if len(criteria) > maxOfferCriteria {
+		return nil, fmt.Errorf("invalid criteria: at most %d criteria", maxOfferCriteria)
+	}
+
+	labels := make(map[string]string)
+	for _, metric := range model.OfferMetrics {
+		labels[metric.Key] = metric.Label
+	}
+	rated := make(map[string]bool)
+	for _, offer := range offers {
+		for key := range offer.Ratings {
+			rated[key] = true
+		}
+	}
+
+	normalized := make([]model.OfferCriterion, 0, len(criteria))
+	seen := make(map[string]bool)
+	total := 0.0
+	for _, criterion := range criteria {
+		criterion.Key = strings.TrimSpace(criterion.Key)
+		if criterion.Key == "" || seen[criterion.Key] {
+			return nil, fmt.Errorf("invalid criteria: key %q is empty or duplicated", criterion.Key)
+		}
+		if _, builtin := labels[criterion.Key]; !builtin && !rated[criterion.Key] {
+			return nil, fmt.Errorf("invalid criteria: unknown key %q, use a built-in metric or a rating key", criterion.Key)
+		}
+		if criterion.Weight <= 0 || criterion.Weight > 100 {
+			return nil, fmt.Errorf("invalid criteria: weight of %s must be between 0 and 100", criterion.Key)
+		}
+		if criterion.Label = strings.TrimSpace(criterion.Label); criterion.Label == "" {
+			criterion.Label = criterion.Key
+			if label, ok := labels[criterion.Key]; ok {
+				criterion.Label = label
+			}
+		}
+		seen[criterion.Key] = true
+		total += criterion.Weight
+		normalized = append(normalized, criterion)
+	}
+	for i := range normalized {
+		normalized[i].Weight /= total
+	}
+	return normalized, nil
+}
+
+// buildOfferComparison 生成加权决策矩阵：每个维度按 min-max 归一化为 0-100（最优为 100，
+// 所有 offer 相同时均为 100，未评分为 0），总分为按权重占比的加权和
+func buildOfferComparison(offers []model.Offer, criteria []model.OfferCriterion) (*model.OfferComparison, error) {
+	for _, offer := range offers[1:] {
+		if offer.Currency != offers[0].Currency {
+			return nil, fmt.Errorf("invalid offer_ids: offers use different currencies (%s, %s)", offers[0].Currency, offer.Currency)
+		}
+	}
+	normalized, err := normalizeOfferCriteria(criteria, offers)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.OfferComparisonEntry, len(offers))
+	for i := range offers {
+		entries[i] = model.OfferComparisonEntry{
+			Offer:  offers[i],
+			Values: make(map[string]*float64),
+			Scores: make(map[string]float64),
+		}
+		for _, criterion := range normalized {
+			entries[i].Values[criterion.Key] = offerMetricValue(&offers[i], criterion.Key)
+		}
+	}
+
+	for _, criterion := range normalized {
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, entry := range entries {
+			if v := entry.Values[criterion.Key]; v != nil {
+				low, high = math.Min(low, *v), math.Max(high, *v)
+			}
+		}
+		for i := range entries {
+			v := entries[i].Values[criterion.Key]
+			score := 0.0
+			switch {
+			case v == nil:
+			case high == low:
+				score = 100
+			case criterion.LowerIsBetter:
+				score = (high - *v) / (high - low) * 100
+			default:
+				score = (*v - low) / (high - low) * 100
+			}
+			entries[i].Scores[criterion.Key] = math.Round(score*100) / 100
+			entries[i].TotalScore += score * criterion.Weight
+		}
+	}
+
+	for i := range entries {
+		entries[i].TotalScore = math.Round(entries[i].TotalScore*100) / 100
+	}
+	for i := range normalized {
+		normalized[i].Weight = math.Round(normalized[i].Weight*10000) / 10000
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].TotalScore != entries[j].TotalScore {
+			return entries[i].TotalScore > entries[j].TotalScore
+		}
+		if entries[i].Offer.Compensation.FirstYearTotal != entries[j].Offer.Compensation.FirstYearTotal {
+			return entries[i].Offer.Compensation.FirstYearTotal > entries[j].Offer.Compensation.FirstYearTotal
+		}
+		return entries[i].Offer.ID < entries[j].Offer.ID
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].TotalScore == entries[i-1].TotalScore {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+
+	return &model.OfferComparison{Currency: offers[0].Currency, Criteria: normalized, Entries: entries}, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"jobView-backend/internal/excel"
+	"jobView-backend/internal/model"
+)
+
+func testOffer(id int, baseMonthly, months float64, stock float64, ratings map[string]float64) model.Offer {
+	offer := model.Offer{ID: id, Currency: "CNY", BaseMonthly: baseMonthly, SalaryMonths: months, Ratings: ratings}
+	if stock > 0 {
+		offer.StockValue = &stock
+	}
+	offer.Compute()
+	return offer
+}
+
+func TestBuildOfferComparison(t *testing.T) {
+	offers := []model.Offer{
+		testOffer(1, 30000, 15, 0, model.OfferRatings{"growth": 9}),      // 总包 450000
+		testOffer(2, 35000, 13, 400000, model.OfferRatings{"growth": 5}), // 455000 + 100000
+		testOffer(3, 28000, 12, 0, model.OfferRatings{}),                 // 336000，未评分
+	}
+
+	comparison, err := buildOfferComparison(offers, []model.OfferCriterion{
+		{Key: "annual_total", Weight: 3},
+		{Key: "growth", Label: "成长空间", Weight: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comparison.Criteria[0].Weight != 0.75 || comparison.Criteria[0].Label != "年度总包" || comparison.Criteria[1].Label != "成长空间" {
+		t.Fatalf("unexpected criteria: %+v", comparison.Criteria)
+	}
+
+	ids := []int{}
+	for _, entry := range comparison.Entries {
+		ids = append(ids, entry.Offer.ID)
+	}
+	// offer 2 总包最高（100 × 0.75 + 0 × 0.25），offer 1 成长分最高，offer 3 总包最低且未评分
+	if ids[0] != 2 || ids[1] != 1 || ids[2] != 3 {
+		t.Fatalf("unexpected ranking: %v", ids)
+	}
+	first := comparison.Entries[0]
+	if first.Scores["annual_total"] != 100 || first.Scores["growth"] != 0 || first.TotalScore != 75 || first.Rank != 1 {
+		t.Fatalf("unexpected scores for top offer: %+v", first)
+	}
+	last := comparison.Entries[2]
+	if last.Values["growth"] != nil || last.Scores["growth"] != 0 || last.TotalScore != 0 {
+		t.Fatalf("expected unrated offer to score 0, got %+v", last)
+	}
+
+	// 越低越好的维度反向打分
+	comparison, err = buildOfferComparison(offers, []model.OfferCriterion{{Key: "growth", Weight: 1, LowerIsBetter: true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comparison.Entries[0].Offer.ID != 2 {
+		t.Fatalf("expected offer 2 to rank first when lower is better, got %+v", comparison.Entries[0].Offer)
+	}
+
+	invalid := [][]model.OfferCriterion{
+		{{Key: "commute", Weight: 1}},
+		{{Key: "annual_total", Weight: 0}},
+		{{Key: "annual_total", Weight: 1}, {Key: "annual_total", Weight: 2}},
+	}
+	for _, criteria := range invalid {
+		if _, err := buildOfferComparison(offers, criteria); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("expected invalid error for %+v, got %v", criteria, err)
+		}
+	}
+
+	mixed := append([]model.Offer{}, offers...)
+	mixed[1].Currency = "USD"
+	if _, err := buildOfferComparison(mixed, nil); err == nil {
+		t.Fatalf("expected error for offers in different currencies")
+	}
+
+	comparison, _ = buildOfferComparison(offers, nil)
+	generator := excel.NewGenerator()
+	defer generator.Close()
+	if err := generator.WriteOfferComparison(comparison); err != nil {
+		t.Fatalf("failed to write offer comparison: %v", err)
+	}
+	if data, err := generator.GetBuffer(); err != nil || len(data) == 0 {
+		t.Fatalf("failed to generate workbook: %v", err)
+	}
+}
+
+func TestOfferTermChanges(t *testing.T) {
+	bonus := 50000.0
+	current := &model.Offer{Currency: "CNY", BaseMonthly: 30000, SalaryMonths: 15, AnnualBonus: &bonus}
+
+	values, err := normalizeOfferTerms(&model.UpdateOfferRequest{
+		BaseMonthly: floatPtr(33000), SalaryMonths: floatPtr(15), AnnualBonus: floatPtr(0), Location: stringPtr("上海"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := offerTermChanges(current, values)
+	if len(changes) != 2 || changes["base_monthly"].To != 33000.0 || changes["annual_bonus"].To != nil {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	if _, err := normalizeOfferTerms(&model.UpdateOfferRequest{Ratings: map[string]float64{"annual_total": 5}}); err == nil {
+		t.Fatalf("expected rating keys to reject built-in metrics")
+	}
+	if _, err := normalizeOfferTerms(&model.UpdateOfferRequest{Deadline: stringPtr("2025/10/01")}); err == nil {
+		t.Fatalf("expected invalid deadline to be rejected")
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func stringPtr(v string) *string { return &v }
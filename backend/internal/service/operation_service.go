@@ -0,0 +1,241 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/operation_service.go
+// This file records batch operations (status updates, deletes) with a snapshot of the affected rows
+// and reverts them on request. Undo is refused when any affected row was modified after the operation.
+
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// operationQueryer 批量操作所在的事务（*sql.Tx 或 gormOperationTx）
+type operationQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// gormOperationTx 将 gorm 事务适配为 operationQueryer
+type gormOperationTx struct {
+	tx *gorm.DB
+}
+
+func (g gormOperationTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return g.tx.Raw(query, args...).Row()
+}
+
+func (g gormOperationTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	res := g.tx.Exec(query, args...)
+	return gormExecResult(res.RowsAffected), res.Error
+}
+
+// gormExecResult gorm 执行结果的 sql.Result 表示
+type gormExecResult int64
+
+func (r gormExecResult) LastInsertId() (int64, error) { return 0, fmt.Errorf("not supported") }
+func (r gormExecResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+// operationRecorder 在批量操作事务内收集受影响申请的快照
+type operationRecorder struct {
+	q         operationQueryer
+	userID    uint
+	snapshots []model.OperationRowSnapshot
+	index     map[int]int
+}
+
+func newOperationRecorder(q operationQueryer, userID uint) *operationRecorder {
+	return &operationRecorder{q: q, userID: userID, index: make(map[int]int)}
+}
+
+// capture 记录申请修改前的状态，须在修改前调用；不存在、已删除或无权限时返回 false
+func (r *operationRecorder) capture(id int) (bool, error) {
+	if _, ok := r.index[id]; ok {
+		return true, nil
+	}
+	snap := model.OperationRowSnapshot{ID: id}
+	var history, stats []byte
+	err := r.q.QueryRow(`
+		SELECT status, last_status_change, status_version, status_history, status_duration_stats, deleted_at, updated_at
+		FROM job_applications
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, id, r.userID).Scan(&snap.Status, &snap.LastStatusChange, &snap.StatusVersion, &history, &stats, &snap.DeletedAt, &snap.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to snapshot job application %d: %w", id, err)
+	}
+	if len(history) > 0 {
+		snap.StatusHistory = json.RawMessage(history)
+	}
+	if len(stats) > 0 {
+		snap.StatusDurationStats = json.RawMessage(stats)
+	}
+	r.index[id] = len(r.snapshots)
+	r.snapshots = append(r.snapshots, snap)
+	return true, nil
+}
+
+// addHistory 记录本次操作为申请写入的状态历史
+func (r *operationRecorder) addHistory(id int, historyID int64) {
+	if i, ok := r.index[id]; ok {
+		r.snapshots[i].HistoryIDs = append(r.snapshots[i].HistoryIDs, historyID)
+	}
+}
+
+// save 读取修改后的 updated_at 并写入操作记录；没有受影响的申请时不记录
+func (r *operationRecorder) save(operationType model.OperationType) (*model.BatchOperationResult, error) {
+	if len(r.snapshots) == 0 {
+		return &model.BatchOperationResult{}, nil
+	}
+	for i := range r.snapshots {
+		if err := r.q.QueryRow("SELECT updated_at FROM job_applications WHERE id = $1",
+			r.snapshots[i].ID).Scan(&r.snapshots[i].AfterUpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read job application %d after update: %w", r.snapshots[i].ID, err)
+		}
+	}
+
+	snapshotJSON, err := json.Marshal(r.snapshots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal operation snapshot: %w", err)
+	}
+	result := &model.BatchOperationResult{AffectedCount: len(r.snapshots)}
+	if err := r.q.QueryRow(`
+		INSERT INTO batch_operations (user_id, operation_type, affected_count, snapshot)
+		VALUES ($1, $2, $3, $4::jsonb)
+		RETURNING id
+	`, r.userID, operationType, len(r.snapshots), string(snapshotJSON)).Scan(&result.OperationID); err != nil {
+		return nil, fmt.Errorf("failed to record batch operation: %w", err)
+	}
+	return result, nil
+}
+
+type OperationService struct {
+	db *database.DB
+}
+
+func NewOperationService(db *database.DB) *OperationService {
+	return &OperationService{db: db}
+}
+
+// Undo 撤销批量操作：恢复快照中的申请状态并删除本次操作写入的状态历史。
+// 任一申请在操作之后被修改（或已彻底删除）时拒绝撤销。
+func (s *OperationService) Undo(userID uint, operationID int64) (*model.UndoOperationResponse, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		operationType model.OperationType
+		snapshotJSON  []byte
+		undoneAt      sql.NullTime
+	)
+	err = tx.QueryRow(`
+		SELECT operation_type, snapshot, undone_at
+		FROM batch_operations
+		WHERE id = $1 AND user_id = $2
+		FOR UPDATE
+	`, operationID, userID).Scan(&operationType, &snapshotJSON, &undoneAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("operation not found or access denied")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+	if undoneAt.Valid {
+		return nil, fmt.Errorf("operation already undone")
+	}
+
+	var snapshots []model.OperationRowSnapshot
+	if err := json.Unmarshal(snapshotJSON, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode operation snapshot: %w", err)
+	}
+
+	// 检查是否被再次修改
+	var modified []int
+	for _, snap := range snapshots {
+		var updatedAt time.Time
+		err := tx.QueryRow("SELECT updated_at FROM job_applications WHERE id = $1 AND user_id = $2 FOR UPDATE",
+			snap.ID, userID).Scan(&updatedAt)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to check job application %d: %w", snap.ID, err)
+		}
+		if err == sql.ErrNoRows || !updatedAt.Equal(snap.AfterUpdatedAt) {
+			modified = append(modified, snap.ID)
+		}
+	}
+	if len(modified) > 0 {
+		sort.Ints(modified)
+		return nil, fmt.Errorf("operation cannot be undone: job applications modified since: %v", modified)
+	}
+
+	// 直接恢复快照，不经过状态流转校验也不写历史
+	if _, err := tx.Exec("SET LOCAL jobview.skip_history = 'on'"); err != nil {
+		return nil, fmt.Errorf("failed to disable history trigger: %w", err)
+	}
+	if len(snapshots) > 1 {
+		if _, err := tx.Exec(auditBatchSetting); err != nil {
+			return nil, fmt.Errorf("failed to mark batch operation: %w", err)
+		}
+	}
+
+	for _, snap := range snapshots {
+		if _, err := tx.Exec(`
+			UPDATE job_applications
+			SET status = $1, last_status_change = $2, status_version = $3,
+			    status_history = $4::jsonb, status_duration_stats = $5::jsonb,
+			    deleted_at = $6, updated_at = NOW()
+			WHERE id = $7 AND user_id = $8
+		`, snap.Status, snap.LastStatusChange, snap.StatusVersion, nullableJSON(snap.StatusHistory),
+			nullableJSON(snap.StatusDurationStats), snap.DeletedAt, snap.ID, userID); err != nil {
+			return nil, fmt.Errorf("failed to restore job application %d: %w", snap.ID, err)
+		}
+		if len(snap.HistoryIDs) > 0 {
+			placeholders := make([]string, len(snap.HistoryIDs))
+			args := []interface{}{snap.ID}
+			for i, historyID := range snap.HistoryIDs {
+				placeholders[i] = fmt.Sprintf("$%d", i+2)
+				args = append(args, historyID)
+			}
+			query := fmt.Sprintf("DELETE FROM job_status_history WHERE job_application_id = $1 AND id IN (%s)",
+				strings.Join(placeholders, ", "))
+			if _, err := tx.Exec(query, args...); err != nil {
+				return nil, fmt.Errorf("failed to remove status history of job application %d: %w", snap.ID, err)
+			}
+		}
+	}
+
+	response := &model.UndoOperationResponse{
+		OperationID:   operationID,
+		OperationType: operationType,
+		RestoredCount: len(snapshots),
+	}
+	if err := tx.QueryRow("UPDATE batch_operations SET undone_at = NOW() WHERE id = $1 RETURNING undone_at",
+		operationID).Scan(&response.UndoneAt); err != nil {
+		return nil, fmt.Errorf("failed to mark operation undone: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return response, nil
+}
+
+// nullableJSON 将空 JSON 转为 SQL NULL
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	return string(raw)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"encoding/json"
+	"jobView-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestOperationSnapshotRoundTrip(t *testing.T) {
+	after := time.Date(2025, 3, 1, 10, 0, 0, 123456000, time.FixedZone("CST", 8*3600))
+	version := 3
+	snaps := []model.OperationRowSnapshot{{
+		ID:             5,
+		Status:         model.StatusApplied,
+		StatusVersion:  &version,
+		StatusHistory:  json.RawMessage(`{"history":[]}`),
+		UpdatedAt:      after.Add(-time.Hour),
+		AfterUpdatedAt: after,
+		HistoryIDs:     []int64{11, 12},
+	}}
+
+	data, err := json.Marshal(snaps)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded []model.OperationRowSnapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got := decoded[0]
+	if !got.AfterUpdatedAt.Equal(after) || *got.StatusVersion != 3 || len(got.HistoryIDs) != 2 {
+		t.Fatalf("snapshot changed after round trip: %+v", got)
+	}
+	if got.LastStatusChange != nil || got.DeletedAt != nil {
+		t.Fatalf("nil times should stay nil: %+v", got)
+	}
+	if nullableJSON(got.StatusDurationStats) != nil {
+		t.Fatalf("missing stats should be written as NULL")
+	}
+	if v := nullableJSON(got.StatusHistory); v != `{"history":[]}` {
+		t.Fatalf("unexpected status_history value %v", v)
+	}
+}
+
+func TestOperationRecorderWithoutChanges(t *testing.T) {
+	result, err := newOperationRecorder(nil, 1).save(model.OperationBatchDelete)
+	if err != nil || result.OperationID != 0 || result.AffectedCount != 0 {
+		t.Fatalf("expected empty result without snapshots, got %+v %v", result, err)
+	}
+}
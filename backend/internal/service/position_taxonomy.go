@@ -0,0 +1,35 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+)
+
+// positionTaxonomyValues 按当前规则归一化职位名称，
+// 返回 role_family、role_level、tech_stack、role_taxonomy_version 四列的写入值
+func positionTaxonomyValues(positionTitle string) []interface{} {
+	rules := taxonomy.Active()
+	result := rules.Normalize(positionTitle)
+	return []interface{}{
+		optionalString(result.RoleFamily),
+		optionalString(result.RoleLevel),
+		model.StringList(result.TechStack),
+		rules.Version(),
+	}
+}
+
+// applyPositionTaxonomy 将归一化结果填充到投递记录（用于不回读数据库的写入路径）
+func applyPositionTaxonomy(job *model.JobApplication) {
+	result := taxonomy.Active().Normalize(job.PositionTitle)
+	job.RoleFamily = optionalString(result.RoleFamily)
+	job.RoleLevel = optionalString(result.RoleLevel)
+	job.TechStack = model.StringList(result.TechStack)
+}
+
+// optionalString 空字符串视为未设置
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
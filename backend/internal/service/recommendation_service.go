@@ -0,0 +1,412 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/recommendation_service.go
+// This file implements the rule engine behind process insight recommendations.
+// Rules are plain data (conditions over user-level analytics facts or per-application facts, severity and
+// zh/en message templates); they can be overridden with a JSON file via RECOMMENDATION_RULES_FILE.
+
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultRecommendationLang 默认建议语言
+const defaultRecommendationLang = "zh"
+
+// defaultRecommendationRules 内置建议规则
+var defaultRecommendationRules = []model.RecommendationRule{
+	{
+		ID: "low_success_rate", Category: "strategy", Scope: model.RecommendationScopeUser, Severity: model.SeverityHigh,
+		Conditions: []model.RuleCondition{{Fact: "success_rate", Op: "lt", Value: 10}},
+		Title:      map[string]string{"zh": "成功率偏低", "en": "Low success rate"},
+		Message: map[string]string{
+			"zh": "建议优化简历和投递策略，当前成功率较低（{{printf \"%.1f\" .success_rate}}%）",
+			"en": "Consider improving your resume and targeting; your success rate is low ({{printf \"%.1f\" .success_rate}}%)",
+		},
+	},
+	{
+		ID: "high_success_rate", Category: "strategy", Scope: model.RecommendationScopeUser, Severity: model.SeverityLow,
+		Conditions: []model.RuleCondition{{Fact: "success_rate", Op: "gt", Value: 50}},
+		Title:      map[string]string{"zh": "投递策略有效", "en": "Strategy is working"},
+		Message:    map[string]string{"zh": "投递策略很好，继续保持", "en": "Your application strategy works well, keep it up"},
+	},
+	{
+		ID: "few_applications", Category: "volume", Scope: model.RecommendationScopeUser, Severity: model.SeverityMedium,
+		Conditions: []model.RuleCondition{{Fact: "total_applications", Op: "lt", Value: 10}},
+		Title:      map[string]string{"zh": "投递数量较少", "en": "Few applications"},
+		Message:    map[string]string{"zh": "建议增加投递数量，扩大求职机会", "en": "Send more applications to widen your opportunities"},
+	},
+	{
+		ID: "slow_screening", Category: "follow_up", Scope: model.RecommendationScopeUser, Severity: model.SeverityMedium,
+		Conditions: []model.RuleCondition{{Fact: "avg_minutes." + string(model.StatusResumeScreening), Op: "gt", Value: 7 * 24 * 60}},
+		Title:      map[string]string{"zh": "简历筛选耗时长", "en": "Slow resume screening"},
+		Message:    map[string]string{"zh": "简历筛选时间较长，可考虑主动跟进或优化简历", "en": "Resume screening takes long; consider following up or refining your resume"},
+	},
+	{
+		ID: "stale_applications", Category: "follow_up", Scope: model.RecommendationScopeApplication, Severity: model.SeverityMedium,
+		Conditions: []model.RuleCondition{
+			{Fact: "status", Op: "in", Value: []interface{}{string(model.StatusApplied), string(model.StatusResumeScreening)}},
+			{Fact: "days_in_status", Op: "gt", Value: 14},
+		},
+		Title: map[string]string{"zh": "申请长时间无进展", "en": "Stalled applications"},
+		Message: map[string]string{
+			"zh": "{{.count}} 个申请超过14天没有进展（{{.companies}}），建议主动跟进",
+			"en": "{{.count}} application(s) have had no progress for over 14 days ({{.companies}}); consider following up",
+		},
+	},
+	{
+		ID: "follow_up_overdue", Category: "follow_up", Scope: model.RecommendationScopeApplication, Severity: model.SeverityHigh,
+		Conditions: []model.RuleCondition{
+			{Fact: "in_progress", Op: "eq", Value: true},
+			{Fact: "follow_up_overdue", Op: "eq", Value: true},
+		},
+		Title: map[string]string{"zh": "跟进已逾期", "en": "Overdue follow-ups"},
+		Message: map[string]string{
+			"zh": "{{.count}} 个申请已过计划跟进日期（{{.companies}}）",
+			"en": "{{.count}} application(s) are past their follow-up date ({{.companies}})",
+		},
+	},
+	{
+		ID: "upcoming_interview", Category: "interview", Scope: model.RecommendationScopeApplication, Severity: model.SeverityHigh,
+		Conditions: []model.RuleCondition{
+			{Fact: "interview_in_hours", Op: "gte", Value: 0},
+			{Fact: "interview_in_hours", Op: "lte", Value: 48},
+		},
+		Title: map[string]string{"zh": "即将面试", "en": "Upcoming interviews"},
+		Message: map[string]string{
+			"zh": "48小时内有 {{.count}} 场面试（{{.companies}}），记得提前准备",
+			"en": "{{.count}} interview(s) within 48 hours ({{.companies}}); prepare in advance",
+		},
+	},
+	{
+		ID: "unlikely_offers", Category: "strategy", Scope: model.RecommendationScopeApplication, Severity: model.SeverityLow,
+		Conditions: []model.RuleCondition{
+			{Fact: "in_progress", Op: "eq", Value: true},
+			{Fact: "success_probability", Op: "lt", Value: 0.05},
+			{Fact: "days_in_status", Op: "gt", Value: 30},
+		},
+		Title: map[string]string{"zh": "可考虑关闭的申请", "en": "Applications to close"},
+		Message: map[string]string{
+			"zh": "{{.count}} 个申请长期停滞且成功率很低（{{.companies}}），可考虑标记为流程结束",
+			"en": "{{.count}} application(s) are stalled with a very low success probability ({{.companies}}); consider closing them",
+		},
+	},
+	{
+		ID: "keep_going", Category: "general", Scope: model.RecommendationScopeUser, Severity: model.SeverityLow, Fallback: true,
+		Title:   map[string]string{"zh": "进展良好", "en": "On track"},
+		Message: map[string]string{"zh": "继续保持良好的求职进展", "en": "Keep up the good progress"},
+	},
+}
+
+type RecommendationService struct {
+	db    *database.DB
+	rules []model.RecommendationRule
+}
+
+func NewRecommendationService(db *database.DB) *RecommendationService {
+	rules := defaultRecommendationRules
+	if path := os.Getenv("RECOMMENDATION_RULES_FILE"); path != "" {
+		loaded, err := loadRecommendationRules(path)
+		if err != nil {
+			log.Printf("Warning: failed to load recommendation rules from %s, using defaults: %v", path, err)
+		} else {
+			rules = loaded
+		}
+	}
+	return &RecommendationService{db: db, rules: rules}
+}
+
+// loadRecommendationRules 从JSON文件加载规则
+func loadRecommendationRules(path string) ([]model.RecommendationRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []model.RecommendationRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// applicationFacts 单条申请的事实，用于规则匹配
+type applicationFacts struct {
+	ID          int
+	CompanyName string
+	Facts       map[string]interface{}
+}
+
+// GenerateRecommendations 基于用户统计与各申请的状态生成建议
+func (s *RecommendationService) GenerateRecommendations(userID uint, analytics *model.StatusAnalyticsResponse, trends []model.StatusTrend, lang string) ([]model.Recommendation, error) {
+	apps, err := s.loadApplicationFacts(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return evaluateRecommendationRules(s.rules, buildUserFacts(analytics, trends, apps), apps, lang), nil
+}
+
+// loadApplicationFacts 读取用户申请并计算事实
+func (s *RecommendationService) loadApplicationFacts(userID uint, now time.Time) ([]applicationFacts, error) {
+	rows, err := s.db.Query(`
+		SELECT id, company_name, status, application_date,
+		       COALESCE(last_status_change, updated_at, created_at),
+		       follow_up_date, interview_time,
+		       (status_duration_stats->'analytics'->>'success_probability')::float8
+		FROM job_applications
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job applications: %w", err)
+	}
+	defer rows.Close()
+
+	today := now.Format("2006-01-02")
+	var apps []applicationFacts
+	for rows.Next() {
+		var (
+			app             applicationFacts
+			status          model.ApplicationStatus
+			applicationDate string
+			lastChange      time.Time
+			followUpDate    sql.NullString
+			interviewTime   sql.NullTime
+			probability     sql.NullFloat64
+		)
+		if err := rows.Scan(&app.ID, &app.CompanyName, &status, &applicationDate, &lastChange,
+			&followUpDate, &interviewTime, &probability); err != nil {
+			return nil, fmt.Errorf("failed to scan job application: %w", err)
+		}
+
+		facts := map[string]interface{}{
+			"status":            string(status),
+			"company_name":      app.CompanyName,
+			"in_progress":       status.IsInProgressStatus() || status.IsPassedStatus() && !isOfferStatus(status) && status != model.StatusProcessFinished,
+			"days_in_status":    now.Sub(lastChange).Hours() / 24,
+			"follow_up_overdue": followUpDate.Valid && followUpDate.String != "" && followUpDate.String < today,
+		}
+		if d, err := time.Parse("2006-01-02", applicationDate); err == nil {
+			facts["days_since_applied"] = now.Sub(d).Hours() / 24
+		}
+		if interviewTime.Valid {
+			facts["interview_in_hours"] = interviewTime.Time.Sub(now).Hours()
+		}
+		if probability.Valid {
+			facts["success_probability"] = probability.Float64
+		}
+		app.Facts = facts
+		apps = append(apps, app)
+	}
+	return apps, rows.Err()
+}
+
+// buildUserFacts 从分析数据中提取用户级事实
+func buildUserFacts(analytics *model.StatusAnalyticsResponse, trends []model.StatusTrend, apps []applicationFacts) map[string]interface{} {
+	facts := map[string]interface{}{
+		"total_applications": float64(analytics.TotalApplications),
+		"success_rate":       analytics.SuccessRate,
+	}
+	for status, minutes := range analytics.AverageDurations {
+		facts["avg_minutes."+status] = minutes
+	}
+	active := 0
+	for _, app := range apps {
+		if app.Facts["in_progress"] == true {
+			active++
+		}
+	}
+	facts["active_applications"] = float64(active)
+	changes := 0
+	for _, trend := range trends {
+		changes += trend.Count
+	}
+	facts["recent_status_changes"] = float64(changes)
+	return facts
+}
+
+// evaluateRecommendationRules 执行规则：用户级规则各生成一条，申请级规则按规则合并并附带申请ID，最后按内容去重
+func evaluateRecommendationRules(rules []model.RecommendationRule, userFacts map[string]interface{}, apps []applicationFacts, lang string) []model.Recommendation {
+	if lang == "" {
+		lang = defaultRecommendationLang
+	}
+
+	var results []model.Recommendation
+	var fallbacks []model.RecommendationRule
+	for _, rule := range rules {
+		if rule.Fallback {
+			fallbacks = append(fallbacks, rule)
+			continue
+		}
+		switch rule.Scope {
+		case model.RecommendationScopeApplication:
+			var ids []int
+			var companies []string
+			seen := make(map[string]bool)
+			for _, app := range apps {
+				if !matchRuleConditions(rule.Conditions, app.Facts) {
+					continue
+				}
+				ids = append(ids, app.ID)
+				if !seen[app.CompanyName] {
+					seen[app.CompanyName] = true
+					companies = append(companies, app.CompanyName)
+				}
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if len(companies) > 3 {
+				companies = append(companies[:3], "…")
+			}
+			data := map[string]interface{}{"count": len(ids), "companies": strings.Join(companies, "、"), "ids": ids}
+			results = append(results, renderRecommendation(rule, data, lang, ids))
+		default:
+			if matchRuleConditions(rule.Conditions, userFacts) {
+				results = append(results, renderRecommendation(rule, userFacts, lang, nil))
+			}
+		}
+	}
+
+	results = dedupeRecommendations(results)
+	if len(results) == 0 {
+		for _, rule := range fallbacks {
+			results = append(results, renderRecommendation(rule, userFacts, lang, nil))
+		}
+	}
+
+	severityRank := map[string]int{model.SeverityHigh: 0, model.SeverityMedium: 1, model.SeverityLow: 2}
+	sort.SliceStable(results, func(i, j int) bool {
+		return severityRank[results[i].Severity] < severityRank[results[j].Severity]
+	})
+	return results
+}
+
+// dedupeRecommendations 合并内容相同的建议，申请ID取并集
+func dedupeRecommendations(items []model.Recommendation) []model.Recommendation {
+	var result []model.Recommendation
+	index := make(map[string]int)
+	for _, item := range items {
+		key := item.Title + "\x00" + item.Message
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			result = append(result, item)
+			continue
+		}
+		existing := make(map[int]bool)
+		for _, id := range result[i].ApplicationIDs {
+			existing[id] = true
+		}
+		for _, id := range item.ApplicationIDs {
+			if !existing[id] {
+				result[i].ApplicationIDs = append(result[i].ApplicationIDs, id)
+			}
+		}
+	}
+	return result
+}
+
+// renderRecommendation 按语言渲染规则模板，缺少该语言时回退到默认语言
+func renderRecommendation(rule model.RecommendationRule, data map[string]interface{}, lang string, ids []int) model.Recommendation {
+	pick := func(m map[string]string) string {
+		if v, ok := m[lang]; ok {
+			return v
+		}
+		return m[defaultRecommendationLang]
+	}
+
+	message := pick(rule.Message)
+	if tmpl, err := template.New(rule.ID).Option("missingkey=zero").Parse(message); err == nil {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err == nil {
+			message = buf.String()
+		}
+	}
+
+	return model.Recommendation{
+		RuleID:         rule.ID,
+		Category:       rule.Category,
+		Severity:       rule.Severity,
+		Title:          pick(rule.Title),
+		Message:        message,
+		ApplicationIDs: ids,
+	}
+}
+
+// matchRuleConditions 所有条件均满足时返回 true；事实缺失时条件不满足（exists 除外）
+func matchRuleConditions(conditions []model.RuleCondition, facts map[string]interface{}) bool {
+	for _, cond := range conditions {
+		actual, ok := facts[cond.Fact]
+		if cond.Op == "exists" {
+			if !ok {
+				return false
+			}
+			continue
+		}
+		if !ok || !matchRuleCondition(cond, actual) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchRuleCondition(cond model.RuleCondition, actual interface{}) bool {
+	switch cond.Op {
+	case "lt", "lte", "gt", "gte":
+		a, okA := toFloat(actual)
+		b, okB := toFloat(cond.Value)
+		if !okA || !okB {
+			return false
+		}
+		switch cond.Op {
+		case "lt":
+			return a < b
+		case "lte":
+			return a <= b
+		case "gt":
+			return a > b
+		default:
+			return a >= b
+		}
+	case "eq":
+		return fmt.Sprint(actual) == fmt.Sprint(cond.Value)
+	case "neq":
+		return fmt.Sprint(actual) != fmt.Sprint(cond.Value)
+	case "in":
+		values, ok := cond.Value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if fmt.Sprint(actual) == fmt.Sprint(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// toFloat 将数值类事实转换为 float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
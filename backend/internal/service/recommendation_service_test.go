@@ -0,0 +1,54 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"testing"
+)
+
+func TestEvaluateRecommendationRules(t *testing.T) {
+	userFacts := map[string]interface{}{
+		"total_applications": float64(5),
+		"success_rate":       float64(20),
+	}
+	apps := []applicationFacts{
+		{ID: 1, CompanyName: "A", Facts: map[string]interface{}{"status": "已投递", "days_in_status": float64(20), "in_progress": true}},
+		{ID: 2, CompanyName: "B", Facts: map[string]interface{}{"status": "简历筛选中", "days_in_status": float64(30), "in_progress": true}},
+		{ID: 3, CompanyName: "C", Facts: map[string]interface{}{"status": "一面中", "days_in_status": float64(30), "in_progress": true}},
+		{ID: 4, CompanyName: "D", Facts: map[string]interface{}{"status": "已投递", "days_in_status": float64(2), "in_progress": true}},
+	}
+
+	got := evaluateRecommendationRules(defaultRecommendationRules, userFacts, apps, "zh")
+	if len(got) != 2 {
+		t.Fatalf("expected 2 recommendations, got %+v", got)
+	}
+	if got[0].RuleID != "few_applications" || got[1].RuleID != "stale_applications" {
+		t.Fatalf("unexpected rules/order: %+v", got)
+	}
+	stale := got[1]
+	if len(stale.ApplicationIDs) != 2 || stale.ApplicationIDs[0] != 1 || stale.ApplicationIDs[1] != 2 {
+		t.Fatalf("unexpected application ids: %v", stale.ApplicationIDs)
+	}
+	if stale.Message != "2 个申请超过14天没有进展（A、B），建议主动跟进" {
+		t.Fatalf("unexpected message: %s", stale.Message)
+	}
+
+	en := evaluateRecommendationRules(defaultRecommendationRules, map[string]interface{}{
+		"total_applications": float64(30),
+		"success_rate":       float64(20),
+	}, nil, "en")
+	if len(en) != 1 || en[0].RuleID != "keep_going" || en[0].Message != "Keep up the good progress" {
+		t.Fatalf("expected english fallback, got %+v", en)
+	}
+}
+
+func TestDedupeRecommendations(t *testing.T) {
+	items := []model.Recommendation{
+		{RuleID: "a", Title: "t", Message: "m", ApplicationIDs: []int{1, 2}},
+		{RuleID: "b", Title: "t", Message: "m", ApplicationIDs: []int{2, 3}},
+		{RuleID: "c", Title: "t", Message: "other"},
+	}
+	got := dedupeRecommendations(items)
+	if len(got) != 2 || len(got[0].ApplicationIDs) != 3 {
+		t.Fatalf("unexpected dedupe result: %+v", got)
+	}
+}
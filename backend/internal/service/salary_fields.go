@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/salary"
+)
+
+// salaryColumns 结构化薪酬列，顺序与 salaryColumnValues 的返回值一致
+const salaryColumns = "salary_min_monthly, salary_max_monthly, salary_months, salary_annual_min, salary_annual_max, salary_currency, salary_parser_version"
+
+// salaryColumnValues 解析薪资描述，返回 salaryColumns 七列的写入值；无法解析时薪酬列为 NULL
+func salaryColumnValues(raw *string) []interface{} {
+	if raw != nil {
+		if parsed, ok := salary.Parse(*raw); ok {
+			return []interface{}{parsed.MinMonthly, parsed.MaxMonthly, parsed.Months,
+				parsed.AnnualMin, parsed.AnnualMax, parsed.Currency, salary.ParserVersion}
+		}
+	}
+	return []interface{}{nil, nil, nil, nil, nil, nil, salary.ParserVersion}
+}
+
+// applyParsedSalary 将薪资解析结果填充到投递记录（用于不回读数据库的写入路径）
+func applyParsedSalary(job *model.JobApplication) {
+	job.SalaryMinMonthly, job.SalaryMaxMonthly, job.SalaryMonths = nil, nil, nil
+	job.SalaryAnnualMin, job.SalaryAnnualMax, job.SalaryCurrency = nil, nil, nil
+	if job.SalaryRange == nil {
+		return
+	}
+	parsed, ok := salary.Parse(*job.SalaryRange)
+	if !ok {
+		return
+	}
+	job.SalaryMinMonthly, job.SalaryMaxMonthly, job.SalaryMonths = &parsed.MinMonthly, &parsed.MaxMonthly, &parsed.Months
+	job.SalaryAnnualMin, job.SalaryAnnualMax, job.SalaryCurrency = &parsed.AnnualMin, &parsed.AnnualMax, &parsed.Currency
+}
+
+// salaryRangeCondition 月薪筛选（单位 K，人民币）：保留月薪区间与 [minK, maxK] 有交集的记录，任一端可为空。
+// 金额不做汇率换算，有筛选条件时只匹配人民币薪资
+func salaryRangeCondition(minK, maxK *float64, argIndex int) (string, []interface{}, int, error) {
+	if minK != nil && *minK < 0 || maxK != nil && *maxK < 0 {
+		return "", nil, argIndex, fmt.Errorf("invalid salary range: bounds must not be negative")
+	}
+	if minK != nil && maxK != nil && *minK > *maxK {
+		return "", nil, argIndex, fmt.Errorf("invalid salary range: salary_min is greater than salary_max")
+	}
+
+	if minK == nil && maxK == nil {
+		return "", nil, argIndex, nil
+	}
+	condition := fmt.Sprintf(" AND salary_currency = $%d", argIndex)
+	args := []interface{}{salary.DefaultCurrency}
+	argIndex++
+	if minK != nil {
+		condition += fmt.Sprintf(" AND salary_max_monthly >= $%d", argIndex)
+		args = append(args, *minK*1000)
+		argIndex++
+	}
+	if maxK != nil {
+		condition += fmt.Sprintf(" AND salary_min_monthly <= $%d", argIndex)
+		args = append(args, *maxK*1000)
+		argIndex++
+	}
+	return condition, args, argIndex, nil
+}
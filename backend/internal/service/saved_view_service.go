@@ -0,0 +1,438 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/saved_view_service.go
+// This file implements per-user saved views: named combinations of list/search filters and
+// sort order that can be rerun, pinned on the dashboard with live counts, and used as the
+// filter source of an export.
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/querylang"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxSavedViewsPerUser    = 50
+	maxSavedViewNameLength  = 100
+	maxSavedViewQueryLength = querylang.MaxQueryLength
+	maxSavedViewPageSize    = 100
+	savedViewColumns        = "id, user_id, name, filters, pinned, sort_order, last_used_at, created_at, updated_at"
+)
+
+// savedViewListSortFields 列表视图允许的排序字段（另支持 cf.<key>）
+var savedViewListSortFields = map[string]bool{
+	"application_date":    true,
+	"created_at":          true,
+	"updated_at":          true,
+	"company_name":        true,
+	"position_title":      true,
+	"status":              true,
+	"success_probability": true,
+}
+
+// savedViewSearchSortFields 搜索视图允许的排序字段（排在相关度之后）
+var savedViewSearchSortFields = map[string]bool{
+	"application_date": true,
+	"created_at":       true,
+	"updated_at":       true,
+	"company_name":     true,
+	"position_title":   true,
+	"status":           true,
+}
+
+type SavedViewService struct {
+	db         *database.DB
+	jobService *JobApplicationService
+}
+
+func NewSavedViewService(db *database.DB, jobService *JobApplicationService) *SavedViewService {
+	return &SavedViewService{db: db, jobService: jobService}
+}
+
+// List 获取用户的视图及当前匹配数，固定的视图排在前面
+func (s *SavedViewService) List(userID uint) ([]model.SavedView, error) {
+	views, err := listSavedViews(s.db, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	s.jobService.fillSavedViewCounts(userID, views)
+	return views, nil
+}
+
+// Get 获取单个视图及当前匹配数
+func (s *SavedViewService) Get(userID uint, id int) (*model.SavedView, error) {
+	view, err := loadSavedView(s.db, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	views := []model.SavedView{*view}
+	s.jobService.fillSavedViewCounts(userID, views)
+	return &views[0], nil
+}
+
+// Create 创建视图，同一用户下名称不区分大小写唯一
+func (s *SavedViewService) Create(userID uint, req *model.CreateSavedViewRequest) (*model.SavedView, error) {
+	name, err := normalizeSavedViewName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeSavedViewFilters(&req.Filters); err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM saved_views WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count saved views: %w", err)
+	}
+	if count >= maxSavedViewsPerUser {
+		return nil, fmt.Errorf("invalid request: at most %d saved views allowed", maxSavedViewsPerUser)
+	}
+	if err := s.checkNameAvailable(userID, name, 0); err != nil {
+		return nil, err
+	}
+
+	view := &model.SavedView{
+		UserID:    userID,
+		Name:      name,
+		Filters:   req.Filters,
+		Pinned:    req.Pinned,
+		SortOrder: req.SortOrder,
+	}
+	err = s.db.QueryRow(`
+		INSERT INTO saved_views (user_id, name, filters, pinned, sort_order)
+		VALUES ($1, $2, $3::jsonb, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, userID, name, req.Filters, req.Pinned, req.SortOrder).Scan(&view.ID, &view.CreatedAt, &view.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved view: %w", err)
+	}
+	return s.Get(userID, view.ID)
+}
+
+// Update 修改视图名称、筛选条件、固定状态或排序
+func (s *SavedViewService) Update(userID uint, id int, req *model.UpdateSavedViewRequest) (*model.SavedView, error) {
+	setParts := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if req.Name != nil {
+		name, err := normalizeSavedViewName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkNameAvailable(userID, name, id); err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, name)
+		argIndex++
+	}
+	if req.Filters != nil {
+		if err := normalizeSavedViewFilters(req.Filters); err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("filters = $%d::jsonb", argIndex))
+		args = append(args, *req.Filters)
+		argIndex++
+	}
+	if req.Pinned != nil {
+		setParts = append(setParts, fmt.Sprintf("pinned = $%d", argIndex))
+		args = append(args, *req.Pinned)
+		argIndex++
+	}
+	if req.SortOrder != nil {
+		setParts = append(setParts, fmt.Sprintf("sort_order = $%d", argIndex))
+		args = append(args, *req.SortOrder)
+		argIndex++
+	}
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("invalid request: no fields to update")
+	}
+
+	query := fmt.Sprintf("UPDATE saved_views SET %s, updated_at = NOW() WHERE id = $%d AND user_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	result, err := s.db.Exec(query, append(args, id, userID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved view: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("saved view not found or access denied")
+	}
+	return s.Get(userID, id)
+}
+
+// Delete 删除视图
+func (s *SavedViewService) Delete(userID uint, id int) error {
+	result, err := s.db.Exec("DELETE FROM saved_views WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("saved view not found or access denied")
+	}
+	return nil
+}
+
+// Run 按视图的条件查询申请；pageSize 为 0 时使用视图保存的每页条数
+func (s *SavedViewService) Run(userID uint, id int, page, pageSize int) (*model.PaginationResponse, error) {
+	view, err := loadSavedView(s.db, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.jobService.runSavedView(userID, &view.Filters, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec("UPDATE saved_views SET last_used_at = NOW() WHERE id = $1", id); err != nil {
+		log.Printf("Warning: failed to record saved view %d usage: %v", id, err)
+	}
+	return result, nil
+}
+
+// checkNameAvailable 检查名称是否已被用户的其他视图使用
+func (s *SavedViewService) checkNameAvailable(userID uint, name string, excludeID int) error {
+	var exists bool
+	if err := s.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM saved_views WHERE user_id = $1 AND LOWER(name) = LOWER($2) AND id <> $3)",
+		userID, name, excludeID,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check saved view name uniqueness: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("saved view name '%s' already exists", name)
+	}
+	return nil
+}
+
+// runSavedView 搜索视图走 SearchApplications，列表视图走 GetJobApplicationsWithStatusFilters
+func (s *JobApplicationService) runSavedView(userID uint, filters *model.SavedViewFilters, page, pageSize int) (*model.PaginationResponse, error) {
+	req := filters.PaginationRequest(page)
+	if pageSize > 0 {
+		req.PageSize = pageSize
+	}
+	if filters.IsSearch() {
+		return s.SearchApplications(userID, filters.Query, req)
+	}
+	var stage *string
+	if filters.Stage != "" {
+		stage = &filters.Stage
+	}
+	return s.GetJobApplicationsWithStatusFilters(userID, filters.Status, stage, req)
+}
+
+// fillSavedViewCounts 计算每个视图当前的匹配数；单个视图失败（如引用的自定义字段已删除）时不填充
+func (s *JobApplicationService) fillSavedViewCounts(userID uint, views []model.SavedView) {
+	for i := range views {
+		filters := views[i].Filters
+		filters.SortBy = "" // 计数与排序无关，避免按成功率排序时触发重算
+		result, err := s.runSavedView(userID, &filters, 1, 1)
+		if err != nil {
+			log.Printf("Warning: failed to count saved view %d: %v", views[i].ID, err)
+			continue
+		}
+		total := result.Total
+		views[i].Count = &total
+	}
+}
+
+// getPinnedSavedViews 仪表板上固定的视图及当前匹配数
+func (s *JobApplicationService) getPinnedSavedViews(userID uint) ([]model.SavedView, error) {
+	views, err := listSavedViews(s.db, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	s.fillSavedViewCounts(userID, views)
+	return views, nil
+}
+
+// listSavedViews 读取用户的视图，pinnedOnly 时只返回固定的视图
+func listSavedViews(db *database.DB, userID uint, pinnedOnly bool) ([]model.SavedView, error) {
+	query := "SELECT " + savedViewColumns + " FROM saved_views WHERE user_id = $1"
+	if pinnedOnly {
+		query += " AND pinned"
+	}
+	query += " ORDER BY pinned DESC, sort_order, LOWER(name)"
+
+	rows, err := db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []model.SavedView{}
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate saved views: %w", err)
+	}
+	return views, nil
+}
+
+// loadSavedView 读取单个视图（带用户权限检查）
+func loadSavedView(db *database.DB, userID uint, id int) (*model.SavedView, error) {
+	row := db.QueryRow("SELECT "+savedViewColumns+" FROM saved_views WHERE id = $1 AND user_id = $2", id, userID)
+	view, err := scanSavedView(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("saved view not found or access denied")
+	}
+	return view, err
+}
+
+func scanSavedView(row interface{ Scan(...interface{}) error }) (*model.SavedView, error) {
+	var view model.SavedView
+	var lastUsedAt sql.NullTime
+	if err := row.Scan(&view.ID, &view.UserID, &view.Name, &view.Filters, &view.Pinned, &view.SortOrder,
+		&lastUsedAt, &view.CreatedAt, &view.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan saved view: %w", err)
+	}
+	if lastUsedAt.Valid {
+		view.LastUsedAt = &lastUsedAt.Time
+	}
+	return &view, nil
+}
+
+// savedViewExportFilters 将视图条件转换为导出筛选条件：状态与阶段取交集，
+// 搜索视图的关键词按全文搜索的规则匹配，导出的申请与运行视图看到的一致
+func savedViewExportFilters(filters *model.SavedViewFilters) (*model.ExportFilters, error) {
+	export := &model.ExportFilters{
+		Search:        filters.Query,
+		TagIDs:        filters.TagIDs,
+		CustomFilters: filters.CustomFilters,
+		SalaryMin:     filters.SalaryMin,
+		SalaryMax:     filters.SalaryMax,
+	}
+
+	var statuses []model.ApplicationStatus
+	if filters.Stage != "" {
+		for _, status := range model.StatusesForStage(filters.Stage) {
+			if filters.Status == nil || *filters.Status == model.ApplicationStatus(status) {
+				statuses = append(statuses, model.ApplicationStatus(status))
+			}
+		}
+		if len(statuses) == 0 {
+			return nil, fmt.Errorf("没有符合条件的数据可导出")
+		}
+	} else if filters.Status != nil {
+		statuses = []model.ApplicationStatus{*filters.Status}
+	}
+	export.Status = statuses
+
+	if filters.StartDate != "" || filters.EndDate != "" {
+		// 导出的日期范围要求两端都有值，缺省端取不限制的边界
+		export.DateRange = &model.DateRange{Start: filters.StartDate, End: filters.EndDate}
+		if export.DateRange.Start == "" {
+			export.DateRange.Start = "0001-01-01"
+		}
+		if export.DateRange.End == "" {
+			export.DateRange.End = "9999-12-31"
+		}
+	}
+	return export, nil
+}
+
+// normalizeSavedViewName 去除首尾空白并校验长度
+func normalizeSavedViewName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("invalid name: view name is required")
+	}
+	if utf8.RuneCountInString(name) > maxSavedViewNameLength {
+		return "", fmt.Errorf("invalid name: at most %d characters", maxSavedViewNameLength)
+	}
+	return name, nil
+}
+
+// normalizeSavedViewFilters 校验视图条件并规范化排序方向和标签ID；
+// 自定义字段是否存在在执行时校验，字段被删除后视图执行会返回 invalid 错误
+func normalizeSavedViewFilters(f *model.SavedViewFilters) error {
+	f.Query = strings.TrimSpace(f.Query)
+	if utf8.RuneCountInString(f.Query) > maxSavedViewQueryLength {
+		return fmt.Errorf("invalid query: at most %d characters", maxSavedViewQueryLength)
+	}
+	if _, err := querylang.Parse(f.Query); err != nil {
+		return err
+	}
+	if f.Status != nil && !f.Status.IsValid() {
+		return fmt.Errorf("invalid status: %s", *f.Status)
+	}
+	if f.Stage != "" && len(model.StatusesForStage(f.Stage)) == 0 {
+		return fmt.Errorf("invalid stage: %s", f.Stage)
+	}
+	if f.IsSearch() && (f.Stage != "" || len(f.CustomFilters) > 0) {
+		return fmt.Errorf("invalid filters: stage and custom field filters are not supported by search views")
+	}
+
+	tagIDs, err := normalizeTagIDs(f.TagIDs)
+	if err != nil {
+		return err
+	}
+	f.TagIDs = tagIDs
+	if len(f.TagIDs) == 0 {
+		f.TagIDs = nil
+	}
+
+	for key, value := range f.CustomFilters {
+		if !customFieldKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid custom field filter: unknown field %q", key)
+		}
+		if strings.TrimSpace(value) == "" {
+			delete(f.CustomFilters, key)
+		}
+	}
+	if len(f.CustomFilters) == 0 {
+		f.CustomFilters = nil
+	}
+
+	if f.StartDate != "" && !isValidDate(f.StartDate) {
+		return fmt.Errorf("invalid start date format: %s", f.StartDate)
+	}
+	if f.EndDate != "" && !isValidDate(f.EndDate) {
+		return fmt.Errorf("invalid end date format: %s", f.EndDate)
+	}
+	if f.StartDate != "" && f.EndDate != "" && f.StartDate > f.EndDate {
+		return fmt.Errorf("invalid date range: start date is after end date")
+	}
+	if _, _, _, err := salaryRangeCondition(f.SalaryMin, f.SalaryMax, 1); err != nil {
+		return err
+	}
+
+	if f.SortBy != "" {
+		allowed := savedViewListSortFields
+		if f.IsSearch() {
+			allowed = savedViewSearchSortFields
+		}
+		key, custom := strings.CutPrefix(f.SortBy, model.CustomFieldPrefix)
+		if !allowed[f.SortBy] && !(custom && !f.IsSearch() && customFieldKeyPattern.MatchString(key)) {
+			return fmt.Errorf("invalid sort_by: %s", f.SortBy)
+		}
+	}
+	f.SortDir = strings.ToUpper(strings.TrimSpace(f.SortDir))
+	if f.SortDir != "" && f.SortDir != "ASC" && f.SortDir != "DESC" {
+		return fmt.Errorf("invalid sort_dir: must be ASC or DESC")
+	}
+	if f.PageSize < 0 || f.PageSize > maxSavedViewPageSize {
+		return fmt.Errorf("invalid page_size: must be between 1 and %d", maxSavedViewPageSize)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSavedViewFilters(t *testing.T) {
+	status := model.StatusFirstInterview
+	filters := model.SavedViewFilters{
+		Query:   "  字节  ",
+		Status:  &status,
+		TagIDs:  []int{3, 3, 1},
+		SortBy:  "updated_at",
+		SortDir: "asc",
+	}
+	if err := normalizeSavedViewFilters(&filters); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filters.Query != "字节" || filters.SortDir != "ASC" || len(filters.TagIDs) != 2 {
+		t.Fatalf("unexpected normalized filters %+v", filters)
+	}
+
+	list := model.SavedViewFilters{Stage: "interviews", SortBy: "cf.team", CustomFilters: map[string]string{"team": "infra", "level": " "}}
+	if err := normalizeSavedViewFilters(&list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.CustomFilters) != 1 || list.IsSearch() {
+		t.Fatalf("expected blank custom filter to be dropped, got %+v", list)
+	}
+
+	invalid := []model.SavedViewFilters{
+		{Stage: "unknown"},
+		{Query: "go", Stage: "interviews"},
+		{Query: "go", SortBy: "success_probability"},
+		{SortBy: "salary"},
+		{SortDir: "sideways"},
+		{StartDate: "2025-09-31x"},
+		{SalaryMin: floatPtr(40), SalaryMax: floatPtr(20)},
+		{StartDate: "2025-10-01", EndDate: "2025-09-01"},
+		{PageSize: maxSavedViewPageSize + 1},
+		{CustomFilters: map[string]string{"Bad-Key": "x"}},
+		{TagIDs: []int{0}},
+		{Query: "stauts:一面中"},
+	}
+	for _, f := range invalid {
+		f := f
+		if err := normalizeSavedViewFilters(&f); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("filters %+v: expected invalid error, got %v", f, err)
+		}
+	}
+}
+
+func TestSavedViewExportFilters(t *testing.T) {
+	status := model.StatusSecondInterview
+	filters := &model.SavedViewFilters{Stage: "interviews", Status: &status, StartDate: "2025-09-01", TagIDs: []int{2}}
+	export, err := savedViewExportFilters(filters)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(export.Status) != 1 || export.Status[0] != status {
+		t.Fatalf("expected status/stage intersection, got %v", export.Status)
+	}
+	if export.DateRange == nil || export.DateRange.Start != "2025-09-01" || export.DateRange.End != "9999-12-31" {
+		t.Fatalf("unexpected date range %+v", export.DateRange)
+	}
+
+	stageOnly, err := savedViewExportFilters(&model.SavedViewFilters{Stage: "screening", Query: "go"})
+	if err != nil || len(stageOnly.Status) != 2 || stageOnly.Search != "go" || stageOnly.Query != "" || stageOnly.DateRange != nil {
+		t.Fatalf("unexpected stage export filters %+v %v", stageOnly, err)
+	}
+
+	salaryView, err := savedViewExportFilters(&model.SavedViewFilters{SalaryMin: floatPtr(20), SalaryMax: floatPtr(35)})
+	if err != nil || salaryView.SalaryMin == nil || *salaryView.SalaryMin != 20 || salaryView.SalaryMax == nil || *salaryView.SalaryMax != 35 {
+		t.Fatalf("expected salary range to be exported, got %+v %v", salaryView, err)
+	}
+
+	offer := model.StatusOfferReceived
+	if _, err := savedViewExportFilters(&model.SavedViewFilters{Stage: "screening", Status: &offer}); err == nil {
+		t.Fatalf("expected empty intersection to be rejected")
+	}
+}
+
+func TestSalaryRangeCondition(t *testing.T) {
+	cond, args, next, err := salaryRangeCondition(floatPtr(20), nil, 3)
+	if err != nil || cond != " AND salary_currency = $3 AND salary_max_monthly >= $4" || next != 5 {
+		t.Fatalf("unexpected condition %q %d %v", cond, next, err)
+	}
+	if len(args) != 2 || args[0] != "CNY" || args[1] != 20000.0 {
+		t.Fatalf("expected CNY restriction and monthly bound, got %v", args)
+	}
+	if cond, args, next, err := salaryRangeCondition(nil, nil, 3); err != nil || cond != "" || args != nil || next != 3 {
+		t.Fatalf("expected no condition without bounds, got %q %v %d %v", cond, args, next, err)
+	}
+	if _, _, _, err := salaryRangeCondition(floatPtr(30), floatPtr(20), 1); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected invalid range error, got %v", err)
+	}
+}
+
+func TestDateRangeCondition(t *testing.T) {
+	cond, args, next, err := dateRangeCondition("2025-09-01", "", 4)
+	if err != nil || cond != " AND application_date >= $4" || len(args) != 1 || next != 5 {
+		t.Fatalf("unexpected condition %q %v %d %v", cond, args, next, err)
+	}
+	if _, _, _, err := dateRangeCondition("", "2025/09/01", 2); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected invalid end date error, got %v", err)
+	}
+}
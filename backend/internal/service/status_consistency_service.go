@@ -0,0 +1,423 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/status_consistency_service.go
+// This file implements the consistency checker for status history storage.
+// job_status_history is the source of truth; the status_history / status_duration_stats JSONB
+// columns on job_applications are projections that can drift (batch updates, trigger writes, backward moves).
+// Used by the status consistency handler and the reconcile CLI to report and rebuild those projections.
+
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// preserveUpdatedAtSetting 只写派生列（投影、关联）时在事务内设置，updated_at 触发器据此保留原值，
+// 避免影响批量操作撤销的修改检测与按更新时间排序
+const preserveUpdatedAtSetting = "SET LOCAL jobview.preserve_updated_at = 'on'"
+
+type StatusConsistencyService struct {
+	db *database.DB
+}
+
+func NewStatusConsistencyService(db *database.DB) *StatusConsistencyService {
+	return &StatusConsistencyService{db: db}
+}
+
+// statusProjectionSource 一条岗位申请的投影与其历史表数据
+type statusProjectionSource struct {
+	ID            int
+	UserID        uint
+	CompanyName   string
+	PositionTitle string
+	Status        model.ApplicationStatus
+	StatusVersion sql.NullInt32
+	HistoryJSON   string
+	StatsJSON     string
+	Entries       []model.StatusHistoryEntry
+}
+
+// Reconcile 检查状态历史一致性，非 dry-run 时从 job_status_history 重建 JSONB 投影
+func (s *StatusConsistencyService) Reconcile(req *model.StatusReconcileRequest) (*model.StatusReconcileResult, error) {
+	result := &model.StatusReconcileResult{
+		DryRun:      req.DryRun,
+		IssueCounts: make(map[model.StatusDriftType]int),
+		Reports:     []model.StatusConsistencyReport{},
+		StartedAt:   time.Now(),
+	}
+
+	ids, err := s.listApplicationIDs(req.UserID, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		report, err := s.reconcileOne(id, req.DryRun)
+		if err != nil {
+			return nil, err
+		}
+		if report == nil {
+			continue // 检查过程中被删除
+		}
+		result.Checked++
+		if len(report.Issues) == 0 {
+			continue
+		}
+		result.Drifted++
+		if report.Repaired {
+			result.Repaired++
+		}
+		for _, issue := range report.Issues {
+			result.IssueCounts[issue.Type]++
+		}
+		result.Reports = append(result.Reports, *report)
+	}
+
+	result.FinishedAt = time.Now()
+	return result, nil
+}
+
+// StartScheduler 按固定间隔执行全量一致性检查；autoFix 为 false 时仅记录日志
+func (s *StatusConsistencyService) StartScheduler(interval time.Duration, autoFix bool) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			result, err := s.Reconcile(&model.StatusReconcileRequest{DryRun: !autoFix})
+			if err != nil {
+				log.Printf("Warning: status consistency reconcile failed: %v", err)
+				continue
+			}
+			if result.Drifted > 0 {
+				log.Printf("[STATUS-RECONCILE] checked=%d drifted=%d repaired=%d issues=%v",
+					result.Checked, result.Drifted, result.Repaired, result.IssueCounts)
+			}
+		}
+	}()
+}
+
+// listApplicationIDs 获取需要检查的岗位申请ID
+func (s *StatusConsistencyService) listApplicationIDs(userID *uint, limit int) ([]int, error) {
+	query := "SELECT id FROM job_applications"
+	args := []interface{}{}
+	if userID != nil {
+		query += " WHERE user_id = $1"
+		args = append(args, *userID)
+	}
+	query += " ORDER BY id"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job applications: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job application id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// reconcileOne 在事务内锁定单条记录，检查并按需重建投影
+func (s *StatusConsistencyService) reconcileOne(id int, dryRun bool) (*model.StatusConsistencyReport, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	src := statusProjectionSource{ID: id}
+	var historyJSON, statsJSON sql.NullString
+	err = tx.QueryRow(`
+		SELECT user_id, company_name, position_title, status, status_version,
+		       status_history::text, status_duration_stats::text
+		FROM job_applications
+		WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&src.UserID, &src.CompanyName, &src.PositionTitle, &src.Status,
+		&src.StatusVersion, &historyJSON, &statsJSON)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load job application %d: %w", id, err)
+	}
+	src.HistoryJSON = historyJSON.String
+	src.StatsJSON = statsJSON.String
+
+	rows, err := tx.Query(`
+		SELECT id, old_status, new_status, status_changed_at, duration_minutes, created_at
+		FROM job_status_history
+		WHERE job_application_id = $1
+		ORDER BY status_changed_at ASC, id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status history for %d: %w", id, err)
+	}
+	for rows.Next() {
+		var entry model.StatusHistoryEntry
+		var oldStatus sql.NullString
+		var duration sql.NullInt32
+		if err := rows.Scan(&entry.ID, &oldStatus, &entry.NewStatus, &entry.StatusChangedAt, &duration, &entry.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan status history for %d: %w", id, err)
+		}
+		if oldStatus.Valid {
+			st := model.ApplicationStatus(oldStatus.String)
+			entry.OldStatus = &st
+		}
+		if duration.Valid {
+			d := int(duration.Int32)
+			entry.DurationMinutes = &d
+		}
+		entry.JobApplicationID = id
+		entry.UserID = src.UserID
+		src.Entries = append(src.Entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status history for %d: %w", id, err)
+	}
+
+	report := &model.StatusConsistencyReport{
+		JobApplicationID: id,
+		UserID:           src.UserID,
+		CompanyName:      src.CompanyName,
+		PositionTitle:    src.PositionTitle,
+		Issues:           detectStatusDrift(src),
+	}
+
+	if dryRun || !hasRepairableIssue(report.Issues) {
+		return report, nil
+	}
+
+	history, stats := rebuildStatusProjections(src.Entries, src.Status, src.StatsJSON)
+	historyBytes, err := json.Marshal(history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status history for %d: %w", id, err)
+	}
+	statsBytes, err := json.Marshal(stats)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal duration stats for %d: %w", id, err)
+	}
+
+	// 仅重写投影，不触发状态变更触发器，也不改变 updated_at（修复不是用户修改）
+	if _, err := tx.Exec("SET LOCAL jobview.skip_history = 'on'"); err != nil {
+		return nil, fmt.Errorf("failed to disable history trigger: %w", err)
+	}
+	if _, err := tx.Exec(preserveUpdatedAtSetting); err != nil {
+		return nil, fmt.Errorf("failed to preserve updated_at: %w", err)
+	}
+	_, err = tx.Exec(`
+		UPDATE job_applications
+		SET status_history = $1::jsonb, status_duration_stats = $2::jsonb
+		WHERE id = $3
+	`, string(historyBytes), string(statsBytes), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild status projections for %d: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	report.Repaired = true
+	return report, nil
+}
+
+// detectStatusDrift 对比历史表与 JSONB 投影，返回发现的问题
+func detectStatusDrift(src statusProjectionSource) []model.StatusDriftIssue {
+	issues := []model.StatusDriftIssue{}
+
+	var projected model.StatusHistory
+	if strings.TrimSpace(src.HistoryJSON) != "" {
+		_ = json.Unmarshal([]byte(src.HistoryJSON), &projected)
+	}
+
+	// 1. 条数不一致
+	if len(projected.History) != len(src.Entries) {
+		issues = append(issues, model.StatusDriftIssue{
+			Type:       model.DriftCountMismatch,
+			Message:    "status_history 条数与 job_status_history 不一致",
+			Expected:   len(src.Entries),
+			Actual:     len(projected.History),
+			Repairable: true,
+		})
+	}
+
+	// 2. 当前状态与最后一条历史不一致（回退确认等场景不会写历史，只能报告）
+	if len(src.Entries) > 0 {
+		last := src.Entries[len(src.Entries)-1]
+		if last.NewStatus != src.Status {
+			issues = append(issues, model.StatusDriftIssue{
+				Type:     model.DriftCurrentStatusMismatch,
+				Message:  "当前状态与最后一条状态历史不一致",
+				Expected: string(last.NewStatus),
+				Actual:   string(src.Status),
+			})
+		}
+	}
+
+	// 3. 负数停留时长
+	negativeRows := 0
+	for _, e := range src.Entries {
+		if e.DurationMinutes != nil && *e.DurationMinutes < 0 {
+			negativeRows++
+		}
+	}
+	if negativeRows > 0 {
+		issues = append(issues, model.StatusDriftIssue{
+			Type:    model.DriftNegativeDuration,
+			Message: "job_status_history 中存在负数停留时长",
+			Actual:  negativeRows,
+		})
+	}
+	negativeProjected := 0
+	for _, e := range projected.History {
+		if e.DurationMinutes != nil && *e.DurationMinutes < 0 {
+			negativeProjected++
+		}
+	}
+	if strings.TrimSpace(src.StatsJSON) != "" {
+		var stats model.DurationStats
+		if err := json.Unmarshal([]byte(src.StatsJSON), &stats); err == nil {
+			for _, d := range stats.StatusDurations {
+				if d.TotalMinutes < 0 {
+					negativeProjected++
+				}
+			}
+		}
+	}
+	if negativeProjected > 0 {
+		issues = append(issues, model.StatusDriftIssue{
+			Type:       model.DriftNegativeDuration,
+			Message:    "JSONB 投影中存在负数停留时长",
+			Actual:     negativeProjected,
+			Repairable: true,
+		})
+	}
+
+	// 4. status_version 与变更次数不匹配（初始版本为1，每次状态变更+1）
+	transitions := 0
+	for _, e := range src.Entries {
+		if e.OldStatus != nil {
+			transitions++
+		}
+	}
+	expectedVersion := 1 + transitions
+	if src.StatusVersion.Valid && int(src.StatusVersion.Int32) != expectedVersion {
+		issues = append(issues, model.StatusDriftIssue{
+			Type:     model.DriftVersionGap,
+			Message:  "status_version 与历史变更次数不匹配",
+			Expected: expectedVersion,
+			Actual:   int(src.StatusVersion.Int32),
+		})
+	}
+
+	// 5. 汇总信息不一致（包括触发器写入的旧结构 summary）
+	if len(projected.History) == len(src.Entries) &&
+		(projected.Metadata.TotalChanges != len(projected.History) ||
+			projected.Metadata.CurrentStatus != string(src.Status)) {
+		issues = append(issues, model.StatusDriftIssue{
+			Type:       model.DriftSummaryMismatch,
+			Message:    "status_history 汇总信息与历史记录不一致",
+			Expected:   map[string]interface{}{"total_changes": len(src.Entries), "current_status": string(src.Status)},
+			Actual:     map[string]interface{}{"total_changes": projected.Metadata.TotalChanges, "current_status": projected.Metadata.CurrentStatus},
+			Repairable: true,
+		})
+	}
+
+	return issues
+}
+
+// hasRepairableIssue 是否存在可重建修复的问题
+func hasRepairableIssue(issues []model.StatusDriftIssue) bool {
+	for _, issue := range issues {
+		if issue.Repairable {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildStatusProjections 从历史表记录重建 status_history 与 status_duration_stats
+// 保留已有统计中的 analytics 部分，负数时长不计入统计
+func rebuildStatusProjections(entries []model.StatusHistoryEntry, currentStatus model.ApplicationStatus, currentStatsStr string) (model.StatusHistory, model.DurationStats) {
+	history := model.StatusHistory{History: make([]model.StatusHistoryEntry, 0, len(entries))}
+
+	var previous model.DurationStats
+	if currentStatsStr != "" {
+		_ = json.Unmarshal([]byte(currentStatsStr), &previous)
+	}
+	stats := model.DurationStats{
+		StatusDurations: make(map[string]model.StatusDuration),
+		Milestones:      make(map[string]time.Time),
+		Analytics:       previous.Analytics,
+	}
+
+	totalDuration := 0
+	for _, e := range entries {
+		entry := model.StatusHistoryEntry{
+			OldStatus:       e.OldStatus,
+			NewStatus:       e.NewStatus,
+			StatusChangedAt: e.StatusChangedAt,
+			CreatedAt:       e.CreatedAt,
+		}
+		if e.DurationMinutes != nil && *e.DurationMinutes >= 0 {
+			d := *e.DurationMinutes
+			entry.DurationMinutes = &d
+		}
+		history.History = append(history.History, entry)
+
+		// 初始记录（old_status 为空）没有前一状态的停留时长
+		if e.OldStatus == nil {
+			continue
+		}
+		if entry.DurationMinutes != nil {
+			totalDuration += *entry.DurationMinutes
+			key := string(*e.OldStatus)
+			d := stats.StatusDurations[key]
+			d.TotalMinutes += *entry.DurationMinutes
+			stats.StatusDurations[key] = d
+		}
+		if _, ok := stats.Milestones["first_response"]; !ok {
+			stats.Milestones["first_response"] = e.StatusChangedAt
+		}
+		if e.NewStatus.IsInProgressStatus() && strings.Contains(string(e.NewStatus), "面") {
+			if _, ok := stats.Milestones["first_interview"]; !ok {
+				stats.Milestones["first_interview"] = e.StatusChangedAt
+			}
+		}
+	}
+
+	if totalDuration > 0 {
+		for key, d := range stats.StatusDurations {
+			d.Percentage = float64(d.TotalMinutes) / float64(totalDuration) * 100
+			stats.StatusDurations[key] = d
+		}
+	}
+
+	history.Metadata.TotalChanges = len(history.History)
+	history.Metadata.CurrentStatus = string(currentStatus)
+	history.Metadata.TotalDurationMinutes = totalDuration
+	if n := len(entries); n > 0 {
+		history.Metadata.LastChanged = entries[n-1].StatusChangedAt
+	}
+
+	return history, stats
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"jobView-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func consistencyEntries(base time.Time) []model.StatusHistoryEntry {
+	applied := model.StatusApplied
+	screening := model.StatusResumeScreening
+	d1, d2 := 60, 120
+	return []model.StatusHistoryEntry{
+		{OldStatus: nil, NewStatus: applied, StatusChangedAt: base},
+		{OldStatus: &applied, NewStatus: screening, StatusChangedAt: base.Add(time.Hour), DurationMinutes: &d1},
+		{OldStatus: &screening, NewStatus: model.StatusFirstInterview, StatusChangedAt: base.Add(3 * time.Hour), DurationMinutes: &d2},
+	}
+}
+
+// Test drift detection and projection rebuild from job_status_history rows
+func TestStatusDriftDetectAndRebuild(t *testing.T) {
+	base := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+	entries := consistencyEntries(base)
+
+	// 批量更新未写 JSONB：条数不一致，版本正常
+	src := statusProjectionSource{
+		Status:        model.StatusFirstInterview,
+		StatusVersion: sql.NullInt32{Int32: 3, Valid: true},
+		HistoryJSON:   `{"history": [], "summary": {}}`,
+		Entries:       entries,
+	}
+	issues := detectStatusDrift(src)
+	if len(issues) != 1 || issues[0].Type != model.DriftCountMismatch || !issues[0].Repairable {
+		t.Fatalf("expected a single repairable count mismatch, got %+v", issues)
+	}
+
+	history, stats := rebuildStatusProjections(entries, src.Status, `{"analytics": {"success_probability": 0.4}}`)
+	if history.Metadata.TotalChanges != 3 || history.Metadata.TotalDurationMinutes != 180 {
+		t.Fatalf("unexpected rebuilt metadata: %+v", history.Metadata)
+	}
+	if stats.StatusDurations[string(model.StatusResumeScreening)].TotalMinutes != 120 {
+		t.Fatalf("expected 120 minutes in screening, got %+v", stats.StatusDurations)
+	}
+	if !stats.Milestones["first_interview"].Equal(base.Add(3 * time.Hour)) {
+		t.Fatalf("unexpected first_interview milestone: %v", stats.Milestones)
+	}
+	if stats.Analytics.SuccessProbability != 0.4 {
+		t.Fatalf("expected analytics to be preserved, got %+v", stats.Analytics)
+	}
+
+	// 重建后的投影不应再报告可修复问题
+	raw, _ := json.Marshal(history)
+	src.HistoryJSON = string(raw)
+	if issues := detectStatusDrift(src); len(issues) != 0 {
+		t.Fatalf("expected no drift after rebuild, got %+v", issues)
+	}
+
+	// 回退未写历史 + 版本跳号：只报告，不可自动修复
+	src.Status = model.StatusResumeScreening
+	src.StatusVersion = sql.NullInt32{Int32: 5, Valid: true}
+	issues = detectStatusDrift(src)
+	types := map[model.StatusDriftType]bool{}
+	for _, issue := range issues {
+		types[issue.Type] = true
+	}
+	if !types[model.DriftCurrentStatusMismatch] || !types[model.DriftVersionGap] {
+		t.Fatalf("expected status mismatch and version gap, got %+v", issues)
+	}
+}
@@ -40,7 +40,7 @@ func (s *StatusTrackingService) GetStatusHistory(userID uint, jobApplicationID i
 
 	// 验证用户权限
 	var exists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2)"
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)"
 	err := s.db.QueryRow(checkQuery, jobApplicationID, userID).Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify job application access: %w", err)
@@ -129,7 +129,7 @@ func (s *StatusTrackingService) getStatusHistoryGorm(userID uint, jobApplication
 
     // 验证用户权限
     var exists bool
-    if err := s.db.ORM.WithContext(ctx).Raw("SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2)", jobApplicationID, userID).Row().Scan(&exists); err != nil {
+    if err := s.db.ORM.WithContext(ctx).Raw("SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", jobApplicationID, userID).Row().Scan(&exists); err != nil {
         return nil, fmt.Errorf("failed to verify job application access: %w", err)
     }
     if !exists { return nil, fmt.Errorf("job application not found or access denied") }
@@ -184,9 +184,14 @@ func (s *StatusTrackingService) getStatusHistoryGorm(userID uint, jobApplication
 
 // UpdateJobStatus 更新岗位状态并记录历史
 func (s *StatusTrackingService) UpdateJobStatus(userID uint, jobApplicationID int, request *model.StatusUpdateRequest) (*model.JobApplication, error) {
+	return s.updateJobStatusWith(userID, jobApplicationID, request, nil)
+}
+
+// updateJobStatusWith 更新岗位状态，after 非空时在同一事务内、提交前执行附加写入（状态未变化时不执行）
+func (s *StatusTrackingService) updateJobStatusWith(userID uint, jobApplicationID int, request *model.StatusUpdateRequest, after func(q operationQueryer) error) (*model.JobApplication, error) {
     // GORM path behind flag
     if s.db != nil && s.db.UseGorm && s.db.ORM != nil {
-        return s.updateJobStatusGorm(userID, jobApplicationID, request)
+        return s.updateJobStatusGorm(userID, jobApplicationID, request, after)
     }
     // 验证状态有效性
     if !request.Status.IsValid() {
@@ -214,7 +219,7 @@ func (s *StatusTrackingService) UpdateJobStatus(userID uint, jobApplicationID in
 		       created_at, updated_at, last_status_change, status_version,
 		       status_history, status_duration_stats
 		FROM job_applications 
-		WHERE id = $1 AND user_id = $2
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 	`
 
 	var lastStatusChange sql.NullTime
@@ -493,6 +498,12 @@ func (s *StatusTrackingService) UpdateJobStatus(userID uint, jobApplicationID in
 		}
 	}
 
+	if after != nil {
+		if err := after(tx); err != nil {
+			return nil, err
+		}
+	}
+
 	// 提交事务
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
@@ -502,7 +513,7 @@ func (s *StatusTrackingService) UpdateJobStatus(userID uint, jobApplicationID in
 }
 
 // updateJobStatusGorm 使用 GORM 事务执行状态更新（保留与原有逻辑一致的行为）
-func (s *StatusTrackingService) updateJobStatusGorm(userID uint, jobApplicationID int, request *model.StatusUpdateRequest) (*model.JobApplication, error) {
+func (s *StatusTrackingService) updateJobStatusGorm(userID uint, jobApplicationID int, request *model.StatusUpdateRequest, after func(q operationQueryer) error) (*model.JobApplication, error) {
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
     defer cancel()
     if !request.Status.IsValid() {
@@ -530,7 +541,7 @@ func (s *StatusTrackingService) updateJobStatusGorm(userID uint, jobApplicationI
                created_at, updated_at, last_status_change, status_version,
                status_history, status_duration_stats
         FROM job_applications 
-        WHERE id = $1 AND user_id = $2`
+        WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`
 
     row := tx.Raw(getCurrentQuery, jobApplicationID, userID).Row()
     if err := row.Scan(
@@ -737,6 +748,12 @@ func (s *StatusTrackingService) updateJobStatusGorm(userID uint, jobApplicationI
         }
     }
 
+    if after != nil {
+        if err := after(gormOperationTx{tx: tx}); err != nil {
+            return nil, err
+        }
+    }
+
     if err := tx.Commit().Error; err != nil {
         return nil, fmt.Errorf("failed to commit transaction: %w", err)
     }
@@ -750,7 +767,7 @@ func (s *StatusTrackingService) GetStatusTimeline(userID uint, jobApplicationID
     }
 	// 验证用户权限
 	var exists bool
-	checkQuery := "SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2)"
+	checkQuery := "SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)"
 	err := s.db.QueryRow(checkQuery, jobApplicationID, userID).Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify job application access: %w", err)
@@ -825,7 +842,7 @@ func (s *StatusTrackingService) getStatusTimelineGorm(userID uint, jobApplicatio
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
     defer cancel()
     var exists bool
-    if err := s.db.ORM.WithContext(ctx).Raw("SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2)", jobApplicationID, userID).Row().Scan(&exists); err != nil {
+    if err := s.db.ORM.WithContext(ctx).Raw("SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", jobApplicationID, userID).Row().Scan(&exists); err != nil {
         return nil, fmt.Errorf("failed to verify job application access: %w", err)
     }
     if !exists { return nil, fmt.Errorf("job application not found or access denied") }
@@ -859,32 +876,41 @@ func (s *StatusTrackingService) getStatusTimelineGorm(userID uint, jobApplicatio
     return map[string]interface{}{"job_application_id": jobApplicationID, "timeline": timeline, "total_duration_minutes": totalDuration, "total_changes": len(timeline)}, nil
 }
 
-// BatchUpdateStatus 批量状态更新
-func (s *StatusTrackingService) BatchUpdateStatus(userID uint, updates []model.BatchStatusUpdate) error {
+// BatchUpdateStatus 批量状态更新，记录为可撤销的批量操作
+func (s *StatusTrackingService) BatchUpdateStatus(userID uint, updates []model.BatchStatusUpdate) (*model.BatchOperationResult, error) {
     if s.db != nil && s.db.UseGorm && s.db.ORM != nil {
         return s.batchUpdateStatusGorm(userID, updates)
     }
 	if len(updates) == 0 {
-		return nil
+		return &model.BatchOperationResult{}, nil
 	}
 	if len(updates) > 100 {
-		return fmt.Errorf("batch size too large: maximum 100 updates allowed")
+		return nil, fmt.Errorf("batch size too large: maximum 100 updates allowed")
 	}
 
 	// 验证所有状态
 	for _, update := range updates {
 		if !update.Status.IsValid() {
-			return fmt.Errorf("invalid status: %s for ID %d", update.Status, update.ID)
+			return nil, fmt.Errorf("invalid status: %s for ID %d", update.Status, update.ID)
 		}
 	}
 
 	// 开始事务
 	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	// 历史由应用侧写入（以便撤销时定位），并标记为批量操作
+	if _, err := tx.Exec("SET LOCAL jobview.skip_history = 'on'"); err != nil {
+		return nil, fmt.Errorf("failed to disable history trigger: %w", err)
+	}
+	if _, err := tx.Exec(auditBatchSetting); err != nil {
+		return nil, fmt.Errorf("failed to mark batch operation: %w", err)
+	}
+
+	recorder := newOperationRecorder(tx, userID)
 	now := time.Now()
 
 	for _, update := range updates {
@@ -894,14 +920,14 @@ func (s *StatusTrackingService) BatchUpdateStatus(userID uint, updates []model.B
 		getCurrentQuery := `
 			SELECT status, last_status_change 
 			FROM job_applications 
-			WHERE id = $1 AND user_id = $2
+			WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
 		`
 		err = tx.QueryRow(getCurrentQuery, update.ID, userID).Scan(&currentStatus, &lastStatusChange)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				continue // 跳过不存在或无权限的记录
 			}
-			return fmt.Errorf("failed to get current status for ID %d: %w", update.ID, err)
+			return nil, fmt.Errorf("failed to get current status for ID %d: %w", update.ID, err)
 		}
 
 		// 跳过相同状态
@@ -911,12 +937,15 @@ func (s *StatusTrackingService) BatchUpdateStatus(userID uint, updates []model.B
 
         // 选项A：批量更新不允许回退
         if s.isBackwardTransition(currentStatus, update.Status) {
-            return fmt.Errorf("backward transitions are not allowed in batch updates (ID %d: %s -> %s)", update.ID, currentStatus, update.Status)
+            return nil, fmt.Errorf("backward transitions are not allowed in batch updates (ID %d: %s -> %s)", update.ID, currentStatus, update.Status)
         }
         // 验证状态转换（前进方向仍按模板/直通规则校验）
         if err := s.validateStatusTransition(userID, currentStatus, update.Status); err != nil {
-            return fmt.Errorf("invalid transition for ID %d: %w", update.ID, err)
+            return nil, fmt.Errorf("invalid transition for ID %d: %w", update.ID, err)
         }
+		if _, err := recorder.capture(update.ID); err != nil {
+			return nil, err
+		}
 
 		// 计算持续时间
 		var durationMinutes *int
@@ -930,11 +959,14 @@ func (s *StatusTrackingService) BatchUpdateStatus(userID uint, updates []model.B
 			INSERT INTO job_status_history (job_application_id, user_id, old_status, new_status, 
 			                               status_changed_at, duration_minutes, metadata)
 			VALUES ($1, $2, $3, $4, $5, $6, '{}')
+			RETURNING id
 		`
-		_, err = tx.Exec(insertHistoryQuery, update.ID, userID, currentStatus, update.Status, now, durationMinutes)
+		var historyID int64
+		err = tx.QueryRow(insertHistoryQuery, update.ID, userID, currentStatus, update.Status, now, durationMinutes).Scan(&historyID)
 		if err != nil {
-			return fmt.Errorf("failed to insert history for ID %d: %w", update.ID, err)
+			return nil, fmt.Errorf("failed to insert history for ID %d: %w", update.ID, err)
 		}
+		recorder.addHistory(update.ID, historyID)
 
 		// 更新状态
 		updateQuery := `
@@ -945,53 +977,68 @@ func (s *StatusTrackingService) BatchUpdateStatus(userID uint, updates []model.B
 		`
 		_, err = tx.Exec(updateQuery, update.Status, now, now, update.ID, userID)
 		if err != nil {
-			return fmt.Errorf("failed to update status for ID %d: %w", update.ID, err)
+			return nil, fmt.Errorf("failed to update status for ID %d: %w", update.ID, err)
 		}
 	}
 
-	return tx.Commit()
+	result, err := recorder.save(model.OperationBatchStatusUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
 }
 
-func (s *StatusTrackingService) batchUpdateStatusGorm(userID uint, updates []model.BatchStatusUpdate) error {
-    if len(updates) == 0 { return nil }
+func (s *StatusTrackingService) batchUpdateStatusGorm(userID uint, updates []model.BatchStatusUpdate) (*model.BatchOperationResult, error) {
+    if len(updates) == 0 { return &model.BatchOperationResult{}, nil }
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
     defer cancel()
-    if len(updates) > 100 { return fmt.Errorf("batch size too large: maximum 100 updates allowed") }
-    for _, u := range updates { if !u.Status.IsValid() { return fmt.Errorf("invalid status: %s for ID %d", u.Status, u.ID) } }
+    if len(updates) > 100 { return nil, fmt.Errorf("batch size too large: maximum 100 updates allowed") }
+    for _, u := range updates { if !u.Status.IsValid() { return nil, fmt.Errorf("invalid status: %s for ID %d", u.Status, u.ID) } }
 
     tx := s.db.ORM.WithContext(ctx).Begin()
-    if tx.Error != nil { return fmt.Errorf("failed to begin tx: %w", tx.Error) }
+    if tx.Error != nil { return nil, fmt.Errorf("failed to begin tx: %w", tx.Error) }
     defer func() { _ = tx.Rollback().Error }()
 
     // 禁用触发器写历史，由应用侧写入
     _ = tx.Exec("SET LOCAL jobview.skip_history = 'on'").Error
+    if err := tx.Exec(auditBatchSetting).Error; err != nil { return nil, fmt.Errorf("failed to mark batch operation: %w", err) }
+    recorder := newOperationRecorder(gormOperationTx{tx: tx}, userID)
     now := time.Now()
 
     for _, u := range updates {
         var currentStatus model.ApplicationStatus
         var lastStatusChange sql.NullTime
-        row := tx.Raw("SELECT status, last_status_change FROM job_applications WHERE id = $1 AND user_id = $2", u.ID, userID).Row()
+        row := tx.Raw("SELECT status, last_status_change FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", u.ID, userID).Row()
         if err := row.Scan(&currentStatus, &lastStatusChange); err != nil {
             if err == sql.ErrNoRows { continue }
-            return fmt.Errorf("failed to get current status for ID %d: %w", u.ID, err)
+            return nil, fmt.Errorf("failed to get current status for ID %d: %w", u.ID, err)
         }
         if currentStatus == u.Status { continue }
-        if s.isBackwardTransition(currentStatus, u.Status) { return fmt.Errorf("backward transitions are not allowed in batch updates (ID %d: %s -> %s)", u.ID, currentStatus, u.Status) }
-        if err := s.validateStatusTransition(userID, currentStatus, u.Status); err != nil { return fmt.Errorf("invalid transition for ID %d: %w", u.ID, err) }
+        if s.isBackwardTransition(currentStatus, u.Status) { return nil, fmt.Errorf("backward transitions are not allowed in batch updates (ID %d: %s -> %s)", u.ID, currentStatus, u.Status) }
+        if err := s.validateStatusTransition(userID, currentStatus, u.Status); err != nil { return nil, fmt.Errorf("invalid transition for ID %d: %w", u.ID, err) }
+        if _, err := recorder.capture(u.ID); err != nil { return nil, err }
 
         var durationMinutes *int
         if lastStatusChange.Valid { d := int(now.Sub(lastStatusChange.Time).Minutes()); durationMinutes = &d }
 
         // 插入历史
-        if err := tx.Exec("INSERT INTO job_status_history (job_application_id, user_id, old_status, new_status, status_changed_at, duration_minutes, metadata) VALUES ($1,$2,$3,$4,$5,$6,'{}')", u.ID, userID, currentStatus, u.Status, now, durationMinutes).Error; err != nil {
-            return fmt.Errorf("failed to insert history for ID %d: %w", u.ID, err)
+        var historyID int64
+        if err := tx.Raw("INSERT INTO job_status_history (job_application_id, user_id, old_status, new_status, status_changed_at, duration_minutes, metadata) VALUES ($1,$2,$3,$4,$5,$6,'{}') RETURNING id", u.ID, userID, currentStatus, u.Status, now, durationMinutes).Row().Scan(&historyID); err != nil {
+            return nil, fmt.Errorf("failed to insert history for ID %d: %w", u.ID, err)
         }
+        recorder.addHistory(u.ID, historyID)
         // 更新主表
         if err := tx.Exec("UPDATE job_applications SET status=$1, last_status_change=$2, updated_at=$3, status_version = COALESCE(status_version,0)+1 WHERE id=$4 AND user_id=$5", u.Status, now, now, u.ID, userID).Error; err != nil {
-            return fmt.Errorf("failed to update status for ID %d: %w", u.ID, err)
+            return nil, fmt.Errorf("failed to update status for ID %d: %w", u.ID, err)
         }
     }
-    return tx.Commit().Error
+    result, err := recorder.save(model.OperationBatchStatusUpdate)
+    if err != nil { return nil, err }
+    if err := tx.Commit().Error; err != nil { return nil, fmt.Errorf("failed to commit tx: %w", err) }
+    return result, nil
 }
 
 // GetStatusAnalytics 获取用户状态分析数据
@@ -1010,7 +1057,7 @@ func (s *StatusTrackingService) GetStatusAnalytics(userID uint) (*model.StatusAn
 	statusQuery := `
 		SELECT status, COUNT(*) as count
 		FROM job_applications 
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 		GROUP BY status
 		ORDER BY count DESC
 	`
@@ -1048,6 +1095,7 @@ func (s *StatusTrackingService) GetStatusAnalytics(userID uint) (*model.StatusAn
 		SELECT old_status, AVG(duration_minutes) as avg_duration
 		FROM job_status_history 
 		WHERE user_id = $1 AND old_status IS NOT NULL AND duration_minutes IS NOT NULL
+		  AND ` + activeHistoryCondition + `
 		GROUP BY old_status
 	`
 	durationRows, err := s.db.Query(durationQuery, userID)
@@ -1078,9 +1126,9 @@ func (s *StatusTrackingService) GetStatusAnalytics(userID uint) (*model.StatusAn
         // reached: 曾经到过该阶段（出现 new_status = Entry）或当前就在该阶段（job_applications.status = Entry）
         reachedQuery := `
             SELECT COUNT(*) FROM (
-                SELECT DISTINCT job_application_id FROM job_status_history WHERE user_id = $1 AND new_status = $2
+                SELECT DISTINCT job_application_id FROM job_status_history WHERE user_id = $1 AND new_status = $2 AND ` + activeHistoryCondition + `
                 UNION
-                SELECT id AS job_application_id FROM job_applications WHERE user_id = $1 AND status = $2
+                SELECT id AS job_application_id FROM job_applications WHERE user_id = $1 AND status = $2 AND deleted_at IS NULL
             ) t`
         var total int
         if err := s.db.QueryRow(reachedQuery, userID, st.Entry).Scan(&total); err != nil {
@@ -1091,7 +1139,7 @@ func (s *StatusTrackingService) GetStatusAnalytics(userID uint) (*model.StatusAn
         passQuery := `
             SELECT COUNT(DISTINCT job_application_id)
             FROM job_status_history
-            WHERE user_id = $1 AND old_status = $2 AND new_status = $3`
+            WHERE user_id = $1 AND old_status = $2 AND new_status = $3 AND ` + activeHistoryCondition
         var passed int
         if err := s.db.QueryRow(passQuery, userID, st.Entry, st.Next).Scan(&passed); err != nil {
             return nil, fmt.Errorf("failed to compute stage pass for %s: %w", st.Name, err)
@@ -1119,7 +1167,7 @@ func (s *StatusTrackingService) getStatusAnalyticsGorm(userID uint) (*model.Stat
     defer cancel()
     analytics := &model.StatusAnalyticsResponse{UserID: userID, StatusDistribution: make(map[string]int), AverageDurations: make(map[string]float64), StageAnalysis: make(map[string]model.StageStatistics)}
     // 分布
-    rows, err := s.db.ORM.WithContext(ctx).Raw("SELECT status, COUNT(*) as count FROM job_applications WHERE user_id = $1 GROUP BY status ORDER BY count DESC", userID).Rows()
+    rows, err := s.db.ORM.WithContext(ctx).Raw("SELECT status, COUNT(*) as count FROM job_applications WHERE user_id = $1 AND deleted_at IS NULL GROUP BY status ORDER BY count DESC", userID).Rows()
     if err != nil { return nil, fmt.Errorf("failed to get status distribution: %w", err) }
     defer rows.Close()
     total := 0
@@ -1140,7 +1188,7 @@ func (s *StatusTrackingService) getStatusAnalyticsGorm(userID uint) (*model.Stat
     if total>0 { analytics.SuccessRate = float64(success)/float64(total)*100 }
 
     // 平均持续时间
-    dRows, err := s.db.ORM.WithContext(ctx).Raw("SELECT old_status, AVG(duration_minutes) as avg_duration FROM job_status_history WHERE user_id = $1 AND old_status IS NOT NULL AND duration_minutes IS NOT NULL GROUP BY old_status", userID).Rows()
+    dRows, err := s.db.ORM.WithContext(ctx).Raw("SELECT old_status, AVG(duration_minutes) as avg_duration FROM job_status_history WHERE user_id = $1 AND old_status IS NOT NULL AND duration_minutes IS NOT NULL AND "+activeHistoryCondition+" GROUP BY old_status", userID).Rows()
     if err != nil { return nil, fmt.Errorf("failed to get average durations: %w", err) }
     defer dRows.Close()
     for dRows.Next() {
@@ -1156,8 +1204,8 @@ func (s *StatusTrackingService) getStatusAnalyticsGorm(userID uint) (*model.Stat
     type stageDef struct{ Name, Entry, Next string }
     stages := []stageDef{{"written", string(model.StatusWrittenTest), string(model.StatusFirstInterview)},{"first", string(model.StatusFirstInterview), string(model.StatusSecondInterview)},{"second", string(model.StatusSecondInterview), string(model.StatusThirdInterview)},{"third", string(model.StatusThirdInterview), string(model.StatusHRInterview)}}
     for _, st := range stages {
-        var totalStage int; if err := s.db.ORM.WithContext(ctx).Raw(`SELECT COUNT(*) FROM (SELECT DISTINCT job_application_id FROM job_status_history WHERE user_id = $1 AND new_status = $2 UNION SELECT id AS job_application_id FROM job_applications WHERE user_id = $1 AND status = $2) t`, userID, st.Entry).Row().Scan(&totalStage); err != nil { return nil, fmt.Errorf("failed to compute stage total for %s: %w", st.Name, err) }
-        var passed int; if err := s.db.ORM.WithContext(ctx).Raw(`SELECT COUNT(DISTINCT job_application_id) FROM job_status_history WHERE user_id = $1 AND old_status = $2 AND new_status = $3`, userID, st.Entry, st.Next).Row().Scan(&passed); err != nil { return nil, fmt.Errorf("failed to compute stage pass for %s: %w", st.Name, err) }
+        var totalStage int; if err := s.db.ORM.WithContext(ctx).Raw(`SELECT COUNT(*) FROM (SELECT DISTINCT job_application_id FROM job_status_history WHERE user_id = $1 AND new_status = $2 AND `+activeHistoryCondition+` UNION SELECT id AS job_application_id FROM job_applications WHERE user_id = $1 AND status = $2 AND deleted_at IS NULL) t`, userID, st.Entry).Row().Scan(&totalStage); err != nil { return nil, fmt.Errorf("failed to compute stage total for %s: %w", st.Name, err) }
+        var passed int; if err := s.db.ORM.WithContext(ctx).Raw(`SELECT COUNT(DISTINCT job_application_id) FROM job_status_history WHERE user_id = $1 AND old_status = $2 AND new_status = $3 AND `+activeHistoryCondition, userID, st.Entry, st.Next).Row().Scan(&passed); err != nil { return nil, fmt.Errorf("failed to compute stage pass for %s: %w", st.Name, err) }
         var rate float64; if totalStage>0 { rate = float64(passed)/float64(totalStage)*100 }
         analytics.StageAnalysis[st.Name] = model.StageStatistics{StageName: st.Name, TotalCount: totalStage, SuccessCount: passed, SuccessRate: rate, AverageDurationDays: 0}
     }
@@ -1178,7 +1226,7 @@ func (s *StatusTrackingService) GetStatusTrends(userID uint, days int) ([]model.
 	trendsQuery := `
 		SELECT DATE(status_changed_at) as date, new_status, COUNT(*) as count
 		FROM job_status_history 
-		WHERE user_id = $1 AND status_changed_at >= $2
+		WHERE user_id = $1 AND status_changed_at >= $2 AND ` + activeHistoryCondition + `
 		GROUP BY DATE(status_changed_at), new_status
 		ORDER BY date DESC, count DESC
 	`
@@ -1209,7 +1257,7 @@ func (s *StatusTrackingService) getStatusTrendsGorm(userID uint, days int) ([]mo
     defer cancel()
     if days <= 0 || days > 365 { days = 30 }
     startDate := time.Now().AddDate(0,0,-days)
-    rows, err := s.db.ORM.WithContext(ctx).Raw("SELECT DATE(status_changed_at) as date, new_status, COUNT(*) as count FROM job_status_history WHERE user_id = $1 AND status_changed_at >= $2 GROUP BY DATE(status_changed_at), new_status ORDER BY date DESC, count DESC", userID, startDate).Rows()
+    rows, err := s.db.ORM.WithContext(ctx).Raw("SELECT DATE(status_changed_at) as date, new_status, COUNT(*) as count FROM job_status_history WHERE user_id = $1 AND status_changed_at >= $2 AND "+activeHistoryCondition+" GROUP BY DATE(status_changed_at), new_status ORDER BY date DESC, count DESC", userID, startDate).Rows()
     if err != nil { return nil, fmt.Errorf("failed to get status trends: %w", err) }
     defer rows.Close()
     var trends []model.StatusTrend
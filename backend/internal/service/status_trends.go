@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/model"
+	"jobView-backend/internal/taxonomy"
+	"sort"
+	"time"
+)
+
+// statusTrendMaxDays 趋势查询允许的最大跨度
+const statusTrendMaxDays = 731
+
+// statusTrendRow 按时间桶聚合后的一行原始数据
+type statusTrendRow struct {
+	Bucket  string
+	Status  model.ApplicationStatus
+	Company string
+	Role    string // 岗位类别 key，未分类为空
+	Level   string // 职级 key，未分类为空
+	Count   int
+}
+
+// GetStatusTrendSeries 按粒度、时区和分组维度统计状态变更趋势，并补齐无数据的时间桶
+func (s *StatusTrackingService) GetStatusTrendSeries(userID uint, req *model.StatusTrendRequest) (*model.StatusTrendResponse, error) {
+	if req.Granularity == "" {
+		req.Granularity = model.TrendGranularityDay
+	}
+	if req.Granularity != model.TrendGranularityDay && req.Granularity != model.TrendGranularityWeek && req.Granularity != model.TrendGranularityMonth {
+		return nil, fmt.Errorf("invalid granularity: %s", req.Granularity)
+	}
+	if req.GroupBy == "" {
+		req.GroupBy = model.TrendGroupByStatus
+	}
+	switch req.GroupBy {
+	case model.TrendGroupByStatus, model.TrendGroupByStage, model.TrendGroupByCompany, model.TrendGroupByRole, model.TrendGroupByLevel:
+	default:
+		return nil, fmt.Errorf("invalid group_by: %s", req.GroupBy)
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %s", req.Timezone)
+	}
+
+	start, end, err := resolveTrendRange(req, time.Now().In(loc), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// 时间桶在用户时区下划分；过滤条件换算为绝对时间以便使用索引
+	query := fmt.Sprintf(`
+		SELECT date_trunc('%s', h.status_changed_at AT TIME ZONE $2) AS bucket,
+		       h.new_status, COALESCE(c.name, ja.company_name),
+		       COALESCE(ja.role_family, ''), COALESCE(ja.role_level, ''), COUNT(*)
+		FROM job_status_history h
+		JOIN job_applications ja ON ja.id = h.job_application_id
+		LEFT JOIN companies c ON c.id = ja.company_id
+		WHERE h.user_id = $1 AND ja.deleted_at IS NULL AND h.status_changed_at >= $3 AND h.status_changed_at < $4
+		GROUP BY 1, 2, 3, 4, 5
+	`, req.Granularity)
+
+	rows, err := s.db.Query(query, userID, req.Timezone, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status trends: %w", err)
+	}
+	defer rows.Close()
+
+	var data []statusTrendRow
+	for rows.Next() {
+		var row statusTrendRow
+		var bucket time.Time
+		if err := rows.Scan(&bucket, &row.Status, &row.Company, &row.Role, &row.Level, &row.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan status trend: %w", err)
+		}
+		row.Bucket = bucket.Format("2006-01-02")
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status trends: %w", err)
+	}
+
+	buckets := trendBuckets(start, end, req.Granularity)
+	groups, trends := buildStatusTrendSeries(data, buckets, req.GroupBy)
+	return &model.StatusTrendResponse{
+		Days:        int(end.Sub(start).Hours()/24) + 1,
+		StartDate:   start.Format("2006-01-02"),
+		EndDate:     end.Format("2006-01-02"),
+		Granularity: req.Granularity,
+		Timezone:    req.Timezone,
+		GroupBy:     req.GroupBy,
+		Buckets:     buckets,
+		Groups:      groups,
+		Trends:      trends,
+	}, nil
+}
+
+// resolveTrendRange 解析用户时区下的起止日期（均为当天0点），未指定时取最近 Days 天
+func resolveTrendRange(req *model.StatusTrendRequest, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	if req.Days <= 0 || req.Days > 365 {
+		req.Days = 30
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if req.EndDate != "" {
+		d, err := time.ParseInLocation("2006-01-02", req.EndDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format: %s", req.EndDate)
+		}
+		end = d
+	}
+	start := end.AddDate(0, 0, -req.Days)
+	if req.StartDate != "" {
+		d, err := time.ParseInLocation("2006-01-02", req.StartDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format: %s", req.StartDate)
+		}
+		start = d
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range: start date is after end date")
+	}
+	if end.Sub(start).Hours()/24 > statusTrendMaxDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range: at most %d days", statusTrendMaxDays)
+	}
+	return start, end, nil
+}
+
+// trendBuckets 生成 [start, end] 内所有时间桶的起始日期（周从周一开始）
+func trendBuckets(start, end time.Time, granularity string) []string {
+	cur := start
+	switch granularity {
+	case model.TrendGranularityWeek:
+		offset := (int(cur.Weekday()) + 6) % 7
+		cur = cur.AddDate(0, 0, -offset)
+	case model.TrendGranularityMonth:
+		cur = time.Date(cur.Year(), cur.Month(), 1, 0, 0, 0, 0, cur.Location())
+	}
+
+	var buckets []string
+	for !cur.After(end) {
+		buckets = append(buckets, cur.Format("2006-01-02"))
+		switch granularity {
+		case model.TrendGranularityWeek:
+			cur = cur.AddDate(0, 0, 7)
+		case model.TrendGranularityMonth:
+			cur = cur.AddDate(0, 1, 0)
+		default:
+			cur = cur.AddDate(0, 0, 1)
+		}
+	}
+	return buckets
+}
+
+// trendGroupKey 返回记录在分组维度下的取值
+func trendGroupKey(row statusTrendRow, groupBy string) string {
+	switch groupBy {
+	case model.TrendGroupByStage:
+		if idx := model.FunnelStageIndex(row.Status); idx >= 0 {
+			return model.FunnelStages[idx].Name
+		}
+		return sankeyOtherNode
+	case model.TrendGroupByCompany:
+		return row.Company
+	case model.TrendGroupByRole:
+		if row.Role == "" {
+			return model.UnclassifiedRoleName
+		}
+		return taxonomy.Active().FamilyName(row.Role)
+	case model.TrendGroupByLevel:
+		if row.Level == "" {
+			return model.UnclassifiedRoleName
+		}
+		return taxonomy.Active().LevelName(row.Level)
+	}
+	return string(row.Status)
+}
+
+// buildStatusTrendSeries 按分组汇总并补零；分组按总量降序排列
+func buildStatusTrendSeries(rows []statusTrendRow, buckets []string, groupBy string) ([]string, []model.StatusTrend) {
+	counts := make(map[string]map[string]int)
+	totals := make(map[string]int)
+	for _, row := range rows {
+		group := trendGroupKey(row, groupBy)
+		if counts[group] == nil {
+			counts[group] = make(map[string]int)
+		}
+		counts[group][row.Bucket] += row.Count
+		totals[group] += row.Count
+	}
+
+	groups := make([]string, 0, len(totals))
+	for group := range totals {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if totals[groups[i]] != totals[groups[j]] {
+			return totals[groups[i]] > totals[groups[j]]
+		}
+		return groups[i] < groups[j]
+	})
+
+	trends := make([]model.StatusTrend, 0, len(buckets)*len(groups))
+	for _, bucket := range buckets {
+		for _, group := range groups {
+			trend := model.StatusTrend{Date: bucket, Group: group, Count: counts[group][bucket]}
+			if groupBy == model.TrendGroupByStatus {
+				trend.Status = group
+			}
+			trends = append(trends, trend)
+		}
+	}
+	return groups, trends
+}
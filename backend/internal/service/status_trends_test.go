@@ -0,0 +1,71 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func TestResolveTrendRangeAndBuckets(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	req := &model.StatusTrendRequest{StartDate: "2025-01-29", EndDate: "2025-03-02"}
+	start, end, err := resolveTrendRange(req, time.Now(), loc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start.Location() != loc || start.Format("2006-01-02") != "2025-01-29" || end.Format("2006-01-02") != "2025-03-02" {
+		t.Fatalf("unexpected range: %v - %v", start, end)
+	}
+
+	weeks := trendBuckets(start, end, model.TrendGranularityWeek)
+	if len(weeks) != 5 || weeks[0] != "2025-01-27" || weeks[4] != "2025-02-24" {
+		t.Fatalf("unexpected week buckets: %v", weeks)
+	}
+	months := trendBuckets(start, end, model.TrendGranularityMonth)
+	if len(months) != 3 || months[0] != "2025-01-01" || months[2] != "2025-03-01" {
+		t.Fatalf("unexpected month buckets: %v", months)
+	}
+	if days := trendBuckets(start, end, model.TrendGranularityDay); len(days) != 33 {
+		t.Fatalf("expected 33 day buckets, got %d", len(days))
+	}
+
+	if _, _, err := resolveTrendRange(&model.StatusTrendRequest{StartDate: "2025-03-03", EndDate: "2025-03-02"}, time.Now(), loc); err == nil {
+		t.Fatalf("expected error for reversed range")
+	}
+}
+
+func TestBuildStatusTrendSeries(t *testing.T) {
+	rows := []statusTrendRow{
+		{Bucket: "2025-03-01", Status: model.StatusFirstInterview, Company: "A", Count: 2},
+		{Bucket: "2025-03-01", Status: model.StatusFirstPass, Company: "B", Count: 1},
+		{Bucket: "2025-03-03", Status: model.StatusApplied, Company: "A", Count: 1},
+	}
+	buckets := []string{"2025-03-01", "2025-03-02", "2025-03-03"}
+
+	groups, trends := buildStatusTrendSeries(rows, buckets, model.TrendGroupByStage)
+	if len(groups) != 2 || groups[0] != "一面" || groups[1] != "已投递" {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+	if len(trends) != 6 {
+		t.Fatalf("expected zero-filled 6 points, got %d", len(trends))
+	}
+	if trends[0].Count != 3 || trends[2].Count != 0 || trends[5].Count != 1 {
+		t.Fatalf("unexpected counts: %+v", trends)
+	}
+
+	groups, trends = buildStatusTrendSeries(rows, buckets, model.TrendGroupByStatus)
+	if len(groups) != 3 || trends[0].Status != trends[0].Group {
+		t.Fatalf("unexpected status series: %v %+v", groups, trends)
+	}
+
+	rows[0].Role, rows[0].Level = "backend", "senior"
+	rows[2].Role, rows[2].Level = "backend", "senior"
+	groups, _ = buildStatusTrendSeries(rows, buckets, model.TrendGroupByRole)
+	if len(groups) != 2 || groups[0] != "后端开发" || groups[1] != model.UnclassifiedRoleName {
+		t.Fatalf("unexpected role groups: %v", groups)
+	}
+	groups, _ = buildStatusTrendSeries(rows, buckets, model.TrendGroupByLevel)
+	if len(groups) != 2 || groups[0] != "高级" || groups[1] != model.UnclassifiedRoleName {
+		t.Fatalf("unexpected level groups: %v", groups)
+	}
+}
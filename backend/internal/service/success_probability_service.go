@@ -0,0 +1,419 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/success_probability_service.go
+// This file implements the scoring engine behind ProcessAnalytics.SuccessProbability.
+// Scores estimate P(reaching an offer | current stage, time already spent in it) from the user's own
+// job_status_history, optionally shrunk towards anonymized global rates, and are stored in status_duration_stats
+// by a background job (StartScoreJob) so that reads never write to job_applications.
+
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// successPriorWeight 先验权重（等价样本数），本人样本越多越接近本人经验值
+const successPriorWeight = 5.0
+
+// defaultStageSuccessRates 无历史数据时各阶段拿到offer的默认概率
+var defaultStageSuccessRates = map[string]float64{
+	"applied":   0.05,
+	"screening": 0.08,
+	"written":   0.12,
+	"first":     0.15,
+	"second":    0.30,
+	"third":     0.40,
+	"hr":        0.60,
+}
+
+type SuccessProbabilityService struct {
+	db *database.DB
+}
+
+func NewSuccessProbabilityService(db *database.DB) *SuccessProbabilityService {
+	return &SuccessProbabilityService{db: db}
+}
+
+// successSample 训练样本：在某阶段停留 DwellDays 天后离开，最终是否拿到offer
+type successSample struct {
+	Stage     string
+	DwellDays float64
+	Success   bool
+}
+
+// useGlobalPrior 是否使用全部用户的匿名历史作为先验（默认关闭）
+func useGlobalPrior() bool {
+	v := os.Getenv("SUCCESS_SCORE_GLOBAL_PRIOR")
+	return strings.EqualFold(v, "true") || v == "1"
+}
+
+// successScoreInterval 后台重新计算成功率的间隔
+const successScoreInterval = time.Hour
+
+// successScoreBatchSize 后台重算时每批加载的用户数，避免一次读入整张申请表
+const successScoreBatchSize = 200
+
+// successCount 某阶段的样本数与其中拿到offer的数量
+type successCount struct {
+	Samples int
+	Offers  int
+}
+
+// successStageCounts 按阶段汇总的样本计数，全局先验只需要阶段级计数
+type successStageCounts map[string]successCount
+
+// countSuccessSamples 按阶段汇总样本
+func countSuccessSamples(samples []successSample) successStageCounts {
+	counts := make(successStageCounts)
+	for _, sample := range samples {
+		c := counts[sample.Stage]
+		c.Samples++
+		if sample.Success {
+			c.Offers++
+		}
+		counts[sample.Stage] = c
+	}
+	return counts
+}
+
+// add 累加另一组计数
+func (c successStageCounts) add(other successStageCounts) {
+	for stage, o := range other {
+		cur := c[stage]
+		cur.Samples += o.Samples
+		cur.Offers += o.Offers
+		c[stage] = cur
+	}
+}
+
+// without 返回扣除 own 之后的计数（用于从全局计数中排除用户本人）
+func (c successStageCounts) without(own successStageCounts) successStageCounts {
+	result := make(successStageCounts, len(c))
+	for stage, cur := range c {
+		o := own[stage]
+		result[stage] = successCount{Samples: cur.Samples - o.Samples, Offers: cur.Offers - o.Offers}
+	}
+	return result
+}
+
+// priorRate 阶段的先验成功率：有全局计数时用全局计数向默认值收缩，否则使用默认值
+func (c successStageCounts) priorRate(stage string) float64 {
+	base := defaultStageSuccessRates[stage]
+	if c == nil {
+		return base
+	}
+	cur := c[stage]
+	return (float64(cur.Offers) + successPriorWeight*base) / (float64(cur.Samples) + successPriorWeight)
+}
+
+// RefreshUserScores 重新计算用户全部申请的成功率并写入 status_duration_stats
+func (s *SuccessProbabilityService) RefreshUserScores(userID uint) (*model.SuccessScoreResult, error) {
+	apps, err := loadApplicationTimelines(s.db, []uint{userID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先验：全局匿名计数（仅使用阶段/结果计数，不涉及其他用户的申请内容）或默认值
+	var global successStageCounts
+	usesGlobal := useGlobalPrior()
+	if usesGlobal {
+		userIDs, err := s.scoredUserIDs()
+		if err != nil {
+			return nil, err
+		}
+		total, byUser, err := s.countUserSamples(userIDs)
+		if err != nil {
+			return nil, err
+		}
+		global = total.without(byUser[userID])
+	}
+
+	result := scoreUserApplications(apps, global, time.Now())
+	result.UsesGlobal = usesGlobal
+	if err := s.saveScores(userID, result.Scores); err != nil {
+		return nil, err
+	}
+	result.Updated = len(result.Scores)
+	return result, nil
+}
+
+// RefreshAllScores 分批重新计算所有用户的成功率；启用全局先验时先分批汇总各阶段计数，
+// 再为每个用户扣除其本人计数。返回更新的申请数
+func (s *SuccessProbabilityService) RefreshAllScores() (int, error) {
+	userIDs, err := s.scoredUserIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	usesGlobal := useGlobalPrior()
+	var total successStageCounts
+	var byUser map[uint]successStageCounts
+	if usesGlobal {
+		if total, byUser, err = s.countUserSamples(userIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	now := time.Now()
+	updated := 0
+	for start := 0; start < len(userIDs); start += successScoreBatchSize {
+		batch := userIDs[start:min(start+successScoreBatchSize, len(userIDs))]
+		apps, err := loadApplicationTimelines(s.db, batch, nil)
+		if err != nil {
+			return updated, err
+		}
+		appsByUser := make(map[uint][]applicationTimeline)
+		for _, app := range apps {
+			appsByUser[app.UserID] = append(appsByUser[app.UserID], app)
+		}
+		for _, userID := range batch {
+			var global successStageCounts
+			if usesGlobal {
+				global = total.without(byUser[userID])
+			}
+			result := scoreUserApplications(appsByUser[userID], global, now)
+			if err := s.saveScores(userID, result.Scores); err != nil {
+				return updated, err
+			}
+			updated += len(result.Scores)
+		}
+	}
+	return updated, nil
+}
+
+// scoredUserIDs 有未删除申请的用户
+func (s *SuccessProbabilityService) scoredUserIDs() ([]uint, error) {
+	rows, err := s.db.Query("SELECT DISTINCT user_id FROM job_applications WHERE deleted_at IS NULL ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scored users: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uint
+	for rows.Next() {
+		var userID uint
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user id: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// countUserSamples 分批加载申请，返回全部用户的阶段计数及每个用户本人的计数
+func (s *SuccessProbabilityService) countUserSamples(userIDs []uint) (successStageCounts, map[uint]successStageCounts, error) {
+	total := make(successStageCounts)
+	byUser := make(map[uint]successStageCounts)
+	for start := 0; start < len(userIDs); start += successScoreBatchSize {
+		apps, err := loadApplicationTimelines(s.db, userIDs[start:min(start+successScoreBatchSize, len(userIDs))], nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, app := range apps {
+			counts := countSuccessSamples(successTrainingSamples(app))
+			if byUser[app.UserID] == nil {
+				byUser[app.UserID] = make(successStageCounts)
+			}
+			byUser[app.UserID].add(counts)
+			total.add(counts)
+		}
+	}
+	return total, byUser, nil
+}
+
+// StartScoreJob 定期在后台重新计算成功率，查询时直接读取已保存的结果
+func (s *SuccessProbabilityService) StartScoreJob() {
+	go func() {
+		ticker := time.NewTicker(successScoreInterval)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			updated, err := s.RefreshAllScores()
+			if err != nil {
+				log.Printf("Warning: success probability refresh failed: %v", err)
+				continue
+			}
+			log.Printf("[SUCCESS-SCORE] applications=%d", updated)
+		}
+	}()
+}
+
+// scoreUserApplications 用同一用户的历史训练并为其全部申请评分
+func scoreUserApplications(apps []applicationTimeline, global successStageCounts, now time.Time) *model.SuccessScoreResult {
+	var userSamples []successSample
+	for _, app := range apps {
+		userSamples = append(userSamples, successTrainingSamples(app)...)
+	}
+
+	result := &model.SuccessScoreResult{
+		Stages: buildSuccessStageRates(userSamples, global),
+		Scores: make(map[int]float64),
+	}
+	for _, app := range apps {
+		result.Scores[app.ID] = scoreApplication(app, userSamples, global, now)
+	}
+	return result
+}
+
+// saveScores 写入 status_duration_stats.analytics.success_probability
+func (s *SuccessProbabilityService) saveScores(userID uint, scores map[int]float64) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 只更新投影字段，不触发状态历史，也不改变 updated_at（撤销与按更新时间排序依赖它）
+	if _, err := tx.Exec("SET LOCAL jobview.skip_history = 'on'"); err != nil {
+		return fmt.Errorf("failed to disable history trigger: %w", err)
+	}
+	if _, err := tx.Exec(preserveUpdatedAtSetting); err != nil {
+		return fmt.Errorf("failed to preserve updated_at: %w", err)
+	}
+	stmt, err := tx.Prepare(`
+		UPDATE job_applications
+		SET status_duration_stats = COALESCE(status_duration_stats, '{}'::jsonb) ||
+		    jsonb_build_object('analytics',
+		        COALESCE(status_duration_stats->'analytics', '{}'::jsonb) ||
+		        jsonb_build_object('success_probability', $1::float8))
+		WHERE id = $2 AND user_id = $3
+		  AND (status_duration_stats->'analytics'->>'success_probability')::float8 IS DISTINCT FROM $1::float8
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare score update: %w", err)
+	}
+	defer stmt.Close()
+
+	for id, score := range scores {
+		if _, err := stmt.Exec(score, id, userID); err != nil {
+			return fmt.Errorf("failed to save success probability for %d: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// successStageOf 返回状态对应的评分阶段；终态（offer/失败/流程结束）返回空
+func successStageOf(st model.ApplicationStatus) string {
+	if st.IsFailedStatus() || isOfferStatus(st) || st == model.StatusProcessFinished {
+		return ""
+	}
+	idx := model.FunnelStageIndex(st)
+	if idx < 0 {
+		return ""
+	}
+	key := model.FunnelStages[idx].Key
+	if _, ok := defaultStageSuccessRates[key]; !ok {
+		return ""
+	}
+	return key
+}
+
+// successOutcome 判断申请是否已有结果以及是否拿到offer
+func successOutcome(app applicationTimeline) (resolved bool, success bool) {
+	for _, e := range app.History {
+		if isOfferStatus(e.NewStatus) {
+			return true, true
+		}
+	}
+	if isOfferStatus(app.Status) {
+		return true, true
+	}
+	if app.Status.IsFailedStatus() || app.Status == model.StatusProcessFinished {
+		return true, false
+	}
+	return false, false
+}
+
+// successTrainingSamples 把已有结果的申请拆成“阶段 + 停留时长 -> 结果”样本
+func successTrainingSamples(app applicationTimeline) []successSample {
+	resolved, success := successOutcome(app)
+	if !resolved {
+		return nil
+	}
+
+	var samples []successSample
+	since := app.StartedAt
+	for _, e := range app.History {
+		if e.OldStatus == nil {
+			continue
+		}
+		if stage := successStageOf(*e.OldStatus); stage != "" {
+			samples = append(samples, successSample{Stage: stage, DwellDays: daysBetween(since, e.StatusChangedAt), Success: success})
+		}
+		since = e.StatusChangedAt
+	}
+	return samples
+}
+
+// smoothedRate 在停留至少 dwell 天的样本中统计成功率，并向先验收缩
+func smoothedRate(samples []successSample, stage string, dwell float64, prior float64) (float64, int, int) {
+	n, offers := 0, 0
+	for _, sample := range samples {
+		if sample.Stage == stage && sample.DwellDays >= dwell {
+			n++
+			if sample.Success {
+				offers++
+			}
+		}
+	}
+	return (float64(offers) + successPriorWeight*prior) / (float64(n) + successPriorWeight), n, offers
+}
+
+// buildSuccessStageRates 汇总各阶段的基础成功率（不考虑停留时长）；global 为空时使用默认先验
+func buildSuccessStageRates(userSamples []successSample, global successStageCounts) []model.SuccessStageRate {
+	var rates []model.SuccessStageRate
+	for _, stage := range model.FunnelStages {
+		if _, ok := defaultStageSuccessRates[stage.Key]; !ok {
+			continue
+		}
+		prior := global.priorRate(stage.Key)
+		rate, n, offers := smoothedRate(userSamples, stage.Key, 0, prior)
+		rates = append(rates, model.SuccessStageRate{
+			StageKey:   stage.Key,
+			StageName:  stage.Name,
+			Samples:    n,
+			Offers:     offers,
+			PriorRate:  roundProbability(prior),
+			SmoothRate: roundProbability(rate),
+		})
+	}
+	return rates
+}
+
+// scoreApplication 估计单条申请最终拿到offer的概率
+func scoreApplication(app applicationTimeline, userSamples []successSample, global successStageCounts, now time.Time) float64 {
+	if resolved, success := successOutcome(app); resolved {
+		if success {
+			return 1
+		}
+		return 0
+	}
+
+	stage := successStageOf(app.Status)
+	if stage == "" {
+		return 0
+	}
+	since := app.StartedAt
+	if n := len(app.History); n > 0 && app.History[n-1].OldStatus != nil {
+		since = app.History[n-1].StatusChangedAt
+	}
+	dwell := daysBetween(since, now)
+
+	// 阶段基础成功率（本人 -> 全局/默认先验），再按停留时长条件化
+	prior := global.priorRate(stage)
+	stageRate, _, _ := smoothedRate(userSamples, stage, 0, prior)
+	rate, _, _ := smoothedRate(userSamples, stage, dwell, stageRate)
+	return roundProbability(rate)
+}
+
+// roundProbability 保留4位小数
+func roundProbability(p float64) float64 {
+	return math.Round(p*10000) / 10000
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"testing"
+	"time"
+)
+
+func successTimeline(id int, base time.Time, statuses ...model.ApplicationStatus) applicationTimeline {
+	app := applicationTimeline{ID: id, StartedAt: base, Status: statuses[len(statuses)-1]}
+	prev := model.StatusApplied
+	for i, st := range statuses {
+		old := prev
+		app.History = append(app.History, model.StatusHistoryEntry{
+			OldStatus:       &old,
+			NewStatus:       st,
+			StatusChangedAt: base.Add(time.Duration(i+1) * 24 * time.Hour),
+		})
+		prev = st
+	}
+	return app
+}
+
+// Test success probability scoring from the user's own history
+func TestScoreApplication(t *testing.T) {
+	base := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	history := []applicationTimeline{
+		successTimeline(1, base, model.StatusFirstInterview, model.StatusSecondInterview, model.StatusOfferReceived),
+		successTimeline(2, base, model.StatusFirstInterview, model.StatusFirstFail),
+		successTimeline(3, base, model.StatusFirstInterview, model.StatusSecondInterview, model.StatusOfferReceived),
+	}
+	var samples []successSample
+	for _, app := range history {
+		samples = append(samples, successTrainingSamples(app)...)
+	}
+
+	now := base.Add(10 * 24 * time.Hour)
+	if p := scoreApplication(history[0], samples, nil, now); p != 1 {
+		t.Fatalf("expected offer to score 1, got %v", p)
+	}
+	if p := scoreApplication(history[1], samples, nil, now); p != 0 {
+		t.Fatalf("expected failure to score 0, got %v", p)
+	}
+
+	open := successTimeline(4, base, model.StatusFirstInterview, model.StatusSecondInterview)
+	// 二面基础成功率：本人2个样本都拿到offer，向默认先验 0.30 收缩 => (2 + 5*0.3) / (2 + 5) = 0.5
+	// 停留1天：同样停留过1天的2个样本 => (2 + 5*0.5) / (2 + 5) ≈ 0.6429
+	second := scoreApplication(open, samples, nil, base.Add(3*24*time.Hour))
+	if second != 0.6429 {
+		t.Fatalf("expected dwell-conditioned second-round score 0.6429, got %v", second)
+	}
+	// 停留8天：没有停留这么久的样本，回落到阶段基础成功率
+	if p := scoreApplication(open, samples, nil, now); p != 0.5 {
+		t.Fatalf("expected long-dwell score to fall back to 0.5, got %v", p)
+	}
+	early := successTimeline(5, base, model.StatusApplied)
+	early.History = nil
+	if p := scoreApplication(early, samples, nil, now); p >= second {
+		t.Fatalf("expected earlier stage to score lower than second round, got %v", p)
+	}
+}
+
+// Test batch scoring trains on the user's own history and scores every application
+func TestScoreUserApplications(t *testing.T) {
+	base := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	apps := []applicationTimeline{
+		successTimeline(1, base, model.StatusFirstInterview, model.StatusSecondInterview, model.StatusOfferReceived),
+		successTimeline(2, base, model.StatusFirstInterview, model.StatusFirstFail),
+		successTimeline(3, base, model.StatusFirstInterview, model.StatusSecondInterview),
+	}
+	var samples []successSample
+	for _, app := range apps {
+		samples = append(samples, successTrainingSamples(app)...)
+	}
+
+	now := base.Add(10 * 24 * time.Hour)
+	result := scoreUserApplications(apps, nil, now)
+	if len(result.Scores) != 3 || result.Scores[1] != 1 || result.Scores[2] != 0 {
+		t.Fatalf("unexpected scores: %+v", result.Scores)
+	}
+	if want := scoreApplication(apps[2], samples, nil, now); result.Scores[3] != want {
+		t.Fatalf("expected open application score %v, got %v", want, result.Scores[3])
+	}
+	if len(result.Stages) == 0 {
+		t.Fatalf("expected stage rates to be reported")
+	}
+}
+
+// Test the global prior is built from stage counts with the user's own samples subtracted
+func TestSuccessStageCountsPrior(t *testing.T) {
+	base := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	own := countSuccessSamples(successTrainingSamples(
+		successTimeline(1, base, model.StatusSecondInterview, model.StatusSecondFail)))
+	others := countSuccessSamples(append(
+		successTrainingSamples(successTimeline(2, base, model.StatusSecondInterview, model.StatusOfferReceived)),
+		successTrainingSamples(successTimeline(3, base, model.StatusSecondInterview, model.StatusOfferReceived))...))
+
+	total := make(successStageCounts)
+	total.add(own)
+	total.add(others)
+	global := total.without(own)
+	if global["second"] != others["second"] {
+		t.Fatalf("expected own samples to be subtracted, got %+v want %+v", global["second"], others["second"])
+	}
+	// 他人二面2个样本都拿到offer => (2 + 5*0.3) / (2 + 5) = 0.5
+	if p := roundProbability(global.priorRate("second")); p != 0.5 {
+		t.Fatalf("expected global prior 0.5, got %v", p)
+	}
+	var none successStageCounts
+	if p := none.priorRate("second"); p != defaultStageSuccessRates["second"] {
+		t.Fatalf("expected default prior without global counts, got %v", p)
+	}
+}
@@ -0,0 +1,374 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/tag_service.go
+// This file implements user-scoped tags: CRUD, assigning tags to job applications,
+// and the helpers used by list/search/export to filter by tag and attach tags to results.
+
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultTagColor       = "#6366f1"
+	maxTagNameLength      = 50
+	maxTagsPerApplication = 20
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type TagService struct {
+	db *database.DB
+}
+
+func NewTagService(db *database.DB) *TagService {
+	return &TagService{db: db}
+}
+
+// List 获取用户的全部标签及使用次数
+func (s *TagService) List(userID uint) ([]model.Tag, error) {
+	rows, err := s.db.Query(`
+		SELECT t.id, t.user_id, t.name, t.color, COUNT(ja.id), t.created_at, t.updated_at
+		FROM tags t
+		LEFT JOIN job_application_tags jt ON jt.tag_id = t.id
+		LEFT JOIN job_applications ja ON ja.id = jt.job_application_id AND ja.deleted_at IS NULL
+		WHERE t.user_id = $1
+		GROUP BY t.id
+		ORDER BY LOWER(t.name)
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.Color, &tag.ApplicationCount,
+			&tag.CreatedAt, &tag.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Create 创建标签，同一用户下名称不区分大小写唯一
+func (s *TagService) Create(userID uint, req *model.CreateTagRequest) (*model.Tag, error) {
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	color, err := normalizeTagColor(req.Color)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(userID, name, 0); err != nil {
+		return nil, err
+	}
+
+	tag := &model.Tag{UserID: userID, Name: name, Color: color}
+	err = s.db.QueryRow(`
+		INSERT INTO tags (user_id, name, color)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, userID, name, color).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return tag, nil
+}
+
+// Update 修改标签名称或颜色
+func (s *TagService) Update(userID uint, id int, req *model.UpdateTagRequest) (*model.Tag, error) {
+	setParts := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if req.Name != nil {
+		name, err := normalizeTagName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkNameAvailable(userID, name, id); err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, name)
+		argIndex++
+	}
+	if req.Color != nil {
+		if *req.Color == "" {
+			return nil, fmt.Errorf("invalid color: must be #RRGGBB")
+		}
+		color, err := normalizeTagColor(*req.Color)
+		if err != nil {
+			return nil, err
+		}
+		setParts = append(setParts, fmt.Sprintf("color = $%d", argIndex))
+		args = append(args, color)
+		argIndex++
+	}
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("invalid request: no fields to update")
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE tags SET %s, updated_at = NOW()
+		WHERE id = $%d AND user_id = $%d
+		RETURNING id, user_id, name, color, created_at, updated_at
+	`, strings.Join(setParts, ", "), argIndex, argIndex+1)
+	args = append(args, id, userID)
+
+	var tag model.Tag
+	err := s.db.QueryRow(query, args...).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.Color, &tag.CreatedAt, &tag.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tag not found or access denied")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+	if err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM job_application_tags jt
+		JOIN job_applications ja ON ja.id = jt.job_application_id AND ja.deleted_at IS NULL
+		WHERE jt.tag_id = $1
+	`, tag.ID).Scan(&tag.ApplicationCount); err != nil {
+		return nil, fmt.Errorf("failed to count tagged applications: %w", err)
+	}
+	return &tag, nil
+}
+
+// Delete 删除标签，关联关系随之删除
+func (s *TagService) Delete(userID uint, id int) error {
+	result, err := s.db.Exec("DELETE FROM tags WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("tag not found or access denied")
+	}
+	return nil
+}
+
+// GetApplicationTags 获取申请的标签
+func (s *TagService) GetApplicationTags(userID uint, applicationID int) ([]model.ApplicationTag, error) {
+	var exists bool
+	if err := s.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)",
+		applicationID, userID,
+	).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check job application: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("job application not found or access denied")
+	}
+
+	tagsByApp, err := loadApplicationTags(s.db, userID, []int{applicationID})
+	if err != nil {
+		return nil, err
+	}
+	if tags := tagsByApp[applicationID]; tags != nil {
+		return tags, nil
+	}
+	return []model.ApplicationTag{}, nil
+}
+
+// SetApplicationTags 整体替换申请的标签，标签必须属于当前用户
+func (s *TagService) SetApplicationTags(userID uint, applicationID int, tagIDs []int) ([]model.ApplicationTag, error) {
+	tagIDs, err := normalizeTagIDs(tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tagIDs) > maxTagsPerApplication {
+		return nil, fmt.Errorf("invalid request: at most %d tags per application", maxTagsPerApplication)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(
+		"SELECT id FROM job_applications WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+		applicationID, userID,
+	).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("job application not found or access denied")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job application: %w", err)
+	}
+
+	if len(tagIDs) > 0 {
+		inClause, args, _ := tagIDPlaceholders(tagIDs, 2)
+		var owned int
+		if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM tags WHERE user_id = $1 AND id IN (%s)", inClause),
+			append([]interface{}{userID}, args...)...).Scan(&owned); err != nil {
+			return nil, fmt.Errorf("failed to check tags: %w", err)
+		}
+		if owned != len(tagIDs) {
+			return nil, fmt.Errorf("tag not found or access denied")
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM job_application_tags WHERE job_application_id = $1", applicationID); err != nil {
+		return nil, fmt.Errorf("failed to clear application tags: %w", err)
+	}
+	if len(tagIDs) > 0 {
+		values := make([]string, len(tagIDs))
+		args := []interface{}{applicationID}
+		for i, tagID := range tagIDs {
+			values[i] = fmt.Sprintf("($1, $%d)", i+2)
+			args = append(args, tagID)
+		}
+		query := "INSERT INTO job_application_tags (job_application_id, tag_id) VALUES " + strings.Join(values, ", ")
+		if _, err := tx.Exec(query, args...); err != nil {
+			return nil, fmt.Errorf("failed to assign application tags: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return s.GetApplicationTags(userID, applicationID)
+}
+
+// checkNameAvailable 检查名称是否已被用户的其他标签使用
+func (s *TagService) checkNameAvailable(userID uint, name string, excludeID int) error {
+	var exists bool
+	if err := s.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM tags WHERE user_id = $1 AND LOWER(name) = LOWER($2) AND id <> $3)",
+		userID, name, excludeID,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check tag name uniqueness: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("tag name '%s' already exists", name)
+	}
+	return nil
+}
+
+// normalizeTagName 去除首尾空白并校验长度
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("invalid name: tag name is required")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", fmt.Errorf("invalid name: at most %d characters", maxTagNameLength)
+	}
+	return name, nil
+}
+
+// normalizeTagColor 校验 #RRGGBB 格式并统一为小写，为空时使用默认颜色
+func normalizeTagColor(color string) (string, error) {
+	color = strings.TrimSpace(color)
+	if color == "" {
+		return defaultTagColor, nil
+	}
+	if !tagColorPattern.MatchString(color) {
+		return "", fmt.Errorf("invalid color: must be #RRGGBB")
+	}
+	return strings.ToLower(color), nil
+}
+
+// normalizeTagIDs 校验并去重标签ID，保持原有顺序
+func normalizeTagIDs(tagIDs []int) ([]int, error) {
+	seen := make(map[int]bool, len(tagIDs))
+	unique := make([]int, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid tag id: %d", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// tagIDPlaceholders 生成从 $startIndex 开始的占位符，返回下一个参数序号
+func tagIDPlaceholders(tagIDs []int, startIndex int) (string, []interface{}, int) {
+	placeholders := make([]string, len(tagIDs))
+	args := make([]interface{}, len(tagIDs))
+	for i, id := range tagIDs {
+		placeholders[i] = fmt.Sprintf("$%d", startIndex+i)
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args, startIndex + len(tagIDs)
+}
+
+// tagFilterCondition 标签筛选条件（命中任一标签），tagIDs 为空时返回空串
+func tagFilterCondition(tagIDs []int, argIndex int) (string, []interface{}, int) {
+	if len(tagIDs) == 0 {
+		return "", nil, argIndex
+	}
+	inClause, args, next := tagIDPlaceholders(tagIDs, argIndex)
+	condition := fmt.Sprintf(" AND EXISTS (SELECT 1 FROM job_application_tags jt WHERE jt.job_application_id = job_applications.id AND jt.tag_id IN (%s))", inClause)
+	return condition, args, next
+}
+
+// loadApplicationTags 批量读取申请的标签，按标签名排序
+func loadApplicationTags(db *database.DB, userID uint, applicationIDs []int) (map[int][]model.ApplicationTag, error) {
+	result := make(map[int][]model.ApplicationTag)
+	if len(applicationIDs) == 0 {
+		return result, nil
+	}
+
+	inClause, args, _ := tagIDPlaceholders(applicationIDs, 2)
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT jt.job_application_id, t.id, t.name, t.color
+		FROM job_application_tags jt
+		JOIN tags t ON t.id = jt.tag_id
+		WHERE t.user_id = $1 AND jt.job_application_id IN (%s)
+		ORDER BY LOWER(t.name)
+	`, inClause), append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query application tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var applicationID int
+		var tag model.ApplicationTag
+		if err := rows.Scan(&applicationID, &tag.ID, &tag.Name, &tag.Color); err != nil {
+			return nil, fmt.Errorf("failed to scan application tag: %w", err)
+		}
+		result[applicationID] = append(result[applicationID], tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate application tags: %w", err)
+	}
+	return result, nil
+}
+
+// attachApplicationTags 为查询结果填充标签
+func attachApplicationTags(db *database.DB, userID uint, jobs []model.JobApplication) error {
+	ids := make([]int, len(jobs))
+	for i := range jobs {
+		ids[i] = jobs[i].ID
+	}
+	tagsByApp, err := loadApplicationTags(db, userID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		jobs[i].Tags = tagsByApp[jobs[i].ID]
+	}
+	return nil
+}
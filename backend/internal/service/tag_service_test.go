@@ -0,0 +1,80 @@
+package service
+
+import (
+	"jobView-backend/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestTagNameAndColorValidation(t *testing.T) {
+	if name, err := normalizeTagName("  远程  "); err != nil || name != "远程" {
+		t.Fatalf("expected trimmed name, got %q %v", name, err)
+	}
+	for _, name := range []string{"", "   ", strings.Repeat("标", maxTagNameLength+1)} {
+		if _, err := normalizeTagName(name); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("name %q: expected invalid error, got %v", name, err)
+		}
+	}
+	if _, err := normalizeTagName(strings.Repeat("标", maxTagNameLength)); err != nil {
+		t.Fatalf("name at max length should be accepted: %v", err)
+	}
+
+	if color, err := normalizeTagColor(""); err != nil || color != defaultTagColor {
+		t.Fatalf("expected default color, got %q %v", color, err)
+	}
+	if color, err := normalizeTagColor("#10B981"); err != nil || color != "#10b981" {
+		t.Fatalf("expected lowercase color, got %q %v", color, err)
+	}
+	for _, color := range []string{"10b981", "#10b98", "#10b9811", "#gggggg", "red"} {
+		if _, err := normalizeTagColor(color); err == nil {
+			t.Fatalf("color %q should be rejected", color)
+		}
+	}
+}
+
+func TestTagIDsAndFilterCondition(t *testing.T) {
+	ids, err := normalizeTagIDs([]int{3, 1, 3, 2, 1})
+	if err != nil || len(ids) != 3 || ids[0] != 3 || ids[1] != 1 || ids[2] != 2 {
+		t.Fatalf("expected deduplicated ids in order, got %v %v", ids, err)
+	}
+	if _, err := normalizeTagIDs([]int{1, 0}); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected invalid tag id error, got %v", err)
+	}
+
+	tooMany := make([]int, maxTagsPerApplication+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	if _, err := (&TagService{}).SetApplicationTags(1, 1, tooMany); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected too many tags error, got %v", err)
+	}
+
+	if cond, args, next := tagFilterCondition(nil, 4); cond != "" || args != nil || next != 4 {
+		t.Fatalf("empty filter should add nothing, got %q %v %d", cond, args, next)
+	}
+	cond, args, next := tagFilterCondition([]int{5, 8}, 3)
+	if !strings.Contains(cond, "jt.tag_id IN ($3, $4)") || !strings.HasPrefix(cond, " AND EXISTS") {
+		t.Fatalf("unexpected tag condition %q", cond)
+	}
+	if len(args) != 2 || args[0] != 5 || args[1] != 8 || next != 5 {
+		t.Fatalf("unexpected args %v next %d", args, next)
+	}
+}
+
+func TestBulkEditWhereTagFilter(t *testing.T) {
+	s := &JobApplicationService{}
+	filter := &model.BulkEditFilter{ExportFilters: model.ExportFilters{Keywords: "go", TagIDs: []int{7, 7}}}
+	where, args, err := s.bulkEditWhere(1, filter, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// $2 user_id, $3 keyword, $4 tag（重复ID去重）
+	if !strings.Contains(where, "ILIKE $3") || !strings.Contains(where, "jt.tag_id IN ($4)") || len(args) != 3 {
+		t.Fatalf("unexpected where %q args %v", where, args)
+	}
+
+	filter.TagIDs = []int{-1}
+	if _, _, err := s.bulkEditWhere(1, filter, 1); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Fatalf("expected invalid tag id error, got %v", err)
+	}
+}
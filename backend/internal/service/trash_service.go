@@ -0,0 +1,229 @@
+// Location: /Users/lutao/GolandProjects/jobView/backend/internal/service/trash_service.go
+// This file implements the trash (soft delete) of job applications: listing deleted applications,
+// restoring them, purging them permanently and the retention job that purges expired ones.
+
+package service
+
+import (
+	"fmt"
+	"jobView-backend/internal/database"
+	"jobView-backend/internal/model"
+	"log"
+	"strings"
+	"time"
+)
+
+// activeHistoryCondition 排除已删除（回收站中）申请的状态历史
+const activeHistoryCondition = "NOT EXISTS (SELECT 1 FROM job_applications d WHERE d.id = job_application_id AND d.deleted_at IS NOT NULL)"
+
+// maxTrashBatchSize 单次恢复/彻底删除的最大数量
+const maxTrashBatchSize = 100
+
+type TrashService struct {
+	db            *database.DB
+	retentionDays int
+}
+
+func NewTrashService(db *database.DB, retentionDays int) *TrashService {
+	return &TrashService{db: db, retentionDays: retentionDays}
+}
+
+// List 分页列出回收站中的申请，按删除时间倒序
+func (s *TrashService) List(userID uint, page, pageSize int) (*model.PaginationResponse, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var total int64
+	if err := s.db.QueryRow(
+		"SELECT COUNT(*) FROM job_applications WHERE user_id = $1 AND deleted_at IS NOT NULL", userID,
+	).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count trash: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, company_name, position_title, application_date, status, deleted_at
+		FROM job_applications
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash: %w", err)
+	}
+	defer rows.Close()
+
+	items := []model.TrashItem{}
+	for rows.Next() {
+		var item model.TrashItem
+		if err := rows.Scan(&item.ID, &item.CompanyName, &item.PositionTitle, &item.ApplicationDate,
+			&item.Status, &item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trash item: %w", err)
+		}
+		if s.retentionDays > 0 {
+			purgeAt := item.DeletedAt.AddDate(0, 0, s.retentionDays)
+			item.PurgeAt = &purgeAt
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trash: %w", err)
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return &model.PaginationResponse{
+		Data:       items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+// Restore 从回收站恢复申请
+func (s *TrashService) Restore(userID uint, ids []int) (int64, error) {
+	if err := validateTrashIDs(ids); err != nil {
+		return 0, err
+	}
+	inClause, args := trashIDArgs(userID, ids)
+	query := fmt.Sprintf(`
+		UPDATE job_applications
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NOT NULL AND id IN (%s)
+	`, inClause)
+
+	affected, err := s.exec(len(ids) > 1, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore job applications: %w", err)
+	}
+	if affected == 0 {
+		return 0, fmt.Errorf("job application not found or access denied")
+	}
+	return affected, nil
+}
+
+// Purge 彻底删除回收站中的申请（不可恢复）
+func (s *TrashService) Purge(userID uint, ids []int) (int64, error) {
+	if err := validateTrashIDs(ids); err != nil {
+		return 0, err
+	}
+	inClause, args := trashIDArgs(userID, ids)
+	query := fmt.Sprintf(`
+		DELETE FROM job_applications
+		WHERE user_id = $1 AND deleted_at IS NOT NULL AND id IN (%s)
+	`, inClause)
+
+	affected, err := s.exec(len(ids) > 1, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge job applications: %w", err)
+	}
+	if affected == 0 {
+		return 0, fmt.Errorf("job application not found or access denied")
+	}
+	return affected, nil
+}
+
+// Empty 清空用户的回收站
+func (s *TrashService) Empty(userID uint) (int64, error) {
+	affected, err := s.exec(true,
+		"DELETE FROM job_applications WHERE user_id = $1 AND deleted_at IS NOT NULL", userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+	return affected, nil
+}
+
+// PurgeExpired 彻底删除超过保留天数的回收站记录，retentionDays <= 0 表示不自动清理
+func (s *TrashService) PurgeExpired(retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	affected, err := s.exec(true,
+		"DELETE FROM job_applications WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - make_interval(days => $1)",
+		retentionDays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired trash: %w", err)
+	}
+	return affected, nil
+}
+
+// StartPurgeJob 每天清理一次过期的回收站记录
+func (s *TrashService) StartPurgeJob() {
+	if s.retentionDays <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for ; true; <-ticker.C {
+			purged, err := s.PurgeExpired(s.retentionDays)
+			if err != nil {
+				log.Printf("Warning: trash retention failed: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("[TRASH-RETENTION] purged=%d retention_days=%d", purged, s.retentionDays)
+			}
+		}
+	}()
+}
+
+// exec 在事务内执行；batch 为 true 时审计日志记为 batch_*
+func (s *TrashService) exec(batch bool, query string, args ...interface{}) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if batch {
+		if _, err := tx.Exec(auditBatchSetting); err != nil {
+			return 0, err
+		}
+	}
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// validateTrashIDs 校验待恢复/删除的ID列表
+func validateTrashIDs(ids []int) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("invalid ids: at least one id is required")
+	}
+	if len(ids) > maxTrashBatchSize {
+		return fmt.Errorf("invalid ids: at most %d ids allowed, got %d", maxTrashBatchSize, len(ids))
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("invalid id: %d", id)
+		}
+	}
+	return nil
+}
+
+// trashIDArgs 构建 IN 子句占位符与参数（$1 为 userID）
+func trashIDArgs(userID uint, ids []int) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, userID)
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args = append(args, id)
+	}
+	return strings.Join(placeholders, ", "), args
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrashIDValidation(t *testing.T) {
+	s := &TrashService{}
+	tooMany := make([]int, maxTrashBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	for _, ids := range [][]int{nil, {0}, {3, -1}, tooMany} {
+		if _, err := s.Restore(1, ids); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("restore %v: expected invalid error, got %v", ids, err)
+		}
+		if _, err := s.Purge(1, ids); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Fatalf("purge %v: expected invalid error, got %v", ids, err)
+		}
+	}
+
+	if n, err := s.PurgeExpired(0); err != nil || n != 0 {
+		t.Fatalf("retention 0 should keep everything, got %d %v", n, err)
+	}
+
+	clause, args := trashIDArgs(7, []int{4, 9})
+	if clause != "$2, $3" || len(args) != 3 || args[0] != uint(7) || args[2] != 9 {
+		t.Fatalf("unexpected IN clause %q args %v", clause, args)
+	}
+}
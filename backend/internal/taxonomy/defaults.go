@@ -0,0 +1,50 @@
+package taxonomy
+
+// defaultTaxonomy 内置规则。关键词不区分大小写；纯英文关键词按单词匹配，中文关键词按子串匹配
+func defaultTaxonomy() *Taxonomy {
+	return &Taxonomy{
+		Families: []Family{
+			{Key: "backend", Name: "后端开发", Keywords: []string{"后端", "后台开发", "服务端", "服务器开发", "backend", "back-end", "back end", "server"}},
+			{Key: "frontend", Name: "前端开发", Keywords: []string{"前端", "web前端", "h5", "frontend", "front-end", "front end", "web developer"}},
+			{Key: "mobile", Name: "移动开发", Keywords: []string{"客户端", "移动端", "移动开发", "安卓", "mobile", "client"}},
+			{Key: "fullstack", Name: "全栈开发", Keywords: []string{"全栈", "fullstack", "full-stack", "full stack"}},
+			{Key: "algorithm", Name: "算法", Keywords: []string{"算法", "机器学习", "深度学习", "大模型", "推荐", "搜索算法", "计算机视觉", "自然语言处理", "algorithm", "machine learning", "ml", "ai", "nlp", "cv", "llm", "research scientist"}},
+			{Key: "data", Name: "数据", Keywords: []string{"数据开发", "数据分析", "数据工程", "数仓", "数据仓库", "大数据", "bi", "data engineer", "data analyst", "data scientist", "data"}},
+			{Key: "devops", Name: "运维/SRE", Keywords: []string{"运维", "基础架构", "基础设施", "云原生", "devops", "sre", "site reliability", "infrastructure", "platform engineer"}},
+			{Key: "qa", Name: "测试", Keywords: []string{"测试", "质量保障", "qa", "test", "testing", "sdet", "quality assurance"}},
+			{Key: "security", Name: "安全", Keywords: []string{"安全", "渗透", "security", "infosec"}},
+			{Key: "embedded", Name: "嵌入式/硬件", Keywords: []string{"嵌入式", "驱动开发", "硬件", "芯片", "fpga", "embedded", "firmware", "hardware"}},
+			{Key: "game", Name: "游戏开发", Keywords: []string{"游戏", "引擎开发", "unity", "unreal", "ue4", "ue5", "game", "gameplay"}},
+			{Key: "product", Name: "产品", Keywords: []string{"产品经理", "产品策划", "产品", "product manager", "product owner", "pm"}},
+			{Key: "design", Name: "设计", Keywords: []string{"设计师", "交互设计", "视觉设计", "ui设计", "ux", "ui designer", "designer"}},
+			{Key: "operations", Name: "运营", Keywords: []string{"运营", "产品运营", "用户增长", "operations", "operation", "growth"}},
+		},
+		Levels: []Level{
+			{Key: "intern", Name: "实习", Rank: 0, Keywords: []string{"实习", "intern", "internship"}},
+			{Key: "campus", Name: "校招", Rank: 1, Keywords: []string{"校招", "应届", "届", "管培生", "new grad", "graduate", "campus"}},
+			{Key: "junior", Name: "初级", Rank: 2, Keywords: []string{"初级", "助理", "junior", "jr", "associate", "entry level"}},
+			{Key: "mid", Name: "中级", Rank: 3, Keywords: []string{"中级", "mid", "intermediate"}},
+			{Key: "senior", Name: "高级", Rank: 4, Keywords: []string{"高级", "资深", "senior", "sr"}},
+			{Key: "staff", Name: "专家", Rank: 5, Keywords: []string{"专家", "架构师", "staff", "principal", "architect"}},
+			{Key: "lead", Name: "负责人", Rank: 6, Keywords: []string{"负责人", "组长", "技术经理", "研发经理", "总监", "主管", "leader", "lead", "head", "director", "engineering manager"}},
+		},
+		TechStacks: []TechStack{
+			{Key: "go", Name: "Go", Family: "backend", Keywords: []string{"go", "golang"}},
+			{Key: "java", Name: "Java", Family: "backend", Keywords: []string{"java"}},
+			{Key: "python", Name: "Python", Family: "backend", Keywords: []string{"python"}},
+			{Key: "cpp", Name: "C/C++", Family: "backend", Keywords: []string{"c++", "cpp", "c/c++"}},
+			{Key: "rust", Name: "Rust", Family: "backend", Keywords: []string{"rust"}},
+			{Key: "php", Name: "PHP", Family: "backend", Keywords: []string{"php"}},
+			{Key: "nodejs", Name: "Node.js", Family: "backend", Keywords: []string{"node", "nodejs", "node.js"}},
+			{Key: "react", Name: "React", Family: "frontend", Keywords: []string{"react", "react native"}},
+			{Key: "vue", Name: "Vue", Family: "frontend", Keywords: []string{"vue", "vue.js", "vuejs"}},
+			{Key: "typescript", Name: "TypeScript/JavaScript", Family: "frontend", Keywords: []string{"typescript", "javascript", "ts", "js"}},
+			{Key: "ios", Name: "iOS", Family: "mobile", Keywords: []string{"ios", "swift", "objective-c"}},
+			{Key: "android", Name: "Android", Family: "mobile", Keywords: []string{"android", "安卓", "kotlin"}},
+			{Key: "flutter", Name: "Flutter", Family: "mobile", Keywords: []string{"flutter"}},
+			{Key: "kubernetes", Name: "Kubernetes", Family: "devops", Keywords: []string{"k8s", "kubernetes", "docker"}},
+			{Key: "bigdata", Name: "Spark/Flink", Family: "data", Keywords: []string{"spark", "flink", "hadoop", "hive"}},
+			{Key: "deeplearning", Name: "PyTorch/TensorFlow", Family: "algorithm", Keywords: []string{"pytorch", "tensorflow"}},
+		},
+	}
+}
@@ -0,0 +1,298 @@
+// Package taxonomy 将自由填写的职位名称归一化为岗位类别、职级与技术栈，例如：
+//
+//	"后端开发工程师"、"Golang后端"、"Backend Engineer" -> 岗位类别 backend
+//	"高级Java开发工程师" -> 岗位类别 backend、职级 senior、技术栈 [java]
+//
+// 规则为纯数据（关键词表），内置默认规则，可通过 POSITION_TAXONOMY_FILE 指定 JSON 文件覆盖。
+package taxonomy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Family 岗位类别
+type Family struct {
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+}
+
+// Level 职级，Rank 越大级别越高
+type Level struct {
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Rank     int      `json:"rank"`
+	Keywords []string `json:"keywords"`
+}
+
+// TechStack 技术栈关键词。Family 非空时，职位名称未命中任何类别关键词时以此推断类别
+type TechStack struct {
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Family   string   `json:"family,omitempty"`
+	Keywords []string `json:"keywords"`
+}
+
+// Taxonomy 岗位分类规则
+type Taxonomy struct {
+	Families   []Family    `json:"families"`
+	Levels     []Level     `json:"levels"`
+	TechStacks []TechStack `json:"tech_stacks"`
+
+	version string
+}
+
+// Result 归一化结果，未识别的类别/职级为空字符串
+type Result struct {
+	RoleFamily string   `json:"role_family"`
+	RoleLevel  string   `json:"role_level"`
+	TechStack  []string `json:"tech_stack"`
+}
+
+// internLevel 实习职级优先于其他职级关键词（如“高级算法实习生”仍为实习）
+const internLevel = "intern"
+
+// Normalize 归一化职位名称：
+// 岗位类别取命中的最长关键词（同长取规则中靠前者），未命中时按技术栈推断；
+// 职级命中实习时为实习，否则取命中的最高职级；技术栈按规则顺序列出全部命中项
+func (t *Taxonomy) Normalize(title string) Result {
+	text := strings.ToLower(strings.TrimSpace(title))
+	result := Result{TechStack: []string{}}
+	if text == "" {
+		return result
+	}
+
+	bestLength := 0
+	for _, family := range t.Families {
+		if length := longestMatch(text, family.Keywords); length > bestLength {
+			bestLength = length
+			result.RoleFamily = family.Key
+		}
+	}
+
+	for _, stack := range t.TechStacks {
+		if longestMatch(text, stack.Keywords) == 0 {
+			continue
+		}
+		result.TechStack = append(result.TechStack, stack.Key)
+		if result.RoleFamily == "" && stack.Family != "" {
+			result.RoleFamily = stack.Family
+		}
+	}
+
+	bestRank := -1
+	for _, level := range t.Levels {
+		if longestMatch(text, level.Keywords) == 0 {
+			continue
+		}
+		if level.Key == internLevel {
+			result.RoleLevel = level.Key
+			break
+		}
+		if level.Rank > bestRank {
+			bestRank = level.Rank
+			result.RoleLevel = level.Key
+		}
+	}
+	return result
+}
+
+// longestMatch 返回命中的最长关键词长度（字符数），未命中返回 0。
+// 纯 ASCII 关键词要求两侧不是字母或数字（避免 go 命中 google），其余关键词按子串匹配
+func longestMatch(text string, keywords []string) int {
+	best := 0
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		length := len([]rune(keyword))
+		if length <= best || !containsKeyword(text, keyword) {
+			continue
+		}
+		best = length
+	}
+	return best
+}
+
+// containsKeyword 判断文本是否包含关键词
+func containsKeyword(text, keyword string) bool {
+	if keyword == "" {
+		return false
+	}
+	if !isASCII(keyword) {
+		return strings.Contains(text, keyword)
+	}
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], keyword)
+		if idx < 0 {
+			return false
+		}
+		start, end := offset+idx, offset+idx+len(keyword)
+		if !isWordByte(text, start-1) && !isWordByte(text, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// isWordByte 指定位置是否为 ASCII 字母或数字（越界视为边界）
+func isWordByte(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return false
+	}
+	c := text[i]
+	return c < unicode.MaxASCII && (c >= 'a' && c <= 'z' || c >= '0' && c <= '9')
+}
+
+// isASCII 是否全部为 ASCII 字符
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// Version 规则版本（内容哈希），规则变更后用于识别需要重新归一化的记录
+func (t *Taxonomy) Version() string {
+	return t.version
+}
+
+// FamilyName 类别显示名称，未知类别返回 key 本身
+func (t *Taxonomy) FamilyName(key string) string {
+	for _, family := range t.Families {
+		if family.Key == key {
+			return family.Name
+		}
+	}
+	return key
+}
+
+// LevelName 职级显示名称，未知职级返回 key 本身
+func (t *Taxonomy) LevelName(key string) string {
+	for _, level := range t.Levels {
+		if level.Key == key {
+			return level.Name
+		}
+	}
+	return key
+}
+
+// StackName 技术栈显示名称，未知技术栈返回 key 本身
+func (t *Taxonomy) StackName(key string) string {
+	for _, stack := range t.TechStacks {
+		if stack.Key == key {
+			return stack.Name
+		}
+	}
+	return key
+}
+
+// ResolveFamily 按 key 或显示名称（不区分大小写）查找类别 key
+func (t *Taxonomy) ResolveFamily(value string) (string, bool) {
+	for _, family := range t.Families {
+		if strings.EqualFold(family.Key, value) || strings.EqualFold(family.Name, value) {
+			return family.Key, true
+		}
+	}
+	return "", false
+}
+
+// ResolveLevel 按 key 或显示名称（不区分大小写）查找职级 key
+func (t *Taxonomy) ResolveLevel(value string) (string, bool) {
+	for _, level := range t.Levels {
+		if strings.EqualFold(level.Key, value) || strings.EqualFold(level.Name, value) {
+			return level.Key, true
+		}
+	}
+	return "", false
+}
+
+// validate 校验规则并计算版本
+func (t *Taxonomy) validate() error {
+	families := make(map[string]bool)
+	for _, family := range t.Families {
+		if family.Key == "" || families[family.Key] {
+			return fmt.Errorf("invalid taxonomy: family key %q is empty or duplicated", family.Key)
+		}
+		families[family.Key] = true
+	}
+	levels := make(map[string]bool)
+	for _, level := range t.Levels {
+		if level.Key == "" || levels[level.Key] {
+			return fmt.Errorf("invalid taxonomy: level key %q is empty or duplicated", level.Key)
+		}
+		levels[level.Key] = true
+	}
+	stacks := make(map[string]bool)
+	for _, stack := range t.TechStacks {
+		if stack.Key == "" || stacks[stack.Key] {
+			return fmt.Errorf("invalid taxonomy: tech stack key %q is empty or duplicated", stack.Key)
+		}
+		if stack.Family != "" && !families[stack.Family] {
+			return fmt.Errorf("invalid taxonomy: tech stack %q refers to unknown family %q", stack.Key, stack.Family)
+		}
+		stacks[stack.Key] = true
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	t.version = hex.EncodeToString(sum[:])[:12]
+	return nil
+}
+
+// Load 从 JSON 文件加载规则
+func Load(path string) (*Taxonomy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Taxonomy
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Default 返回内置规则
+func Default() *Taxonomy {
+	t := defaultTaxonomy()
+	if err := t.validate(); err != nil {
+		panic(err)
+	}
+	return t
+}
+
+var (
+	activeOnce sync.Once
+	active     *Taxonomy
+)
+
+// Active 返回当前生效的规则：POSITION_TAXONOMY_FILE 指定且加载成功时使用文件，否则使用内置规则
+func Active() *Taxonomy {
+	activeOnce.Do(func() {
+		active = Default()
+		if path := os.Getenv("POSITION_TAXONOMY_FILE"); path != "" {
+			loaded, err := Load(path)
+			if err != nil {
+				log.Printf("Warning: failed to load position taxonomy from %s, using defaults: %v", path, err)
+				return
+			}
+			active = loaded
+		}
+	})
+	return active
+}
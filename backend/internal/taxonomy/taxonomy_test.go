@@ -0,0 +1,66 @@
+package taxonomy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tx := Default()
+	cases := []struct {
+		title  string
+		family string
+		level  string
+		stack  []string
+	}{
+		{"后端开发工程师", "backend", "", []string{}},
+		{"Golang后端", "backend", "", []string{"go"}},
+		{"Backend Engineer", "backend", "", []string{}},
+		{"Senior Backend Engineer (Go)", "backend", "senior", []string{"go"}},
+		{"高级Java开发工程师", "backend", "senior", []string{"java"}},
+		{"Java开发", "backend", "", []string{"java"}},
+		{"Google 前端工程师", "frontend", "", []string{}},
+		{"React Native 开发", "frontend", "", []string{"react"}},
+		{"iOS开发工程师", "mobile", "", []string{"ios"}},
+		{"高级算法实习生", "algorithm", "intern", []string{}},
+		{"2026届校招-推荐算法工程师", "algorithm", "campus", []string{}},
+		{"AI工程师", "algorithm", "", []string{}},
+		{"C++服务端开发", "backend", "", []string{"cpp"}},
+		{"产品经理", "product", "", []string{}},
+		{"产品运营", "operations", "", []string{}},
+		{"测试开发专家", "qa", "staff", []string{}},
+		{"Staff SRE, Kubernetes", "devops", "staff", []string{"kubernetes"}},
+		{"市场专员", "", "", []string{}},
+		{"", "", "", []string{}},
+	}
+	for _, c := range cases {
+		got := tx.Normalize(c.title)
+		if got.RoleFamily != c.family || got.RoleLevel != c.level || !reflect.DeepEqual(got.TechStack, c.stack) {
+			t.Errorf("Normalize(%q) = %+v, want family %q level %q stack %v", c.title, got, c.family, c.level, c.stack)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	os.WriteFile(valid, []byte(`{"families":[{"key":"backend","name":"后端","keywords":["后端"]}],"tech_stacks":[{"key":"go","name":"Go","family":"backend","keywords":["go"]}]}`), 0o644)
+	tx, err := Load(valid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.Version() == "" || tx.Version() == Default().Version() {
+		t.Fatalf("expected a distinct version, got %q", tx.Version())
+	}
+	if got := tx.Normalize("go开发"); got.RoleFamily != "backend" {
+		t.Fatalf("expected family inferred from tech stack, got %+v", got)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`{"tech_stacks":[{"key":"go","family":"missing","keywords":["go"]}]}`), 0o644)
+	if _, err := Load(invalid); err == nil {
+		t.Fatalf("expected error for unknown family")
+	}
+}
@@ -0,0 +1,34 @@
+// 文本处理工具：编辑距离与 LIKE 转义，供查询语言、搜索与名称归一化使用
+
+package utils
+
+import "strings"
+
+// Levenshtein 按字符（rune）计算编辑距离，适用于中英文混合文本
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr := make([]int, len(rb)+1)
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(rb)]
+}
+
+// EscapeLike 转义 LIKE/ILIKE 模式中的通配符（% 与 _）及转义符本身
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
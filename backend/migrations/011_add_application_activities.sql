@@ -0,0 +1,24 @@
+-- Migration: Add application activities table for the unified timeline
+-- File: 011_add_application_activities.sql
+-- Description: Append-only event log (notes, attachments, exports) merged with
+--              job_status_history into the per-application activity timeline
+
+CREATE TABLE IF NOT EXISTS application_activities (
+    id BIGSERIAL PRIMARY KEY,
+    job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_type VARCHAR(30) NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    payload JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_activity_payload_is_object CHECK (jsonb_typeof(payload) = 'object')
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_activities_job_time
+    ON application_activities(job_application_id, occurred_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_application_activities_user_type
+    ON application_activities(user_id, activity_type);
+
+COMMENT ON TABLE application_activities IS 'Per-application activity events shown in the unified timeline';
+COMMENT ON COLUMN application_activities.activity_type IS 'note, attachment, export';
+COMMENT ON COLUMN application_activities.payload IS 'Type specific details, e.g. export task id';
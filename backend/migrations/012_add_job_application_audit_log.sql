@@ -0,0 +1,66 @@
+-- Migration: Add field-level audit log for job applications
+-- File: 012_add_job_application_audit_log.sql
+-- Description: Row trigger recording before/after values of every changed field on
+--              create/update/delete. Batch operations set jobview.audit_batch = 'on'
+--              so their entries are recorded as batch_* and share a transaction_id.
+
+CREATE TABLE IF NOT EXISTS job_application_audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    job_application_id INTEGER NOT NULL, -- 不加外键，删除后仍保留审计记录
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,         -- create/update/delete/batch_create/batch_update/batch_delete
+    changes JSONB NOT NULL DEFAULT '{}', -- {"field": {"before": ..., "after": ...}}
+    transaction_id BIGINT NOT NULL DEFAULT txid_current(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_job_time ON job_application_audit_logs(job_application_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_time ON job_application_audit_logs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_changes_gin ON job_application_audit_logs USING GIN (changes);
+
+CREATE OR REPLACE FUNCTION trigger_job_application_audit()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_action TEXT;
+    v_changes JSONB;
+    v_ignored TEXT[] := ARRAY['id', 'user_id', 'created_at', 'updated_at', 'status_history',
+                              'status_duration_stats', 'last_status_change', 'status_version'];
+BEGIN
+    v_action := lower(TG_OP);
+    IF COALESCE(current_setting('jobview.audit_batch', true), '') = 'on' THEN
+        v_action := 'batch_' || v_action;
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        SELECT jsonb_object_agg(key, jsonb_build_object('before', NULL, 'after', value)) INTO v_changes
+        FROM jsonb_each(to_jsonb(NEW))
+        WHERE key <> ALL(v_ignored) AND value <> 'null'::jsonb;
+    ELSIF TG_OP = 'UPDATE' THEN
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('before', o.value, 'after', n.value)) INTO v_changes
+        FROM jsonb_each(to_jsonb(NEW)) n
+        JOIN jsonb_each(to_jsonb(OLD)) o ON o.key = n.key
+        WHERE n.key <> ALL(v_ignored) AND n.value IS DISTINCT FROM o.value;
+        IF v_changes IS NULL THEN
+            RETURN NEW;
+        END IF;
+    ELSE
+        SELECT jsonb_object_agg(key, jsonb_build_object('before', value, 'after', NULL)) INTO v_changes
+        FROM jsonb_each(to_jsonb(OLD))
+        WHERE key <> ALL(v_ignored) AND value <> 'null'::jsonb;
+        INSERT INTO job_application_audit_logs (job_application_id, user_id, action, changes)
+        VALUES (OLD.id, OLD.user_id, v_action, COALESCE(v_changes, '{}'::jsonb));
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO job_application_audit_logs (job_application_id, user_id, action, changes)
+    VALUES (NEW.id, NEW.user_id, v_action, COALESCE(v_changes, '{}'::jsonb));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_job_application_audit ON job_applications;
+CREATE TRIGGER trg_job_application_audit
+    AFTER INSERT OR UPDATE OR DELETE ON job_applications
+    FOR EACH ROW EXECUTE FUNCTION trigger_job_application_audit();
+
+COMMENT ON TABLE job_application_audit_logs IS 'Field-level audit trail of job application changes';
@@ -0,0 +1,62 @@
+-- Migration: Soft delete (trash) for job applications
+-- File: 013_add_soft_delete.sql
+-- Description: Deleting an application now sets deleted_at instead of removing the row.
+--              Deleted rows are hidden from lists, search, statistics and exports, can be
+--              restored from /api/v1/trash, and are purged after TRASH_RETENTION_DAYS.
+--              The audit trigger records soft delete/restore as delete/restore and
+--              physical removal as purge.
+
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_job_applications_deleted
+    ON job_applications(user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION trigger_job_application_audit()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_action TEXT;
+    v_changes JSONB;
+    v_ignored TEXT[] := ARRAY['id', 'user_id', 'created_at', 'updated_at', 'status_history',
+                              'status_duration_stats', 'last_status_change', 'status_version'];
+BEGIN
+    v_action := lower(TG_OP);
+    -- 软删除/恢复通过 deleted_at 表达；物理删除记为 purge
+    IF TG_OP = 'UPDATE' AND OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        v_action := 'delete';
+    ELSIF TG_OP = 'UPDATE' AND OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        v_action := 'restore';
+    ELSIF TG_OP = 'DELETE' THEN
+        v_action := 'purge';
+    END IF;
+    IF COALESCE(current_setting('jobview.audit_batch', true), '') = 'on' THEN
+        v_action := 'batch_' || v_action;
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        SELECT jsonb_object_agg(key, jsonb_build_object('before', NULL, 'after', value)) INTO v_changes
+        FROM jsonb_each(to_jsonb(NEW))
+        WHERE key <> ALL(v_ignored) AND value <> 'null'::jsonb;
+    ELSIF TG_OP = 'UPDATE' THEN
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('before', o.value, 'after', n.value)) INTO v_changes
+        FROM jsonb_each(to_jsonb(NEW)) n
+        JOIN jsonb_each(to_jsonb(OLD)) o ON o.key = n.key
+        WHERE n.key <> ALL(v_ignored) AND n.value IS DISTINCT FROM o.value;
+        IF v_changes IS NULL THEN
+            RETURN NEW;
+        END IF;
+    ELSE
+        SELECT jsonb_object_agg(key, jsonb_build_object('before', value, 'after', NULL)) INTO v_changes
+        FROM jsonb_each(to_jsonb(OLD))
+        WHERE key <> ALL(v_ignored) AND value <> 'null'::jsonb;
+        INSERT INTO job_application_audit_logs (job_application_id, user_id, action, changes)
+        VALUES (OLD.id, OLD.user_id, v_action, COALESCE(v_changes, '{}'::jsonb));
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO job_application_audit_logs (job_application_id, user_id, action, changes)
+    VALUES (NEW.id, NEW.user_id, v_action, COALESCE(v_changes, '{}'::jsonb));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON COLUMN job_applications.deleted_at IS 'Set when moved to trash; NULL for active applications';
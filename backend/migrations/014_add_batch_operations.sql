@@ -0,0 +1,19 @@
+-- Migration: Add batch operations table for undo
+-- File: 014_add_batch_operations.sql
+-- Description: Each batch status update / batch delete stores a snapshot of the affected
+--              rows (and the status history entries it wrote) so that it can be reverted
+--              with POST /api/v1/operations/{id}/undo while the rows are unmodified.
+
+CREATE TABLE IF NOT EXISTS batch_operations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    operation_type VARCHAR(30) NOT NULL,   -- batch_status_update / batch_delete
+    affected_count INTEGER NOT NULL DEFAULT 0,
+    snapshot JSONB NOT NULL DEFAULT '[]',  -- 修改前的行快照及操作后的 updated_at
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    undone_at TIMESTAMP WITH TIME ZONE     -- 已撤销时间，非空表示不可再次撤销
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_operations_user_time ON batch_operations(user_id, created_at DESC);
+
+COMMENT ON TABLE batch_operations IS 'Undoable batch operations on job applications';
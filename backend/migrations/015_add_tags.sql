@@ -0,0 +1,28 @@
+-- Migration: Add tags for job applications
+-- File: 015_add_tags.sql
+-- Description: User-scoped tags with colors and a many-to-many link to job applications.
+--              Applications can be filtered by tag (tag_ids) in list, search and export,
+--              and the dashboard reports per-tag statistics.
+
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#6366f1',  -- #RRGGBB
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS job_application_tags (
+    job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (job_application_id, tag_id)
+);
+
+-- 同一用户下标签名不区分大小写唯一
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_job_application_tags_tag ON job_application_tags(tag_id);
+
+COMMENT ON TABLE tags IS 'User-defined labels for job applications';
+COMMENT ON TABLE job_application_tags IS 'Many-to-many assignment of tags to job applications';
@@ -0,0 +1,26 @@
+-- Migration: Add user-defined custom fields
+-- File: 016_add_custom_fields.sql
+-- Description: Per-user field definitions (text, number, date, enum, url). Values are stored
+--              in job_applications.custom_fields keyed by field_key, validated by the service
+--              on create/update, and can be filtered, sorted and exported as cf.<field_key>.
+
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field_key VARCHAR(50) NOT NULL,     -- custom_fields 中的键，创建后不可修改
+    label VARCHAR(100) NOT NULL,
+    field_type VARCHAR(10) NOT NULL CHECK (field_type IN ('text', 'number', 'date', 'enum', 'url')),
+    options JSONB NOT NULL DEFAULT '[]', -- enum 可选值
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, field_key)
+);
+
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_job_applications_custom_fields ON job_applications USING GIN (custom_fields);
+
+COMMENT ON TABLE custom_field_definitions IS 'User-defined custom fields for job applications';
+COMMENT ON COLUMN job_applications.custom_fields IS 'Custom field values keyed by custom_field_definitions.field_key';
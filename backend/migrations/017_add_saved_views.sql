@@ -0,0 +1,22 @@
+-- Migration: Add saved views
+-- File: 017_add_saved_views.sql
+-- Description: Per-user named filter combinations for the application list and search
+--              (status, stage, tags, custom fields, date range, keyword, sort order).
+--              Pinned views are shown on the dashboard with live counts, and a view can be
+--              passed as view_id to the export endpoint instead of explicit filters.
+
+CREATE TABLE IF NOT EXISTS saved_views (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}', -- model.SavedViewFilters
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_views_user_name ON saved_views(user_id, LOWER(name));
+
+COMMENT ON TABLE saved_views IS 'Saved filter/sort combinations for the application list and search';
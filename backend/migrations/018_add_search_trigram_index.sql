@@ -0,0 +1,13 @@
+-- Migration: Add trigram search index
+-- File: 018_add_search_trigram_index.sql
+-- Description: Enables pg_trgm and indexes a combined search document (company, position,
+--              location, HR name, notes, job description) so that search can match Chinese
+--              substrings and tolerate typos. The expression must stay identical to
+--              database.ApplicationSearchDocument, otherwise the planner will not use the index.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_job_applications_search_trgm ON job_applications USING GIN (
+    (COALESCE(company_name, '') || ' ' || COALESCE(position_title, '') || ' ' || COALESCE(work_location, '') || ' ' || COALESCE(hr_name, '') || ' ' || COALESCE(notes, '') || ' ' || COALESCE(job_description, ''))
+    gin_trgm_ops
+);
@@ -0,0 +1,125 @@
+-- Migration: Add companies and company aliases
+-- File: 019_add_companies.sql
+-- Description: Turns the free-text company_name into a company entity. Every spelling of a
+--              company ("字节跳动", "ByteDance", "字节") is stored as an alias keyed by
+--              company_name_key(), and a trigger links each application to its company,
+--              creating the company on first use. company_alias_seeds groups well-known
+--              companies so their Chinese/English names resolve to the same record.
+--              Seeding the alias groups, clustering existing names and backfilling
+--              company_id run at application startup (database.ensureCompanies); the audit
+--              trigger ignores company_id so the backfill does not produce audit entries. The
+--              backfill only touches unlinked rows with a non-empty company_name and keeps
+--              updated_at (jobview.preserve_updated_at, see 025_preserve_updated_at.sql).
+
+CREATE TABLE IF NOT EXISTS companies (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL, -- canonical name
+    industry VARCHAR(100),
+    size VARCHAR(20),           -- model.CompanySizes
+    website VARCHAR(255),
+    notes TEXT,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS company_aliases (
+    id SERIAL PRIMARY KEY,
+    company_id INTEGER NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    alias_key VARCHAR(255) NOT NULL, -- company_name_key(alias)
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, alias_key)
+);
+
+CREATE TABLE IF NOT EXISTS company_alias_seeds (
+    alias_key VARCHAR(255) PRIMARY KEY,
+    group_name VARCHAR(255) NOT NULL
+);
+
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_companies_user ON companies(user_id, LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_company_aliases_company ON company_aliases(company_id);
+CREATE INDEX IF NOT EXISTS idx_company_alias_seeds_group ON company_alias_seeds(group_name);
+CREATE INDEX IF NOT EXISTS idx_job_applications_company_id ON job_applications(company_id);
+
+-- Must stay identical to model.CompanyNameKey
+CREATE OR REPLACE FUNCTION company_name_key(p_name TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    v_lowered TEXT := lower(btrim(COALESCE(p_name, ''), ' '));
+    v_key TEXT;
+    v_stripped TEXT;
+BEGIN
+    v_key := regexp_replace(v_lowered, '[\s\-_.,·•()（）【】\[\]&/''"“”‘’]+', '', 'g');
+    v_stripped := regexp_replace(v_key, '(股份有限公司|有限责任公司|有限公司|集团|公司|corporation|limited|ltd|inc)$', '');
+    IF v_stripped <> '' THEN
+        v_key := v_stripped;
+    END IF;
+    IF v_key = '' THEN
+        v_key := v_lowered;
+    END IF;
+    RETURN v_key;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION resolve_application_company()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_name TEXT;
+    v_key TEXT;
+    v_company_id INTEGER;
+BEGIN
+    -- 显式改变 company_id（如合并公司、拆分别名）时保留指定值
+    IF TG_OP = 'UPDATE' AND NEW.company_id IS NOT NULL AND NEW.company_id IS DISTINCT FROM OLD.company_id THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.company_id IS NOT NULL AND NEW.company_name IS NOT DISTINCT FROM OLD.company_name THEN
+        RETURN NEW;
+    END IF;
+
+    v_name := btrim(COALESCE(NEW.company_name, ''));
+    IF v_name = '' THEN
+        NEW.company_id := NULL;
+        RETURN NEW;
+    END IF;
+    v_key := company_name_key(v_name);
+
+    SELECT company_id INTO v_company_id
+    FROM company_aliases
+    WHERE user_id = NEW.user_id AND alias_key = v_key;
+
+    IF v_company_id IS NULL THEN
+        SELECT a.company_id INTO v_company_id
+        FROM company_alias_seeds s
+        JOIN company_alias_seeds g ON g.group_name = s.group_name
+        JOIN company_aliases a ON a.alias_key = g.alias_key AND a.user_id = NEW.user_id
+        WHERE s.alias_key = v_key
+        ORDER BY a.company_id
+        LIMIT 1;
+    END IF;
+
+    IF v_company_id IS NULL THEN
+        INSERT INTO companies (user_id, name) VALUES (NEW.user_id, v_name)
+        RETURNING id INTO v_company_id;
+    END IF;
+
+    INSERT INTO company_aliases (company_id, user_id, alias, alias_key)
+    VALUES (v_company_id, NEW.user_id, v_name, v_key)
+    ON CONFLICT (user_id, alias_key) DO NOTHING;
+
+    NEW.company_id := v_company_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_job_application_company ON job_applications;
+CREATE TRIGGER trg_job_application_company
+    BEFORE INSERT OR UPDATE OF company_name, company_id ON job_applications
+    FOR EACH ROW EXECUTE FUNCTION resolve_application_company();
+
+COMMENT ON TABLE companies IS 'Companies applied to; applications link via job_applications.company_id';
+COMMENT ON TABLE company_aliases IS 'Spellings of a company used to resolve job_applications.company_name';
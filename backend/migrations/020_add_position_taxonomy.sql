@@ -0,0 +1,22 @@
+-- Migration: Add normalized position taxonomy columns
+-- File: 020_add_position_taxonomy.sql
+-- Description: Stores the role family, level and tech stack derived from the free-text
+--              position_title ("后端开发工程师", "Golang后端", "Backend Engineer" -> backend)
+--              so analytics and exports can group by them. The rules live in the
+--              application (internal/taxonomy, overridable via POSITION_TAXONOMY_FILE);
+--              role_taxonomy_version records which rule set produced the values, and rows
+--              with a different version are re-normalized at application startup
+--              (database.ensurePositionTaxonomy). The audit trigger ignores these columns
+--              and the backfill keeps updated_at (jobview.preserve_updated_at).
+
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS role_family VARCHAR(30);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS role_level VARCHAR(20);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS tech_stack JSONB NOT NULL DEFAULT '[]'::jsonb;
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS role_taxonomy_version VARCHAR(16);
+
+CREATE INDEX IF NOT EXISTS idx_job_applications_role ON job_applications(user_id, role_family, role_level);
+
+COMMENT ON COLUMN job_applications.role_family IS 'Role family normalized from position_title, NULL when unrecognized';
+COMMENT ON COLUMN job_applications.role_level IS 'Seniority level normalized from position_title, NULL when unrecognized';
+COMMENT ON COLUMN job_applications.tech_stack IS 'Tech stack keys matched in position_title';
+COMMENT ON COLUMN job_applications.role_taxonomy_version IS 'Version of the taxonomy rules that produced role_family/role_level/tech_stack';
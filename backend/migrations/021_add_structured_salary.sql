@@ -0,0 +1,30 @@
+-- Migration: Add structured salary columns
+-- File: 021_add_structured_salary.sql
+-- Description: Stores the compensation parsed from the free-text salary_range
+--              ("20-30K·15薪", "25k*16", "30-45万/年", "$120k") as monthly min/max, months per
+--              year, annual min/max and currency so lists, exports and compensation analytics
+--              can filter and aggregate on it. Parsing happens in the application
+--              (internal/salary); salary_parser_version records which parser produced the
+--              values and rows with an older version are re-parsed at application startup
+--              (database.ensureStructuredSalary). The audit trigger ignores these columns
+--              and the backfill keeps updated_at (jobview.preserve_updated_at).
+
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_min_monthly NUMERIC(12, 2);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_max_monthly NUMERIC(12, 2);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_months NUMERIC(4, 1);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_annual_min NUMERIC(14, 2);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_annual_max NUMERIC(14, 2);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_currency VARCHAR(3);
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS salary_parser_version SMALLINT;
+
+CREATE INDEX IF NOT EXISTS idx_job_applications_salary
+    ON job_applications(user_id, salary_currency, salary_max_monthly)
+    WHERE salary_max_monthly IS NOT NULL;
+
+COMMENT ON COLUMN job_applications.salary_min_monthly IS 'Monthly salary lower bound parsed from salary_range, in currency units';
+COMMENT ON COLUMN job_applications.salary_max_monthly IS 'Monthly salary upper bound parsed from salary_range, in currency units';
+COMMENT ON COLUMN job_applications.salary_months IS 'Salary months per year (e.g. 15 for 15薪), 12 when not stated';
+COMMENT ON COLUMN job_applications.salary_annual_min IS 'Annual total lower bound';
+COMMENT ON COLUMN job_applications.salary_annual_max IS 'Annual total upper bound';
+COMMENT ON COLUMN job_applications.salary_currency IS 'ISO currency code, NULL when salary_range could not be parsed';
+COMMENT ON COLUMN job_applications.salary_parser_version IS 'Version of the salary parser that produced the structured columns';
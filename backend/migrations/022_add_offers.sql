@@ -0,0 +1,49 @@
+-- Migration: Add offers
+-- File: 022_add_offers.sql
+-- Description: Offer terms attached to a job application (one offer per application):
+--              base salary and months, bonus, stock grant and vesting, sign-on bonus,
+--              benefits, location, start date, response deadline and decision status.
+--              ratings holds the user's own 0-10 scores for custom criteria (growth, commute,
+--              ...) used by the weighted offer comparison. Every change to the compensation
+--              terms is recorded in offer_negotiations together with an optional note.
+
+CREATE TABLE IF NOT EXISTS offers (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL DEFAULT 'CNY',
+    base_monthly NUMERIC(12, 2) NOT NULL,
+    salary_months NUMERIC(4, 1) NOT NULL DEFAULT 12,
+    annual_bonus NUMERIC(14, 2),
+    sign_on_bonus NUMERIC(14, 2),
+    stock_value NUMERIC(14, 2),
+    vesting_years SMALLINT,
+    vesting_schedule VARCHAR(255),
+    benefits JSONB NOT NULL DEFAULT '[]',
+    location VARCHAR(255),
+    start_date DATE,
+    deadline DATE,
+    decision_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (decision_status IN ('pending', 'negotiating', 'accepted', 'declined', 'expired')),
+    ratings JSONB NOT NULL DEFAULT '{}',
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (job_application_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_offers_user_status ON offers(user_id, decision_status);
+
+CREATE TABLE IF NOT EXISTS offer_negotiations (
+    id SERIAL PRIMARY KEY,
+    offer_id INTEGER NOT NULL REFERENCES offers(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note TEXT,
+    changes JSONB NOT NULL DEFAULT '{}', -- model.OfferTermChanges
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_negotiations_offer ON offer_negotiations(offer_id, created_at);
+
+COMMENT ON TABLE offers IS 'Offer terms and decision status, one per job application';
+COMMENT ON TABLE offer_negotiations IS 'Negotiation history: term changes and notes per offer';
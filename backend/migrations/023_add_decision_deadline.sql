@@ -0,0 +1,31 @@
+-- Migration: Add offer decision deadline
+-- File: 023_add_decision_deadline.sql
+-- Description: The date by which an offer must be answered now lives on the job application
+--              (decision_deadline) instead of offers.deadline, so it can be set as soon as the
+--              application reaches 已收到offer even before the offer terms are recorded.
+--              Existing offer deadlines are copied over, then offers.deadline is dropped.
+--              Reminders 7/3/1 days before the deadline are written to application_activities
+--              with activity_type 'deadline_reminder'.
+
+ALTER TABLE job_applications ADD COLUMN IF NOT EXISTS decision_deadline DATE;
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'offers' AND column_name = 'deadline'
+    ) THEN
+        UPDATE job_applications ja
+        SET decision_deadline = o.deadline
+        FROM offers o
+        WHERE o.job_application_id = ja.id AND o.deadline IS NOT NULL AND ja.decision_deadline IS NULL;
+
+        ALTER TABLE offers DROP COLUMN deadline;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_job_applications_decision_deadline
+    ON job_applications(decision_deadline)
+    WHERE decision_deadline IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN job_applications.decision_deadline IS 'Date by which the received offer must be answered';
@@ -0,0 +1,58 @@
+-- Migration: Add contacts
+-- File: 024_add_contacts.sql
+-- Description: Recruiters, HR, hiring managers and referrers as a contacts entity instead of
+--              hr_name/hr_phone/hr_email strings repeated on every application. A contact has a
+--              role, an optional company (resolved through company aliases), a list of channels
+--              (phone, email, wechat, ...), notes and a follow-up reminder, and is linked
+--              many-to-many to applications. contact_interactions logs calls, emails and
+--              messages; the latest one is kept in contacts.last_contacted_at.
+--              Existing HR fields are migrated by the application at startup
+--              (database.migrateHRContacts): applications are grouped by email, phone or name
+--              and linked to one 'hr' contact per person. The HR columns are kept unchanged.
+
+CREATE TABLE IF NOT EXISTS contacts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'other'
+        CHECK (role IN ('recruiter', 'hr', 'hiring_manager', 'interviewer', 'referrer', 'other')),
+    company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL,
+    channels JSONB NOT NULL DEFAULT '[]', -- model.ContactChannels
+    notes TEXT,
+    last_contacted_at TIMESTAMP WITH TIME ZONE,
+    follow_up_at TIMESTAMP WITH TIME ZONE,
+    follow_up_note VARCHAR(255),
+    source VARCHAR(20) NOT NULL DEFAULT 'manual', -- manual | hr_fields
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_contacts_user_name ON contacts(user_id, LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_contacts_user_follow_up ON contacts(user_id, follow_up_at) WHERE follow_up_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS contact_applications (
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    job_application_id INTEGER NOT NULL REFERENCES job_applications(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (contact_id, job_application_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_applications_application ON contact_applications(job_application_id);
+
+CREATE TABLE IF NOT EXISTS contact_interactions (
+    id SERIAL PRIMARY KEY,
+    contact_id INTEGER NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_application_id INTEGER REFERENCES job_applications(id) ON DELETE SET NULL,
+    interaction_type VARCHAR(20) NOT NULL
+        CHECK (interaction_type IN ('call', 'email', 'message', 'meeting', 'other')),
+    direction VARCHAR(10) NOT NULL DEFAULT 'outbound' CHECK (direction IN ('inbound', 'outbound')),
+    summary TEXT NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_interactions_contact ON contact_interactions(contact_id, occurred_at DESC);
+
+COMMENT ON TABLE contacts IS 'Recruiters, HR, hiring managers and referrers, linked to applications';
+COMMENT ON TABLE contact_interactions IS 'Calls, emails and messages exchanged with a contact';
@@ -0,0 +1,19 @@
+-- Migration: Let projection writes keep updated_at
+-- File: 025_preserve_updated_at.sql
+-- Description: update_updated_at_column() keeps the previous updated_at when the session sets
+--              jobview.preserve_updated_at = 'on'. Background jobs that only refresh derived
+--              columns (e.g. the success probability stored in status_duration_stats) set it so
+--              that viewing or rescoring an application is not recorded as a user change, which
+--              would break undo of batch operations and updated_at ordering.
+
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(current_setting('jobview.preserve_updated_at', true), '') = 'on' THEN
+        NEW.updated_at = OLD.updated_at;
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,111 @@
+-- Migration: Link HR fields to contacts once, then on write
+-- File: 027_link_hr_contacts.sql
+-- Description: The startup migration of hr_name/hr_phone/hr_email into contacts
+--              (database.migrateHRContacts) now runs once and is recorded in data_migrations,
+--              so contacts a user deleted or unlinked are not recreated on every restart.
+--              From now on link_application_hr_contact() links (or creates) the 'hr' contact in
+--              the same transaction whenever an application's HR fields change, using the same
+--              grouping rules: first channel (email, then phone), then name. Existing links are
+--              never removed and contact edits are not written back to the HR fields.
+
+CREATE TABLE IF NOT EXISTS data_migrations (
+    name VARCHAR(100) PRIMARY KEY,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Databases that already ran the repeated migration only need the marker
+INSERT INTO data_migrations (name)
+SELECT 'hr_contacts' WHERE EXISTS (SELECT 1 FROM contacts WHERE source = 'hr_fields')
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION link_application_hr_contact()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_name TEXT := btrim(COALESCE(NEW.hr_name, ''));
+    v_email TEXT := btrim(COALESCE(NEW.hr_email, ''));
+    v_phone TEXT := btrim(COALESCE(NEW.hr_phone, ''));
+    v_normalized TEXT;
+    v_channels JSONB := '[]'::jsonb;
+    v_existing JSONB;
+    v_merged JSONB;
+    v_contact_id INTEGER;
+BEGIN
+    IF NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW.hr_name IS NOT DISTINCT FROM OLD.hr_name AND NEW.hr_phone IS NOT DISTINCT FROM OLD.hr_phone
+           AND NEW.hr_email IS NOT DISTINCT FROM OLD.hr_email THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    IF v_name = '' AND v_email = '' AND v_phone = '' THEN
+        RETURN NEW;
+    END IF;
+
+    -- 不合法的邮箱/电话按 other 类型原样保存
+    IF v_email <> '' THEN
+        v_normalized := lower(v_email);
+        IF v_normalized ~ '^[^\s@]+@[^\s@]+\.[^\s@]+$' AND char_length(v_normalized) <= 255 THEN
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'email', 'value', v_normalized));
+        ELSE
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'other', 'value', v_email));
+        END IF;
+    END IF;
+    IF v_phone <> '' THEN
+        v_normalized := regexp_replace(v_phone, '[\s\-().（）]+', '', 'g');
+        IF v_normalized ~ '^\+?[0-9]{3,20}(#[0-9]{1,6})?$' THEN
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'phone', 'value', v_normalized));
+        ELSE
+            v_channels := v_channels || jsonb_build_array(jsonb_build_object('type', 'other', 'value', v_phone));
+        END IF;
+    END IF;
+    IF v_name = '' THEN
+        v_name := v_channels->0->>'value';
+        IF v_channels->0->>'type' = 'email' THEN
+            v_name := split_part(v_name, '@', 1);
+        END IF;
+    END IF;
+    v_name := left(v_name, 100);
+
+    IF jsonb_array_length(v_channels) > 0 THEN
+        SELECT id, channels INTO v_contact_id, v_existing
+        FROM contacts
+        WHERE user_id = NEW.user_id AND channels @> jsonb_build_array(v_channels->0)
+        ORDER BY id LIMIT 1;
+    END IF;
+    IF v_contact_id IS NULL THEN
+        SELECT id, channels INTO v_contact_id, v_existing
+        FROM contacts
+        WHERE user_id = NEW.user_id AND LOWER(name) = LOWER(v_name)
+          AND (jsonb_array_length(v_channels) = 0 OR channels = '[]'::jsonb)
+        ORDER BY id LIMIT 1;
+    END IF;
+
+    IF v_contact_id IS NULL THEN
+        INSERT INTO contacts (user_id, name, role, company_id, channels, source)
+        VALUES (NEW.user_id, v_name, 'hr', NEW.company_id, v_channels, 'hr_fields')
+        RETURNING id INTO v_contact_id;
+    ELSE
+        SELECT v_existing || COALESCE(jsonb_agg(c), '[]'::jsonb) INTO v_merged
+        FROM jsonb_array_elements(v_channels) c
+        WHERE NOT EXISTS (
+            SELECT 1 FROM jsonb_array_elements(v_existing) e
+            WHERE e->>'type' = c->>'type' AND lower(e->>'value') = lower(c->>'value')
+        );
+        IF v_merged <> v_existing THEN
+            UPDATE contacts SET channels = v_merged, updated_at = NOW() WHERE id = v_contact_id;
+        END IF;
+    END IF;
+
+    INSERT INTO contact_applications (contact_id, job_application_id)
+    VALUES (v_contact_id, NEW.id)
+    ON CONFLICT DO NOTHING;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_job_application_hr_contact ON job_applications;
+CREATE TRIGGER trg_job_application_hr_contact
+    AFTER INSERT OR UPDATE OF hr_name, hr_phone, hr_email ON job_applications
+    FOR EACH ROW EXECUTE FUNCTION link_application_hr_contact();
@@ -142,6 +142,23 @@ export class StatusTrackingAPI {
     }
   }
 
+  /**
+   * 撤销批量操作（批量状态更新/批量删除返回的 operation_id）
+   * @param operationId 操作ID
+   */
+  static async undoOperation(operationId: number): Promise<{
+    operation_id: number;
+    operation_type: 'batch_status_update' | 'batch_delete';
+    restored_count: number;
+    undone_at: string;
+  }> {
+    const response = await request.post(`/api/v1/operations/${operationId}/undo`)
+    if (!response.data.data) {
+      throw new Error(response.data?.message || '撤销失败')
+    }
+    return response.data.data
+  }
+
   // ========== 状态配置管理API ==========
 
   /**
@@ -276,8 +293,9 @@ export class StatusTrackingAPI {
    */
   static async getStatusTrends(params?: {
     period?: 'week' | 'month' | 'quarter';
-    start_date?: string; // 目前后端未使用
-    end_date?: string;   // 目前后端未使用
+    start_date?: string;
+    end_date?: string;
+    granularity?: 'day' | 'week' | 'month';
   }): Promise<{
     trends: Array<{
       date: string;
@@ -290,13 +308,21 @@ export class StatusTrackingAPI {
     const period = params?.period
     const days = period === 'week' ? 7 : period === 'quarter' ? 90 : 30
 
-    const url = `/api/v1/job-applications/status-trends?days=${days}`
+    const query = new URLSearchParams({
+      days: String(days),
+      timezone: Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC',
+    })
+    if (params?.start_date) query.set('start_date', params.start_date)
+    if (params?.end_date) query.set('end_date', params.end_date)
+    if (params?.granularity) query.set('granularity', params.granularity)
+
+    const url = `/api/v1/job-applications/status-trends?${query.toString()}`
     const response = await request.get(url)
     if (!response.data?.data) {
       throw new Error('获取趋势数据失败')
     }
 
-    // 后端返回形如 { days: number, trends: Array<{date,status,count}> }，有可能 trends 为 null
+    // 后端返回形如 { days, granularity, buckets, groups, trends: Array<{date,status,count}> }，空桶 count 为 0
     const raw = response.data.data
     const list: Array<{ date: string; status: string; count: number }> = Array.isArray(raw)
       ? raw as any
@@ -340,10 +366,12 @@ export class StatusTrackingAPI {
       priority: number;
     }>;
     recommendations: Array<{
+      rule_id: string;
       category: string;
+      severity: 'high' | 'medium' | 'low';
       title: string;
-      description: string;
-      impact_level: 'high' | 'medium' | 'low';
+      message: string;
+      application_ids?: number[];
     }>;
   }> {
     const response = await request.get('/api/v1/job-applications/process-insights')